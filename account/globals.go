@@ -109,10 +109,10 @@ const (
 
 // db
 var (
-	dbAccount     *pttdb.LDBBatch    = nil
-	dbAccountCore *pttdb.LDBDatabase = nil
+	dbAccount     pttdb.IndexBatch = nil
+	dbAccountCore pttdb.DB         = nil
 
-	dbMeta *pttdb.LDBDatabase = nil
+	dbMeta pttdb.DB = nil
 
 	DBProfilePrefix = []byte(".pfdb")
 
@@ -155,7 +155,7 @@ var (
 func InitAccount(dataDir string) error {
 	var err error
 
-	dbAccountCore, err = pttdb.NewLDBDatabase("account", dataDir, 0, 0)
+	dbAccountCore, err = pttdb.NewDatabase("account", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
@@ -165,7 +165,7 @@ func InitAccount(dataDir string) error {
 		return err
 	}
 
-	dbMeta, err = pttdb.NewLDBDatabase("accountmeta", dataDir, 0, 0)
+	dbMeta, err = pttdb.NewDatabase("accountmeta", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
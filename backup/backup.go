@@ -0,0 +1,481 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/pttdb"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+/*
+dbEntry is the db to backup.
+The snapshot is taken before writing any data,
+so that all the dbs are in the state of (about) the same time while the node is running.
+*/
+type dbEntry struct {
+	path string
+	snap pttdb.Snapshot
+	db   pttdb.DB // not nil if opened by backup (not opened by the node).
+}
+
+/*
+Export writes the passphrase-encrypted backup of the data-dir to w.
+
+The dbs opened by the node (through pttdb) are backed up with the snapshots,
+and the other dbs are opened read-only (failed if locked by another process).
+The dbs in SkipDBNames are skipped.
+The other regular files (ex: the keys) are copied as they are.
+*/
+func Export(w io.Writer, dataDir string, passphrase []byte) error {
+	dbEntries, files, err := collectEntries(dataDir)
+	defer func() {
+		for _, entry := range dbEntries {
+			entry.snap.Release()
+			if entry.db != nil {
+				entry.db.Close()
+			}
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	encWriter, err := newEncryptWriter(w, passphrase)
+	if err != nil {
+		return err
+	}
+
+	gzWriter := gzip.NewWriter(encWriter)
+	bufWriter := bufio.NewWriter(gzWriter)
+
+	for _, entry := range dbEntries {
+		err = writeDB(bufWriter, dataDir, entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, path := range files {
+		err = writeFile(bufWriter, dataDir, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = bufWriter.WriteByte(recordTypeEnd)
+	if err != nil {
+		return err
+	}
+
+	err = bufWriter.Flush()
+	if err != nil {
+		return err
+	}
+
+	err = gzWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return encWriter.Close()
+}
+
+func collectEntries(dataDir string) ([]*dbEntry, []string, error) {
+	dbEntries := make([]*dbEntry, 0)
+	files := make([]string, 0)
+
+	err := filepath.Walk(dataDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			if info.Mode().IsRegular() {
+				files = append(files, path)
+			}
+			return nil
+		}
+
+		if path == filepath.Join(dataDir, BackupDirName) {
+			return filepath.SkipDir
+		}
+
+		_, err = pttdb.GetEngineType(path)
+		if err != nil {
+			return nil
+		}
+
+		if isSkipDB(path) {
+			log.Debug("backup.Export: skip db", "path", path)
+			return filepath.SkipDir
+		}
+
+		entry, err := snapshotDB(path)
+		if err != nil {
+			log.Error("backup.Export: unable to snapshot db", "path", path, "e", err)
+			return err
+		}
+		dbEntries = append(dbEntries, entry)
+
+		return filepath.SkipDir
+	})
+
+	return dbEntries, files, err
+}
+
+func isSkipDB(path string) bool {
+	name := filepath.Base(path)
+	for _, each := range SkipDBNames {
+		if name == each {
+			return true
+		}
+	}
+
+	return false
+}
+
+func snapshotDB(path string) (*dbEntry, error) {
+	var db pttdb.DB
+	isOpened := false
+
+	openedDB := pttdb.GetOpenedDB(path)
+	if openedDB != nil {
+		db = openedDB
+	} else {
+		var err error
+		db, err = pttdb.OpenDatabaseReadOnly(path)
+		if err != nil {
+			return nil, err
+		}
+		isOpened = true
+	}
+
+	snap, err := db.NewSnapshot()
+	if err != nil {
+		if isOpened {
+			db.Close()
+		}
+		return nil, err
+	}
+
+	entry := &dbEntry{path: path, snap: snap}
+	if isOpened {
+		entry.db = db
+	}
+
+	return entry, nil
+}
+
+/*
+writeDB writes the db-record:
+
+	recordTypeDB | path | (1 | key | value)* | 0
+*/
+func writeDB(w *bufio.Writer, dataDir string, entry *dbEntry) error {
+	err := w.WriteByte(recordTypeDB)
+	if err != nil {
+		return err
+	}
+
+	err = writeRelPath(w, dataDir, entry.path)
+	if err != nil {
+		return err
+	}
+
+	iter := entry.snap.NewIterator(nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		err = w.WriteByte(1)
+		if err != nil {
+			return err
+		}
+		err = writeBytes(w, iter.Key())
+		if err != nil {
+			return err
+		}
+		err = writeBytes(w, iter.Value())
+		if err != nil {
+			return err
+		}
+	}
+	err = iter.Error()
+	if err != nil {
+		return err
+	}
+
+	return w.WriteByte(0)
+}
+
+/*
+writeFile writes the file-record:
+
+	recordTypeFile | path | mode | content
+*/
+func writeFile(w *bufio.Writer, dataDir string, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	err = w.WriteByte(recordTypeFile)
+	if err != nil {
+		return err
+	}
+
+	err = writeRelPath(w, dataDir, path)
+	if err != nil {
+		return err
+	}
+
+	err = writeUvarint(w, uint64(info.Mode().Perm()))
+	if err != nil {
+		return err
+	}
+
+	return writeBytes(w, content)
+}
+
+/*
+Import restores the backup from r to the data-dir.
+
+The data-dir is required to be empty (or not existing).
+The data-dir is removed if the backup is invalid.
+
+The entities are re-registered (through BasePtt.RegisterEntity) while the node starts with the restored data-dir.
+*/
+func Import(r io.Reader, dataDir string, passphrase []byte) (err error) {
+	fileInfos, err := ioutil.ReadDir(dataDir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(fileInfos) != 0 {
+		return ErrDataDirNotEmpty
+	}
+
+	decReader, err := newDecryptReader(r, passphrase)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dataDir, 0700)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dataDir)
+		}
+	}()
+
+	gzReader, err := gzip.NewReader(decReader)
+	if err != nil {
+		return wrapReadErr(err)
+	}
+	bufReader := bufio.NewReader(gzReader)
+
+	var recordType uint8
+	for {
+		recordType, err = bufReader.ReadByte()
+		if err != nil {
+			return wrapReadErr(err)
+		}
+
+		switch recordType {
+		case recordTypeEnd:
+			return nil
+		case recordTypeDB:
+			err = readDB(bufReader, dataDir)
+		case recordTypeFile:
+			err = readFile(bufReader, dataDir)
+		default:
+			err = ErrInvalidBackup
+		}
+		if err != nil {
+			return wrapReadErr(err)
+		}
+	}
+}
+
+func readDB(r *bufio.Reader, dataDir string) error {
+	path, err := readRelPath(r, dataDir)
+	if err != nil {
+		return err
+	}
+
+	// the data is not persisted with the memory-engine.
+	engine := pttdb.CurrentEngine
+	if engine == pttdb.EngineMemory {
+		engine = pttdb.EngineLevelDB
+	}
+
+	db, err := pttdb.NewDatabaseWithEngine(engine, filepath.Base(path), filepath.Dir(path), 0, 0)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	batch := new(leveldb.Batch)
+
+	var flag uint8
+	var key, val []byte
+	for {
+		flag, err = r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if flag == 0 {
+			break
+		}
+
+		key, err = readBytes(r)
+		if err != nil {
+			return err
+		}
+		val, err = readBytes(r)
+		if err != nil {
+			return err
+		}
+
+		batch.Put(key, val)
+		if len(batch.Dump()) < pttdb.IdealBatchSize {
+			continue
+		}
+
+		err = db.Write(batch)
+		if err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+
+	return db.Write(batch)
+}
+
+func readFile(r *bufio.Reader, dataDir string) error {
+	path, err := readRelPath(r, dataDir)
+	if err != nil {
+		return err
+	}
+
+	mode, err := binary.ReadUvarint(r)
+	if err != nil {
+		return err
+	}
+
+	content, err := readBytes(r)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, content, os.FileMode(mode).Perm())
+}
+
+func wrapReadErr(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrTruncated
+	}
+
+	return err
+}
+
+/**********
+ * utils
+ **********/
+
+func writeUvarint(w *bufio.Writer, v uint64) error {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, v)
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+func writeBytes(w *bufio.Writer, theBytes []byte) error {
+	err := writeUvarint(w, uint64(len(theBytes)))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(theBytes)
+	return err
+}
+
+func readBytes(r *bufio.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	theBytes := make([]byte, length)
+	_, err = io.ReadFull(r, theBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return theBytes, nil
+}
+
+func writeRelPath(w *bufio.Writer, dataDir string, path string) error {
+	relPath, err := filepath.Rel(dataDir, path)
+	if err != nil {
+		return err
+	}
+
+	return writeBytes(w, []byte(filepath.ToSlash(relPath)))
+}
+
+/*
+readRelPath reads the relative path and resolves with the data-dir.
+The absolute paths and the paths outside the data-dir are rejected.
+*/
+func readRelPath(r *bufio.Reader, dataDir string) (string, error) {
+	relPathBytes, err := readBytes(r)
+	if err != nil {
+		return "", err
+	}
+
+	relPath := filepath.FromSlash(string(relPathBytes))
+	if relPath == "" || filepath.IsAbs(relPath) {
+		return "", ErrInvalidPath
+	}
+
+	relPath = filepath.Clean(relPath)
+	if relPath == "." || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", ErrInvalidPath
+	}
+
+	return filepath.Join(dataDir, relPath), nil
+}
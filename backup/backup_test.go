@@ -0,0 +1,275 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/pttdb"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+func prepareTestDataDir(t *testing.T, srcDir string) (pttdb.DB, [][]byte) {
+	// db opened by the node (random values to be with multiple chunks)
+	db, err := pttdb.NewDatabase("oplog", filepath.Join(srcDir, "ptt"), 0, 0)
+	if err != nil {
+		t.Fatalf("unable to create db: e: %v", err)
+	}
+	vals := make([][]byte, 1000)
+	for i := range vals {
+		vals[i] = make([]byte, 100)
+		rand.Read(vals[i])
+		db.Put([]byte{byte(i >> 8), byte(i)}, vals[i])
+	}
+
+	// db not opened by the node
+	closedDB, err := leveldb.OpenFile(filepath.Join(srcDir, "me", "me"), nil)
+	if err != nil {
+		t.Fatalf("unable to create db: e: %v", err)
+	}
+	closedDB.Put([]byte("key"), []byte("val"), nil)
+	closedDB.Close()
+
+	// key
+	ioutil.WriteFile(filepath.Join(srcDir, "me", "mykey"), []byte("test-key"), 0600)
+
+	// skipped backups
+	os.MkdirAll(filepath.Join(srcDir, BackupDirName), 0700)
+	ioutil.WriteFile(filepath.Join(srcDir, BackupDirName, "old.bak"), []byte("old"), 0600)
+
+	return db, vals
+}
+
+func TestBackup_ExportImport(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	srcDir := filepath.Join(tDataDir, "src")
+	db, vals := prepareTestDataDir(t, srcDir)
+	defer db.Close()
+
+	// skipped db locked by others
+	nodesDB, err := leveldb.OpenFile(filepath.Join(srcDir, "gptt", "nodes"), nil)
+	if err != nil {
+		t.Fatalf("unable to create db: e: %v", err)
+	}
+	defer nodesDB.Close()
+
+	// export
+	buf := &bytes.Buffer{}
+	err = Export(buf, srcDir, []byte("passphrase"))
+	if err != nil {
+		t.Errorf("Export: e: %v", err)
+		return
+	}
+
+	// data after the snapshot is not in the backup
+	db.Put([]byte("after"), []byte("snapshot"))
+
+	// import
+	dstDir := filepath.Join(tDataDir, "dst")
+	err = Import(bytes.NewReader(buf.Bytes()), dstDir, []byte("passphrase"))
+	if err != nil {
+		t.Errorf("Import: e: %v", err)
+		return
+	}
+
+	dstDB, err := leveldb.OpenFile(filepath.Join(dstDir, "ptt", "oplog"), nil)
+	if err != nil {
+		t.Errorf("unable to open imported db: e: %v", err)
+		return
+	}
+	for i := range vals {
+		val, err := dstDB.Get([]byte{byte(i >> 8), byte(i)}, nil)
+		if err != nil || !reflect.DeepEqual(val, vals[i]) {
+			t.Errorf("imported db: i: %v val: %v e: %v", i, val, err)
+			break
+		}
+	}
+	_, err = dstDB.Get([]byte("after"), nil)
+	if err != leveldb.ErrNotFound {
+		t.Errorf("imported db: after-snapshot e: %v", err)
+	}
+	dstDB.Close()
+
+	dstDB, err = leveldb.OpenFile(filepath.Join(dstDir, "me", "me"), nil)
+	if err != nil {
+		t.Errorf("unable to open imported db: e: %v", err)
+		return
+	}
+	val, err := dstDB.Get([]byte("key"), nil)
+	if err != nil || string(val) != "val" {
+		t.Errorf("imported db: val: %v e: %v", val, err)
+	}
+	dstDB.Close()
+
+	key, err := ioutil.ReadFile(filepath.Join(dstDir, "me", "mykey"))
+	if err != nil || string(key) != "test-key" {
+		t.Errorf("imported key: key: %v e: %v", key, err)
+	}
+	info, err := os.Stat(filepath.Join(dstDir, "me", "mykey"))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("imported key: info: %v e: %v", info, err)
+	}
+
+	_, err = os.Stat(filepath.Join(dstDir, BackupDirName))
+	if !os.IsNotExist(err) {
+		t.Errorf("imported backups: e: %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(dstDir, "gptt", "nodes"))
+	if !os.IsNotExist(err) {
+		t.Errorf("imported skipped db: e: %v", err)
+	}
+
+	// import to non-empty data-dir
+	err = Import(bytes.NewReader(buf.Bytes()), dstDir, []byte("passphrase"))
+	if err != ErrDataDirNotEmpty {
+		t.Errorf("Import: non-empty data-dir: e: %v", err)
+	}
+
+	// teardown test
+}
+
+func TestBackup_ImportInvalid(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	srcDir := filepath.Join(tDataDir, "src")
+	db, _ := prepareTestDataDir(t, srcDir)
+	defer db.Close()
+
+	buf := &bytes.Buffer{}
+	err := Export(buf, srcDir, []byte("passphrase"))
+	if err != nil {
+		t.Errorf("Export: e: %v", err)
+		return
+	}
+	theBytes := buf.Bytes()
+
+	tampered := make([]byte, len(theBytes))
+	copy(tampered, theBytes)
+	tampered[len(tampered)/2] ^= 0x01
+
+	tamperedHeader := make([]byte, len(theBytes))
+	copy(tamperedHeader, theBytes)
+	tamperedHeader[len(Magic)+1+12] ^= 0x01
+
+	// N = 2^30 (1 TB of memory with r = 8)
+	tamperedScrypt := make([]byte, len(theBytes))
+	copy(tamperedScrypt, theBytes)
+	binary.BigEndian.PutUint32(tamperedScrypt[len(Magic)+1:], 1<<30)
+
+	// prepare test-cases
+	tests := []struct {
+		name       string
+		backup     []byte
+		passphrase string
+		wantErr    error
+	}{
+		{
+			name:       "wrong-passphrase",
+			backup:     theBytes,
+			passphrase: "wrong",
+			wantErr:    ErrInvalidPassphrase,
+		},
+		{
+			name:       "tampered",
+			backup:     tampered,
+			passphrase: "passphrase",
+			wantErr:    ErrInvalidPassphrase,
+		},
+		{
+			name:       "tampered-header",
+			backup:     tamperedHeader,
+			passphrase: "passphrase",
+			wantErr:    ErrInvalidPassphrase,
+		},
+		{
+			name:       "tampered-scrypt",
+			backup:     tamperedScrypt,
+			passphrase: "passphrase",
+			wantErr:    ErrInvalidScrypt,
+		},
+		{
+			name:       "truncated",
+			backup:     theBytes[:len(theBytes)-10],
+			passphrase: "passphrase",
+			wantErr:    ErrTruncated,
+		},
+		{
+			name:       "truncated-last-chunk",
+			backup:     theBytes[:len(Magic)+1+12+SizeSalt+SizeNoncePrefix+4+ChunkSize+16],
+			passphrase: "passphrase",
+			wantErr:    ErrTruncated,
+		},
+		{
+			name:       "invalid",
+			backup:     []byte("not a backup"),
+			passphrase: "passphrase",
+			wantErr:    ErrInvalidBackup,
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dstDir := filepath.Join(tDataDir, "dst-"+tt.name)
+			err := Import(bytes.NewReader(tt.backup), dstDir, []byte(tt.passphrase))
+			if err != tt.wantErr {
+				t.Errorf("Import() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := os.Stat(dstDir); !os.IsNotExist(err) {
+				t.Errorf("Import() data-dir is not removed: e: %v", err)
+			}
+		})
+	}
+
+	// teardown test
+}
+
+func TestBackup_ExportLocked(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	srcDir := filepath.Join(tDataDir, "src")
+	db, _ := prepareTestDataDir(t, srcDir)
+	defer db.Close()
+
+	// db locked by others
+	lockedDB, err := leveldb.OpenFile(filepath.Join(srcDir, "friend", "friend"), nil)
+	if err != nil {
+		t.Fatalf("unable to create db: e: %v", err)
+	}
+	defer lockedDB.Close()
+
+	err = Export(&bytes.Buffer{}, srcDir, []byte("passphrase"))
+	if err == nil {
+		t.Errorf("Export: locked db: no error")
+	}
+
+	// teardown test
+}
@@ -0,0 +1,333 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+package backup
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+/*
+header is the plaintext header of the backup:
+
+	magic | version | scrypt-N | scrypt-r | scrypt-p | salt | nonce-prefix
+
+The header is authenticated as the additional-data of every chunk.
+*/
+type header struct {
+	Version     uint8
+	N           uint32
+	R           uint32
+	P           uint32
+	Salt        []byte
+	NoncePrefix []byte
+}
+
+func newHeader() (*header, error) {
+	salt := make([]byte, SizeSalt)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	noncePrefix := make([]byte, SizeNoncePrefix)
+	_, err = rand.Read(noncePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &header{
+		Version:     CurrentVersion,
+		N:           uint32(ScryptN),
+		R:           uint32(ScryptR),
+		P:           uint32(ScryptP),
+		Salt:        salt,
+		NoncePrefix: noncePrefix,
+	}, nil
+}
+
+func (h *header) Marshal() []byte {
+	buf := make([]byte, 0, len(Magic)+1+12+SizeSalt+SizeNoncePrefix)
+	buf = append(buf, Magic...)
+	buf = append(buf, h.Version)
+
+	uint32Bytes := make([]byte, 4)
+	for _, each := range []uint32{h.N, h.R, h.P} {
+		binary.BigEndian.PutUint32(uint32Bytes, each)
+		buf = append(buf, uint32Bytes...)
+	}
+
+	buf = append(buf, h.Salt...)
+	buf = append(buf, h.NoncePrefix...)
+
+	return buf
+}
+
+func readHeader(r io.Reader) (*header, []byte, error) {
+	buf := make([]byte, len(Magic)+1+12+SizeSalt+SizeNoncePrefix)
+	_, err := io.ReadFull(r, buf)
+	if err != nil {
+		return nil, nil, ErrInvalidBackup
+	}
+
+	if !bytes.Equal(buf[:len(Magic)], Magic) {
+		return nil, nil, ErrInvalidBackup
+	}
+	offset := len(Magic)
+
+	h := &header{Version: buf[offset]}
+	if h.Version != CurrentVersion {
+		return nil, nil, ErrInvalidVersion
+	}
+	offset++
+
+	h.N = binary.BigEndian.Uint32(buf[offset:])
+	h.R = binary.BigEndian.Uint32(buf[offset+4:])
+	h.P = binary.BigEndian.Uint32(buf[offset+8:])
+	offset += 12
+
+	if h.N > MaxScryptN || h.R > MaxScryptR || h.P > MaxScryptP {
+		return nil, nil, ErrInvalidScrypt
+	}
+
+	h.Salt = buf[offset : offset+SizeSalt]
+	offset += SizeSalt
+
+	h.NoncePrefix = buf[offset : offset+SizeNoncePrefix]
+
+	return h, buf, nil
+}
+
+func (h *header) NewAEAD(passphrase []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, h.Salt, int(h.N), int(h.R), int(h.P), SizeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(noncePrefix []byte, counter uint64) []byte {
+	nonce := make([]byte, SizeNoncePrefix+8)
+	copy(nonce, noncePrefix)
+	binary.BigEndian.PutUint64(nonce[SizeNoncePrefix:], counter)
+	return nonce
+}
+
+func chunkAD(headerBytes []byte, isLast bool) []byte {
+	ad := make([]byte, len(headerBytes)+1)
+	copy(ad, headerBytes)
+	if isLast {
+		ad[len(headerBytes)] = 1
+	}
+	return ad
+}
+
+/*
+encryptWriter encrypts the stream as AES-GCM chunks:
+
+	uint32 length (the highest bit for the last chunk) | ciphertext
+
+Each chunk is with the nonce of nonce-prefix | counter,
+so that the chunks cannot be reordered, and the truncation is detected with the missing last chunk.
+*/
+type encryptWriter struct {
+	w           io.Writer
+	aead        cipher.AEAD
+	headerBytes []byte
+	noncePrefix []byte
+	counter     uint64
+	buf         []byte
+}
+
+func newEncryptWriter(w io.Writer, passphrase []byte) (*encryptWriter, error) {
+	h, err := newHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := h.NewAEAD(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	headerBytes := h.Marshal()
+	_, err = w.Write(headerBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w:           w,
+		aead:        aead,
+		headerBytes: headerBytes,
+		noncePrefix: h.NoncePrefix,
+		buf:         make([]byte, 0, ChunkSize),
+	}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		toCopy := ChunkSize - len(e.buf)
+		if toCopy > len(p) {
+			toCopy = len(p)
+		}
+		e.buf = append(e.buf, p[:toCopy]...)
+		p = p[toCopy:]
+
+		if len(e.buf) < ChunkSize {
+			break
+		}
+
+		err := e.writeChunk(false)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return n, nil
+}
+
+/*
+Close writes the last chunk. The underlying writer is not closed.
+*/
+func (e *encryptWriter) Close() error {
+	return e.writeChunk(true)
+}
+
+func (e *encryptWriter) writeChunk(isLast bool) error {
+	nonce := chunkNonce(e.noncePrefix, e.counter)
+	e.counter++
+
+	ciphertext := e.aead.Seal(nil, nonce, e.buf, chunkAD(e.headerBytes, isLast))
+	e.buf = e.buf[:0]
+
+	length := uint32(len(ciphertext))
+	if isLast {
+		length |= flagLastChunk
+	}
+
+	lengthBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthBytes, length)
+
+	_, err := e.w.Write(lengthBytes)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(ciphertext)
+	return err
+}
+
+/*
+decryptReader decrypts the stream written by encryptWriter.
+*/
+type decryptReader struct {
+	r           io.Reader
+	aead        cipher.AEAD
+	headerBytes []byte
+	noncePrefix []byte
+	counter     uint64
+	buf         []byte
+	isLast      bool
+}
+
+func newDecryptReader(r io.Reader, passphrase []byte) (*decryptReader, error) {
+	h, headerBytes, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := h.NewAEAD(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		r:           r,
+		aead:        aead,
+		headerBytes: headerBytes,
+		noncePrefix: h.NoncePrefix,
+	}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.isLast {
+			return 0, io.EOF
+		}
+
+		err := d.readChunk()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+
+	return n, nil
+}
+
+func (d *decryptReader) readChunk() error {
+	lengthBytes := make([]byte, 4)
+	_, err := io.ReadFull(d.r, lengthBytes)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrTruncated
+	}
+	if err != nil {
+		return err
+	}
+
+	length := binary.BigEndian.Uint32(lengthBytes)
+	isLast := length&flagLastChunk != 0
+	length &^= flagLastChunk
+	if length > ChunkSize+uint32(d.aead.Overhead()) {
+		return ErrInvalidBackup
+	}
+
+	ciphertext := make([]byte, length)
+	_, err = io.ReadFull(d.r, ciphertext)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrTruncated
+	}
+	if err != nil {
+		return err
+	}
+
+	nonce := chunkNonce(d.noncePrefix, d.counter)
+	d.counter++
+
+	d.buf, err = d.aead.Open(ciphertext[:0], nonce, ciphertext, chunkAD(d.headerBytes, isLast))
+	if err != nil {
+		return ErrInvalidPassphrase
+	}
+	d.isLast = isLast
+
+	return nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+package backup
+
+import "errors"
+
+var (
+	ErrInvalidBackup     = errors.New("invalid backup")
+	ErrInvalidVersion    = errors.New("invalid backup version")
+	ErrInvalidScrypt     = errors.New("invalid scrypt parameters in backup")
+	ErrInvalidPath       = errors.New("invalid path in backup")
+	ErrInvalidPassphrase = errors.New("invalid passphrase or corrupted backup")
+	ErrTruncated         = errors.New("truncated backup")
+	ErrDataDirNotEmpty   = errors.New("data-dir is not empty")
+)
@@ -0,0 +1,57 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+package backup
+
+const (
+	CurrentVersion uint8 = 1
+
+	SizeSalt        = 32
+	SizeNoncePrefix = 4
+	SizeKey         = 32
+
+	ChunkSize = 64 * 1024
+
+	// the highest bit of the chunk-length marks the last chunk.
+	flagLastChunk uint32 = 1 << 31
+
+	// the name of the backup-dir in the data-dir, skipped in the backup.
+	BackupDirName = "backups"
+)
+
+var (
+	Magic = []byte("PTTAIBAK")
+
+	// the dbs not opened through pttdb and re-built after restore (the p2p node-db, see node.DataDirNodeDatabase).
+	SkipDBNames = []string{"nodes"}
+
+	// scrypt parameters. N is var for the tests.
+	ScryptN = 1 << 18
+	ScryptR = 8
+	ScryptP = 1
+
+	// the max scrypt parameters accepted from the header (the ones the writer uses),
+	// so that a crafted backup cannot exhaust the memory / cpu on restore.
+	MaxScryptN uint32 = 1 << 18
+	MaxScryptR uint32 = 8
+	MaxScryptP uint32 = 1
+)
+
+// record-type
+const (
+	recordTypeEnd uint8 = iota
+	recordTypeDB
+	recordTypeFile
+)
@@ -0,0 +1,147 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package chat
+
+import (
+	"context"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+	"github.com/ailabstw/go-pttai/rpc"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+type PrivateAPI struct {
+	b *Backend
+}
+
+func NewPrivateAPI(b *Backend) *PrivateAPI {
+	return &PrivateAPI{b}
+}
+
+/**********
+ * Chat
+ **********/
+
+func (api *PrivateAPI) CreateChat(title []byte) (*BackendCreateChat, error) {
+	return api.b.CreateChat(title)
+}
+
+func (api *PrivateAPI) GetChat(entityID string) (*BackendGetChat, error) {
+	return api.b.GetChat([]byte(entityID))
+}
+
+func (api *PrivateAPI) GetRawChat(entityID string) (*Chat, error) {
+	return api.b.GetRawChat([]byte(entityID))
+}
+
+func (api *PrivateAPI) GetChatList(startingChatID string, limit int, listOrder pttdb.ListOrder) ([]*BackendGetChat, error) {
+	return api.b.GetChatList(
+		[]byte(startingChatID),
+		limit,
+		listOrder,
+	)
+}
+
+func (api *PrivateAPI) DeleteChat(entityID string) (bool, error) {
+	return api.b.DeleteChat([]byte(entityID))
+}
+
+func (api *PrivateAPI) MarkChatSeen(entityID string) (types.Timestamp, error) {
+	return api.b.MarkChatSeen([]byte(entityID))
+}
+
+/**********
+ * Member
+ **********/
+
+func (api *PrivateAPI) ShowChatURL(entityID string) (*pkgservice.BackendJoinURL, error) {
+	return api.b.ShowChatURL([]byte(entityID))
+}
+
+func (api *PrivateAPI) GetJoinKeyInfos(entityID string) ([]*pkgservice.KeyInfo, error) {
+	return api.b.GetJoinKeys([]byte(entityID))
+}
+
+func (api *PrivateAPI) LeaveChat(entityID string) (bool, error) {
+	return api.b.LeaveEntity([]byte(entityID))
+}
+
+func (api *PrivateAPI) DeleteMember(entityID string, userID string) (bool, error) {
+	return api.b.DeleteMember([]byte(entityID), []byte(userID))
+}
+
+func (api *PrivateAPI) GetMasterListFromCache(entityID string) ([]*pkgservice.Master, error) {
+	return api.b.GetMasterListFromCache([]byte(entityID))
+}
+
+func (api *PrivateAPI) GetMemberList(entityID string, startID string, limit int, listOrder pttdb.ListOrder) ([]*pkgservice.Member, error) {
+	return api.b.GetMemberList([]byte(entityID), []byte(startID), limit, listOrder)
+}
+
+/**********
+ * Message
+ **********/
+
+func (api *PrivateAPI) CreateMessage(entityID string, message [][]byte, mediaIDs []string) (*BackendCreateMessage, error) {
+	return api.b.CreateMessage(
+		[]byte(entityID),
+		message,
+		mediaIDs,
+	)
+}
+
+func (api *PrivateAPI) GetMessageList(entityID string, startingMessageID string, limit int, listOrder pttdb.ListOrder) ([]*BackendGetMessage, error) {
+	return api.b.GetMessageList(
+		[]byte(entityID),
+		[]byte(startingMessageID),
+		limit,
+		listOrder,
+	)
+}
+
+func (api *PrivateAPI) GetMessageBlockList(entityID string, messageID string, limit uint32) ([]*BackendMessageBlock, error) {
+	return api.b.GetMessageBlockList([]byte(entityID), []byte(messageID), limit)
+}
+
+/**********
+ * ChatOplog
+ **********/
+
+func (api *PrivateAPI) GetChatOplogList(entityID string, logID string, limit int, listOrder pttdb.ListOrder) ([]*ChatOplog, error) {
+	return api.b.GetChatOplogList([]byte(entityID), []byte(logID), limit, listOrder)
+}
+
+func (api *PrivateAPI) ChatOplogs(ctx context.Context, entityID string) (*rpc.Subscription, error) {
+	return api.b.SubscribeChatOplogs(ctx, []byte(entityID))
+}
+
+/**********
+ * Peer
+ **********/
+
+func (api *PrivateAPI) CountPeers(entityID string) (int, error) {
+	return api.b.CountPeers([]byte(entityID))
+}
+
+func (api *PrivateAPI) GetPeers(entityID string) ([]*pkgservice.BackendPeer, error) {
+	return api.b.GetPeers([]byte(entityID))
+}
+
+func (api *PrivateAPI) ForceSync(entityID string) (bool, error) {
+	return api.b.ForceSync([]byte(entityID))
+}
@@ -0,0 +1,296 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package chat
+
+import (
+	"context"
+
+	"github.com/ailabstw/go-pttai/account"
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/pttdb"
+	"github.com/ailabstw/go-pttai/rpc"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func (b *Backend) CreateChat(title []byte) (*BackendCreateChat, error) {
+
+	chat, err := b.SPM().(*ServiceProtocolManager).CreateChat(title)
+	if err != nil {
+		return nil, err
+	}
+
+	return chatToBackendCreateChat(chat), nil
+}
+
+func (b *Backend) GetChat(entityIDBytes []byte) (*BackendGetChat, error) {
+
+	chat, err := b.GetRawChat(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	userName, err := b.accountBackend.GetRawUserNameByID(chat.CreatorID)
+	if err != nil {
+		userName = account.NewEmptyUserName()
+	}
+
+	return chatToBackendGetChat(chat, userName.Name), nil
+}
+
+func (b *Backend) GetRawChat(entityIDBytes []byte) (*Chat, error) {
+
+	entity, err := b.EntityIDToEntity(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	chat := entity.(*Chat)
+
+	return chat, nil
+}
+
+func (b *Backend) GetChatList(startingIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BackendGetChat, error) {
+
+	startID, err := types.UnmarshalTextPttID(startingIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	chatList, err := b.SPM().(*ServiceProtocolManager).GetChatList(startID, limit, listOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	accountBackend := b.accountBackend
+	backendChatList := make([]*BackendGetChat, len(chatList))
+	var userName *account.UserName
+	for i, c := range chatList {
+		userName, err = accountBackend.GetRawUserNameByID(c.CreatorID)
+		if err != nil {
+			userName = account.NewEmptyUserName()
+		}
+		backendChatList[i] = chatToBackendGetChat(c, userName.Name)
+	}
+
+	return backendChatList, nil
+}
+
+func (b *Backend) DeleteChat(entityIDBytes []byte) (bool, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return false, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	err = pm.DeleteChat()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/*
+ShowChatURL shows the url to invite the others to the chat.
+Only the master of the chat is able to invite the others.
+*/
+func (b *Backend) ShowChatURL(entityIDBytes []byte) (*pkgservice.BackendJoinURL, error) {
+
+	theEntity, err := b.EntityIDToEntity(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	chat := theEntity.(*Chat)
+	pm := chat.PM().(*ProtocolManager)
+
+	nodeID := b.Ptt().MyNodeID()
+	myID := b.Ptt().GetMyEntity().GetID()
+
+	if !pm.IsMaster(myID, false) {
+		return nil, types.ErrInvalidID
+	}
+
+	keyInfo, err := pm.GetJoinKey()
+	log.Debug("ShowChatURL: after get join key", "e", err)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgservice.MarshalBackendJoinURL(chat.CreatorID, nodeID, keyInfo, chat.Title, pkgservice.PathJoinChat)
+}
+
+func (b *Backend) GetJoinKeys(entityIDBytes []byte) ([]*pkgservice.KeyInfo, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	return pm.JoinKeyList(), nil
+}
+
+func (b *Backend) DeleteMember(entityIDBytes []byte, userIDBytes []byte) (bool, error) {
+
+	userID, err := types.UnmarshalTextPttID(userIDBytes, false)
+	if err != nil {
+		return false, err
+	}
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return false, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	return pm.DeleteMember(userID)
+}
+
+/**********
+ * Message
+ **********/
+
+func (b *Backend) CreateMessage(entityIDBytes []byte, message [][]byte, mediaIDStrs []string) (*BackendCreateMessage, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	lenMediaIDs := len(mediaIDStrs)
+	var mediaIDs []*types.PttID = nil
+	var eachMediaID *types.PttID
+	if len(mediaIDStrs) != 0 {
+		mediaIDs = make([]*types.PttID, lenMediaIDs)
+		for i, mediaIDStr := range mediaIDStrs {
+			eachMediaID, err = types.UnmarshalTextPttID([]byte(mediaIDStr), false)
+			if err != nil {
+				return nil, err
+			}
+			mediaIDs[i] = eachMediaID
+		}
+	}
+
+	theMessage, err := pm.CreateMessage(message, mediaIDs)
+	log.Debug("CreateMessage: after CreateMessage", "e", err)
+	if err != nil {
+		return nil, err
+	}
+
+	return messageToBackendCreateMessage(theMessage), nil
+}
+
+func (b *Backend) GetMessageList(entityIDBytes []byte, startIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BackendGetMessage, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	startID, err := types.UnmarshalTextPttID(startIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	messageList, err := pm.GetMessageList(startID, limit, listOrder, false)
+	if err != nil {
+		return nil, err
+	}
+
+	backendMessageList := make([]*BackendGetMessage, len(messageList))
+	for i, message := range messageList {
+		backendMessageList[i] = messageToBackendGetMessage(message)
+	}
+
+	return backendMessageList, nil
+}
+
+func (b *Backend) GetMessageBlockList(entityIDBytes []byte, msgIDBytes []byte, limit uint32) ([]*BackendMessageBlock, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	msgID, err := types.UnmarshalTextPttID(msgIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+	if msgID == nil {
+		return nil, types.ErrInvalidID
+	}
+
+	msg, contentBlocks, err := pm.GetMessageBlockList(msgID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	blockInfo := msg.GetBlockInfo()
+	if blockInfo == nil {
+		return nil, pkgservice.ErrInvalidBlock
+	}
+	blockInfoID := blockInfo.ID
+
+	backendMsgBlocks := make([]*BackendMessageBlock, len(contentBlocks))
+	for i, contentBlock := range contentBlocks {
+		backendMsgBlocks[i] = contentBlockToBackendMessageBlock(msg, blockInfoID, contentBlock)
+	}
+
+	return backendMsgBlocks, nil
+}
+
+func (b *Backend) MarkChatSeen(entityIDBytes []byte) (types.Timestamp, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return types.ZeroTimestamp, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	return pm.SaveLastSeen(types.ZeroTimestamp)
+}
+
+/**********
+ * ChatOplog
+ **********/
+
+func (b *Backend) GetChatOplogList(entityIDBytes []byte, logIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*ChatOplog, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	logID, err := types.UnmarshalTextPttID(logIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return pm.GetChatOplogList(logID, limit, listOrder, types.StatusAlive)
+}
+
+func (b *Backend) SubscribeChatOplogs(ctx context.Context, entityIDBytes []byte) (*rpc.Subscription, error) {
+	entityID, err := types.UnmarshalTextPttID(entityIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgservice.SubscribeOplogEvents(ctx, b.SPM().EventMux(), entityID)
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package chat
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/friend"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+type BackendCreateChat struct {
+	ID        *types.PttID
+	CreateTS  types.Timestamp `json:"CT"`
+	UpdateTS  types.Timestamp `json:"UT"`
+	CreatorID *types.PttID    `json:"CID"`
+
+	Status types.Status `json:"S"`
+
+	Title []byte `json:"T"`
+}
+
+func chatToBackendCreateChat(c *Chat) *BackendCreateChat {
+	return &BackendCreateChat{
+		ID:        c.ID,
+		CreateTS:  c.CreateTS,
+		UpdateTS:  c.UpdateTS,
+		CreatorID: c.CreatorID,
+		Status:    c.Status,
+		Title:     c.Title,
+	}
+}
+
+type BackendGetChat struct {
+	ID              *types.PttID
+	Title           []byte          `json:"T"`
+	Status          types.Status    `json:"S"`
+	CreatorID       *types.PttID    `json:"CID"`
+	CreatorName     []byte          `json:"CN"`
+	MessageCreateTS types.Timestamp `json:"MT"`
+	LastSeen        types.Timestamp `json:"LT"`
+}
+
+func chatToBackendGetChat(c *Chat, creatorName []byte) *BackendGetChat {
+	messageCreateTS := c.MessageCreateTS
+	if messageCreateTS.IsLess(c.CreateTS) {
+		messageCreateTS = c.CreateTS
+	}
+
+	return &BackendGetChat{
+		ID:              c.ID,
+		Title:           c.Title,
+		Status:          c.Status,
+		CreatorID:       c.CreatorID,
+		CreatorName:     creatorName,
+		MessageCreateTS: messageCreateTS,
+		LastSeen:        c.LastSeen,
+	}
+}
+
+type BackendCreateMessage struct {
+	ChatID    *types.PttID `json:"CID"`
+	MessageID *types.PttID `json:"AID"`
+	BlockID   *types.PttID `json:"cID"`
+	NBlock    int          `json:"NB"`
+}
+
+func messageToBackendCreateMessage(m *friend.Message) *BackendCreateMessage {
+
+	return &BackendCreateMessage{
+		ChatID:    m.EntityID,
+		MessageID: m.ID,
+		BlockID:   m.BlockInfo.ID,
+		NBlock:    m.BlockInfo.NBlock,
+	}
+}
+
+type BackendGetMessage struct {
+	ID        *types.PttID
+	CreateTS  types.Timestamp //`json:"CT"`
+	UpdateTS  types.Timestamp //`json:"UT"`
+	CreatorID *types.PttID    //`json:"CID"`
+	ChatID    *types.PttID    //`json:"ChID"`
+	BlockID   *types.PttID    //`json:"cID"`
+	NBlock    int             //`json:"N"`
+	Status    types.Status    `json:"S"`
+}
+
+func messageToBackendGetMessage(m *friend.Message) *BackendGetMessage {
+
+	return &BackendGetMessage{
+		ID:        m.ID,
+		CreateTS:  m.CreateTS,
+		UpdateTS:  m.UpdateTS,
+		CreatorID: m.CreatorID,
+		ChatID:    m.EntityID,
+		BlockID:   m.BlockInfo.ID,
+		NBlock:    m.BlockInfo.NBlock,
+		Status:    m.Status,
+	}
+}
+
+type BackendMessageBlock struct {
+	V         types.Version
+	ID        *types.PttID
+	MessageID *types.PttID `json:"AID"`
+	BlockID   uint32       `json:"BID"`
+
+	Status types.Status `json:"S"`
+
+	CreateTS types.Timestamp `json:"CT"`
+	UpdateTS types.Timestamp `json:"UT"`
+
+	CreatorID *types.PttID `json:"CID"`
+
+	Buf [][]byte `json:"B"`
+}
+
+func contentBlockToBackendMessageBlock(msg *friend.Message, blockInfoID *types.PttID, contentBlock *pkgservice.ContentBlock) *BackendMessageBlock {
+
+	return &BackendMessageBlock{
+		V:         types.CurrentVersion,
+		ID:        blockInfoID,
+		MessageID: msg.ID,
+		BlockID:   contentBlock.BlockID,
+		Status:    msg.Status,
+
+		CreateTS: msg.CreateTS,
+		UpdateTS: msg.UpdateTS,
+
+		CreatorID: msg.CreatorID,
+
+		Buf: contentBlock.Buf,
+	}
+}
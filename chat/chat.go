@@ -0,0 +1,246 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package chat
+
+import (
+	"encoding/json"
+
+	"github.com/ailabstw/go-pttai/common"
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/pttdb"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+/*
+Chat is the group-chat among multiple users.
+The creator is the master of the chat, and the others join the chat as the members.
+*/
+type Chat struct {
+	*pkgservice.BaseEntity `json:"e"`
+
+	UpdateTS types.Timestamp `json:"UT"`
+
+	Title []byte `json:"T,omitempty"`
+
+	// get from other dbs
+	LastSeen        types.Timestamp `json:"-"`
+	MessageCreateTS types.Timestamp `json:"-"`
+}
+
+func NewEmptyChat() *Chat {
+	return &Chat{BaseEntity: &pkgservice.BaseEntity{SyncInfo: &pkgservice.BaseSyncInfo{}}}
+}
+
+func NewChat(myID *types.PttID, ts types.Timestamp, ptt pkgservice.Ptt, service pkgservice.Service, spm pkgservice.ServiceProtocolManager, dbLock *types.LockMap) (*Chat, error) {
+
+	id, err := pkgservice.NewPttIDWithMyID(myID)
+	if err != nil {
+		return nil, err
+	}
+
+	e := pkgservice.NewBaseEntity(id, ts, myID, types.StatusInit, dbChat, dbLock)
+
+	c := &Chat{
+		BaseEntity: e,
+		UpdateTS:   ts,
+	}
+
+	err = c.Init(ptt, service, spm)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Chat) GetUpdateTS() types.Timestamp {
+	return c.UpdateTS
+}
+
+func (c *Chat) SetUpdateTS(ts types.Timestamp) {
+	c.UpdateTS = ts
+}
+
+func (c *Chat) Init(ptt pkgservice.Ptt, service pkgservice.Service, spm pkgservice.ServiceProtocolManager) error {
+
+	c.SetDB(dbChat, spm.GetDBLock())
+
+	err := c.InitPM(ptt, service)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Chat) InitPM(ptt pkgservice.Ptt, service pkgservice.Service) error {
+	pm, err := NewProtocolManager(c, ptt, service)
+	if err != nil {
+		log.Error("InitPM: unable to NewProtocolManager", "e", err)
+		return err
+	}
+
+	c.BaseEntity.Init(pm, ptt, service)
+
+	return nil
+}
+
+func (c *Chat) IdxKey() ([]byte, error) {
+	return common.Concat([][]byte{DBChatIdxPrefix, c.ID[:]})
+}
+
+func (c *Chat) MarshalKey() ([]byte, error) {
+	marshalTimestamp, err := c.JoinTS.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return common.Concat([][]byte{DBChatPrefix, marshalTimestamp, c.ID[:]})
+}
+
+func (c *Chat) Marshal() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *Chat) Unmarshal(theBytes []byte) error {
+	return json.Unmarshal(theBytes, c)
+}
+
+func (c *Chat) Save(isLocked bool) error {
+	if !isLocked {
+		err := c.Lock()
+		if err != nil {
+			return err
+		}
+		defer c.Unlock()
+	}
+
+	key, err := c.MarshalKey()
+	if err != nil {
+		return err
+	}
+
+	marshaled, err := c.Marshal()
+	if err != nil {
+		return err
+	}
+
+	idxKey, err := c.IdxKey()
+	if err != nil {
+		return err
+	}
+
+	idx := &pttdb.Index{Keys: [][]byte{key}, UpdateTS: c.UpdateTS}
+
+	kvs := []*pttdb.KeyVal{
+		&pttdb.KeyVal{
+			K: key,
+			V: marshaled,
+		},
+	}
+
+	_, err = dbChat.ForcePutAll(idxKey, idx, kvs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Chat) SaveLastSeen(ts types.Timestamp) error {
+	c.LastSeen = ts
+
+	key, err := c.MarshalLastSeenKey()
+	if err != nil {
+		return err
+	}
+
+	return c.saveTS(key, ts)
+}
+
+func (c *Chat) LoadLastSeen() (types.Timestamp, error) {
+	key, err := c.MarshalLastSeenKey()
+	if err != nil {
+		return types.ZeroTimestamp, err
+	}
+
+	return c.loadTS(key)
+}
+
+func (c *Chat) MarshalLastSeenKey() ([]byte, error) {
+	return common.Concat([][]byte{DBChatLastSeenPrefix, c.ID[:]})
+}
+
+func (c *Chat) SaveMessageCreateTS(ts types.Timestamp) error {
+	c.MessageCreateTS = ts
+
+	key, err := c.MarshalMessageCreateTSKey()
+	if err != nil {
+		return err
+	}
+
+	return c.saveTS(key, ts)
+}
+
+func (c *Chat) LoadMessageCreateTS() (types.Timestamp, error) {
+	key, err := c.MarshalMessageCreateTSKey()
+	if err != nil {
+		return types.ZeroTimestamp, err
+	}
+
+	return c.loadTS(key)
+}
+
+func (c *Chat) MarshalMessageCreateTSKey() ([]byte, error) {
+	return common.Concat([][]byte{DBChatMessageCreateTSPrefix, c.ID[:]})
+}
+
+func (c *Chat) saveTS(key []byte, ts types.Timestamp) error {
+	val := &pttdb.DBable{
+		UpdateTS: ts,
+	}
+	marshaled, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	_, err = dbChatCore.TryPut(key, marshaled, ts)
+	if err != nil && err != pttdb.ErrInvalidUpdateTS {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Chat) loadTS(key []byte) (types.Timestamp, error) {
+	data, err := dbChatCore.Get(key)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			err = nil
+		}
+		return types.ZeroTimestamp, err
+	}
+
+	val := &pttdb.DBable{}
+	err = json.Unmarshal(data, val)
+	if err != nil {
+		return types.ZeroTimestamp, err
+	}
+
+	return val.UpdateTS, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package chat
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+type ChatOplog struct {
+	*pkgservice.BaseOplog `json:"O"`
+}
+
+func (o *ChatOplog) GetBaseOplog() *pkgservice.BaseOplog {
+	return o.BaseOplog
+}
+
+func NewChatOplog(objID *types.PttID, ts types.Timestamp, doerID *types.PttID, op pkgservice.OpType, opData pkgservice.OpData, userID *types.PttID, dbLock *types.LockMap) (*ChatOplog, error) {
+
+	oplog, err := pkgservice.NewOplog(objID, ts, doerID, op, opData, dbChat, userID, DBChatOplogPrefix, DBChatIdxOplogPrefix, DBChatMerkleOplogPrefix, dbLock)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChatOplog{
+		BaseOplog: oplog,
+	}, nil
+}
+
+func (pm *ProtocolManager) NewChatOplog(objID *types.PttID, op pkgservice.OpType, opData pkgservice.OpData) (pkgservice.Oplog, error) {
+
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return nil, err
+	}
+
+	return pm.NewChatOplogWithTS(objID, ts, op, opData)
+}
+
+func (pm *ProtocolManager) NewChatOplogWithTS(objID *types.PttID, ts types.Timestamp, op pkgservice.OpType, opData pkgservice.OpData) (pkgservice.Oplog, error) {
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	entityID := pm.Entity().GetID()
+
+	oplog, err := NewChatOplog(objID, ts, myID, op, opData, entityID, pm.dbChatLock)
+	if err != nil {
+		return nil, err
+	}
+	pm.SetChatDB(oplog.BaseOplog)
+	return oplog, nil
+}
+
+func (spm *ServiceProtocolManager) NewChatOplogWithTS(entityID *types.PttID, ts types.Timestamp, op pkgservice.OpType, opData pkgservice.OpData) (pkgservice.Oplog, error) {
+
+	myID := spm.Ptt().GetMyEntity().GetID()
+	log.Debug("spm.NewChatOplogWithTS: start", "ts", ts)
+
+	return NewChatOplog(entityID, ts, myID, op, opData, entityID, spm.GetDBLogLock())
+}
+
+func (pm *ProtocolManager) SetChatDB(oplog *pkgservice.BaseOplog) {
+	userID := pm.Entity().GetID()
+	oplog.SetDB(dbChat, userID, DBChatOplogPrefix, DBChatIdxOplogPrefix, DBChatMerkleOplogPrefix, pm.dbChatLock)
+}
+
+func OplogsToChatOplogs(logs []*pkgservice.BaseOplog) []*ChatOplog {
+	typedLogs := make([]*ChatOplog, len(logs))
+	for i, log := range logs {
+		typedLogs[i] = &ChatOplog{BaseOplog: log}
+	}
+	return typedLogs
+}
+
+func ChatOplogsToOplogs(typedLogs []*ChatOplog) []*pkgservice.BaseOplog {
+	logs := make([]*pkgservice.BaseOplog, len(typedLogs))
+	for i, log := range typedLogs {
+		logs[i] = log.BaseOplog
+	}
+	return logs
+}
@@ -14,15 +14,12 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
 
-package content
-
-import (
-)
-
-type Reply struct {
+package chat
 
+type Config struct {
+	DataDir string
 }
 
-func NewReply() (*Reply, error) {
-    return &Reply{}, nil
+func NewConfig() (*Config, error) {
+	return &Config{}, nil
 }
@@ -0,0 +1,132 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package chat
+
+import (
+	"path/filepath"
+
+	"github.com/ailabstw/go-pttai/node"
+	"github.com/ailabstw/go-pttai/pttdb"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+// config
+var (
+	DefaultConfig = Config{
+		DataDir: filepath.Join(node.DefaultDataDir(), "chat"),
+	}
+)
+
+// protocol
+const (
+	_ pkgservice.OpType = iota + pkgservice.NMsg
+	// chat-oplog
+	AddChatOplogMsg
+	AddChatOplogsMsg
+
+	AddPendingChatOplogMsg
+	AddPendingChatOplogsMsg
+
+	SyncChatOplogMsg
+	SyncChatOplogAckMsg
+	SyncChatOplogNewOplogsMsg
+	SyncChatOplogNewOplogsAckMsg
+
+	InvalidSyncChatOplogMsg
+
+	ForceSyncChatOplogMsg
+	ForceSyncChatOplogAckMsg
+	ForceSyncChatOplogByMerkleMsg
+	ForceSyncChatOplogByMerkleAckMsg
+	ForceSyncChatOplogByOplogAckMsg
+
+	SyncPendingChatOplogMsg
+	SyncPendingChatOplogAckMsg
+
+	// sync message
+	SyncCreateMessageMsg
+	SyncCreateMessageAckMsg
+	SyncCreateMessageBlockMsg
+	SyncCreateMessageBlockAckMsg
+)
+
+// db
+var (
+	dbChatCore pttdb.DB         = nil
+	dbChat     pttdb.IndexBatch = nil
+
+	DBChatIdxOplogPrefix    = []byte(".chig")
+	DBChatOplogPrefix       = []byte(".chlg")
+	DBChatMerkleOplogPrefix = []byte(".chmk")
+
+	DBChatPrefix                = []byte(".chdb")
+	DBChatIdxPrefix             = []byte(".chix")
+	DBChatLastSeenPrefix        = []byte(".chls")
+	DBChatMessageCreateTSPrefix = []byte(".chmc")
+	DBMessagePrefix             = []byte(".msdb")
+	DBMessageIdxPrefix          = []byte(".msix")
+)
+
+// max-masters
+const (
+	MaxMasters = 1
+)
+
+// sync
+const (
+	MaxSyncRandomSeconds = 30
+	MinSyncRandomSeconds = 15
+)
+
+// op-key
+var (
+	RenewOpKeySeconds  int64 = 86400
+	ExpireOpKeySeconds int64 = 259200
+)
+
+// message
+const (
+	NFirstLineInBlock = 1
+)
+
+func InitChat(dataDir string) error {
+	var err error
+
+	// db
+	dbChatCore, err = pttdb.NewDatabase("chat", dataDir, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	dbChat, err = pttdb.NewLDBBatch(dbChatCore)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func TeardownChat() {
+	if dbChat != nil {
+		dbChat = nil
+	}
+
+	if dbChatCore != nil {
+		dbChatCore.Close()
+		dbChatCore = nil
+	}
+}
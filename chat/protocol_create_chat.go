@@ -0,0 +1,82 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package chat
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+type CreateChat struct {
+	Title []byte `json:"T"`
+}
+
+/*
+CreateChat creates the group-chat with me as the master.
+*/
+func (spm *ServiceProtocolManager) CreateChat(title []byte) (*Chat, error) {
+
+	data := &CreateChat{
+		Title: title,
+	}
+
+	entity, err := spm.CreateEntity(data, ChatOpTypeCreateChat, spm.NewChat, spm.NewChatOplogWithTS, nil, spm.postcreateChat)
+	if err != nil {
+		return nil, err
+	}
+
+	chat, ok := entity.(*Chat)
+	if !ok {
+		return nil, pkgservice.ErrInvalidEntity
+	}
+
+	return chat, nil
+}
+
+func (spm *ServiceProtocolManager) NewChat(theData pkgservice.CreateData, ptt pkgservice.Ptt, service pkgservice.Service) (pkgservice.Entity, pkgservice.OpData, error) {
+
+	data, ok := theData.(*CreateChat)
+	if !ok {
+		return nil, nil, pkgservice.ErrInvalidData
+	}
+
+	myID := spm.Ptt().GetMyEntity().GetID()
+
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chat, err := NewChat(myID, ts, ptt, service, spm, spm.GetDBLock())
+	if err != nil {
+		return nil, nil, err
+	}
+	chat.EntityType = pkgservice.EntityTypePrivate
+	chat.Title = data.Title
+
+	return chat, &ChatOpCreateChat{Title: data.Title}, nil
+}
+
+func (spm *ServiceProtocolManager) postcreateChat(entity pkgservice.Entity) error {
+
+	err := spm.Ptt().GetMyEntity().CreateEntityOplog(entity)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
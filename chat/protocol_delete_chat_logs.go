@@ -0,0 +1,91 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package chat
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func (pm *ProtocolManager) handleDeleteChatLogs(oplog *pkgservice.BaseOplog, info *ProcessChatInfo) ([]*pkgservice.BaseOplog, error) {
+
+	opData := &ChatOpDeleteChat{}
+
+	log.Debug("handleDeleteChatLogs: start", "entity", pm.Entity().IDString())
+
+	return pm.HandleDeleteEntityLog(
+		oplog,
+		info,
+
+		opData,
+		types.StatusTerminal,
+
+		pm.chatOplogMerkle,
+
+		pm.SetChatDB,
+		nil,
+		pm.updateChatDeleteInfo,
+	)
+}
+
+func (pm *ProtocolManager) handlePendingDeleteChatLogs(oplog *pkgservice.BaseOplog, info *ProcessChatInfo) (types.Bool, []*pkgservice.BaseOplog, error) {
+
+	opData := &ChatOpDeleteChat{}
+
+	return pm.HandlePendingDeleteEntityLog(
+		oplog,
+		info,
+
+		types.StatusInternalTerminal,
+		types.StatusPendingTerminal,
+		ChatOpTypeDeleteChat,
+		opData,
+
+		pm.chatOplogMerkle,
+
+		pm.SetChatDB,
+		pm.setPendingDeleteChatSyncInfo,
+		pm.updateChatDeleteInfo,
+	)
+}
+
+func (pm *ProtocolManager) setNewestDeleteChatLog(oplog *pkgservice.BaseOplog) (types.Bool, error) {
+
+	return false, nil
+}
+
+func (pm *ProtocolManager) handleFailedDeleteChatLog(oplog *pkgservice.BaseOplog) error {
+
+	return pm.HandleFailedDeleteEntityLog(oplog)
+}
+
+/**********
+ * Customize
+ **********/
+
+func (pm *ProtocolManager) updateChatDeleteInfo(oplog *pkgservice.BaseOplog, theInfo pkgservice.ProcessInfo) error {
+
+	info, ok := theInfo.(*ProcessChatInfo)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	info.ChatInfo[*oplog.ObjID] = oplog
+
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package chat
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+type ProcessChatInfo struct {
+	CreateMessageInfo map[types.PttID]*pkgservice.BaseOplog
+	BlockInfo         map[types.PttID]*pkgservice.BaseOplog
+
+	ChatInfo map[types.PttID]*pkgservice.BaseOplog
+}
+
+func NewProcessChatInfo() *ProcessChatInfo {
+	return &ProcessChatInfo{
+		CreateMessageInfo: make(map[types.PttID]*pkgservice.BaseOplog),
+		BlockInfo:         make(map[types.PttID]*pkgservice.BaseOplog),
+
+		ChatInfo: make(map[types.PttID]*pkgservice.BaseOplog),
+	}
+}
+
+/**********
+ * Process Oplog
+ **********/
+
+func (pm *ProtocolManager) processChatLog(oplog *pkgservice.BaseOplog, processInfo pkgservice.ProcessInfo) (origLogs []*pkgservice.BaseOplog, err error) {
+	info, ok := processInfo.(*ProcessChatInfo)
+	if !ok {
+		return nil, pkgservice.ErrInvalidData
+	}
+
+	switch oplog.Op {
+	case ChatOpTypeDeleteChat:
+		origLogs, err = pm.handleDeleteChatLogs(oplog, info)
+
+	case ChatOpTypeCreateMessage:
+		origLogs, err = pm.handleCreateMessageLogs(oplog, info)
+	}
+	return
+}
+
+/**********
+ * Process Pending Oplog
+ **********/
+
+func (pm *ProtocolManager) processPendingChatLog(oplog *pkgservice.BaseOplog, processInfo pkgservice.ProcessInfo) (isToSign types.Bool, origLogs []*pkgservice.BaseOplog, err error) {
+	info, ok := processInfo.(*ProcessChatInfo)
+	if !ok {
+		return false, nil, pkgservice.ErrInvalidData
+	}
+
+	switch oplog.Op {
+	case ChatOpTypeDeleteChat:
+		isToSign, origLogs, err = pm.handlePendingDeleteChatLogs(oplog, info)
+
+	case ChatOpTypeCreateMessage:
+		isToSign, origLogs, err = pm.handlePendingCreateMessageLogs(oplog, info)
+	}
+
+	return
+}
+
+/**********
+ * Postprocess Oplog
+ **********/
+
+func (pm *ProtocolManager) postprocessChatOplogs(processInfo pkgservice.ProcessInfo, toBroadcastLogs []*pkgservice.BaseOplog, peer *pkgservice.PttPeer, isPending bool) (err error) {
+	info, ok := processInfo.(*ProcessChatInfo)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	// message
+	createMessageIDs := pkgservice.ProcessInfoToSyncIDList(info.CreateMessageInfo, ChatOpTypeCreateMessage)
+	createMessageBlockIDs := pkgservice.ProcessInfoToSyncBlockIDList(info.BlockInfo, ChatOpTypeCreateMessage)
+	pm.SyncMessage(SyncCreateMessageMsg, createMessageIDs, peer)
+	pm.SyncBlock(SyncCreateMessageBlockMsg, createMessageBlockIDs, peer)
+
+	// broadcast
+	pm.PostOplogEvents(pkgservice.OplogEventTypeLog0, toBroadcastLogs)
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	if isPending || pm.IsMaster(myID, false) {
+		pm.broadcastChatOplogsCore(toBroadcastLogs)
+	}
+
+	// post-delete-chat
+	if !isPending && len(info.ChatInfo) > 0 {
+		pm.postdeleteChat(nil, false)
+	}
+
+	return
+}
+
+/**********
+ * Set Newest Oplog
+ **********/
+
+func (pm *ProtocolManager) SetNewestChatOplog(oplog *pkgservice.BaseOplog) (err error) {
+	var isNewer types.Bool
+
+	switch oplog.Op {
+	case ChatOpTypeDeleteChat:
+		isNewer, err = pm.setNewestDeleteChatLog(oplog)
+
+	case ChatOpTypeCreateMessage:
+		isNewer, err = pm.setNewestCreateMessageLog(oplog)
+	}
+
+	oplog.IsNewer = isNewer
+
+	return
+}
+
+/**********
+ * Handle Failed Oplog
+ **********/
+
+func (pm *ProtocolManager) HandleFailedChatOplog(oplog *pkgservice.BaseOplog) (err error) {
+
+	switch oplog.Op {
+	case ChatOpTypeDeleteChat:
+		err = pm.handleFailedDeleteChatLog(oplog)
+
+	case ChatOpTypeCreateMessage:
+		err = pm.handleFailedCreateMessageLog(oplog)
+	}
+
+	return
+}
+
+/**********
+ * Handle Failed Valid Oplog
+ **********/
+
+func (pm *ProtocolManager) HandleFailedValidChatOplog(oplog *pkgservice.BaseOplog, processInfo pkgservice.ProcessInfo) (err error) {
+
+	info, ok := processInfo.(*ProcessChatInfo)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	switch oplog.Op {
+	case ChatOpTypeDeleteChat:
+
+	case ChatOpTypeCreateMessage:
+		err = pm.handleFailedValidCreateMessageLog(oplog, info)
+	}
+
+	return
+}
+
+func (pm *ProtocolManager) postprocessFailedValidChatOplogs(processInfo pkgservice.ProcessInfo, peer *pkgservice.PttPeer) error {
+
+	return nil
+}
+
+/**********
+ * Postsync Oplog
+ **********/
+
+func (pm *ProtocolManager) postsyncChatOplogs(peer *pkgservice.PttPeer) (err error) {
+	err = pm.SyncPendingChatOplog(peer)
+
+	return
+}
@@ -0,0 +1,243 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package chat
+
+import pkgservice "github.com/ailabstw/go-pttai/service"
+
+/**********
+ * AddChatOplog
+ **********/
+
+func (pm *ProtocolManager) HandleAddChatOplog(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleAddOplog(dataBytes, pm.HandleChatOplogs, peer)
+}
+
+func (pm *ProtocolManager) HandleAddChatOplogs(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleAddOplogs(dataBytes, pm.HandleChatOplogs, peer)
+}
+
+func (pm *ProtocolManager) HandleAddPendingChatOplog(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleAddPendingOplog(dataBytes, pm.HandlePendingChatOplogs, peer)
+}
+
+func (pm *ProtocolManager) HandleAddPendingChatOplogs(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleAddPendingOplogs(dataBytes, pm.HandlePendingChatOplogs, peer)
+}
+
+/**********
+ * SyncChatOplog
+ **********/
+
+func (pm *ProtocolManager) HandleSyncChatOplog(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleSyncOplog(
+		dataBytes,
+		peer,
+
+		pm.chatOplogMerkle,
+
+		ForceSyncChatOplogByMerkleMsg,
+		ForceSyncChatOplogByMerkleAckMsg,
+		InvalidSyncChatOplogMsg,
+		SyncChatOplogAckMsg,
+	)
+}
+
+func (pm *ProtocolManager) HandleForceSyncChatOplogByMerkle(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleForceSyncOplogByMerkle(
+		dataBytes,
+		peer,
+
+		ForceSyncChatOplogByMerkleAckMsg,
+		ForceSyncChatOplogByOplogAckMsg,
+
+		pm.SetChatDB,
+		pm.SetNewestChatOplog,
+
+		pm.chatOplogMerkle,
+	)
+}
+
+func (pm *ProtocolManager) HandleForceSyncChatOplogByMerkleAck(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleForceSyncOplogByMerkleAck(
+		dataBytes,
+		peer,
+
+		ForceSyncChatOplogByMerkleMsg,
+
+		pm.chatOplogMerkle,
+	)
+}
+
+func (pm *ProtocolManager) HandleForceSyncChatOplogByOplogAck(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleForceSyncOplogByOplogAck(
+		dataBytes,
+		peer,
+
+		pm.HandleChatOplogs,
+
+		pm.chatOplogMerkle,
+	)
+}
+
+func (pm *ProtocolManager) HandleForceSyncChatOplog(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleForceSyncOplog(
+		dataBytes,
+		peer,
+
+		pm.chatOplogMerkle,
+		ForceSyncChatOplogAckMsg,
+	)
+}
+
+func (pm *ProtocolManager) HandleForceSyncChatOplogAck(dataBytes []byte, peer *pkgservice.PttPeer) error {
+
+	info := NewProcessChatInfo()
+
+	return pm.HandleForceSyncOplogAck(
+		dataBytes,
+		peer,
+
+		pm.chatOplogMerkle,
+		info,
+
+		pm.SetChatDB,
+		pm.HandleFailedValidChatOplog,
+		pm.SetNewestChatOplog,
+		pm.postprocessFailedValidChatOplogs,
+
+		SyncChatOplogNewOplogsMsg,
+	)
+}
+
+func (pm *ProtocolManager) HandleSyncChatOplogInvalid(dataBytes []byte, peer *pkgservice.PttPeer) error {
+
+	return pm.HandleSyncOplogInvalid(
+		dataBytes,
+		peer,
+
+		pm.chatOplogMerkle,
+		ForceSyncChatOplogMsg,
+	)
+}
+
+func (pm *ProtocolManager) HandleSyncChatOplogAck(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleSyncOplogAck(
+		dataBytes,
+		peer,
+
+		pm.chatOplogMerkle,
+
+		pm.SetChatDB,
+		pm.SetNewestChatOplog,
+		pm.postsyncChatOplogs,
+
+		SyncChatOplogNewOplogsMsg,
+	)
+}
+
+func (pm *ProtocolManager) HandleSyncNewChatOplog(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleSyncOplogNewOplogs(
+		dataBytes,
+		peer,
+
+		pm.SetChatDB,
+		pm.HandleChatOplogs,
+		pm.SetNewestChatOplog,
+
+		SyncChatOplogNewOplogsAckMsg,
+	)
+}
+
+func (pm *ProtocolManager) HandleSyncNewChatOplogAck(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleSyncOplogNewOplogsAck(
+		dataBytes,
+		peer,
+
+		pm.SetChatDB,
+		pm.HandleChatOplogs,
+		pm.postsyncChatOplogs,
+	)
+}
+
+/**********
+ * SyncPendingChatOplog
+ **********/
+
+func (pm *ProtocolManager) HandleSyncPendingChatOplog(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleSyncPendingOplog(
+		dataBytes,
+		peer,
+
+		pm.HandlePendingChatOplogs,
+		pm.SetChatDB,
+		pm.HandleFailedChatOplog,
+
+		SyncPendingChatOplogAckMsg,
+	)
+}
+
+func (pm *ProtocolManager) HandleSyncPendingChatOplogAck(dataBytes []byte, peer *pkgservice.PttPeer) error {
+	return pm.HandleSyncPendingOplogAck(
+		dataBytes,
+		peer,
+
+		pm.HandlePendingChatOplogs,
+	)
+}
+
+/**********
+ * HandleOplogs
+ **********/
+
+func (pm *ProtocolManager) HandleChatOplogs(oplogs []*pkgservice.BaseOplog, peer *pkgservice.PttPeer, isUpdateSyncTime bool) error {
+
+	info := NewProcessChatInfo()
+
+	return pkgservice.HandleOplogs(
+		oplogs,
+		peer,
+
+		isUpdateSyncTime,
+		pm,
+		info,
+		pm.chatOplogMerkle,
+
+		pm.SetChatDB,
+		pm.processChatLog,
+		pm.postprocessChatOplogs,
+	)
+}
+
+func (pm *ProtocolManager) HandlePendingChatOplogs(oplogs []*pkgservice.BaseOplog, peer *pkgservice.PttPeer) error {
+
+	info := NewProcessChatInfo()
+
+	return pkgservice.HandlePendingOplogs(
+		oplogs,
+		peer,
+
+		pm,
+		info,
+
+		pm.chatOplogMerkle,
+
+		pm.SetChatDB,
+		pm.processPendingChatLog,
+		pm.processChatLog,
+		pm.postprocessChatOplogs,
+	)
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package chat
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+type ProtocolManager struct {
+	*pkgservice.BaseProtocolManager
+
+	// db
+	dbChatLock      *types.LockMap
+	chatOplogMerkle *pkgservice.Merkle
+
+	// message
+	dbMessagePrefix    []byte
+	dbMessageIdxPrefix []byte
+}
+
+func newBaseProtocolManager(pm *ProtocolManager, ptt pkgservice.Ptt, entity pkgservice.Entity, svc pkgservice.Service) *pkgservice.BaseProtocolManager {
+
+	b, err := pkgservice.NewBaseProtocolManager(
+		ptt,
+
+		RenewOpKeySeconds,
+		ExpireOpKeySeconds,
+		MaxSyncRandomSeconds,
+		MinSyncRandomSeconds,
+
+		MaxMasters,
+
+		pm.chatOplogMerkle, // log0Merkle
+
+		// sign
+		nil,
+		nil,
+		nil,
+		nil,
+
+		pm.SetChatDB,        // setLog0DB
+		pm.HandleChatOplogs, // handleLog0s
+
+		nil, // isMaster
+		nil, // isMember
+
+		// peer-type
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+
+		pm.SyncChatOplog, // postsyncMemberOplog
+
+		pm.DeleteChat,     // theDelete
+		pm.postdeleteChat, // postdelete
+
+		entity, // entity
+		svc,
+
+		dbChat, //db
+	)
+	if err != nil {
+		return nil
+	}
+
+	return b
+}
+
+func NewProtocolManager(c *Chat, ptt pkgservice.Ptt, svc pkgservice.Service) (*ProtocolManager, error) {
+	dbChatLock, err := types.NewLockMap(pkgservice.SleepTimeLock)
+	if err != nil {
+		return nil, err
+	}
+
+	entityID := c.ID
+	entityIDBytes, _ := entityID.MarshalText()
+	entityIDStr := string(entityIDBytes)
+
+	chatOplogMerkle, err := pkgservice.NewMerkle(DBChatOplogPrefix, DBChatMerkleOplogPrefix, c.ID, dbChat, "("+entityIDStr+"/"+svc.Name()+":chat)")
+	if err != nil {
+		return nil, err
+	}
+	pm := &ProtocolManager{
+		dbChatLock:      dbChatLock,
+		chatOplogMerkle: chatOplogMerkle,
+	}
+	pm.BaseProtocolManager = newBaseProtocolManager(pm, ptt, c, svc)
+
+	// message
+	pm.dbMessagePrefix = append(DBMessagePrefix, entityID[:]...)
+	pm.dbMessageIdxPrefix = append(DBMessageIdxPrefix, entityID[:]...)
+
+	return pm, nil
+}
+
+func (pm *ProtocolManager) Start() error {
+	err := pm.BaseProtocolManager.Start()
+	if err == pkgservice.ErrAlreadyStarted {
+		log.Warn("Start: already started", "entity", pm.Entity().IDString())
+		return nil
+	}
+	if err != nil {
+		log.Error("Start: unable to start BaseProtocolManager", "e", err, "entity", pm.Entity().IDString())
+		return err
+	}
+
+	// sync-wg
+	syncWG := pm.SyncWG()
+
+	syncWG.Add(1)
+	go func() {
+		defer syncWG.Done()
+		pm.CreateJoinKeyLoop()
+	}()
+
+	// oplog-merkle-tree
+	syncWG.Add(1)
+	go func() {
+		defer syncWG.Done()
+		pkgservice.PMOplogMerkleTreeLoop(pm, pm.chatOplogMerkle)
+	}()
+
+	return nil
+}
+
+func (pm *ProtocolManager) Stop() error {
+	return nil
+}
+
+func (pm *ProtocolManager) Sync(peer *pkgservice.PttPeer) error {
+	if peer == nil {
+		pm.SyncPendingMasterOplog(peer)
+		pm.SyncPendingMemberOplog(peer)
+		pm.SyncPendingChatOplog(peer)
+		return nil
+	}
+
+	return pm.SyncOplog(peer, pm.MasterMerkle(), pkgservice.SyncMasterOplogMsg)
+}
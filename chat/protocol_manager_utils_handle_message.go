@@ -0,0 +1,81 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package chat
+
+import (
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func (pm *ProtocolManager) HandleMessage(op pkgservice.OpType, dataBytes []byte, peer *pkgservice.PttPeer) error {
+
+	var err error
+
+	switch op {
+	// chat oplog
+	case SyncChatOplogMsg:
+		err = pm.HandleSyncChatOplog(dataBytes, peer)
+
+	case ForceSyncChatOplogByMerkleMsg:
+		return pm.HandleForceSyncChatOplogByMerkle(dataBytes, peer)
+	case ForceSyncChatOplogByMerkleAckMsg:
+		return pm.HandleForceSyncChatOplogByMerkleAck(dataBytes, peer)
+	case ForceSyncChatOplogByOplogAckMsg:
+		return pm.HandleForceSyncChatOplogByOplogAck(dataBytes, peer)
+	case InvalidSyncChatOplogMsg:
+		err = pm.HandleSyncChatOplogInvalid(dataBytes, peer)
+
+	case ForceSyncChatOplogMsg:
+		err = pm.HandleForceSyncChatOplog(dataBytes, peer)
+	case ForceSyncChatOplogAckMsg:
+		err = pm.HandleForceSyncChatOplogAck(dataBytes, peer)
+
+	case SyncChatOplogAckMsg:
+		err = pm.HandleSyncChatOplogAck(dataBytes, peer)
+	case SyncChatOplogNewOplogsMsg:
+		err = pm.HandleSyncNewChatOplog(dataBytes, peer)
+	case SyncChatOplogNewOplogsAckMsg:
+		err = pm.HandleSyncNewChatOplogAck(dataBytes, peer)
+	case SyncPendingChatOplogMsg:
+		err = pm.HandleSyncPendingChatOplog(dataBytes, peer)
+	case SyncPendingChatOplogAckMsg:
+		err = pm.HandleSyncPendingChatOplogAck(dataBytes, peer)
+
+	case AddChatOplogMsg:
+		err = pm.HandleAddChatOplog(dataBytes, peer)
+	case AddChatOplogsMsg:
+		err = pm.HandleAddChatOplogs(dataBytes, peer)
+	case AddPendingChatOplogMsg:
+		err = pm.HandleAddPendingChatOplog(dataBytes, peer)
+	case AddPendingChatOplogsMsg:
+		err = pm.HandleAddPendingChatOplogs(dataBytes, peer)
+
+	// message
+	case SyncCreateMessageMsg:
+		err = pm.HandleSyncCreateMessage(dataBytes, peer, SyncCreateMessageAckMsg)
+	case SyncCreateMessageAckMsg:
+		err = pm.HandleSyncCreateMessageAck(dataBytes, peer)
+	case SyncCreateMessageBlockMsg:
+		err = pm.HandleSyncMessageBlock(dataBytes, peer)
+	case SyncCreateMessageBlockAckMsg:
+		err = pm.HandleSyncCreateMessageBlockAck(dataBytes, peer)
+
+	default:
+		err = pkgservice.ErrInvalidMsgCode
+	}
+
+	return err
+}
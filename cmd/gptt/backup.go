@@ -0,0 +1,226 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of go-pttai.
+//
+// go-pttai is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-pttai is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-pttai. If not, see <http://www.gnu.org/licenses/>.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/ailabstw/go-pttai/backup"
+	"github.com/ailabstw/go-pttai/cmd/utils"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/rpc"
+	"golang.org/x/crypto/ssh/terminal"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// backupNode is the backup command.
+func backupNode(ctx *cli.Context) error {
+	filename := ctx.Args().First()
+	if filename == "" {
+		return ErrInvalidBackupFilename
+	}
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	utils.SetNodeConfig(ctx, cfg.Node)
+
+	passphrase, err := getPassphrase(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	// running node: the dbs are locked by the node, export through the node.
+	if endpoint := cfg.Node.IPCEndpoint(); endpoint != "" {
+		client, err := rpc.Dial(endpoint)
+		if err == nil {
+			defer client.Close()
+			return backupNodeByRPC(client, filename, passphrase)
+		}
+		log.Debug("backupNode: node not running", "endpoint", endpoint, "e", err)
+	}
+
+	// the encrypted dbs are opened with the keystore passphrase.
+	err = setDBPassphrase(ctx, cfg.Node)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = backup.Export(f, cfg.Node.DataDir, passphrase)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filename)
+		return err
+	}
+
+	fmt.Printf("Backup: %v\n", filename)
+
+	return nil
+}
+
+func backupNodeByRPC(client *rpc.Client, filename string, passphrase []byte) error {
+	exportedFilename := ""
+	err := client.Call(&exportedFilename, "me_exportBackup", string(passphrase))
+	if err != nil {
+		return err
+	}
+
+	err = moveFile(exportedFilename, filename)
+	if err != nil {
+		return fmt.Errorf("unable to move backup from %v: %v", exportedFilename, err)
+	}
+
+	fmt.Printf("Backup: %v\n", filename)
+
+	return nil
+}
+
+// restoreNode is the restore command.
+func restoreNode(ctx *cli.Context) error {
+	filename := ctx.Args().First()
+	if filename == "" {
+		return ErrInvalidBackupFilename
+	}
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	utils.SetNodeConfig(ctx, cfg.Node)
+
+	// the dbs are restored with the db-engine.
+	utils.SetPttConfig(ctx, cfg.Ptt, cfg.Node, gitCommit, theVersion)
+
+	passphrase, err := getPassphrase(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	// the dbs are restored encrypted with the keystore passphrase.
+	err = setDBPassphrase(ctx, cfg.Node)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = backup.Import(f, cfg.Node.DataDir, passphrase)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored to: %v\n", cfg.Node.DataDir)
+
+	return nil
+}
+
+/*
+getPassphrase gets the passphrase from the flag, the passphrase-file, or the prompt.
+*/
+func getPassphrase(ctx *cli.Context, isConfirm bool) ([]byte, error) {
+	if passphrase := ctx.String(passphraseFlag.Name); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	if file := ctx.String(passphraseFileFlag.Name); file != "" {
+		passphrase, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		passphrase = bytes.TrimRight(passphrase, "\r\n")
+		if len(passphrase) == 0 {
+			return nil, ErrInvalidPassphrase
+		}
+		return passphrase, nil
+	}
+
+	passphrase, err := promptPassphrase("Passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, ErrInvalidPassphrase
+	}
+
+	if !isConfirm {
+		return passphrase, nil
+	}
+
+	confirm, err := promptPassphrase("Repeat passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(passphrase, confirm) {
+		return nil, ErrPassphraseMismatch
+	}
+
+	return passphrase, nil
+}
+
+func promptPassphrase(prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+
+	return terminal.ReadPassword(int(os.Stdin.Fd()))
+}
+
+func moveFile(src string, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+
+	// possibly in different file-systems
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dstFile, srcFile)
+	closeErr := dstFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	return os.Remove(src)
+}
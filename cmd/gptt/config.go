@@ -23,6 +23,7 @@ import (
 	"os"
 
 	"github.com/ailabstw/go-pttai/account"
+	"github.com/ailabstw/go-pttai/chat"
 	"github.com/ailabstw/go-pttai/cmd/utils"
 	"github.com/ailabstw/go-pttai/content"
 	"github.com/ailabstw/go-pttai/friend"
@@ -39,6 +40,7 @@ type Config struct {
 	Content *content.Config
 	Account *account.Config
 	Friend  *friend.Config
+	Chat    *chat.Config
 	Ptt     *pkgservice.Config
 	Utils   *utils.Config
 }
@@ -50,6 +52,7 @@ func NewConfig(ctx *cli.Context) (*Config, error) {
 		Content: &content.DefaultConfig,
 		Account: &account.DefaultConfig,
 		Friend:  &friend.DefaultConfig,
+		Chat:    &chat.DefaultConfig,
 		Ptt:     &pkgservice.DefaultConfig,
 		Utils:   &utils.DefaultConfig,
 	}, nil
@@ -0,0 +1,110 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ailabstw/go-pttai/cmd/utils"
+	"github.com/ailabstw/go-pttai/node"
+	"github.com/ailabstw/go-pttai/pttdb"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// encryptDB is the db encrypt command.
+func encryptDB(ctx *cli.Context) error {
+	return migrateDBs(ctx, true)
+}
+
+// decryptDB is the db decrypt command.
+func decryptDB(ctx *cli.Context) error {
+	return migrateDBs(ctx, false)
+}
+
+/*
+migrateDBs encrypts / decrypts all the leveldb databases in the data-dir with the keystore passphrase.
+The node is required to be stopped (the databases are locked by the running node).
+*/
+func migrateDBs(ctx *cli.Context, isEncrypt bool) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	utils.SetNodeConfig(ctx, cfg.Node)
+
+	passphrase, err := getKeystorePassphrase(ctx, cfg.Node)
+	if err != nil {
+		return err
+	}
+	if len(passphrase) == 0 {
+		return ErrKeystoreNotEncrypted
+	}
+	pttdb.SetPassphrase(passphrase)
+
+	paths, err := collectDBs(cfg.Node.DataDir)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if isEncrypt {
+			err = pttdb.EncryptDatabase(path)
+		} else {
+			err = pttdb.DecryptDatabase(path)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to migrate %v: %v", path, err)
+		}
+		fmt.Printf("Migrated: %v\n", path)
+	}
+
+	return nil
+}
+
+/*
+collectDBs collects the leveldb databases opened through pttdb in the data-dir.
+*/
+func collectDBs(dataDir string) ([]string, error) {
+	paths := make([]string, 0)
+	err := filepath.Walk(dataDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		engine, err := pttdb.GetEngineType(path)
+		if err != nil {
+			return nil
+		}
+
+		// the node-db is opened by p2p directly.
+		if engine != pttdb.EngineLevelDB || filepath.Base(path) == node.DataDirNodeDatabase {
+			return filepath.SkipDir
+		}
+
+		paths = append(paths, path)
+
+		return filepath.SkipDir
+	})
+
+	return paths, err
+}
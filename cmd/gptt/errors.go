@@ -16,4 +16,13 @@
 
 package main
 
-var ()
+import "errors"
+
+var (
+	ErrInvalidBackupFilename = errors.New("invalid backup filename")
+	ErrInvalidPassphrase     = errors.New("invalid passphrase")
+	ErrPassphraseMismatch    = errors.New("passphrases do not match")
+	ErrKeystoreNotEncrypted  = errors.New("keystore not encrypted (set --keystore.password-file)")
+	ErrInvalidTokenName      = errors.New("invalid token name")
+	ErrInvalidTokenID        = errors.New("invalid token id")
+)
@@ -22,6 +22,7 @@ import (
 	"unicode"
 
 	"github.com/ailabstw/go-pttai/account"
+	"github.com/ailabstw/go-pttai/chat"
 	"github.com/ailabstw/go-pttai/cmd/utils"
 	"github.com/ailabstw/go-pttai/content"
 	"github.com/ailabstw/go-pttai/friend"
@@ -42,6 +43,7 @@ var (
 		Content: &content.DefaultConfig,
 		Account: &account.DefaultConfig,
 		Friend:  &friend.DefaultConfig,
+		Chat:    &chat.DefaultConfig,
 		Ptt:     &pkgservice.DefaultConfig,
 		Utils:   &utils.DefaultConfig,
 	}
@@ -54,11 +56,53 @@ var (
 		Usage: "TOML configuration file",
 	}
 
+	passphraseFlag = cli.StringFlag{
+		Name:  "passphrase",
+		Usage: "Passphrase of the backup (prompted if not set)",
+	}
+
+	passphraseFileFlag = cli.StringFlag{
+		Name:  "passphrasefile",
+		Usage: "File containing the passphrase of the backup",
+	}
+
+	// flags that configure backup / restore
+	backupFlags = []cli.Flag{
+		configFileFlag,
+		utils.DataDirFlag,
+		utils.IPCPathFlag,
+		utils.DBEngineFlag,
+		passphraseFlag,
+		passphraseFileFlag,
+		utils.KeystorePasswordFileFlag,
+	}
+
+	tokenScopeFlag = cli.StringFlag{
+		Name:  "scope",
+		Usage: "Comma separated scopes of the token ({module}, {module}:read, or *)",
+		Value: "content:read",
+	}
+
+	// flags that configure token create / list / revoke
+	tokenFlags = []cli.Flag{
+		configFileFlag,
+		utils.DataDirFlag,
+		utils.IPCPathFlag,
+	}
+
+	// flags that configure db encrypt / decrypt
+	dbFlags = []cli.Flag{
+		configFileFlag,
+		utils.DataDirFlag,
+		utils.KeystorePasswordFileFlag,
+	}
+
 	// flags that configure me
 	meFlags = []cli.Flag{
 		utils.MyDataDirFlag,
 		utils.MyKeyFileFlag,
 		utils.MyKeyHexFlag,
+		utils.KeystorePasswordFileFlag,
 		utils.ServerFlag,
 	}
 
@@ -77,6 +121,7 @@ var (
 	// flags that configure content
 	serviceFlags = []cli.Flag{
 		utils.ServiceExpireOplogSecondsFlag,
+		utils.ServiceSignKeyTypeFlag,
 	}
 
 	// flags that configure http-server
@@ -117,8 +162,11 @@ var (
 		utils.MetricsInfluxDBUsernameFlag,
 		utils.MetricsInfluxDBPasswordFlag,
 		utils.MetricsInfluxDBHostTagFlag,
+		utils.MetricsPrometheusAddrFlag,
 
 		utils.LogFilenameFlag,
+
+		utils.DBEngineFlag,
 	}
 
 	// flags that configure p2p-network
@@ -140,6 +188,7 @@ var (
 		utils.NetrestrictFlag,
 
 		utils.P2PListenPortFlag,
+		utils.P2PKeyTypeFlag,
 
 		utils.WebrtcSignalServerFlag,
 	}
@@ -151,6 +200,7 @@ var (
 		utils.RPCPortFlag,
 		utils.RPCCORSDomainFlag,
 		utils.RPCVirtualHostsFlag,
+		utils.RPCAuthFlag,
 		utils.ExternRPCAddrFlag,
 
 		utils.RPCApiFlag,
@@ -187,6 +237,105 @@ The output of this command is supposed to be machine-readable.
 		Category:  "MISCELLANEOUS COMMANDS",
 	}
 
+	backupCommand = cli.Command{
+		Action:    utils.MigrateFlags(backupNode),
+		Name:      "backup",
+		Usage:     "Export the passphrase-encrypted backup of the node",
+		ArgsUsage: "<filename>",
+		Flags:     backupFlags,
+		Category:  "BACKUP COMMANDS",
+		Description: `
+The backup command exports the identity (keys) and all the data (me, account, content, friend, ptt) in the data-dir
+as a single passphrase-encrypted file.
+
+If the node is running, the backup is exported by the node (me_exportBackup through IPC)
+with the consistent snapshots of the databases.
+`,
+	}
+
+	restoreCommand = cli.Command{
+		Action:    utils.MigrateFlags(restoreNode),
+		Name:      "restore",
+		Usage:     "Restore the node from the backup",
+		ArgsUsage: "<filename>",
+		Flags:     backupFlags,
+		Category:  "BACKUP COMMANDS",
+		Description: `
+The restore command restores the backup to the data-dir, which is required to be empty.
+
+The boards and the friends are re-registered to ptt (and rejoined with the peers) when gptt starts with the data-dir.
+`,
+	}
+
+	dbCommand = cli.Command{
+		Name:     "db",
+		Usage:    "Manage the databases",
+		Category: "DATABASE COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Action: utils.MigrateFlags(encryptDB),
+				Name:   "encrypt",
+				Usage:  "Encrypt the leveldb databases with the keystore passphrase",
+				Flags:  dbFlags,
+				Description: `
+The encrypt command encrypts the values of the existing plaintext leveldb databases in the data-dir (offline).
+Each database is encrypted with its own data-key, wrapped by the keystore passphrase.
+
+The databases created by gptt with the keystore passphrase are encrypted already.
+`,
+			},
+			{
+				Action: utils.MigrateFlags(decryptDB),
+				Name:   "decrypt",
+				Usage:  "Decrypt the leveldb databases",
+				Flags:  dbFlags,
+				Description: `
+The decrypt command decrypts the encrypted leveldb databases in the data-dir back to plaintext (offline).
+`,
+			},
+		},
+	}
+
+	tokenCommand = cli.Command{
+		Name:     "token",
+		Usage:    "Manage the auth-tokens of the HTTP / websocket RPC and the http-server",
+		Category: "TOKEN COMMANDS",
+		Description: `
+The auth-tokens are required when gptt starts with --rpcauth,
+as "Authorization: Bearer {token}" or the access_token query.
+
+Scopes:
+    {module}: all the methods in the module (ex: me).
+    {module}:read: the read-only methods in the module (ex: content:read).
+    *: all the modules.
+
+If the node is running, the tokens are managed by the node (through IPC).
+`,
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(createToken),
+				Name:      "create",
+				Usage:     "Create a new token",
+				ArgsUsage: "<name>",
+				Flags:     append(tokenFlags, tokenScopeFlag),
+			},
+			{
+				Action:    utils.MigrateFlags(listTokens),
+				Name:      "list",
+				Usage:     "List the tokens",
+				ArgsUsage: " ",
+				Flags:     tokenFlags,
+			},
+			{
+				Action:    utils.MigrateFlags(revokeToken),
+				Name:      "revoke",
+				Usage:     "Revoke the token",
+				ArgsUsage: "<id>",
+				Flags:     tokenFlags,
+			},
+		},
+	}
+
 	dumpConfigCommand = cli.Command{
 		Action:      utils.MigrateFlags(dumpConfig),
 		Name:        "dumpconfig",
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/ailabstw/go-pttai/account"
+	"github.com/ailabstw/go-pttai/chat"
 	"github.com/ailabstw/go-pttai/cmd/utils"
 	"github.com/ailabstw/go-pttai/content"
 	"github.com/ailabstw/go-pttai/friend"
@@ -53,6 +54,12 @@ func gptt(ctx *cli.Context) error {
 	// we need NodeConfig be the 1st. The DataDir in other configs are referring to the DataDir in NodeConfig.
 	utils.SetNodeConfig(ctx, cfg.Node)
 
+	// keystore needs to be unlocked before loading the keys.
+	err = setKeystore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
 	utils.SetMeConfig(ctx, cfg.Me, cfg.Node)
 
 	utils.SetAccountConfig(ctx, cfg.Account, cfg.Node)
@@ -61,6 +68,8 @@ func gptt(ctx *cli.Context) error {
 
 	utils.SetFriendConfig(ctx, cfg.Friend, cfg.Node)
 
+	utils.SetChatConfig(ctx, cfg.Chat, cfg.Node)
+
 	utils.SetPttConfig(ctx, cfg.Ptt, cfg.Node, gitCommit, theVersion)
 
 	// Setup metrics
@@ -156,8 +165,18 @@ func registerServices(ctx *pkgservice.ServiceContext, cfg *Config) (pkgservice.P
 		return nil, err
 	}
 
+	// chat
+	chatBackend, err := chat.NewBackend(ctx, cfg.Chat, cfg.Me.ID, ptt, accountBackend)
+	if err != nil {
+		return nil, err
+	}
+	err = ptt.RegisterService(chatBackend)
+	if err != nil {
+		return nil, err
+	}
+
 	// me
-	meBackend, err := me.NewBackend(ctx, cfg.Me, ptt, accountBackend, contentBackend, friendBackend)
+	meBackend, err := me.NewBackend(ctx, cfg.Me, ptt, accountBackend, contentBackend, friendBackend, chatBackend)
 	if err != nil {
 		return nil, err
 	}
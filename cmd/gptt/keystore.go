@@ -0,0 +1,129 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/ailabstw/go-pttai/cmd/utils"
+	"github.com/ailabstw/go-pttai/key"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/me"
+	"github.com/ailabstw/go-pttai/node"
+	"github.com/ailabstw/go-pttai/pttdb"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+/*
+setKeystore unlocks the keystore of the node key and me key, and sets it to the configs.
+We need to set the keystore before SetMeConfig, which loads the me key.
+
+ 1. with the password-file: the key-files are encrypted / migrated with the passphrase.
+ 2. with the encrypted key-files: prompt the passphrase to unlock.
+ 3. otherwise: the key-files are kept in plaintext.
+*/
+func setKeystore(ctx *cli.Context, cfg *Config) error {
+	passphrase, err := getKeystorePassphrase(ctx, cfg.Node)
+	if err != nil {
+		return err
+	}
+
+	ks := key.NewKeystore(passphrase)
+	cfg.Node.Keystore = ks
+	cfg.Me.Keystore = ks
+
+	// the data-keys of the encrypted databases are wrapped by the same passphrase.
+	pttdb.SetPassphrase(passphrase)
+
+	log.Info("setKeystore: done", "isEncrypted", ks.IsEncrypted())
+
+	return nil
+}
+
+func setDBPassphrase(ctx *cli.Context, cfgNode *node.Config) error {
+	passphrase, err := getKeystorePassphrase(ctx, cfgNode)
+	if err != nil {
+		return err
+	}
+
+	pttdb.SetPassphrase(passphrase)
+
+	return nil
+}
+
+func getKeystorePassphrase(ctx *cli.Context, cfgNode *node.Config) ([]byte, error) {
+	encrypted := encryptedKeyFile(cfgNode)
+
+	var passphrase []byte
+	var err error
+	switch file := ctx.GlobalString(utils.KeystorePasswordFileFlag.Name); {
+	case file != "":
+		passphrase, err = ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		passphrase = bytes.TrimRight(passphrase, "\r\n")
+	case encrypted != nil:
+		passphrase, err = promptPassphrase("Keystore passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	if len(passphrase) == 0 {
+		return nil, ErrInvalidPassphrase
+	}
+
+	if encrypted != nil {
+		_, err = key.DecryptKey(encrypted, passphrase)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return passphrase, nil
+}
+
+/*
+encryptedKeyFile returns the content of the first encrypted key-file of the node / me.
+*/
+func encryptedKeyFile(cfgNode *node.Config) []byte {
+	if cfgNode.DataDir == "" {
+		return nil
+	}
+
+	keyfiles := []string{
+		cfgNode.ResolvePath(node.DataDirPrivateKey),
+		filepath.Join(cfgNode.DataDir, "me", me.DataDirPrivateKey),
+	}
+
+	for _, keyfile := range keyfiles {
+		theBytes, err := ioutil.ReadFile(keyfile)
+		if err != nil {
+			continue
+		}
+		if key.IsKeystoreJSON(theBytes) {
+			return theBytes
+		}
+	}
+
+	return nil
+}
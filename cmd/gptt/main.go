@@ -74,6 +74,10 @@ func InitApp() *cli.App {
 		versionCommand,
 		licenseCommand,
 		dumpConfigCommand,
+		backupCommand,
+		restoreCommand,
+		dbCommand,
+		tokenCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
@@ -0,0 +1,136 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ailabstw/go-pttai/cmd/utils"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/node"
+	"github.com/ailabstw/go-pttai/rpc"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// createToken is the token create command.
+func createToken(ctx *cli.Context) error {
+	name := ctx.Args().First()
+	if name == "" {
+		return ErrInvalidTokenName
+	}
+
+	scopes := make([]string, 0)
+	for _, scope := range strings.Split(ctx.String(tokenScopeFlag.Name), ",") {
+		scope = strings.TrimSpace(scope)
+		if scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	token := &node.CreatedAuthToken{}
+	err := withTokens(ctx, func(client *rpc.Client) error {
+		return client.Call(token, "admin_createToken", name, scopes)
+	}, func(tokens *rpc.TokenStore) error {
+		var err error
+		token.Secret, token.AuthToken, err = tokens.Create(name, scopes)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("ID: %v\n", token.ID)
+	fmt.Printf("Name: %v\n", token.Name)
+	fmt.Printf("Scopes: %v\n", strings.Join(token.Scopes, ","))
+	fmt.Printf("Token: %v\n", token.Secret)
+	fmt.Println("The token is shown only once.")
+
+	return nil
+}
+
+// listTokens is the token list command.
+func listTokens(ctx *cli.Context) error {
+	var theTokens []*rpc.AuthToken
+	err := withTokens(ctx, func(client *rpc.Client) error {
+		return client.Call(&theTokens, "admin_listTokens")
+	}, func(tokens *rpc.TokenStore) error {
+		theTokens = tokens.List()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, token := range theTokens {
+		fmt.Printf("%v\t%v\t%v\t%v\n", token.ID, token.Name, strings.Join(token.Scopes, ","), token.CreateTS.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// revokeToken is the token revoke command.
+func revokeToken(ctx *cli.Context) error {
+	id := ctx.Args().First()
+	if id == "" {
+		return ErrInvalidTokenID
+	}
+
+	err := withTokens(ctx, func(client *rpc.Client) error {
+		isOk := false
+		return client.Call(&isOk, "admin_revokeToken", id)
+	}, func(tokens *rpc.TokenStore) error {
+		return tokens.Revoke(id)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Revoked: %v\n", id)
+
+	return nil
+}
+
+/*
+withTokens manages the tokens through the running node (IPC),
+or directly in the data-dir if the node is not running.
+*/
+func withTokens(ctx *cli.Context, byRPC func(client *rpc.Client) error, byStore func(tokens *rpc.TokenStore) error) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	utils.SetNodeConfig(ctx, cfg.Node)
+
+	// running node: the tokens are kept by the node.
+	if endpoint := cfg.Node.IPCEndpoint(); endpoint != "" {
+		client, err := rpc.Dial(endpoint)
+		if err == nil {
+			defer client.Close()
+			return byRPC(client)
+		}
+		log.Debug("withTokens: node not running", "endpoint", endpoint, "e", err)
+	}
+
+	tokens, err := rpc.NewTokenStore(cfg.Node.AuthTokensPath())
+	if err != nil {
+		return err
+	}
+
+	return byStore(tokens)
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/ailabstw/go-pttai/content"
 	"github.com/ailabstw/go-pttai/me"
 	"github.com/ailabstw/go-pttai/node"
+	pkgservice "github.com/ailabstw/go-pttai/service"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -102,6 +103,12 @@ var (
 		Usage: "log filename",
 	}
 
+	DBEngineFlag = cli.StringFlag{
+		Name:  "db.engine",
+		Usage: "Storage engine of the databases (leveldb, memory, bolt)",
+		Value: string(pkgservice.DefaultConfig.DBEngine),
+	}
+
 	// My settings
 	MyDataDirFlag = DirectoryFlag{
 		Name:  "mydatadir",
@@ -116,6 +123,10 @@ var (
 		Name:  "mykeyhex",
 		Usage: "my key as hex (for testing)",
 	}
+	KeystorePasswordFileFlag = cli.StringFlag{
+		Name:  "keystore.password-file",
+		Usage: "File containing the passphrase of the node / my key-files (the plaintext key-files are encrypted with it)",
+	}
 
 	MyPostfixFlag = cli.StringFlag{
 		Name:  "mypostfix",
@@ -133,6 +144,12 @@ var (
 		Usage: "expire oplog seconds",
 	}
 
+	ServiceSignKeyTypeFlag = cli.StringFlag{
+		Name:  "servicesignkeytype",
+		Usage: "Key type of the newly generated sign-keys and op-keys (secp256k1, ed25519)",
+		Value: pkgservice.DefaultConfig.SignKeyType,
+	}
+
 	// Content settings
 	ContentDataDirFlag = DirectoryFlag{
 		Name:  "contentdatadir",
@@ -216,6 +233,11 @@ var (
 		Usage: "InfluxDB `host` tag attached to all measurements",
 		Value: "localhost",
 	}
+	MetricsPrometheusAddrFlag = cli.StringFlag{
+		Name:  "metrics.prometheus.addr",
+		Usage: "Prometheus endpoint listening addr (serving /metrics, disabled if empty)",
+		Value: "",
+	}
 
 	// HTTP server
 	HTTPAddrFlag = cli.StringFlag{
@@ -260,6 +282,10 @@ var (
 		Usage: "Comma separated list of virtual hostnames from which to accept requests (server enforced). Accepts '*' wildcard.",
 		Value: strings.Join(node.DefaultConfig.HTTPVirtualHosts, ","),
 	}
+	RPCAuthFlag = cli.BoolFlag{
+		Name:  "rpcauth",
+		Usage: "Require the auth-tokens (gptt token) on the HTTP-RPC / WS-RPC and the http-server",
+	}
 	RPCApiFlag = cli.StringFlag{
 		Name:  "rpcapi",
 		Usage: "API's offered over the HTTP-RPC interface",
@@ -342,6 +368,11 @@ var (
 		Usage: "Network listening port",
 		Value: 9487,
 	}
+	P2PKeyTypeFlag = cli.StringFlag{
+		Name:  "p2pkeytype",
+		Usage: "Key type of the libp2p identity derived from the node key (secp256k1, ed25519)",
+		Value: "secp256k1",
+	}
 	WebrtcSignalServerFlag = cli.StringFlag{
 		Name:  "webrtcsignalserver",
 		Usage: "webrtc signal server",
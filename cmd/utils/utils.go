@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	golog "log"
+	"net/http"
 	"net/url"
 	"path/filepath"
 	"strconv"
@@ -28,10 +29,13 @@ import (
 	"time"
 
 	"github.com/ailabstw/go-pttai/account"
+	"github.com/ailabstw/go-pttai/chat"
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/content"
 	"github.com/ailabstw/go-pttai/friend"
 	"github.com/ailabstw/go-pttai/internal/debug"
+	"github.com/ailabstw/go-pttai/internal/prometheus"
+	"github.com/ailabstw/go-pttai/key"
 	"github.com/ailabstw/go-pttai/log"
 	"github.com/ailabstw/go-pttai/me"
 	"github.com/ailabstw/go-pttai/node"
@@ -40,6 +44,7 @@ import (
 	"github.com/ailabstw/go-pttai/p2p/nat"
 	"github.com/ailabstw/go-pttai/p2p/netutil"
 	"github.com/ailabstw/go-pttai/params"
+	"github.com/ailabstw/go-pttai/pttdb"
 	"github.com/ailabstw/etcd/raft"
 	pkgservice "github.com/ailabstw/go-pttai/service"
 	"github.com/ethereum/go-ethereum/common/fdlimit"
@@ -109,6 +114,7 @@ func SetMeConfig(ctx *cli.Context, cfg *me.Config, cfgNode *node.Config) {
 	// data-dir
 	log.Debug("SetMeConfig: to set DataDir", "cfgNode.DataDIR", cfgNode.DataDir)
 	cfg.DataDir = filepath.Join(cfgNode.DataDir, "me")
+	cfg.NodeDataDir = cfgNode.DataDir
 
 	// key/id/postfix
 	setMyKey(ctx, cfg)
@@ -171,6 +177,13 @@ func SetFriendConfig(ctx *cli.Context, cfg *friend.Config, cfgNode *node.Config)
 	friend.MinSyncRandomSeconds = cfg.MinSyncRandomSeconds
 }
 
+// SetChatConfig applies chat-related command line flags to the config.
+func SetChatConfig(ctx *cli.Context, cfg *chat.Config, cfgNode *node.Config) {
+	// datadir
+	log.Debug("SetChatConfig: to set DataDir", "cfgNode.DataDIR", cfgNode.DataDir)
+	cfg.DataDir = filepath.Join(cfgNode.DataDir, "chat")
+}
+
 // SetPttConfig applies ptt-related command line flags to the config.
 func SetPttConfig(ctx *cli.Context, cfg *pkgservice.Config, cfgNode *node.Config, gitCommit string, version string) {
 	log.Debug("SetPttConfig: start", "cfg", cfg, "cfgNode", cfgNode, "cfgNode.DataDir", cfgNode.DataDir, "params.Version", params.Version)
@@ -195,6 +208,16 @@ func SetPttConfig(ctx *cli.Context, cfg *pkgservice.Config, cfgNode *node.Config
 	}
 	pkgservice.ExpireOplogSeconds = cfg.ExpireOplogSeconds
 
+	// sign key type
+	if ctx.GlobalIsSet(ServiceSignKeyTypeFlag.Name) {
+		cfg.SignKeyType = ctx.GlobalString(ServiceSignKeyTypeFlag.Name)
+	}
+	signKeyType, err := key.ParseType(cfg.SignKeyType)
+	if err != nil {
+		Fatalf("Invalid sign key type: %v", cfg.SignKeyType)
+	}
+	pkgservice.SignKeyType = signKeyType
+
 	// e2e
 	if ctx.GlobalIsSet(E2EFlag.Name) {
 		cfg.IsE2E = ctx.GlobalBool(E2EFlag.Name)
@@ -212,7 +235,16 @@ func SetPttConfig(ctx *cli.Context, cfg *pkgservice.Config, cfgNode *node.Config
 		types.OffsetSecond = ctx.GlobalInt64(OffsetSecondFlag.Name)
 	}
 
-	log.Debug("SetPttConfig: to return", "ExpireOplogSeconds", pkgservice.ExpireOplogSeconds, "IsE2E", pkgservice.IsE2E, "IsPrivateAsPublic", pkgservice.IsPrivateAsPublic, "OffsetSecond", types.OffsetSecond)
+	// db engine
+	if ctx.GlobalIsSet(DBEngineFlag.Name) {
+		cfg.DBEngine = pttdb.EngineType(ctx.GlobalString(DBEngineFlag.Name))
+	}
+	if !cfg.DBEngine.IsValid() {
+		Fatalf("Invalid db engine: %v", cfg.DBEngine)
+	}
+	pttdb.CurrentEngine = cfg.DBEngine
+
+	log.Debug("SetPttConfig: to return", "ExpireOplogSeconds", pkgservice.ExpireOplogSeconds, "SignKeyType", pkgservice.SignKeyType, "IsE2E", pkgservice.IsE2E, "IsPrivateAsPublic", pkgservice.IsPrivateAsPublic, "OffsetSecond", types.OffsetSecond, "DBEngine", pttdb.CurrentEngine)
 
 }
 
@@ -342,6 +374,19 @@ func setP2PListenAddress(ctx *cli.Context, cfg *p2p.Config) {
 	}
 }
 
+// setP2PKeyType sets the key-type of the libp2p identity from set command line flags.
+func setP2PKeyType(ctx *cli.Context, cfg *p2p.Config) {
+	if !ctx.GlobalIsSet(P2PKeyTypeFlag.Name) {
+		return
+	}
+
+	keyType, err := key.ParseType(ctx.GlobalString(P2PKeyTypeFlag.Name))
+	if err != nil {
+		Fatalf("Invalid p2p key type: %v", ctx.GlobalString(P2PKeyTypeFlag.Name))
+	}
+	cfg.P2PKeyType = keyType
+}
+
 // setNAT creates a port mapper from command line flags.
 func setNAT(ctx *cli.Context, cfg *p2p.Config) {
 	if ctx.GlobalIsSet(NATFlag.Name) {
@@ -385,6 +430,9 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(RPCVirtualHostsFlag.Name) {
 		cfg.HTTPVirtualHosts = splitAndTrim(ctx.GlobalString(RPCVirtualHostsFlag.Name))
 	}
+	if ctx.GlobalIsSet(RPCAuthFlag.Name) {
+		cfg.HTTPAuth = ctx.GlobalBool(RPCAuthFlag.Name)
+	}
 
 	if ctx.GlobalIsSet(ExternRPCAddrFlag.Name) {
 		cfg.ExternHTTPAddr = ctx.GlobalString(ExternRPCAddrFlag.Name)
@@ -450,6 +498,7 @@ func setP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	setListenAddress(ctx, cfg)
 	setBootstrapNodes(ctx, cfg)
 	setP2PListenAddress(ctx, cfg)
+	setP2PKeyType(ctx, cfg)
 	setP2PBootnodes(ctx, cfg)
 	setSignalServerURL(ctx, cfg)
 
@@ -562,6 +611,8 @@ func SetupMetrics(ctx *cli.Context) {
 			username     = ctx.GlobalString(MetricsInfluxDBUsernameFlag.Name)
 			password     = ctx.GlobalString(MetricsInfluxDBPasswordFlag.Name)
 			hosttag      = ctx.GlobalString(MetricsInfluxDBHostTagFlag.Name)
+
+			prometheusAddr = ctx.GlobalString(MetricsPrometheusAddrFlag.Name)
 		)
 
 		if enableExport {
@@ -570,5 +621,17 @@ func SetupMetrics(ctx *cli.Context) {
 				"host": hosttag,
 			})
 		}
+
+		if prometheusAddr != "" {
+			log.Info("Enabling metrics export to Prometheus", "addr", prometheusAddr)
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", prometheus.Handler(metrics.DefaultRegistry))
+			go func() {
+				err := http.ListenAndServe(prometheusAddr, mux)
+				if err != nil {
+					log.Error("unable to start Prometheus endpoint", "addr", prometheusAddr, "e", err)
+				}
+			}()
+		}
 	}
 }
@@ -17,8 +17,11 @@
 package content
 
 import (
+	"context"
+
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/pttdb"
+	"github.com/ailabstw/go-pttai/rpc"
 	pkgservice "github.com/ailabstw/go-pttai/service"
 )
 
@@ -34,6 +37,10 @@ func (api *PrivateAPI) CreateBoard(title []byte, isPrivate bool) (*BackendCreate
 	return api.b.CreateBoard(title, isPrivate)
 }
 
+func (api *PrivateAPI) CreatePublicBoard(title []byte) (*BackendCreateBoard, error) {
+	return api.b.CreatePublicBoard(title)
+}
+
 func (api *PrivateAPI) CreateArticle(entityID string, title []byte, article [][]byte, mediaIDs []string) (*BackendCreateArticle, error) {
 	return api.b.CreateArticle(
 		[]byte(entityID),
@@ -63,6 +70,15 @@ func (api *PrivateAPI) CreateReply(entityID string, articleID string, commentID
 	)
 }
 
+func (api *PrivateAPI) AddReaction(entityID string, articleID string, commentID string, reactionType ReactionType) (*BackendCreateReaction, error) {
+	return api.b.AddReaction(
+		[]byte(entityID),
+		[]byte(articleID),
+		[]byte(commentID),
+		reactionType,
+	)
+}
+
 func (api *PrivateAPI) SetTitle(entityID string, title []byte) (*BackendGetBoard, error) {
 	return api.b.SetTitle([]byte(entityID), title)
 }
@@ -73,6 +89,20 @@ func (api *PrivateAPI) UpdateArticle(entityID string, articleID string, article
 		[]byte(articleID),
 		article,
 		mediaIDs,
+		nil,
+	)
+}
+
+/*
+UpdateArticleWithTags updates the article with the tags (defined in the board).
+*/
+func (api *PrivateAPI) UpdateArticleWithTags(entityID string, articleID string, article [][]byte, mediaIDs []string, tags []string) (*BackendUpdateArticle, error) {
+	return api.b.UpdateArticle(
+		[]byte(entityID),
+		[]byte(articleID),
+		article,
+		mediaIDs,
+		tags,
 	)
 }
 
@@ -90,6 +120,34 @@ func (api *PrivateAPI) DeleteBoard(entityID string) (bool, error) {
 	return api.b.DeleteBoard([]byte(entityID))
 }
 
+func (api *PrivateAPI) SetRetention(entityID string, maxAgeSeconds int64, maxCount int) (*pkgservice.RetentionPolicy, error) {
+	return api.b.SetRetention([]byte(entityID), maxAgeSeconds, maxCount)
+}
+
+func (api *PrivateAPI) GetRetention(entityID string) (*pkgservice.RetentionPolicy, error) {
+	return api.b.GetRetention([]byte(entityID))
+}
+
+func (api *PrivateAPI) SetPins(entityID string, articleIDs []string) (*BoardPins, error) {
+	return api.b.SetPins([]byte(entityID), articleIDs)
+}
+
+func (api *PublicAPI) GetPins(entityID string) (*BoardPins, error) {
+	return api.b.GetPins([]byte(entityID))
+}
+
+func (api *PublicAPI) GetPinnedArticleList(entityID string) ([]*BackendGetArticle, error) {
+	return api.b.GetPinnedArticleList([]byte(entityID))
+}
+
+func (api *PrivateAPI) SetTags(entityID string, tags []string) (*BoardTags, error) {
+	return api.b.SetTags([]byte(entityID), tags)
+}
+
+func (api *PublicAPI) GetTags(entityID string) (*BoardTags, error) {
+	return api.b.GetTags([]byte(entityID))
+}
+
 func (api *PrivateAPI) DeleteArticle(entityID string, articleID string) (*BackendDeleteArticle, error) {
 	return api.b.DeleteArticle(
 		[]byte(entityID),
@@ -112,14 +170,59 @@ func (api *PrivateAPI) DeleteReply(entityID string, articleID string, commentID
 	)
 }
 
+/*
+CreatePoll creates the poll with the options (at least 2), the deadline (unix-timestamp in seconds, 0 as no deadline), and whether multiple options are able to be chosen.
+*/
+func (api *PrivateAPI) CreatePoll(entityID string, question []byte, options [][]byte, deadline int64, isMultiChoice bool) (*BackendGetPoll, error) {
+	return api.b.CreatePoll([]byte(entityID), question, options, deadline, isMultiChoice)
+}
+
+/*
+Vote votes the poll with the indexes of the options. Only my latest vote is counted.
+*/
+func (api *PrivateAPI) Vote(entityID string, pollID string, choices []int) (*BackendVote, error) {
+	return api.b.Vote([]byte(entityID), []byte(pollID), choices)
+}
+
+func (api *PrivateAPI) RemoveReaction(entityID string, reactionID string) (*BackendDeleteReaction, error) {
+	return api.b.RemoveReaction(
+		[]byte(entityID),
+		[]byte(reactionID),
+	)
+}
+
 func (api *PrivateAPI) LeaveBoard(entityID string) (bool, error) {
 	return api.b.LeaveEntity([]byte(entityID))
 }
 
+func (api *PrivateAPI) FollowBoard(boardURL string) (*pkgservice.BackendJoinRequest, error) {
+	return api.b.FollowBoard([]byte(boardURL))
+}
+
 func (api *PrivateAPI) DeleteMember(entityID string, userID string) (bool, error) {
 	return api.b.DeleteMember([]byte(entityID), []byte(userID))
 }
 
+func (api *PrivateAPI) SetMemberRole(entityID string, userID string, role pkgservice.MemberRole) (*BackendRole, error) {
+	return api.b.SetMemberRole([]byte(entityID), []byte(userID), role)
+}
+
+func (api *PrivateAPI) GetRoles(entityID string) ([]*BackendRole, error) {
+	return api.b.GetRoles([]byte(entityID))
+}
+
+func (api *PrivateAPI) BanMember(entityID string, userID string, nodeIDs []string) (bool, error) {
+	return api.b.BanMember([]byte(entityID), []byte(userID), nodeIDs)
+}
+
+func (api *PrivateAPI) UnbanMember(entityID string, userID string) (bool, error) {
+	return api.b.UnbanMember([]byte(entityID), []byte(userID))
+}
+
+func (api *PrivateAPI) GetBanList(entityID string) ([]*pkgservice.BanInfo, error) {
+	return api.b.GetBanList([]byte(entityID))
+}
+
 func (api *PrivateAPI) InviteMaster(entityID string, userID string, nodeURL string) (*BackendInviteMaster, error) {
 	return api.b.InviteMaster(
 		[]byte(entityID),
@@ -132,6 +235,18 @@ func (api *PrivateAPI) GetJoinKeyInfos(entityID string) ([]*pkgservice.KeyInfo,
 	return api.b.GetJoinKeys([]byte(entityID))
 }
 
+func (api *PrivateAPI) CreateInvite(entityID string, maxUses int, expireSeconds int64) (*BackendInvite, error) {
+	return api.b.CreateInvite([]byte(entityID), maxUses, expireSeconds)
+}
+
+func (api *PrivateAPI) RevokeInvite(entityID string, inviteID string) (bool, error) {
+	return api.b.RevokeInvite([]byte(entityID), []byte(inviteID))
+}
+
+func (api *PrivateAPI) GetInvites(entityID string) ([]*BackendInvite, error) {
+	return api.b.GetInvites([]byte(entityID))
+}
+
 func (api *PrivateAPI) GetRawBoard(entityID string) (*Board, error) {
 	return api.b.GetRawBoard([]byte(entityID))
 }
@@ -185,7 +300,7 @@ func (api *PrivateAPI) GetRawComment(entityID string, commentID string) (*Commen
 	)
 }
 
-func (api *PrivateAPI) GetRawReply(entityID string, articleID string, commentID string) (*Reply, error) {
+func (api *PrivateAPI) GetRawReply(entityID string, articleID string, commentID string) (*Comment, error) {
 	return api.b.GetRawReply(
 		[]byte(entityID),
 		[]byte(articleID),
@@ -193,6 +308,17 @@ func (api *PrivateAPI) GetRawReply(entityID string, articleID string, commentID
 	)
 }
 
+/*
+GetReactions gets the counts of each reaction-type of the article (or the comment if commentID is given), with my reaction-ids.
+*/
+func (api *PublicAPI) GetReactions(entityID string, articleID string, commentID string) (*BackendGetReactions, error) {
+	return api.b.GetReactions(
+		[]byte(entityID),
+		[]byte(articleID),
+		[]byte(commentID),
+	)
+}
+
 /*
 GetArticleBlockList gets the list of the blocks-to-show of the article, including main-article, comment, reply.
 
@@ -211,19 +337,71 @@ func (api *PublicAPI) GetArticleBlockList(entityID string, articleID string, sub
 	)
 }
 
-func (api *PublicAPI) GetArticleList(entityID string, startingArticleID string, limit int, listOrder pttdb.ListOrder) ([]*BackendGetArticle, error) {
+/*
+GetCommentList gets the comment-threads (the comments with the nested replies) of the article.
+
+The top-level comments are listed from startingCommentID with the listOrder, with at most limit threads (0 as no limit).
+*/
+func (api *PublicAPI) GetCommentList(entityID string, articleID string, startingCommentID string, limit int, listOrder pttdb.ListOrder) ([]*CommentThread, error) {
+	return api.b.GetCommentList(
+		[]byte(entityID),
+		[]byte(articleID),
+		[]byte(startingCommentID),
+		limit,
+		listOrder,
+	)
+}
+
+/*
+GetArticleList gets the list of the articles, filtered by the (optional) tag.
+*/
+func (api *PublicAPI) GetArticleList(entityID string, startingArticleID string, limit int, listOrder pttdb.ListOrder, tag *string) ([]*BackendGetArticle, error) {
+	theTag := ""
+	if tag != nil {
+		theTag = *tag
+	}
+
 	return api.b.GetArticleList(
 		[]byte(entityID),
 		[]byte(startingArticleID),
 		limit,
 		listOrder,
+		[]byte(theTag),
 	)
 }
 
+func (api *PublicAPI) GetPollList(entityID string, startingPollID string, limit int, listOrder pttdb.ListOrder) ([]*BackendGetPoll, error) {
+	return api.b.GetPollList([]byte(entityID), []byte(startingPollID), limit, listOrder)
+}
+
+/*
+GetPollResults gets the counts of each option of the poll, from the latest vote of each user before the deadline.
+*/
+func (api *PublicAPI) GetPollResults(entityID string, pollID string) (*BackendPollResults, error) {
+	return api.b.GetPollResults([]byte(entityID), []byte(pollID))
+}
+
 func (api *PublicAPI) GetPokedArticleList(entityID string) ([]*BackendGetArticle, error) {
 	return api.b.GetPokedArticleList([]byte(entityID))
 }
 
+/*
+Search searches the articles and comments by the query (in all the boards if entityID is empty).
+*/
+func (api *PublicAPI) Search(entityID string, query string, startingID string, limit int, listOrder pttdb.ListOrder) ([]*BackendSearchContent, error) {
+	return api.b.Search(
+		[]byte(entityID),
+		[]byte(query),
+		[]byte(startingID),
+		limit,
+		listOrder,
+	)
+}
+
+func (api *PrivateAPI) RebuildSearchIndex(entityID string) (bool, error) {
+	return api.b.RebuildSearchIndex([]byte(entityID))
+}
+
 func (api *PublicAPI) ShowBoardURL(entityID string) (*pkgservice.BackendJoinURL, error) {
 	return api.b.ShowBoardURL([]byte(entityID))
 }
@@ -256,6 +434,14 @@ func (api *PrivateAPI) GetBoardOplogMerkle(entityID string) (*pkgservice.Backend
 	return api.b.GetBoardOplogMerkle([]byte(entityID))
 }
 
+/*
+BoardOplogs creates a subscription (content_subscribe("boardOplogs", entityID)) receiving the board-oplogs and member-oplogs when they are valid.
+Receiving the oplogs of all the boards if entityID is empty.
+*/
+func (api *PrivateAPI) BoardOplogs(ctx context.Context, entityID string) (*rpc.Subscription, error) {
+	return api.b.SubscribeBoardOplogs(ctx, []byte(entityID))
+}
+
 func (api *PrivateAPI) UploadFile(entityID string, filename string, bytes []byte) (*BackendUploadFile, error) {
 	return api.b.UploadFile([]byte(entityID), []byte(filename), bytes)
 }
@@ -264,6 +450,37 @@ func (api *PrivateAPI) GetFile(entityID string, mediaID string) (*BackendGetFile
 	return api.b.GetFile([]byte(entityID), []byte(mediaID))
 }
 
+/*
+CreateUpload creates a resumable upload. The chunks are sent with UploadChunk, and the file is created with CommitUpload.
+*/
+func (api *PrivateAPI) CreateUpload(entityID string) (*BackendUpload, error) {
+	return api.b.CreateUpload([]byte(entityID))
+}
+
+func (api *PrivateAPI) GetUpload(entityID string, uploadID string) (*BackendUpload, error) {
+	return api.b.GetUpload([]byte(entityID), []byte(uploadID))
+}
+
+func (api *PrivateAPI) UploadChunk(entityID string, uploadID string, offset int64, bytes []byte) (*BackendUpload, error) {
+	return api.b.UploadChunk([]byte(entityID), []byte(uploadID), offset, bytes)
+}
+
+func (api *PrivateAPI) CommitUpload(entityID string, uploadID string, filename string) (*BackendUploadFile, error) {
+	return api.b.CommitUpload([]byte(entityID), []byte(uploadID), []byte(filename))
+}
+
+func (api *PrivateAPI) CancelUpload(entityID string, uploadID string) (bool, error) {
+	return api.b.CancelUpload([]byte(entityID), []byte(uploadID))
+}
+
+func (api *PrivateAPI) GetFileInfo(entityID string, mediaID string) (*BackendFileInfo, error) {
+	return api.b.GetFileInfo([]byte(entityID), []byte(mediaID))
+}
+
+func (api *PrivateAPI) GetFileChunk(entityID string, mediaID string, offset int64, length int) (*BackendFileChunk, error) {
+	return api.b.GetFileChunk([]byte(entityID), []byte(mediaID), offset, length)
+}
+
 func (api *PrivateAPI) UploadImage(entityID string, fileType string, bytes []byte) (*BackendUploadImg, error) {
 	return api.b.UploadImage([]byte(entityID), fileType, bytes)
 }
@@ -272,6 +489,10 @@ func (api *PrivateAPI) GetImage(entityID string, imgID string) (*BackendGetImg,
 	return api.b.GetImage([]byte(entityID), []byte(imgID))
 }
 
+func (api *PrivateAPI) GetImageThumbnail(entityID string, imgID string, size int) (*BackendGetImg, error) {
+	return api.b.GetImageThumbnail([]byte(entityID), []byte(imgID), size)
+}
+
 func (api *PublicAPI) GetArticleSummary(entityID string, articleInfo *BackendArticleSummaryParams) (*ArticleBlock, error) {
 	return api.b.GetArticleSummary([]byte(entityID), articleInfo)
 }
@@ -288,6 +509,40 @@ func (api *PrivateAPI) MarkArticleSeen(entityID string, articleID string) (types
 	return api.b.MarkArticleSeen([]byte(entityID), []byte(articleID))
 }
 
+/**********
+ * Read-receipt / Typing
+ **********/
+
+/*
+GetReadReceipts gets the latest read-positions of the board members received in this session (of the article if articleID is given).
+*/
+func (api *PrivateAPI) GetReadReceipts(entityID string, articleID string) ([]*pkgservice.ReadReceipt, error) {
+	return api.b.GetReadReceipts([]byte(entityID), []byte(articleID))
+}
+
+/*
+SetTyping sends my typing-state in the board (or in the article if articleID is given) if I share the typing-states.
+*/
+func (api *PrivateAPI) SetTyping(entityID string, articleID string, isTyping bool) (bool, error) {
+	return api.b.SetTyping([]byte(entityID), []byte(articleID), isTyping)
+}
+
+/*
+ReadReceipts creates a subscription (content_subscribe("readReceipts", entityID)) receiving the read-receipts from the board members.
+Receiving the read-receipts of all the boards if entityID is empty.
+*/
+func (api *PrivateAPI) ReadReceipts(ctx context.Context, entityID string) (*rpc.Subscription, error) {
+	return api.b.SubscribeReadReceipts(ctx, []byte(entityID))
+}
+
+/*
+Typings creates a subscription (content_subscribe("typings", entityID)) receiving the typing-states from the board members.
+Receiving the typing-states of all the boards if entityID is empty.
+*/
+func (api *PrivateAPI) Typings(ctx context.Context, entityID string) (*rpc.Subscription, error) {
+	return api.b.SubscribeTypings(ctx, []byte(entityID))
+}
+
 /**********
  * MasterOplog
  **********/
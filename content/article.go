@@ -30,6 +30,8 @@ type SyncArticleInfo struct {
 	*pkgservice.BaseSyncInfo `json:"b"`
 
 	Title []byte `json:"T,omitempty"`
+
+	Tags []string `json:"tg,omitempty"`
 }
 
 func NewEmptySyncArticleInfo() *SyncArticleInfo {
@@ -45,6 +47,7 @@ func (s *SyncArticleInfo) ToObject(theObj pkgservice.Object) error {
 	s.BaseSyncInfo.ToObject(obj)
 
 	obj.Title = s.Title
+	obj.Tags = s.Tags
 
 	return nil
 }
@@ -58,6 +61,8 @@ type Article struct {
 
 	Title []byte `json:"T,omitempty"`
 
+	Tags []string `json:"tg,omitempty"`
+
 	NPush *pkgservice.Count `json:"-"` // from other db-records
 	NBoo  *pkgservice.Count `json:"-"` // from other db-records
 
@@ -474,6 +479,7 @@ func (a *Article) Postdelete(comment *Comment, isLocked bool) error {
 		id, err = comment.KeyToID(key)
 		comment.SetID(id)
 		comment.GetAndDeleteAll(false)
+		removeSearchDoc(id)
 	}
 
 	// push
@@ -31,6 +31,8 @@ type ArticleBlock struct {
 	CommentType CommentType  `json:"mt"`
 	BlockID     uint32       `json:"BID"`
 
+	ParentID *types.PttID `json:"pID,omitempty"`
+
 	Status types.Status `json:"S"`
 
 	CreateTS types.Timestamp `json:"CT"`
@@ -78,6 +80,8 @@ func commentToArticleBlock(pm *ProtocolManager, comment *Comment) (*ArticleBlock
 		CommentType: comment.CommentType,
 		BlockID:     0,
 
+		ParentID: comment.ParentID,
+
 		Status: comment.Status,
 
 		CreateTS:  comment.CreateTS,
@@ -19,6 +19,7 @@ package content
 import (
 	"github.com/ailabstw/go-pttai/account"
 	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
 	"github.com/ailabstw/go-pttai/rpc"
 	pkgservice "github.com/ailabstw/go-pttai/service"
 )
@@ -57,6 +58,17 @@ func NewBackend(ctx *pkgservice.ServiceContext, cfg *Config, id *types.PttID, pt
 }
 
 func (b *Backend) Start() error {
+	expireTS, err := types.GetTimestamp()
+	if err != nil {
+		return err
+	}
+	expireTS.Ts -= ExpireUploadSeconds
+
+	err = removeExpiredUploads(expireTS)
+	if err != nil {
+		log.Warn("Start: unable to remove expired uploads", "e", err)
+	}
+
 	b.SPM().Start()
 	return nil
 }
@@ -17,10 +17,16 @@
 package content
 
 import (
+	"context"
+	"io"
+	"reflect"
+
 	"github.com/ailabstw/go-pttai/account"
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/p2p/discover"
 	"github.com/ailabstw/go-pttai/pttdb"
+	"github.com/ailabstw/go-pttai/rpc"
 	pkgservice "github.com/ailabstw/go-pttai/service"
 	"github.com/syndtr/goleveldb/leveldb"
 )
@@ -32,6 +38,17 @@ func (b *Backend) CreateBoard(title []byte, isPrivate bool) (*BackendCreateBoard
 		entityType = pkgservice.EntityTypePrivate
 	}
 
+	return b.createBoard(title, entityType)
+}
+
+/*
+CreatePublicBoard creates the public board, which the non-members are able to follow.
+*/
+func (b *Backend) CreatePublicBoard(title []byte) (*BackendCreateBoard, error) {
+	return b.createBoard(title, pkgservice.EntityTypePublic)
+}
+
+func (b *Backend) createBoard(title []byte, entityType pkgservice.EntityType) (*BackendCreateBoard, error) {
 	board, err := b.SPM().(*ServiceProtocolManager).CreateBoard(title, entityType)
 	if err != nil {
 		return nil, err
@@ -107,10 +124,44 @@ func (b *Backend) CreateComment(entityIDBytes []byte, articleIDBytes []byte, com
 
 func (b *Backend) CreateReply(entityIDBytes []byte, articleIDBytes []byte, commentIDBytes []byte, reply [][]byte, mediaIDBytes []byte) (*BackendCreateReply, error) {
 
-	return nil, types.ErrNotImplemented
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	articleID, err := types.UnmarshalTextPttID(articleIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+	if articleID == nil {
+		return nil, types.ErrInvalidID
+	}
+
+	commentID, err := types.UnmarshalTextPttID(commentIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+	if commentID == nil {
+		return nil, types.ErrInvalidID
+	}
+
+	mediaID, err := types.UnmarshalTextPttID(mediaIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	theReply, err := pm.CreateReply(articleID, commentID, reply, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	backendReply := commentToBackendCreateReply(theReply)
+
+	return backendReply, nil
 }
 
-func (b *Backend) UpdateArticle(entityIDBytes []byte, articleIDBytes []byte, article [][]byte, mediaIDStrs []string) (*BackendUpdateArticle, error) {
+func (b *Backend) UpdateArticle(entityIDBytes []byte, articleIDBytes []byte, article [][]byte, mediaIDStrs []string, tags []string) (*BackendUpdateArticle, error) {
 
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
@@ -137,7 +188,7 @@ func (b *Backend) UpdateArticle(entityIDBytes []byte, articleIDBytes []byte, art
 		}
 	}
 
-	theArticle, err := pm.UpdateArticle(articleID, article, mediaIDs)
+	theArticle, err := pm.UpdateArticle(articleID, article, mediaIDs, tags)
 	if err != nil {
 		return nil, err
 	}
@@ -197,107 +248,75 @@ func (b *Backend) DeleteComment(entityIDBytes []byte, commentIDBytes []byte) (*B
 	return &BackendDeleteComment{}, nil
 }
 
+/*
+DeleteReply deletes the reply (commentIDBytes as the id of the reply).
+*/
 func (b *Backend) DeleteReply(entityIDBytes []byte, articleIDBytes []byte, commentIDBytes []byte) (*BackendDeleteReply, error) {
 
-	return nil, types.ErrNotImplemented
-}
-
-func (b *Backend) DeleteBoard(entityIDBytes []byte) (bool, error) {
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	pm := thePM.(*ProtocolManager)
 
-	err = pm.DeleteBoard()
+	replyID, err := types.UnmarshalTextPttID(commentIDBytes, false)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	return true, nil
-}
-
-func (b *Backend) InviteMaster(boardID []byte, userID []byte, nodeURL []byte) (*BackendInviteMaster, error) {
-
-	return nil, types.ErrNotImplemented
-}
-
-func (b *Backend) TransferMaster(boardID []byte, userID []byte) (*BackendRevokeMaster, error) {
-
-	return nil, types.ErrNotImplemented
-}
-
-func (b *Backend) GetBoard(entityIDBytes []byte) (*BackendGetBoard, error) {
-
-	board, err := b.GetRawBoard(entityIDBytes)
+	reply, err := pm.GetComment(replyID)
 	if err != nil {
 		return nil, err
 	}
-
-	myID := b.Ptt().GetMyEntity().GetID()
-	accountBackend := b.accountBackend
-	userName, err := accountBackend.GetRawUserNameByID(board.CreatorID)
-	if err != nil {
-		userName = account.NewEmptyUserName()
+	if reply.ParentID == nil {
+		return nil, ErrInvalidParent
 	}
-	theTitle, err := b.GetRawTitleByID(board.ID)
-
-	backendBoard := boardToBackendGetBoard(board, string(userName.Name), theTitle, myID)
-
-	return backendBoard, nil
-}
-
-func (b *Backend) GetRawBoard(entityIDBytes []byte) (*Board, error) {
 
-	entity, err := b.EntityIDToEntity(entityIDBytes)
+	err = pm.DeleteComment(replyID)
 	if err != nil {
 		return nil, err
 	}
-	board := entity.(*Board)
 
-	return board, nil
+	return &BackendDeleteReply{}, nil
 }
 
-func (b *Backend) GetBoardList(startingIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BackendGetBoard, error) {
+func (b *Backend) AddReaction(entityIDBytes []byte, articleIDBytes []byte, commentIDBytes []byte, reactionType ReactionType) (*BackendCreateReaction, error) {
 
-	startID, err := types.UnmarshalTextPttID(startingIDBytes, true)
+	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
 		return nil, err
 	}
+	pm := thePM.(*ProtocolManager)
 
-	boardList, err := b.SPM().(*ServiceProtocolManager).GetBoardList(startID, limit, listOrder)
+	articleID, err := types.UnmarshalTextPttID(articleIDBytes, false)
 	if err != nil {
 		return nil, err
 	}
-
-	accountBackend := b.accountBackend
-	backendBoardList := make([]*BackendGetBoard, len(boardList))
-	var userName *account.UserName
-	var title *Title
-	myID := b.Ptt().GetMyEntity().GetID()
-	for i, f := range boardList {
-		userName, err = accountBackend.GetRawUserNameByID(f.CreatorID)
-		if err != nil {
-			userName = account.NewEmptyUserName()
-		}
-		title, err = b.GetRawTitleByID(f.ID)
-		backendBoardList[i] = boardToBackendGetBoard(f, string(userName.Name), title, myID)
+	if articleID == nil {
+		return nil, types.ErrInvalidID
 	}
 
-	return backendBoardList, nil
-}
-
-func (b *Backend) GetArticle(entityIDBytes []byte, articleIDBytes []byte) (*BackendGetArticle, error) {
+	commentID, err := types.UnmarshalTextPttID(commentIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
 
-	article, err := b.GetRawArticle(entityIDBytes, articleIDBytes)
+	theReaction, err := pm.CreateReaction(articleID, commentID, reactionType)
 	if err != nil {
 		return nil, err
 	}
 
-	return articleToBackendGetArticle(article), nil
+	backendReaction := reactionToBackendCreateReaction(theReaction)
+
+	return backendReaction, nil
 }
 
-func (b *Backend) GetRawArticle(entityIDBytes []byte, articleIDBytes []byte) (*Article, error) {
+/*
+CreatePoll creates the poll in the board.
+
+	deadline: the unix-timestamp (seconds) of the deadline of the poll, 0 as no deadline.
+*/
+func (b *Backend) CreatePoll(entityIDBytes []byte, question []byte, options [][]byte, deadline int64, isMultiChoice bool) (*BackendGetPoll, error) {
 
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
@@ -305,15 +324,19 @@ func (b *Backend) GetRawArticle(entityIDBytes []byte, articleIDBytes []byte) (*A
 	}
 	pm := thePM.(*ProtocolManager)
 
-	articleID, err := types.UnmarshalTextPttID(articleIDBytes, false)
+	if deadline < 0 {
+		return nil, ErrInvalidPoll
+	}
+
+	poll, err := pm.CreatePoll(question, options, types.Timestamp{Ts: deadline}, isMultiChoice)
 	if err != nil {
 		return nil, err
 	}
 
-	return pm.GetArticle(articleID)
+	return pollToBackendGetPoll(poll), nil
 }
 
-func (b *Backend) GetRawComment(entityIDBytes []byte, commentIDBytes []byte) (*Comment, error) {
+func (b *Backend) Vote(entityIDBytes []byte, pollIDBytes []byte, choices []int) (*BackendVote, error) {
 
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
@@ -321,20 +344,20 @@ func (b *Backend) GetRawComment(entityIDBytes []byte, commentIDBytes []byte) (*C
 	}
 	pm := thePM.(*ProtocolManager)
 
-	commentID, err := types.UnmarshalTextPttID(commentIDBytes, false)
+	pollID, err := types.UnmarshalTextPttID(pollIDBytes, false)
 	if err != nil {
 		return nil, err
 	}
 
-	return pm.GetComment(commentID)
-}
-
-func (b *Backend) GetRawReply(entityIDBytes []byte, articleIDBytes []byte, commentIDBytes []byte) (*Reply, error) {
+	vote, err := pm.Vote(pollID, choices)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil, types.ErrNotImplemented
+	return voteToBackendVote(vote), nil
 }
 
-func (b *Backend) GetArticleBlockList(entityIDBytes []byte, articleIDBytes []byte, subContentIDBytes []byte, contentType ContentType, blockID uint32, limit int, listOrder pttdb.ListOrder) ([]*ArticleBlock, error) {
+func (b *Backend) RemoveReaction(entityIDBytes []byte, reactionIDBytes []byte) (*BackendDeleteReaction, error) {
 
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
@@ -342,216 +365,977 @@ func (b *Backend) GetArticleBlockList(entityIDBytes []byte, articleIDBytes []byt
 	}
 	pm := thePM.(*ProtocolManager)
 
-	articleID, err := types.UnmarshalTextPttID(articleIDBytes, false)
+	reactionID, err := types.UnmarshalTextPttID(reactionIDBytes, false)
 	if err != nil {
 		return nil, err
 	}
-	if articleID == nil {
+	if reactionID == nil {
 		return nil, types.ErrInvalidID
 	}
 
-	subContentID, err := types.UnmarshalTextPttID(subContentIDBytes, true)
+	err = pm.DeleteReaction(reactionID)
 	if err != nil {
 		return nil, err
 	}
 
-	articleBlockList, err := pm.GetArticleBlockList(articleID, subContentID, contentType, blockID, limit, listOrder)
+	return &BackendDeleteReaction{}, nil
+}
+
+func (b *Backend) DeleteBoard(entityIDBytes []byte) (bool, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
+	pm := thePM.(*ProtocolManager)
 
-	return articleBlockList, nil
-}
+	err = pm.DeleteBoard()
+	if err != nil {
+		return false, err
+	}
 
-func (b *Backend) GetArticleList(entityIDBytes []byte, startingArticleIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BackendGetArticle, error) {
+	return true, nil
+}
 
+func (b *Backend) SetRetention(entityIDBytes []byte, maxAgeSeconds int64, maxCount int) (*pkgservice.RetentionPolicy, error) {
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
 		return nil, err
 	}
 	pm := thePM.(*ProtocolManager)
 
-	startID, err := types.UnmarshalTextPttID(startingArticleIDBytes, true)
+	err = pm.SetRetention(maxAgeSeconds, maxCount)
 	if err != nil {
 		return nil, err
 	}
 
-	articleList, err := pm.GetArticleList(startID, limit, listOrder, false)
+	return pm.GetRetention()
+}
+
+func (b *Backend) GetRetention(entityIDBytes []byte) (*pkgservice.RetentionPolicy, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
 		return nil, err
 	}
-	theList := make([]*BackendGetArticle, len(articleList))
-	for i, article := range articleList {
-		theList[i] = articleToBackendGetArticle(article)
-	}
-
-	return theList, nil
-}
-
-func (b *Backend) GetPokedArticleList(boardID []byte) ([]*BackendGetArticle, error) {
+	pm := thePM.(*ProtocolManager)
 
-	return nil, types.ErrNotImplemented
+	return pm.GetRetention()
 }
 
-func (b *Backend) ShowBoardURL(entityIDBytes []byte) (*pkgservice.BackendJoinURL, error) {
-
-	theEntity, err := b.EntityIDToEntity(entityIDBytes)
+func (b *Backend) SetPins(entityIDBytes []byte, articleIDStrs []string) (*BoardPins, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
 		return nil, err
 	}
-	board := theEntity.(*Board)
-	pm := board.PM().(*ProtocolManager)
-
-	nodeID := b.Ptt().MyNodeID()
-	myID := b.Ptt().GetMyEntity().GetID()
+	pm := thePM.(*ProtocolManager)
 
-	if !pm.IsMaster(myID, false) {
-		return nil, types.ErrInvalidID
+	articleIDs := make([]*types.PttID, len(articleIDStrs))
+	for i, articleIDStr := range articleIDStrs {
+		articleIDs[i], err = types.UnmarshalTextPttID([]byte(articleIDStr), false)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	keyInfo, err := pm.GetJoinKey()
-	log.Debug("ShowBoardURL: after get join key", "e", err)
+	err = pm.SetPins(articleIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	theTitle, err := pm.GetTitle()
-	log.Debug("ShowBoardURL: after get title", "e", err)
+	return pm.GetPins()
+}
+
+func (b *Backend) GetPins(entityIDBytes []byte) (*BoardPins, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
 		return nil, err
 	}
-	title := board.Title
-	if theTitle != nil {
-		title = theTitle.Title
-	}
+	pm := thePM.(*ProtocolManager)
 
-	return pkgservice.MarshalBackendJoinURL(board.CreatorID, nodeID, keyInfo, title, pkgservice.PathJoinBoard)
+	return pm.GetPins()
 }
 
-/**********
- * BoardOplog
- **********/
-
-func (b *Backend) GetBoardOplogList(entityIDBytes []byte, logIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BoardOplog, error) {
-
+func (b *Backend) SetTags(entityIDBytes []byte, tags []string) (*BoardTags, error) {
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
 		return nil, err
 	}
 	pm := thePM.(*ProtocolManager)
 
-	logID, err := types.UnmarshalTextPttID(logIDBytes, true)
+	err = pm.SetTags(tags)
 	if err != nil {
 		return nil, err
 	}
 
-	return pm.GetBoardOplogList(logID, limit, listOrder, types.StatusAlive)
+	return pm.GetTags()
 }
 
-func (b *Backend) GetPendingBoardOplogMasterList(entityIDBytes []byte, logIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BoardOplog, error) {
-
+func (b *Backend) GetTags(entityIDBytes []byte) (*BoardTags, error) {
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
 		return nil, err
 	}
 	pm := thePM.(*ProtocolManager)
 
-	logID, err := types.UnmarshalTextPttID(logIDBytes, true)
+	return pm.GetTags()
+}
+
+func (b *Backend) SetMemberRole(entityIDBytes []byte, userIDBytes []byte, role pkgservice.MemberRole) (*BackendRole, error) {
+	userID, err := types.UnmarshalTextPttID(userIDBytes, false)
 	if err != nil {
 		return nil, err
 	}
 
-	return pm.GetBoardOplogList(logID, limit, listOrder, types.StatusPending)
-}
-
-func (b *Backend) GetPendingBoardOplogInternalList(entityIDBytes []byte, logIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BoardOplog, error) {
-
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
 		return nil, err
 	}
 	pm := thePM.(*ProtocolManager)
 
-	logID, err := types.UnmarshalTextPttID(logIDBytes, true)
+	err = pm.SetMemberRole(userID, role)
 	if err != nil {
 		return nil, err
 	}
 
-	return pm.GetBoardOplogList(logID, limit, listOrder, types.StatusInternalPending)
-}
+	member, err := pm.GetMember(userID, false)
+	if err != nil {
+		return nil, err
+	}
 
-func (b *Backend) GetBoardOplogMerkleNodeList(entityIDBytes []byte, level pkgservice.MerkleTreeLevel, startKey []byte, limit int, listOrder pttdb.ListOrder) ([]*pkgservice.BackendMerkleNode, error) {
+	return memberToBackendRole(member), nil
+}
 
+/*
+GetRoles gets the masters and the members with the roles (ex: moderators) of the board.
+*/
+func (b *Backend) GetRoles(entityIDBytes []byte) ([]*BackendRole, error) {
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
 		return nil, err
 	}
 	pm := thePM.(*ProtocolManager)
 
-	merkleNodeList, err := pm.GetBoardOplogMerkleNodeList(level, startKey, limit, listOrder)
+	masters, err := pm.GetMasterListFromCache(false)
 	if err != nil {
 		return nil, err
 	}
 
-	results := make([]*pkgservice.BackendMerkleNode, len(merkleNodeList))
-	for i, eachMerkleNode := range merkleNodeList {
-		results[i] = pkgservice.MerkleNodeToBackendMerkleNode(eachMerkleNode)
+	members, err := pm.GetRoleMemberList()
+	if err != nil {
+		return nil, err
 	}
 
-	return results, nil
+	roles := make([]*BackendRole, 0, len(masters)+len(members))
+	for _, master := range masters {
+		roles = append(roles, masterToBackendRole(master))
+	}
+	for _, member := range members {
+		roles = append(roles, memberToBackendRole(member))
+	}
+
+	return roles, nil
 }
 
-func (b *Backend) ForceSyncBoardMerkle(entityIDBytes []byte) (bool, error) {
-	thePM, err := b.EntityIDToPM(entityIDBytes)
+/*
+BanMember bans the user and the node-ids (hex) of the user from the board.
+The user is deleted from the members, and is not able to join the board again.
+*/
+func (b *Backend) BanMember(entityIDBytes []byte, userIDBytes []byte, nodeIDStrs []string) (bool, error) {
+	userID, err := types.UnmarshalTextPttID(userIDBytes, false)
 	if err != nil {
 		return false, err
 	}
-	pm := thePM.(*ProtocolManager)
 
-	return pm.ForceSyncBoardMerkle()
-}
+	nodeIDs := make([]*discover.NodeID, len(nodeIDStrs))
+	for i, nodeIDStr := range nodeIDStrs {
+		nodeID, err := discover.HexID(nodeIDStr)
+		if err != nil {
+			return false, err
+		}
+		nodeIDs[i] = &nodeID
+	}
 
-func (b *Backend) GetBoardOplogMerkle(entityIDBytes []byte) (*pkgservice.BackendMerkle, error) {
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 	pm := thePM.(*ProtocolManager)
 
-	return pkgservice.MerkleToBackendMerkle(pm.boardOplogMerkle), nil
+	err = pm.BanMember(userID, nodeIDs, true)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
-func (b *Backend) UploadFile(entityIDBytes []byte, filename []byte, bytes []byte) (*BackendUploadFile, error) {
+func (b *Backend) UnbanMember(entityIDBytes []byte, userIDBytes []byte) (bool, error) {
+	userID, err := types.UnmarshalTextPttID(userIDBytes, false)
+	if err != nil {
+		return false, err
+	}
 
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 	pm := thePM.(*ProtocolManager)
 
-	media, err := pm.UploadFile(filename, bytes)
+	err = pm.BanMember(userID, nil, false)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 
-	return mediaToBackendUploadFile(media), nil
+	return true, nil
 }
 
-func (b *Backend) GetFile(entityIDBytes []byte, mediaIDBytes []byte) (*BackendGetFile, error) {
-
+func (b *Backend) GetBanList(entityIDBytes []byte) ([]*pkgservice.BanInfo, error) {
 	thePM, err := b.EntityIDToPM(entityIDBytes)
 	if err != nil {
 		return nil, err
 	}
 	pm := thePM.(*ProtocolManager)
 
-	mediaID, err := types.UnmarshalTextPttID(mediaIDBytes, false)
-	if err != nil {
-		return nil, err
-	}
-	if mediaID == nil {
-		return nil, types.ErrInvalidID
-	}
+	return pm.GetBans(), nil
+}
+
+func (b *Backend) InviteMaster(boardID []byte, userID []byte, nodeURL []byte) (*BackendInviteMaster, error) {
+
+	return nil, types.ErrNotImplemented
+}
+
+func (b *Backend) TransferMaster(boardID []byte, userID []byte) (*BackendRevokeMaster, error) {
+
+	return nil, types.ErrNotImplemented
+}
+
+func (b *Backend) GetBoard(entityIDBytes []byte) (*BackendGetBoard, error) {
+
+	board, err := b.GetRawBoard(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	myID := b.Ptt().GetMyEntity().GetID()
+	accountBackend := b.accountBackend
+	userName, err := accountBackend.GetRawUserNameByID(board.CreatorID)
+	if err != nil {
+		userName = account.NewEmptyUserName()
+	}
+	theTitle, err := b.GetRawTitleByID(board.ID)
+
+	backendBoard := boardToBackendGetBoard(board, string(userName.Name), theTitle, myID)
+
+	return backendBoard, nil
+}
+
+func (b *Backend) GetRawBoard(entityIDBytes []byte) (*Board, error) {
+
+	entity, err := b.EntityIDToEntity(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	board := entity.(*Board)
+
+	return board, nil
+}
+
+func (b *Backend) GetBoardList(startingIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BackendGetBoard, error) {
+
+	startID, err := types.UnmarshalTextPttID(startingIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	boardList, err := b.SPM().(*ServiceProtocolManager).GetBoardList(startID, limit, listOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	accountBackend := b.accountBackend
+	backendBoardList := make([]*BackendGetBoard, len(boardList))
+	var userName *account.UserName
+	var title *Title
+	myID := b.Ptt().GetMyEntity().GetID()
+	for i, f := range boardList {
+		userName, err = accountBackend.GetRawUserNameByID(f.CreatorID)
+		if err != nil {
+			userName = account.NewEmptyUserName()
+		}
+		title, err = b.GetRawTitleByID(f.ID)
+		backendBoardList[i] = boardToBackendGetBoard(f, string(userName.Name), title, myID)
+	}
+
+	return backendBoardList, nil
+}
+
+func (b *Backend) GetArticle(entityIDBytes []byte, articleIDBytes []byte) (*BackendGetArticle, error) {
+
+	article, err := b.GetRawArticle(entityIDBytes, articleIDBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return articleToBackendGetArticle(article), nil
+}
+
+func (b *Backend) GetRawArticle(entityIDBytes []byte, articleIDBytes []byte) (*Article, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	articleID, err := types.UnmarshalTextPttID(articleIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return pm.GetArticle(articleID)
+}
+
+func (b *Backend) GetRawComment(entityIDBytes []byte, commentIDBytes []byte) (*Comment, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	commentID, err := types.UnmarshalTextPttID(commentIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return pm.GetComment(commentID)
+}
+
+/*
+GetRawReply gets the reply (commentIDBytes as the id of the reply).
+*/
+func (b *Backend) GetRawReply(entityIDBytes []byte, articleIDBytes []byte, commentIDBytes []byte) (*Comment, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	replyID, err := types.UnmarshalTextPttID(commentIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := pm.GetComment(replyID)
+	if err != nil {
+		return nil, err
+	}
+	if reply.ParentID == nil {
+		return nil, ErrInvalidParent
+	}
+
+	return reply, nil
+}
+
+func (b *Backend) GetReactions(entityIDBytes []byte, articleIDBytes []byte, commentIDBytes []byte) (*BackendGetReactions, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	articleID, err := types.UnmarshalTextPttID(articleIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+	if articleID == nil {
+		return nil, types.ErrInvalidID
+	}
+
+	commentID, err := types.UnmarshalTextPttID(commentIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	targetID := articleID
+	if commentID != nil {
+		targetID = commentID
+	}
+
+	// my reactions
+	reactions, err := pm.GetReactionList(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	myID := b.Ptt().GetMyEntity().GetID()
+	myReactionIDs := make(map[ReactionType]*types.PttID)
+	for _, reaction := range reactions {
+		if reaction.Status > types.StatusAlive {
+			continue
+		}
+		if !reflect.DeepEqual(reaction.CommentID, commentID) || !reflect.DeepEqual(reaction.CreatorID, myID) {
+			continue
+		}
+		myReactionIDs[reaction.ReactionType] = reaction.ID
+	}
+
+	// counts (without the reactions from the blocked users)
+	entityID := pm.Entity().GetID()
+	blockList := b.Ptt().GetMyEntity().GetBlockList()
+	backendReactions := make([]*BackendReactionCount, 0, NReactionType)
+	for reactionType := ReactionTypeLike; reactionType < NReactionType; reactionType++ {
+		backendReactions = append(backendReactions, &BackendReactionCount{
+			ReactionType: reactionType,
+			Count:        pm.getReactionCount(reactions, targetID, reactionType, blockList),
+			MyReactionID: myReactionIDs[reactionType],
+		})
+	}
+
+	return &BackendGetReactions{
+		BoardID:   entityID,
+		ArticleID: articleID,
+		CommentID: commentID,
+		Reactions: backendReactions,
+	}, nil
+}
+
+func (b *Backend) GetArticleBlockList(entityIDBytes []byte, articleIDBytes []byte, subContentIDBytes []byte, contentType ContentType, blockID uint32, limit int, listOrder pttdb.ListOrder) ([]*ArticleBlock, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	articleID, err := types.UnmarshalTextPttID(articleIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+	if articleID == nil {
+		return nil, types.ErrInvalidID
+	}
+
+	subContentID, err := types.UnmarshalTextPttID(subContentIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	articleBlockList, err := pm.GetArticleBlockList(articleID, subContentID, contentType, blockID, limit, listOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	// hide the comments from the blocked users.
+	blockList := pm.Ptt().GetMyEntity().GetBlockList()
+	theList := make([]*ArticleBlock, 0, len(articleBlockList))
+	for _, articleBlock := range articleBlockList {
+		if articleBlock.ContentType == ContentTypeComment && blockList.IsBlocked(articleBlock.CreatorID) {
+			continue
+		}
+		theList = append(theList, articleBlock)
+	}
+
+	return theList, nil
+}
+
+func (b *Backend) GetCommentList(entityIDBytes []byte, articleIDBytes []byte, startingCommentIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*CommentThread, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	articleID, err := types.UnmarshalTextPttID(articleIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+	if articleID == nil {
+		return nil, types.ErrInvalidID
+	}
+
+	startID, err := types.UnmarshalTextPttID(startingCommentIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	threads, err := pm.GetCommentList(articleID, startID, limit, listOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	// hide the comments (with the replies) from the blocked users.
+	blockList := pm.Ptt().GetMyEntity().GetBlockList()
+
+	return filterCommentThreads(threads, blockList.IsBlocked), nil
+}
+
+func filterCommentThreads(threads []*CommentThread, isBlocked func(id *types.PttID) bool) []*CommentThread {
+	theThreads := make([]*CommentThread, 0, len(threads))
+	for _, thread := range threads {
+		if isBlocked(thread.Comment.CreatorID) {
+			continue
+		}
+		thread.Replies = filterCommentThreads(thread.Replies, isBlocked)
+		theThreads = append(theThreads, thread)
+	}
+
+	return theThreads
+}
+
+func (b *Backend) GetArticleList(entityIDBytes []byte, startingArticleIDBytes []byte, limit int, listOrder pttdb.ListOrder, tagBytes []byte) ([]*BackendGetArticle, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	startID, err := types.UnmarshalTextPttID(startingArticleIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	articleList, err := pm.GetArticleListByTag(startID, limit, listOrder, string(tagBytes), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.articlesToBackendGetArticles(pm, articleList), nil
+}
+
+/*
+articlesToBackendGetArticles hides the articles from the blocked users.
+*/
+func (b *Backend) articlesToBackendGetArticles(pm *ProtocolManager, articleList []*Article) []*BackendGetArticle {
+	blockList := pm.Ptt().GetMyEntity().GetBlockList()
+	theList := make([]*BackendGetArticle, 0, len(articleList))
+	for _, article := range articleList {
+		if blockList.IsBlocked(article.CreatorID) {
+			continue
+		}
+		theList = append(theList, articleToBackendGetArticle(article))
+	}
+
+	return theList
+}
+
+func (b *Backend) GetPinnedArticleList(entityIDBytes []byte) ([]*BackendGetArticle, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	articleList, err := pm.GetPinnedArticleList()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.articlesToBackendGetArticles(pm, articleList), nil
+}
+
+func (b *Backend) GetPollList(entityIDBytes []byte, startingPollIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BackendGetPoll, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	startID, err := types.UnmarshalTextPttID(startingPollIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	polls, err := pm.GetPollList(startID, limit, listOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	theList := make([]*BackendGetPoll, 0, len(polls))
+	for _, poll := range polls {
+		if poll.Status != types.StatusAlive {
+			continue
+		}
+		theList = append(theList, pollToBackendGetPoll(poll))
+	}
+
+	return theList, nil
+}
+
+func (b *Backend) GetPollResults(entityIDBytes []byte, pollIDBytes []byte) (*BackendPollResults, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	pollID, err := types.UnmarshalTextPttID(pollIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := pm.GetPollResults(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pollResultsToBackendPollResults(results), nil
+}
+
+func (b *Backend) GetPokedArticleList(boardID []byte) ([]*BackendGetArticle, error) {
+
+	return nil, types.ErrNotImplemented
+}
+
+/*
+Search searches the articles and comments containing all the tokens of the query.
+
+	entityIDBytes: search in all the boards if empty.
+	startingIDBytes: the article-id / comment-id to start with (inclusive).
+
+The articles and comments from the users blocked by me are skipped.
+*/
+func (b *Backend) Search(entityIDBytes []byte, query []byte, startingIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BackendSearchContent, error) {
+
+	entityID, err := types.UnmarshalTextPttID(entityIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+	if entityID != nil {
+		_, err = b.EntityIDToPM(entityIDBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	startID, err := types.UnmarshalTextPttID(startingIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	spm := b.SPM()
+	isValid := func(doc *pkgservice.SearchDoc) bool {
+		return spm.Entity(doc.EntityID) != nil
+	}
+	blockList := b.Ptt().GetMyEntity().GetBlockList()
+
+	docs, err := searchIndex.Search(query, entityID, startID, limit, listOrder, blockList.FilterSearchDoc(isValid))
+	if err != nil {
+		return nil, err
+	}
+
+	theList := make([]*BackendSearchContent, len(docs))
+	for i, doc := range docs {
+		theList[i] = searchDocToBackendSearchContent(doc)
+	}
+
+	return theList, nil
+}
+
+func (b *Backend) RebuildSearchIndex(entityIDBytes []byte) (bool, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return false, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	err = pm.RebuildSearchIndex()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (b *Backend) ShowBoardURL(entityIDBytes []byte) (*pkgservice.BackendJoinURL, error) {
+
+	theEntity, err := b.EntityIDToEntity(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	board := theEntity.(*Board)
+	pm := board.PM().(*ProtocolManager)
+
+	nodeID := b.Ptt().MyNodeID()
+	myID := b.Ptt().GetMyEntity().GetID()
+
+	if !pm.IsJoinApprover(myID) {
+		return nil, types.ErrInvalidID
+	}
+
+	keyInfo, err := pm.GetJoinKey()
+	log.Debug("ShowBoardURL: after get join key", "e", err)
+	if err != nil {
+		return nil, err
+	}
+
+	title, err := boardTitle(board, pm)
+	log.Debug("ShowBoardURL: after get title", "e", err)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgservice.MarshalBackendJoinURL(board.CreatorID, nodeID, keyInfo, title, pkgservice.PathJoinBoard)
+}
+
+func boardTitle(board *Board, pm *ProtocolManager) ([]byte, error) {
+	theTitle, err := pm.GetTitle()
+	if err != nil {
+		return nil, err
+	}
+	if theTitle == nil {
+		return board.Title, nil
+	}
+
+	return theTitle.Title, nil
+}
+
+/*
+CreateInvite creates the invite-url able to be used by maxUses users (0 as unlimited) within expireSeconds.
+*/
+func (b *Backend) CreateInvite(entityIDBytes []byte, maxUses int, expireSeconds int64) (*BackendInvite, error) {
+	theEntity, err := b.EntityIDToEntity(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	board := theEntity.(*Board)
+	pm := board.PM().(*ProtocolManager)
+
+	invite, err := pm.CreateInvite(maxUses, expireSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.inviteToBackendInvite(invite, board, pm)
+}
+
+func (b *Backend) RevokeInvite(entityIDBytes []byte, inviteIDBytes []byte) (bool, error) {
+	inviteID, err := types.UnmarshalTextPttID(inviteIDBytes, false)
+	if err != nil {
+		return false, err
+	}
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return false, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	err = pm.RevokeInvite(inviteID)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (b *Backend) GetInvites(entityIDBytes []byte) ([]*BackendInvite, error) {
+	theEntity, err := b.EntityIDToEntity(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	board := theEntity.(*Board)
+	pm := board.PM().(*ProtocolManager)
+
+	invites, err := pm.GetInviteList()
+	if err != nil {
+		return nil, err
+	}
+
+	backendInvites := make([]*BackendInvite, 0, len(invites))
+	var backendInvite *BackendInvite
+	for _, invite := range invites {
+		backendInvite, err = b.inviteToBackendInvite(invite, board, pm)
+		if err != nil {
+			continue
+		}
+		backendInvites = append(backendInvites, backendInvite)
+	}
+
+	return backendInvites, nil
+}
+
+func (b *Backend) inviteToBackendInvite(invite *pkgservice.Invite, board *Board, pm *ProtocolManager) (*BackendInvite, error) {
+	title, err := boardTitle(board, pm)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeID := b.Ptt().MyNodeID()
+	joinURL, err := pkgservice.MarshalBackendInviteURL(board.CreatorID, nodeID, invite, title, pkgservice.PathJoinBoard)
+	if err != nil {
+		return nil, err
+	}
+
+	return inviteToBackendInvite(invite, joinURL), nil
+}
+
+/*
+FollowBoard follows the public board from the board-url. The board is synced as a follower, which is able to read but not to post.
+*/
+func (b *Backend) FollowBoard(boardURL []byte) (*pkgservice.BackendJoinRequest, error) {
+	joinRequest, err := pkgservice.ParseBackendJoinURL(boardURL, pkgservice.PathJoinBoard)
+	if err != nil {
+		return nil, err
+	}
+
+	myNodeID := b.Ptt().MyNodeID()
+	if reflect.DeepEqual(myNodeID, joinRequest.NodeID) {
+		return nil, ErrInvalidNode
+	}
+
+	joinRequest.IsFollow = true
+
+	myEntity, ok := b.Ptt().GetMyEntity().(pkgservice.PttMyEntity)
+	if !ok {
+		return nil, pkgservice.ErrInvalidEntity
+	}
+	err = myEntity.MyPM().JoinBoard(joinRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgservice.JoinRequestToBackendJoinRequest(joinRequest), nil
+}
+
+/**********
+ * BoardOplog
+ **********/
+
+func (b *Backend) GetBoardOplogList(entityIDBytes []byte, logIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BoardOplog, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	logID, err := types.UnmarshalTextPttID(logIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return pm.GetBoardOplogList(logID, limit, listOrder, types.StatusAlive)
+}
+
+func (b *Backend) GetPendingBoardOplogMasterList(entityIDBytes []byte, logIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BoardOplog, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	logID, err := types.UnmarshalTextPttID(logIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return pm.GetBoardOplogList(logID, limit, listOrder, types.StatusPending)
+}
+
+func (b *Backend) GetPendingBoardOplogInternalList(entityIDBytes []byte, logIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BoardOplog, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	logID, err := types.UnmarshalTextPttID(logIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return pm.GetBoardOplogList(logID, limit, listOrder, types.StatusInternalPending)
+}
+
+func (b *Backend) GetBoardOplogMerkleNodeList(entityIDBytes []byte, level pkgservice.MerkleTreeLevel, startKey []byte, limit int, listOrder pttdb.ListOrder) ([]*pkgservice.BackendMerkleNode, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	merkleNodeList, err := pm.GetBoardOplogMerkleNodeList(level, startKey, limit, listOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*pkgservice.BackendMerkleNode, len(merkleNodeList))
+	for i, eachMerkleNode := range merkleNodeList {
+		results[i] = pkgservice.MerkleNodeToBackendMerkleNode(eachMerkleNode)
+	}
+
+	return results, nil
+}
+
+func (b *Backend) ForceSyncBoardMerkle(entityIDBytes []byte) (bool, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return false, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	return pm.ForceSyncBoardMerkle()
+}
+
+func (b *Backend) GetBoardOplogMerkle(entityIDBytes []byte) (*pkgservice.BackendMerkle, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	return pkgservice.MerkleToBackendMerkle(pm.boardOplogMerkle), nil
+}
+
+func (b *Backend) SubscribeBoardOplogs(ctx context.Context, entityIDBytes []byte) (*rpc.Subscription, error) {
+	entityID, err := types.UnmarshalTextPttID(entityIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgservice.SubscribeOplogEvents(ctx, b.SPM().EventMux(), entityID)
+}
+
+func (b *Backend) UploadFile(entityIDBytes []byte, filename []byte, bytes []byte) (*BackendUploadFile, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	media, err := pm.UploadFile(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return mediaToBackendUploadFile(media), nil
+}
+
+func (b *Backend) GetFile(entityIDBytes []byte, mediaIDBytes []byte) (*BackendGetFile, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	mediaID, err := types.UnmarshalTextPttID(mediaIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+	if mediaID == nil {
+		return nil, types.ErrInvalidID
+	}
 
 	f, err := pm.GetMedia(mediaID)
 	if err != nil {
@@ -561,6 +1345,200 @@ func (b *Backend) GetFile(entityIDBytes []byte, mediaIDBytes []byte) (*BackendGe
 	return mediaToBackendGetFile(f), nil
 }
 
+func (b *Backend) CreateUpload(entityIDBytes []byte) (*BackendUpload, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	myID := b.Ptt().GetMyEntity().GetID()
+	if !pm.IsUploadable(myID) {
+		return nil, types.ErrInvalidID
+	}
+
+	entityID := pm.Entity().GetID()
+	uploadID, err := createUpload(entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackendUpload{ID: uploadID, BoardID: entityID}, nil
+}
+
+func (b *Backend) GetUpload(entityIDBytes []byte, uploadIDBytes []byte) (*BackendUpload, error) {
+
+	entityID, uploadID, err := b.uploadIDs(entityIDBytes, uploadIDBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := getUploadOffset(entityID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackendUpload{ID: uploadID, BoardID: entityID, Offset: offset}, nil
+}
+
+func (b *Backend) UploadChunk(entityIDBytes []byte, uploadIDBytes []byte, offset int64, bytes []byte) (*BackendUpload, error) {
+
+	if len(bytes) > MaxFileChunkSize {
+		return nil, ErrInvalidChunkSize
+	}
+
+	entityID, uploadID, err := b.uploadIDs(entityIDBytes, uploadIDBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err = writeUploadChunk(entityID, uploadID, offset, bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackendUpload{ID: uploadID, BoardID: entityID, Offset: offset}, nil
+}
+
+func (b *Backend) CommitUpload(entityIDBytes []byte, uploadIDBytes []byte, filename []byte) (*BackendUploadFile, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	entityID, uploadID, err := b.uploadIDs(entityIDBytes, uploadIDBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := openUpload(entityID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	media, err := pm.UploadFileFromReader(filename, f)
+	if err != nil {
+		return nil, err
+	}
+
+	err = removeUpload(entityID, uploadID)
+	if err != nil {
+		log.Warn("CommitUpload: unable to remove upload", "entity", entityID, "upload", uploadID, "e", err)
+	}
+
+	return mediaToBackendUploadFile(media), nil
+}
+
+func (b *Backend) CancelUpload(entityIDBytes []byte, uploadIDBytes []byte) (bool, error) {
+
+	entityID, uploadID, err := b.uploadIDs(entityIDBytes, uploadIDBytes)
+	if err != nil {
+		return false, err
+	}
+
+	err = removeUpload(entityID, uploadID)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (b *Backend) uploadIDs(entityIDBytes []byte, uploadIDBytes []byte) (*types.PttID, *types.PttID, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	uploadID, err := types.UnmarshalTextPttID(uploadIDBytes, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if uploadID == nil {
+		return nil, nil, types.ErrInvalidID
+	}
+
+	return thePM.Entity().GetID(), uploadID, nil
+}
+
+func (b *Backend) GetFileInfo(entityIDBytes []byte, mediaIDBytes []byte) (*BackendFileInfo, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	mediaID, err := types.UnmarshalTextPttID(mediaIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+	if mediaID == nil {
+		return nil, types.ErrInvalidID
+	}
+
+	media, r, err := pm.GetMediaReader(mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackendFileInfo{
+		ID:        media.ID,
+		BoardID:   media.EntityID,
+		MediaType: media.MediaType,
+		Size:      r.Size(),
+	}, nil
+}
+
+/*
+GetFileChunk gets at most length bytes of the file starting from offset.
+
+Only the blocks covering the chunk are loaded. Buf is empty if offset is beyond the end of the file.
+*/
+func (b *Backend) GetFileChunk(entityIDBytes []byte, mediaIDBytes []byte, offset int64, length int) (*BackendFileChunk, error) {
+
+	if length <= 0 || length > MaxFileChunkSize {
+		return nil, ErrInvalidChunkSize
+	}
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	mediaID, err := types.UnmarshalTextPttID(mediaIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+	if mediaID == nil {
+		return nil, types.ErrInvalidID
+	}
+
+	_, r, err := pm.GetMediaReader(mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.Seek(offset, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, length)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	return &BackendFileChunk{Offset: offset, Buf: buf[:n]}, nil
+}
+
 func (b *Backend) UploadImage(entityIDBytes []byte, fileType string, bytes []byte) (*BackendUploadImg, error) {
 
 	thePM, err := b.EntityIDToPM(entityIDBytes)
@@ -602,6 +1580,51 @@ func (b *Backend) GetImage(entityIDBytes []byte, mediaIDBytes []byte) (*BackendG
 
 }
 
+/*
+GetImageThumbnail gets the smallest thumbnail with the max-width / max-height at least size.
+
+Getting the original image if there is no such thumbnail or the thumbnail is not synced yet.
+*/
+func (b *Backend) GetImageThumbnail(entityIDBytes []byte, mediaIDBytes []byte, size int) (*BackendGetImg, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	mediaID, err := types.UnmarshalTextPttID(mediaIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+	if mediaID == nil {
+		return nil, types.ErrInvalidID
+	}
+
+	media := pkgservice.NewEmptyMedia()
+	pm.SetMediaDB(media)
+	media.SetID(mediaID)
+	err = media.GetByID(false)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnail := media.Thumbnail(size)
+	if thumbnail != nil {
+		thumbnailMedia, err := pm.GetMedia(thumbnail.ID)
+		if err == nil {
+			return mediaToBackendGetImg(thumbnailMedia), nil
+		}
+	}
+
+	media, err = pm.GetMedia(mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mediaToBackendGetImg(media), nil
+}
+
 func (b *Backend) GetArticleSummary(entityIDBytes []byte, articleInfo *BackendArticleSummaryParams) (*ArticleBlock, error) {
 
 	thePM, err := b.EntityIDToPM(entityIDBytes)
@@ -678,7 +1701,17 @@ func (b *Backend) MarkBoardSeen(entityIDBytes []byte) (types.Timestamp, error) {
 	}
 	pm := thePM.(*ProtocolManager)
 
-	return pm.SaveLastSeen(types.ZeroTimestamp)
+	ts, err := pm.SaveLastSeen(types.ZeroTimestamp)
+	if err != nil {
+		return types.ZeroTimestamp, err
+	}
+
+	err = pm.SendReadReceipt(ReadReceiptMsg, nil, ts)
+	if err != nil {
+		log.Warn("MarkBoardSeen: unable to send read-receipt", "e", err, "entity", pm.Entity().IDString())
+	}
+
+	return ts, nil
 }
 
 func (b *Backend) MarkArticleSeen(entityIDBytes []byte, articleIDBytes []byte) (types.Timestamp, error) {
@@ -694,7 +1727,76 @@ func (b *Backend) MarkArticleSeen(entityIDBytes []byte, articleIDBytes []byte) (
 		return types.ZeroTimestamp, err
 	}
 
-	return pm.SaveArticleLastSeen(articleID, types.ZeroTimestamp)
+	ts, err := pm.SaveArticleLastSeen(articleID, types.ZeroTimestamp)
+	if err != nil {
+		return types.ZeroTimestamp, err
+	}
+
+	err = pm.SendReadReceipt(ReadReceiptMsg, articleID, ts)
+	if err != nil {
+		log.Warn("MarkArticleSeen: unable to send read-receipt", "e", err, "entity", pm.Entity().IDString())
+	}
+
+	return ts, nil
+}
+
+/**********
+ * Read-receipt / Typing
+ **********/
+
+func (b *Backend) GetReadReceipts(entityIDBytes []byte, articleIDBytes []byte) ([]*pkgservice.ReadReceipt, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	articleID, err := types.UnmarshalTextPttID(articleIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return pm.GetReadReceipts(articleID), nil
+}
+
+func (b *Backend) SetTyping(entityIDBytes []byte, articleIDBytes []byte, isTyping bool) (bool, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return false, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	articleID, err := types.UnmarshalTextPttID(articleIDBytes, true)
+	if err != nil {
+		return false, err
+	}
+
+	err = pm.SendTyping(TypingMsg, articleID, isTyping)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (b *Backend) SubscribeReadReceipts(ctx context.Context, entityIDBytes []byte) (*rpc.Subscription, error) {
+	entityID, err := types.UnmarshalTextPttID(entityIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgservice.SubscribeReadReceipts(ctx, b.SPM().EventMux(), entityID)
+}
+
+func (b *Backend) SubscribeTypings(ctx context.Context, entityIDBytes []byte) (*rpc.Subscription, error) {
+	entityID, err := types.UnmarshalTextPttID(entityIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgservice.SubscribeTypings(ctx, b.SPM().EventMux(), entityID)
 }
 
 func (b *Backend) SetTitle(entityIDBytes []byte, title []byte) (*BackendGetBoard, error) {
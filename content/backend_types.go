@@ -103,6 +103,120 @@ type BackendCreateReply struct {
 	ContentBlockID *types.PttID `json:"cID"`
 }
 
+func commentToBackendCreateReply(c *Comment) *BackendCreateReply {
+	blockInfo := c.GetBlockInfo()
+	var blockInfoID *types.PttID
+	if blockInfo != nil {
+		blockInfoID = blockInfo.ID
+	}
+
+	return &BackendCreateReply{
+		BoardID:        c.EntityID,
+		ArticleID:      c.ArticleID,
+		CommentID:      c.ParentID,
+		ReplyID:        c.ID,
+		ContentBlockID: blockInfoID,
+	}
+}
+
+type BackendCreateReaction struct {
+	BoardID      *types.PttID `json:"BID"`
+	ArticleID    *types.PttID `json:"AID"`
+	CommentID    *types.PttID `json:"CID"`
+	ReactionID   *types.PttID `json:"RID"`
+	ReactionType ReactionType `json:"t"`
+}
+
+func reactionToBackendCreateReaction(r *Reaction) *BackendCreateReaction {
+	return &BackendCreateReaction{
+		BoardID:      r.EntityID,
+		ArticleID:    r.ArticleID,
+		CommentID:    r.CommentID,
+		ReactionID:   r.ID,
+		ReactionType: r.ReactionType,
+	}
+}
+
+type BackendGetPoll struct {
+	BoardID       *types.PttID    `json:"BID"`
+	PollID        *types.PttID    `json:"PID"`
+	CreatorID     *types.PttID    `json:"CID"`
+	CreateTS      types.Timestamp `json:"CT"`
+	Question      []byte          `json:"Q"`
+	Options       [][]byte        `json:"O"`
+	Deadline      types.Timestamp `json:"D"`
+	IsMultiChoice bool            `json:"M"`
+	Status        types.Status    `json:"S"`
+}
+
+func pollToBackendGetPoll(p *Poll) *BackendGetPoll {
+	return &BackendGetPoll{
+		BoardID:       p.EntityID,
+		PollID:        p.ID,
+		CreatorID:     p.CreatorID,
+		CreateTS:      p.CreateTS,
+		Question:      p.Question,
+		Options:       p.Options,
+		Deadline:      p.Deadline,
+		IsMultiChoice: p.IsMultiChoice,
+		Status:        p.Status,
+	}
+}
+
+type BackendVote struct {
+	BoardID   *types.PttID    `json:"BID"`
+	PollID    *types.PttID    `json:"PID"`
+	VoteID    *types.PttID    `json:"VID"`
+	CreatorID *types.PttID    `json:"CID"`
+	CreateTS  types.Timestamp `json:"CT"`
+	LogID     *types.PttID    `json:"LID"`
+	Choices   []int           `json:"C"`
+	Status    types.Status    `json:"S"`
+}
+
+func voteToBackendVote(v *Vote) *BackendVote {
+	return &BackendVote{
+		BoardID:   v.EntityID,
+		PollID:    v.PollID,
+		VoteID:    v.ID,
+		CreatorID: v.CreatorID,
+		CreateTS:  v.CreateTS,
+		LogID:     v.LogID,
+		Choices:   v.Choices,
+		Status:    v.Status,
+	}
+}
+
+type BackendPollResults struct {
+	Poll     *BackendGetPoll `json:"P"`
+	Counts   []int           `json:"C"`
+	NVoter   int             `json:"N"`
+	Votes    []*BackendVote  `json:"V"`
+	MyVoteID *types.PttID    `json:"MID,omitempty"`
+	IsClosed bool            `json:"c"`
+}
+
+func pollResultsToBackendPollResults(r *PollResults) *BackendPollResults {
+	votes := make([]*BackendVote, len(r.Votes))
+	for i, vote := range r.Votes {
+		votes[i] = voteToBackendVote(vote)
+	}
+
+	var myVoteID *types.PttID
+	if r.MyVote != nil {
+		myVoteID = r.MyVote.ID
+	}
+
+	return &BackendPollResults{
+		Poll:     pollToBackendGetPoll(r.Poll),
+		Counts:   r.Counts,
+		NVoter:   r.NVoter,
+		Votes:    votes,
+		MyVoteID: myVoteID,
+		IsClosed: r.IsClosed,
+	}
+}
+
 type BackendUpdateArticle struct {
 	BoardID        *types.PttID `json:"BID"`
 	ArticleID      *types.PttID `json:"AID"`
@@ -145,6 +259,22 @@ type BackendDeleteComment struct {
 type BackendDeleteReply struct {
 }
 
+type BackendDeleteReaction struct {
+}
+
+type BackendReactionCount struct {
+	ReactionType ReactionType `json:"t"`
+	Count        uint64       `json:"N"`
+	MyReactionID *types.PttID `json:"MID,omitempty"`
+}
+
+type BackendGetReactions struct {
+	BoardID   *types.PttID            `json:"BID"`
+	ArticleID *types.PttID            `json:"AID"`
+	CommentID *types.PttID            `json:"CID"`
+	Reactions []*BackendReactionCount `json:"R"`
+}
+
 type BackendJoinBoard struct {
 }
 
@@ -222,6 +352,7 @@ type BackendGetArticle struct {
 	CommentCreateTS types.Timestamp `json:"c"`
 	LastSeen        types.Timestamp `json:"L"`
 	Status          types.Status    `json:"S"`
+	Tags            []string        `json:"tg,omitempty"`
 }
 
 func articleToBackendGetArticle(a *Article) *BackendGetArticle {
@@ -262,6 +393,7 @@ func articleToBackendGetArticle(a *Article) *BackendGetArticle {
 		CommentCreateTS: commentCreateTS,
 		LastSeen:        lastSeen,
 		Status:          a.Status,
+		Tags:            a.Tags,
 	}
 }
 
@@ -283,32 +415,36 @@ type BackendShowArticleURL struct {
 }
 
 type BackendUploadImg struct {
-	ID      *types.PttID
-	BoardID *types.PttID         `json:"BID"`
-	Type    pkgservice.MediaType `json:"T"`
+	ID         *types.PttID
+	BoardID    *types.PttID                 `json:"BID"`
+	Type       pkgservice.MediaType         `json:"T"`
+	Thumbnails []*pkgservice.MediaThumbnail `json:"t,omitempty"`
 }
 
 func mediaToBackendUploadImg(img *pkgservice.Media) *BackendUploadImg {
 	return &BackendUploadImg{
-		ID:      img.ID,
-		BoardID: img.EntityID,
-		Type:    img.MediaType,
+		ID:         img.ID,
+		BoardID:    img.EntityID,
+		Type:       img.MediaType,
+		Thumbnails: img.Thumbnails,
 	}
 }
 
 type BackendGetImg struct {
-	ID      *types.PttID
-	BoardID *types.PttID         `json:"BID"`
-	Type    pkgservice.MediaType `json:"T"`
-	Buf     []byte               `json:"B"`
+	ID         *types.PttID
+	BoardID    *types.PttID                 `json:"BID"`
+	Type       pkgservice.MediaType         `json:"T"`
+	Buf        []byte                       `json:"B"`
+	Thumbnails []*pkgservice.MediaThumbnail `json:"t,omitempty"`
 }
 
 func mediaToBackendGetImg(img *pkgservice.Media) *BackendGetImg {
 	return &BackendGetImg{
-		ID:      img.ID,
-		BoardID: img.EntityID,
-		Type:    img.MediaType,
-		Buf:     img.Buf,
+		ID:         img.ID,
+		BoardID:    img.EntityID,
+		Type:       img.MediaType,
+		Buf:        img.Buf,
+		Thumbnails: img.Thumbnails,
 	}
 }
 
@@ -340,7 +476,106 @@ func mediaToBackendGetFile(media *pkgservice.Media) *BackendGetFile {
 	}
 }
 
+type BackendUpload struct {
+	ID      *types.PttID
+	BoardID *types.PttID `json:"BID"`
+	Offset  int64        `json:"O"`
+}
+
+type BackendFileInfo struct {
+	ID        *types.PttID
+	BoardID   *types.PttID         `json:"BID"`
+	MediaType pkgservice.MediaType `json:"M"`
+	Size      int64                `json:"S"`
+}
+
+type BackendFileChunk struct {
+	Offset int64  `json:"O"`
+	Buf    []byte `json:"B"`
+}
+
+type BackendInvite struct {
+	ID        *types.PttID
+	CreateTS  types.Timestamp         `json:"CT"`
+	ExpireTS  types.Timestamp         `json:"ET"`
+	MaxUses   int                     `json:"M"`
+	UsedBy    []*pkgservice.InviteUse `json:"U"`
+	IsRevoked bool                    `json:"R"`
+
+	JoinURL *pkgservice.BackendJoinURL `json:"URL"`
+}
+
+func inviteToBackendInvite(invite *pkgservice.Invite, joinURL *pkgservice.BackendJoinURL) *BackendInvite {
+	usedBy := invite.UsedBy
+	if usedBy == nil {
+		usedBy = make([]*pkgservice.InviteUse, 0)
+	}
+
+	return &BackendInvite{
+		ID:        invite.ID,
+		CreateTS:  invite.CreateTS,
+		ExpireTS:  invite.ExpireTS,
+		MaxUses:   invite.MaxUses,
+		UsedBy:    usedBy,
+		IsRevoked: invite.IsRevoked,
+		JoinURL:   joinURL,
+	}
+}
+
+type BackendRole struct {
+	ID       *types.PttID
+	Role     string          `json:"R"`
+	UpdateTS types.Timestamp `json:"UT"`
+}
+
+func masterToBackendRole(master *pkgservice.Master) *BackendRole {
+	return &BackendRole{
+		ID:       master.ID,
+		Role:     RoleMaster,
+		UpdateTS: master.UpdateTS,
+	}
+}
+
+func memberToBackendRole(member *pkgservice.Member) *BackendRole {
+	role := &BackendRole{
+		ID:   member.ID,
+		Role: member.GetRole().String(),
+	}
+	if member.RoleInfo != nil {
+		role.UpdateTS = member.RoleInfo.UpdateTS
+	}
+
+	return role
+}
+
 type BackendArticleSummaryParams struct {
 	ArticleID      string `json:"A"`
 	ContentBlockID string `json:"B"`
 }
+
+type BackendSearchContent struct {
+	ID          *types.PttID    // article-id or comment-id
+	BoardID     *types.PttID    `json:"BID"`
+	ArticleID   *types.PttID    `json:"AID"`
+	ContentType ContentType     `json:"ct"`
+	CreatorID   *types.PttID    `json:"CID"`
+	CreateTS    types.Timestamp `json:"CT"`
+	UpdateTS    types.Timestamp `json:"UT"`
+}
+
+func searchDocToBackendSearchContent(doc *pkgservice.SearchDoc) *BackendSearchContent {
+	articleID := doc.RefID
+	if ContentType(doc.Type) == ContentTypeArticle {
+		articleID = doc.ID
+	}
+
+	return &BackendSearchContent{
+		ID:          doc.ID,
+		BoardID:     doc.EntityID,
+		ArticleID:   articleID,
+		ContentType: ContentType(doc.Type),
+		CreatorID:   doc.CreatorID,
+		CreateTS:    doc.CreateTS,
+		UpdateTS:    doc.UpdateTS,
+	}
+}
@@ -34,6 +34,9 @@ type Board struct {
 
 	Title []byte `json:"T,omitempty"`
 
+	Pins *BoardPins `json:"P,omitempty"`
+	Tags *BoardTags `json:"TG,omitempty"`
+
 	// get from other dbs
 	LastSeen        types.Timestamp `json:"-"`
 	ArticleCreateTS types.Timestamp `json:"-"`
@@ -45,6 +45,17 @@ const (
 	BoardOpTypeUpdateReply
 	BoardOpTypeDeleteReply
 
+	BoardOpTypeCreateReaction
+	BoardOpTypeDeleteReaction
+
+	BoardOpTypeSetRetention
+
+	BoardOpTypeSetPins
+	BoardOpTypeSetTags
+
+	BoardOpTypeCreatePoll
+	BoardOpTypeCreateVote
+
 	NBoardOpType
 )
 
@@ -85,6 +96,8 @@ type BoardOpUpdateArticle struct {
 	MediaIDs []*types.PttID `json:"ms,omitempty"`
 
 	TitleHash []byte `json:"th"`
+
+	Tags []string `json:"tg,omitempty"`
 }
 
 type BoardOpDeleteArticle struct {
@@ -92,6 +105,7 @@ type BoardOpDeleteArticle struct {
 
 type BoardOpCreateComment struct {
 	ArticleID *types.PttID `json:"AID"`
+	ParentID  *types.PttID `json:"pID,omitempty"`
 
 	BlockInfoID *types.PttID   `json:"BID"`
 	Hashs       [][][]byte     `json:"H"`
@@ -121,3 +135,33 @@ type BoardOpUpdateReply struct {
 	Hashs       [][][]byte     `json:"H"`
 	MediaIDs    []*types.PttID `json:"ms,omitempty"`
 }
+
+type BoardOpCreateReaction struct {
+	ArticleID    *types.PttID `json:"AID"`
+	CommentID    *types.PttID `json:"CID,omitempty"`
+	ReactionType ReactionType `json:"t"`
+}
+
+type BoardOpDeleteReaction struct {
+	ArticleID *types.PttID `json:"AID"`
+}
+
+type BoardOpSetPins struct {
+	ArticleIDs []*types.PttID `json:"A"`
+}
+
+type BoardOpSetTags struct {
+	Tags []string `json:"T"`
+}
+
+type BoardOpCreatePoll struct {
+	Question      []byte          `json:"Q"`
+	Options       [][]byte        `json:"O"`
+	Deadline      types.Timestamp `json:"D"`
+	IsMultiChoice bool            `json:"M,omitempty"`
+}
+
+type BoardOpCreateVote struct {
+	PollID  *types.PttID `json:"PID"`
+	Choices []int        `json:"C"`
+}
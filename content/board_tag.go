@@ -0,0 +1,103 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+/*
+BoardPins is the list of the articles pinned to the top of the board by the masters.
+*/
+type BoardPins struct {
+	ArticleIDs []*types.PttID `json:"A"`
+
+	UpdateTS types.Timestamp `json:"UT"`
+	LogID    *types.PttID    `json:"l,omitempty"`
+}
+
+func (p *BoardPins) IsPinned(articleID *types.PttID) bool {
+	if p == nil {
+		return false
+	}
+
+	for _, eachID := range p.ArticleIDs {
+		if *eachID == *articleID {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+BoardTags is the tag-vocabulary of the board defined by the masters.
+*/
+type BoardTags struct {
+	Tags []string `json:"T"`
+
+	UpdateTS types.Timestamp `json:"UT"`
+	LogID    *types.PttID    `json:"l,omitempty"`
+}
+
+func (t *BoardTags) IsDefined(tag string) bool {
+	if t == nil {
+		return false
+	}
+
+	for _, each := range t.Tags {
+		if each == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+ValidateTags validates the length and the uniqueness of the tags.
+*/
+func ValidateTags(tags []string, maxTags int) error {
+	if len(tags) > maxTags {
+		return ErrInvalidTag
+	}
+
+	tagMap := make(map[string]bool)
+	for _, tag := range tags {
+		if len(tag) == 0 || len(tag) > MaxTagLength {
+			return ErrInvalidTag
+		}
+		if tagMap[tag] {
+			return ErrInvalidTag
+		}
+		tagMap[tag] = true
+	}
+
+	return nil
+}
+
+/*
+ArticleHasTag checks whether the article is tagged with the tag.
+*/
+func ArticleHasTag(article *Article, tag string) bool {
+	for _, each := range article.Tags {
+		if each == tag {
+			return true
+		}
+	}
+	return false
+}
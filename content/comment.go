@@ -35,6 +35,9 @@ type Comment struct {
 	ArticleID        *types.PttID `json:"AID"`
 	ArticleCreatorID *types.PttID `json:"aID"`
 
+	// ParentID is the id of the comment that this comment replies to (nil as the top-level comment).
+	ParentID *types.PttID `json:"pID,omitempty"`
+
 	CommentType CommentType `json:"t"`
 }
 
@@ -49,6 +52,7 @@ func NewComment(
 
 	articleID *types.PttID,
 	articleCreatorID *types.PttID,
+	parentID *types.PttID,
 	commentType CommentType,
 
 ) (*Comment, error) {
@@ -67,6 +71,7 @@ func NewComment(
 
 		ArticleID:        articleID,
 		ArticleCreatorID: articleCreatorID,
+		ParentID:         parentID,
 		CommentType:      commentType,
 	}, nil
 }
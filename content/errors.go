@@ -30,4 +30,26 @@ var (
 	ErrInvalidOP = errors.New("invalid op")
 
 	ErrInvalidTitleLength = errors.New("invalid title length")
+
+	ErrInvalidReactionType = errors.New("invalid reaction type")
+
+	ErrInvalidNode = errors.New("invalid node")
+
+	ErrInvalidUploadOffset = errors.New("invalid upload offset")
+
+	ErrInvalidChunkSize = errors.New("invalid chunk size")
+
+	ErrInvalidUploadSize = errors.New("invalid upload size")
+
+	ErrInvalidTag = errors.New("invalid tag")
+
+	ErrInvalidPin = errors.New("invalid pin")
+
+	ErrInvalidPoll = errors.New("invalid poll")
+
+	ErrInvalidVote = errors.New("invalid vote")
+
+	ErrPollClosed = errors.New("poll closed")
+
+	ErrInvalidParent = errors.New("invalid parent")
 )
@@ -17,7 +17,9 @@
 package content
 
 import (
+	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/log"
@@ -103,16 +105,37 @@ const (
 
 	ForceSyncMediaMsg
 	ForceSyncMediaAckMsg
+
+	// sync reaction
+	SyncCreateReactionMsg
+	SyncCreateReactionAckMsg
+
+	ForceSyncReactionMsg
+	ForceSyncReactionAckMsg
+
+	// ephemeral
+	ReadReceiptMsg
+	TypingMsg
+
+	// sync poll
+	SyncCreatePollMsg
+	SyncCreatePollAckMsg
+
+	// sync vote
+	SyncCreateVoteMsg
+	SyncCreateVoteAckMsg
 )
 
 // db
 var (
-	dbKey *pttdb.LDBDatabase = nil
+	dbKey pttdb.DB = nil
+
+	dbBoardCore pttdb.DB         = nil
+	dbBoard     pttdb.IndexBatch = nil
 
-	dbBoardCore *pttdb.LDBDatabase = nil
-	dbBoard     *pttdb.LDBBatch    = nil
+	dbMeta pttdb.DB = nil
 
-	dbMeta *pttdb.LDBDatabase = nil
+	searchIndex *pkgservice.SearchIndex = nil
 
 	DBBoardIdxOplogPrefix    = []byte(".bdig")
 	DBBoardOplogPrefix       = []byte(".bdlg")
@@ -132,6 +155,13 @@ var (
 	DBBooPrefix                    = []byte(".albo")
 	DBCommentPrefix                = []byte(".ctdb")
 	DBCommentIdxPrefix             = []byte(".ctix")
+	DBReactionPrefix               = []byte(".rcdb")
+	DBReactionIdxPrefix            = []byte(".rcix")
+	DBReactionCountPrefix          = []byte(".rccn")
+	DBPollPrefix                   = []byte(".pldb")
+	DBPollIdxPrefix                = []byte(".plix")
+	DBVotePrefix                   = []byte(".vtdb")
+	DBVoteIdxPrefix                = []byte(".vtix")
 	DBReplyPrefix                  = []byte(".rpdb")
 	DBReplyIdxPrefix               = []byte(".rpix")
 	DBImagePrefix                  = []byte(".imdb")
@@ -140,6 +170,8 @@ var (
 	DBMediaIdxPrefix               = []byte(".maix")
 	DBTitlePrefix                  = []byte(".tldb")
 	DBTitleIdxPrefix               = []byte(".tlix")
+	DBSearchPrefix                 = []byte(".srdb")
+	DBSearchDocPrefix              = []byte(".srdc")
 )
 
 // fix
@@ -152,6 +184,11 @@ const (
 	MaxMasters = 1
 )
 
+// role
+const (
+	RoleMaster = "master"
+)
+
 // sync
 const (
 	MaxSyncRandomSeconds = 30
@@ -176,16 +213,50 @@ const (
 	MaxUploadImageHeight = 8192
 )
 
+// file
+const (
+	MaxFileChunkSize = 4194304 // 4MB
+)
+
+// upload
+var (
+	MaxUploadSize       int64 = 1073741824 // 1GB
+	ExpireUploadSeconds int64 = 86400
+
+	uploadDir  = ""
+	uploadLock sync.Mutex
+)
+
 // count
 const (
-	PCommentCount = 12
+	PCommentCount  = 12
+	PReactionCount = 12
+)
+
+// pin / tag
+const (
+	MaxPins = 10
+
+	MaxBoardTags   = 64
+	MaxArticleTags = 8
+	MaxTagLength   = 32
+)
+
+// poll
+const (
+	MaxPollQuestionLength = 1024
+	MaxPollOptions        = 32
+	MaxPollOptionLength   = 256
+
+	// MaxVoteDelaySeconds is the max delay to receive the pending vote after the poll is closed.
+	MaxVoteDelaySeconds = 3600
 )
 
 func InitContent(dataDir string, keystoreDir string) error {
 	var err error
 
 	// db
-	dbBoardCore, err = pttdb.NewLDBDatabase("board", dataDir, 0, 0)
+	dbBoardCore, err = pttdb.NewDatabase("board", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
@@ -195,12 +266,20 @@ func InitContent(dataDir string, keystoreDir string) error {
 		return err
 	}
 
-	dbKey, err = pttdb.NewLDBDatabase("key", keystoreDir, 0, 0)
+	searchIndex = pkgservice.NewSearchIndex(dbBoardCore, DBSearchPrefix, DBSearchDocPrefix)
+
+	dbKey, err = pttdb.NewDatabase("key", keystoreDir, 0, 0)
 	if err != nil {
 		return err
 	}
 
-	dbMeta, err = pttdb.NewLDBDatabase("contentmeta", dataDir, 0, 0)
+	dbMeta, err = pttdb.NewDatabase("contentmeta", dataDir, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	uploadDir = filepath.Join(dataDir, "upload")
+	err = os.MkdirAll(uploadDir, 0700)
 	if err != nil {
 		return err
 	}
@@ -224,6 +303,10 @@ func DefaultTitle(myID *types.PttID, creatorID *types.PttID, myName string) []by
 }
 
 func TeardownContent() {
+	if searchIndex != nil {
+		searchIndex = nil
+	}
+
 	if dbBoard != nil {
 		dbBoard = nil
 	}
@@ -0,0 +1,325 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"encoding/json"
+
+	"github.com/ailabstw/go-pttai/common"
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+/*
+Poll is the poll in the board with the options, the optional deadline (ZeroTimestamp as no deadline),
+and whether multiple options are able to be chosen in a vote.
+*/
+type Poll struct {
+	*pkgservice.BaseObject `json:"b"`
+
+	UpdateTS types.Timestamp `json:"UT"`
+
+	SyncInfo *pkgservice.BaseSyncInfo `json:"s,omitempty"`
+
+	Question      []byte          `json:"Q"`
+	Options       [][]byte        `json:"O"`
+	Deadline      types.Timestamp `json:"D"`
+	IsMultiChoice bool            `json:"M,omitempty"`
+}
+
+func NewPoll(
+	createTS types.Timestamp,
+	creatorID *types.PttID,
+	entityID *types.PttID,
+
+	logID *types.PttID,
+
+	status types.Status,
+
+	question []byte,
+	options [][]byte,
+	deadline types.Timestamp,
+	isMultiChoice bool,
+
+) (*Poll, error) {
+
+	id, err := types.NewPttID()
+	if err != nil {
+		return nil, err
+	}
+
+	o := pkgservice.NewObject(id, createTS, creatorID, entityID, logID, status)
+
+	return &Poll{
+		BaseObject: o,
+
+		UpdateTS: createTS,
+
+		Question:      question,
+		Options:       options,
+		Deadline:      deadline,
+		IsMultiChoice: isMultiChoice,
+	}, nil
+}
+
+func NewEmptyPoll() *Poll {
+	return &Poll{BaseObject: &pkgservice.BaseObject{}}
+}
+
+func PollsToObjs(typedObjs []*Poll) []pkgservice.Object {
+	objs := make([]pkgservice.Object, len(typedObjs))
+	for i, obj := range typedObjs {
+		objs[i] = obj
+	}
+	return objs
+}
+
+func ObjsToPolls(objs []pkgservice.Object) []*Poll {
+	typedObjs := make([]*Poll, len(objs))
+	for i, obj := range objs {
+		typedObjs[i] = obj.(*Poll)
+	}
+	return typedObjs
+}
+
+func (pm *ProtocolManager) SetPollDB(u *Poll) {
+
+	u.SetDB(dbBoard, pm.DBObjLock(), pm.Entity().GetID(), pm.dbPollPrefix, pm.dbPollIdxPrefix, pm.SetBlockInfoDB, pm.SetMediaDB)
+}
+
+/*
+ValidatePoll validates the question, the options and the deadline of the poll.
+*/
+func ValidatePoll(question []byte, options [][]byte, deadline types.Timestamp, createTS types.Timestamp) error {
+	if len(question) == 0 || len(question) > MaxPollQuestionLength {
+		return ErrInvalidPoll
+	}
+
+	if len(options) < 2 || len(options) > MaxPollOptions {
+		return ErrInvalidPoll
+	}
+
+	for _, option := range options {
+		if len(option) == 0 || len(option) > MaxPollOptionLength {
+			return ErrInvalidPoll
+		}
+	}
+
+	if !deadline.IsEqual(types.ZeroTimestamp) && !createTS.IsLess(deadline) {
+		return ErrInvalidPoll
+	}
+
+	return nil
+}
+
+/*
+IsClosed checks whether the poll is closed at ts.
+*/
+func (p *Poll) IsClosed(ts types.Timestamp) bool {
+	if p.Deadline.IsEqual(types.ZeroTimestamp) {
+		return false
+	}
+
+	return !ts.IsLess(p.Deadline)
+}
+
+/*
+IsValidChoices checks whether the choices (the indexes of the options) are valid in the poll.
+Exactly one choice is required in the single-choice poll.
+*/
+func (p *Poll) IsValidChoices(choices []int) bool {
+	if len(choices) == 0 {
+		return false
+	}
+
+	if !p.IsMultiChoice && len(choices) != 1 {
+		return false
+	}
+
+	chosen := make(map[int]bool)
+	for _, choice := range choices {
+		if choice < 0 || choice >= len(p.Options) {
+			return false
+		}
+		if chosen[choice] {
+			return false
+		}
+		chosen[choice] = true
+	}
+
+	return true
+}
+
+func (p *Poll) Save(isLocked bool) error {
+	var err error
+
+	if !isLocked {
+		err = p.Lock()
+		if err != nil {
+			return err
+		}
+		defer p.Unlock()
+	}
+
+	key, err := p.MarshalKey()
+	if err != nil {
+		return err
+	}
+	marshaled, err := p.Marshal()
+	if err != nil {
+		return err
+	}
+
+	idxKey, err := p.IdxKey()
+	if err != nil {
+		return err
+	}
+
+	idx := &pttdb.Index{Keys: [][]byte{key}, UpdateTS: p.UpdateTS}
+
+	kvs := []*pttdb.KeyVal{
+		&pttdb.KeyVal{K: key, V: marshaled},
+	}
+
+	_, err = p.DB().ForcePutAll(idxKey, idx, kvs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *Poll) NewEmptyObj() pkgservice.Object {
+	newObj := NewEmptyPoll()
+	newObj.CloneDB(p.BaseObject)
+	return newObj
+}
+
+func (p *Poll) GetNewObjByID(id *types.PttID, isLocked bool) (pkgservice.Object, error) {
+	newU := p.NewEmptyObj()
+	newU.SetID(id)
+	err := newU.GetByID(isLocked)
+	if err != nil {
+		return nil, err
+	}
+	return newU, nil
+}
+
+func (p *Poll) SetUpdateTS(ts types.Timestamp) {
+	p.UpdateTS = ts
+}
+
+func (p *Poll) GetUpdateTS() types.Timestamp {
+	return p.UpdateTS
+}
+
+func (p *Poll) Get(isLocked bool) error {
+	var err error
+
+	if !isLocked {
+		err = p.RLock()
+		if err != nil {
+			return err
+		}
+		defer p.RUnlock()
+	}
+
+	key, err := p.MarshalKey()
+	if err != nil {
+		return err
+	}
+
+	val, err := p.DB().DBGet(key)
+	if err != nil {
+		return err
+	}
+
+	return p.Unmarshal(val)
+}
+
+func (p *Poll) GetByID(isLocked bool) error {
+	var err error
+
+	val, err := p.GetValueByID(isLocked)
+	if err != nil {
+		return err
+	}
+
+	return p.Unmarshal(val)
+}
+
+func (p *Poll) MarshalKey() ([]byte, error) {
+	marshalTimestamp, err := p.CreateTS.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return common.Concat([][]byte{p.FullDBPrefix(), marshalTimestamp, p.ID[:]})
+}
+
+func (p *Poll) Marshal() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *Poll) Unmarshal(theBytes []byte) error {
+	return json.Unmarshal(theBytes, p)
+}
+
+func (p *Poll) GetSyncInfo() pkgservice.SyncInfo {
+	if p.SyncInfo == nil {
+		return nil
+	}
+	return p.SyncInfo
+}
+
+func (p *Poll) SetSyncInfo(theSyncInfo pkgservice.SyncInfo) error {
+	if theSyncInfo == nil {
+		p.SyncInfo = nil
+		return nil
+	}
+
+	syncInfo, ok := theSyncInfo.(*pkgservice.BaseSyncInfo)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+	p.SyncInfo = syncInfo
+
+	return nil
+}
+
+func (p *Poll) DeleteAll(isLocked bool) error {
+	return p.Delete(isLocked)
+}
+
+func (p *Poll) GetAndDeleteAll(isLocked bool) error {
+	var err error
+	if !isLocked {
+		err = p.Lock()
+		if err != nil {
+			return err
+		}
+		defer p.Unlock()
+	}
+
+	err = p.GetByID(true)
+	if err != nil {
+		return err
+	}
+
+	return p.DeleteAll(true)
+}
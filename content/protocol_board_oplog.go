@@ -47,6 +47,8 @@ func (pm *ProtocolManager) BroadcastBoardOplog(oplog *BoardOplog) error {
 }
 
 func (pm *ProtocolManager) broadcastBoardOplogCore(oplog *pkgservice.BaseOplog) error {
+	pm.PostOplogEvents(pkgservice.OplogEventTypeLog0, []*pkgservice.BaseOplog{oplog})
+
 	return pm.BroadcastOplog(oplog, AddBoardOplogMsg, AddPendingBoardOplogMsg)
 }
 
@@ -0,0 +1,190 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+/*
+setBoardSetting sets the board-setting (pins / tags) by the master.
+
+ 1. validate (alive and is-master).
+ 2. new-oplog with the setting as the op-data.
+ 3. sign oplog.
+ 4. update board if the oplog is valid.
+ 5. save oplog and broadcast.
+*/
+func (pm *ProtocolManager) setBoardSetting(
+	op pkgservice.OpType,
+	opData pkgservice.OpData,
+
+	setWithOplog func(board *Board, oplog *pkgservice.BaseOplog) error,
+) error {
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	board := pm.Entity().(*Board)
+
+	// 1. validate
+	if board.GetStatus() != types.StatusAlive {
+		return types.ErrInvalidStatus
+	}
+
+	if !pm.IsMaster(myID, false) {
+		return types.ErrInvalidID
+	}
+
+	// lock
+	err := board.Lock()
+	if err != nil {
+		return err
+	}
+
+	// 2. oplog
+	theOplog, err := pm.NewBoardOplog(board.ID, op, opData)
+	if err != nil {
+		board.Unlock()
+		return err
+	}
+	oplog := theOplog.GetBaseOplog()
+
+	// 3. sign
+	err = pm.SignOplog(oplog)
+	if err != nil {
+		board.Unlock()
+		return err
+	}
+
+	// 4. update board
+	if oplog.MasterLogID != nil {
+		err = setWithOplog(board, oplog)
+		if err == nil {
+			err = board.Save(true)
+		}
+		if err != nil {
+			board.Unlock()
+			return err
+		}
+
+		oplog.IsSync = true
+	}
+	board.Unlock()
+
+	// 5. oplog
+	err = oplog.Save(false, pm.boardOplogMerkle)
+	if err != nil {
+		return err
+	}
+
+	pm.broadcastBoardOplogCore(oplog)
+
+	return nil
+}
+
+/*
+handleSetBoardSettingLog handles the valid set-pins / set-tags oplog.
+The setting is updated only if the creator is the master and the oplog is newer than the existing setting.
+*/
+func (pm *ProtocolManager) handleSetBoardSettingLog(
+	oplog *pkgservice.BaseOplog,
+	opData pkgservice.OpData,
+
+	getUpdateTS func(board *Board) types.Timestamp,
+	setWithOplog func(board *Board, oplog *pkgservice.BaseOplog) error,
+) ([]*pkgservice.BaseOplog, error) {
+
+	board := pm.Entity().(*Board)
+
+	err := oplog.GetData(opData)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateBoardSettingCreator(oplog, pm.IsMaster)
+	if err != nil {
+		return nil, err
+	}
+
+	// lock
+	err = board.Lock()
+	if err != nil {
+		return nil, err
+	}
+	defer board.Unlock()
+
+	// newer
+	err = validateBoardSettingUpdateTS(oplog, getUpdateTS(board))
+	if err != nil {
+		return nil, err
+	}
+
+	// save
+	err = setWithOplog(board, oplog)
+	if err != nil {
+		return nil, err
+	}
+
+	err = board.Save(true)
+	if err != nil {
+		return nil, err
+	}
+
+	oplog.IsSync = true
+
+	log.Debug("handleSetBoardSettingLog: done", "entity", board.IDString(), "op", oplog.Op)
+
+	return nil, nil
+}
+
+/*
+handlePendingSetBoardSettingLog handles the pending set-pins / set-tags oplog.
+Only the oplogs from the masters are to be signed.
+*/
+func (pm *ProtocolManager) handlePendingSetBoardSettingLog(oplog *pkgservice.BaseOplog) (types.Bool, []*pkgservice.BaseOplog, error) {
+
+	err := validateBoardSettingCreator(oplog, pm.IsMaster)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return true, nil, nil
+}
+
+/*
+validateBoardSettingCreator validates that the set-pins / set-tags oplog is created by the master.
+*/
+func validateBoardSettingCreator(oplog *pkgservice.BaseOplog, isMaster func(id *types.PttID, isLocked bool) bool) error {
+	if oplog.CreatorID == nil || !isMaster(oplog.CreatorID, false) {
+		return types.ErrInvalidID
+	}
+
+	return nil
+}
+
+/*
+validateBoardSettingUpdateTS validates that the set-pins / set-tags oplog is newer than the existing setting.
+The stale oplogs are with ErrNewerOplog (the setting is already with the newer oplog).
+*/
+func validateBoardSettingUpdateTS(oplog *pkgservice.BaseOplog, origTS types.Timestamp) error {
+	if !origTS.IsLess(oplog.UpdateTS) {
+		return pkgservice.ErrNewerOplog
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func tNewBoardSettingOplog(creatorID *types.PttID, ts int64) *pkgservice.BaseOplog {
+	return &pkgservice.BaseOplog{
+		CreatorID: creatorID,
+		UpdateTS:  types.Timestamp{Ts: ts},
+	}
+}
+
+func Test_validateBoardSettingCreator(t *testing.T) {
+	// setup test
+	masterID := &types.PttID{1}
+	memberID := &types.PttID{2}
+
+	isMaster := func(id *types.PttID, isLocked bool) bool {
+		return *id == *masterID
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		oplog   *pkgservice.BaseOplog
+		wantErr error
+	}{
+		{"master", tNewBoardSettingOplog(masterID, 10), nil},
+		{"member", tNewBoardSettingOplog(memberID, 10), types.ErrInvalidID},
+		{"no creator", tNewBoardSettingOplog(nil, 10), types.ErrInvalidID},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBoardSettingCreator(tt.oplog, isMaster)
+			if err != tt.wantErr {
+				t.Errorf("validateBoardSettingCreator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateBoardSettingUpdateTS(t *testing.T) {
+	// setup test
+	masterID := &types.PttID{1}
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		oplog   *pkgservice.BaseOplog
+		origTS  types.Timestamp
+		wantErr error
+	}{
+		{"no setting", tNewBoardSettingOplog(masterID, 10), types.ZeroTimestamp, nil},
+		{"newer", tNewBoardSettingOplog(masterID, 20), types.Timestamp{Ts: 10}, nil},
+		{"stale", tNewBoardSettingOplog(masterID, 10), types.Timestamp{Ts: 20}, pkgservice.ErrNewerOplog},
+		{"same", tNewBoardSettingOplog(masterID, 10), types.Timestamp{Ts: 10}, pkgservice.ErrNewerOplog},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBoardSettingUpdateTS(tt.oplog, tt.origTS)
+			if err != tt.wantErr {
+				t.Errorf("validateBoardSettingUpdateTS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
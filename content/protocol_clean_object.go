@@ -51,6 +51,8 @@ func (pm *ProtocolManager) CleanObject() error {
 		pm.SetArticleDB(article)
 
 		article.DeleteAll(comment, false)
+
+		pm.deleteReactions(article.ID, nil)
 	}
 
 	// comment
@@ -91,5 +93,71 @@ func (pm *ProtocolManager) CleanObject() error {
 		media.DeleteAll(false)
 	}
 
+	// reaction
+	reaction := NewEmptyReaction()
+	pm.SetReactionDB(reaction)
+
+	iter, err = reaction.GetObjIterWithObj(nil, pttdb.ListOrderNext, false)
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+
+	for iter.Next() {
+		val = iter.Value()
+
+		err = json.Unmarshal(val, reaction)
+		if err != nil {
+			continue
+		}
+		pm.SetReactionDB(reaction)
+
+		reaction.DeleteAll(false)
+	}
+
+	// poll
+	poll := NewEmptyPoll()
+	pm.SetPollDB(poll)
+
+	iter, err = poll.GetObjIterWithObj(nil, pttdb.ListOrderNext, false)
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+
+	for iter.Next() {
+		val = iter.Value()
+
+		err = json.Unmarshal(val, poll)
+		if err != nil {
+			continue
+		}
+		pm.SetPollDB(poll)
+
+		poll.DeleteAll(false)
+	}
+
+	// vote
+	vote := NewEmptyVote()
+	pm.SetVoteDB(vote)
+
+	iter, err = vote.GetObjIterWithObj(nil, pttdb.ListOrderNext, false)
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+
+	for iter.Next() {
+		val = iter.Value()
+
+		err = json.Unmarshal(val, vote)
+		if err != nil {
+			continue
+		}
+		pm.SetVoteDB(vote)
+
+		vote.DeleteAll(false)
+	}
+
 	return nil
 }
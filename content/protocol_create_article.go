@@ -154,6 +154,12 @@ func (pm *ProtocolManager) postcreateArticle(theObj pkgservice.Object, oplog *pk
 		return pkgservice.ErrInvalidData
 	}
 
+	// search-index
+	err := pm.indexArticle(article, true)
+	if err != nil {
+		log.Warn("postcreateArticle: unable to index article", "e", err, "entity", pm.Entity().IDString())
+	}
+
 	myID := pm.Ptt().GetMyEntity().GetID()
 
 	entity := pm.Entity().(*Board)
@@ -166,7 +172,7 @@ func (pm *ProtocolManager) postcreateArticle(theObj pkgservice.Object, oplog *pk
 
 	// I can get only my name and my friends' user name
 	accountSPM := pm.Entity().Service().(*Backend).accountBackend.SPM().(*account.ServiceProtocolManager)
-	_, err := accountSPM.GetUserNameByID(article.CreatorID)
+	_, err = accountSPM.GetUserNameByID(article.CreatorID)
 	if err != nil {
 		return nil
 	}
@@ -186,5 +192,7 @@ func (pm *ProtocolManager) postcreateArticle(theObj pkgservice.Object, oplog *pk
 		return err
 	}
 
+	pm.Ptt().EventMux().Post(pttOplog)
+
 	return nil
 }
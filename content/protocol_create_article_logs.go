@@ -29,6 +29,11 @@ func (pm *ProtocolManager) handleCreateArticleLogs(oplog *pkgservice.BaseOplog,
 
 	opData := &BoardOpCreateArticle{}
 
+	err := pm.ValidateRetentionCreateLog(oplog, pm.getRetentionArticles)
+	if err != nil {
+		return nil, err
+	}
+
 	log.Debug("handleCreateArticleLogs: to HandleCreateObjectLog", "oplog", oplog, "obj", oplog.ObjID)
 
 	return pm.HandleCreateObjectLog(
@@ -42,6 +47,11 @@ func (pm *ProtocolManager) handlePendingCreateArticleLogs(oplog *pkgservice.Base
 
 	opData := &BoardOpCreateArticle{}
 
+	err := pm.ValidateRetentionCreateLog(oplog, pm.getRetentionArticles)
+	if err != nil {
+		return false, nil, err
+	}
+
 	log.Debug("handlePendingCreateArticleLogs: to HandleCreateObjectLog", "oplog", oplog, "obj", oplog.ObjID)
 
 	return pm.HandlePendingCreateObjectLog(
@@ -26,6 +26,7 @@ import (
 
 type CreateComment struct {
 	ArticleID   *types.PttID
+	ParentID    *types.PttID
 	CommentType CommentType
 	Comment     [][]byte
 	MediaIDs    []*types.PttID
@@ -44,6 +45,31 @@ func (pm *ProtocolManager) CreateComment(articleID *types.PttID, commentType Com
 		MediaIDs:    mediaIDs,
 	}
 
+	return pm.createComment(data)
+}
+
+/*
+CreateReply creates the comment replying to the comment (the parent) of the article.
+*/
+func (pm *ProtocolManager) CreateReply(articleID *types.PttID, parentID *types.PttID, reply [][]byte, mediaID *types.PttID) (*Comment, error) {
+
+	var mediaIDs []*types.PttID
+	if mediaID != nil {
+		mediaIDs = []*types.PttID{mediaID}
+	}
+	data := &CreateComment{
+		ArticleID:   articleID,
+		ParentID:    parentID,
+		CommentType: CommentTypeNone,
+		Comment:     reply,
+		MediaIDs:    mediaIDs,
+	}
+
+	return pm.createComment(data)
+}
+
+func (pm *ProtocolManager) createComment(data *CreateComment) (*Comment, error) {
+
 	theComment, err := pm.CreateObject(
 		data,
 		BoardOpTypeCreateComment,
@@ -99,9 +125,20 @@ func (pm *ProtocolManager) NewComment(theData pkgservice.CreateData) (pkgservice
 		return nil, nil, err
 	}
 
+	// parent
+	if data.ParentID != nil {
+		parent, err := pm.GetComment(data.ParentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if parent.Status != types.StatusAlive || !reflect.DeepEqual(parent.ArticleID, articleID) {
+			return nil, nil, ErrInvalidParent
+		}
+	}
+
 	opData := &BoardOpCreateComment{}
 
-	theComment, err := NewComment(ts, myID, entityID, nil, types.StatusInit, articleID, article.CreatorID, data.CommentType)
+	theComment, err := NewComment(ts, myID, entityID, nil, types.StatusInit, articleID, article.CreatorID, data.ParentID, data.CommentType)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -144,6 +181,7 @@ func (pm *ProtocolManager) increateComment(theObj pkgservice.Object, theData pkg
 
 	// op-data
 	opData.ArticleID = obj.ArticleID
+	opData.ParentID = obj.ParentID
 	opData.BlockInfoID = blockID
 	opData.Hashs = blockHashs
 	opData.MediaIDs = data.MediaIDs
@@ -166,6 +204,12 @@ func (pm *ProtocolManager) postcreateComment(theObj pkgservice.Object, oplog *pk
 
 	article.IncreaseComment(comment.ID, comment.CommentType, oplog.UpdateTS)
 
+	// search-index
+	err := pm.indexComment(comment, true)
+	if err != nil {
+		log.Warn("postcreateComment: unable to index comment", "e", err, "entity", pm.Entity().IDString())
+	}
+
 	// ptt-oplog
 	myID := pm.Ptt().GetMyEntity().GetID()
 
@@ -192,5 +236,7 @@ func (pm *ProtocolManager) postcreateComment(theObj pkgservice.Object, oplog *pk
 		return err
 	}
 
+	pm.Ptt().EventMux().Post(pttOplog)
+
 	return nil
 }
@@ -86,6 +86,7 @@ func (pm *ProtocolManager) newCommentWithOplog(oplog *pkgservice.BaseOplog, theO
 	pkgservice.NewObjectWithOplog(obj, oplog)
 
 	obj.ArticleID = opData.ArticleID
+	obj.ParentID = opData.ParentID
 
 	// block info
 	blockInfo, err := pkgservice.NewBlockInfo(opData.BlockInfoID, opData.Hashs, opData.MediaIDs, oplog.CreatorID)
@@ -0,0 +1,105 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+type CreatePoll struct {
+	Question      []byte
+	Options       [][]byte
+	Deadline      types.Timestamp
+	IsMultiChoice bool
+}
+
+/*
+CreatePoll creates the poll in the board (deadline as ZeroTimestamp if no deadline).
+*/
+func (pm *ProtocolManager) CreatePoll(question []byte, options [][]byte, deadline types.Timestamp, isMultiChoice bool) (*Poll, error) {
+
+	data := &CreatePoll{
+		Question:      question,
+		Options:       options,
+		Deadline:      deadline,
+		IsMultiChoice: isMultiChoice,
+	}
+
+	thePoll, err := pm.CreateObject(
+		data,
+		BoardOpTypeCreatePoll,
+
+		pm.boardOplogMerkle,
+
+		pm.NewPoll,
+		pm.NewBoardOplogWithTS,
+		nil,
+
+		pm.SetBoardDB,
+		pm.broadcastBoardOplogsCore,
+		pm.broadcastBoardOplogCore,
+
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, ok := thePoll.(*Poll)
+	if !ok {
+		return nil, pkgservice.ErrInvalidData
+	}
+
+	return poll, nil
+}
+
+func (pm *ProtocolManager) NewPoll(theData pkgservice.CreateData) (pkgservice.Object, pkgservice.OpData, error) {
+
+	data, ok := theData.(*CreatePoll)
+	if !ok {
+		return nil, nil, pkgservice.ErrInvalidData
+	}
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	entityID := pm.Entity().GetID()
+
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = ValidatePoll(data.Question, data.Options, data.Deadline, ts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opData := &BoardOpCreatePoll{
+		Question:      data.Question,
+		Options:       data.Options,
+		Deadline:      data.Deadline,
+		IsMultiChoice: data.IsMultiChoice,
+	}
+
+	thePoll, err := NewPoll(ts, myID, entityID, nil, types.StatusInit, data.Question, data.Options, data.Deadline, data.IsMultiChoice)
+	if err != nil {
+		return nil, nil, err
+	}
+	pm.SetPollDB(thePoll)
+
+	return thePoll, opData, nil
+}
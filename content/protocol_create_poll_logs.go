@@ -0,0 +1,122 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func (pm *ProtocolManager) handleCreatePollLogs(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) ([]*pkgservice.BaseOplog, error) {
+	obj := NewEmptyPoll()
+	pm.SetPollDB(obj)
+
+	opData := &BoardOpCreatePoll{}
+
+	return pm.HandleCreateObjectLog(
+		oplog, obj, opData, info,
+		pm.existsInInfoCreatePoll, pm.newPollWithOplog, nil, pm.updateCreatePollInfo)
+}
+
+func (pm *ProtocolManager) handlePendingCreatePollLogs(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) (types.Bool, []*pkgservice.BaseOplog, error) {
+	obj := NewEmptyPoll()
+	pm.SetPollDB(obj)
+
+	opData := &BoardOpCreatePoll{}
+
+	return pm.HandlePendingCreateObjectLog(
+		oplog, obj, opData, info,
+		pm.existsInInfoCreatePoll, pm.newPollWithOplog, nil, pm.updateCreatePollInfo)
+}
+
+func (pm *ProtocolManager) setNewestCreatePollLog(oplog *pkgservice.BaseOplog) (types.Bool, error) {
+	obj := NewEmptyPoll()
+	pm.SetPollDB(obj)
+
+	return pm.SetNewestCreateObjectLog(oplog, obj)
+}
+
+func (pm *ProtocolManager) handleFailedCreatePollLog(oplog *pkgservice.BaseOplog) error {
+
+	obj := NewEmptyPoll()
+	pm.SetPollDB(obj)
+
+	return pm.HandleFailedCreateObjectLog(oplog, obj, nil)
+}
+
+func (pm *ProtocolManager) handleFailedValidCreatePollLog(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) error {
+
+	obj := NewEmptyPoll()
+	pm.SetPollDB(obj)
+
+	return pm.HandleFailedValidCreateObjectLog(oplog, obj, nil)
+}
+
+/**********
+ * Customize
+ **********/
+
+func (pm *ProtocolManager) newPollWithOplog(oplog *pkgservice.BaseOplog, theOpData pkgservice.OpData) pkgservice.Object {
+
+	opData, ok := theOpData.(*BoardOpCreatePoll)
+	if !ok {
+		return nil
+	}
+
+	err := ValidatePoll(opData.Question, opData.Options, opData.Deadline, oplog.CreateTS)
+	if err != nil {
+		return nil
+	}
+
+	obj := NewEmptyPoll()
+	pm.SetPollDB(obj)
+	pkgservice.NewObjectWithOplog(obj, oplog)
+
+	obj.Question = opData.Question
+	obj.Options = opData.Options
+	obj.Deadline = opData.Deadline
+	obj.IsMultiChoice = opData.IsMultiChoice
+
+	return obj
+}
+
+func (pm *ProtocolManager) existsInInfoCreatePoll(oplog *pkgservice.BaseOplog, theInfo pkgservice.ProcessInfo) (bool, error) {
+	info, ok := theInfo.(*ProcessBoardInfo)
+	if !ok {
+		return false, pkgservice.ErrInvalidData
+	}
+
+	objID := oplog.ObjID
+	_, ok = info.CreatePollInfo[*objID]
+	if ok {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (pm *ProtocolManager) updateCreatePollInfo(obj pkgservice.Object, oplog *pkgservice.BaseOplog, theOpData pkgservice.OpData, theInfo pkgservice.ProcessInfo) error {
+	info, ok := theInfo.(*ProcessBoardInfo)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	info.CreatePollInfo[*oplog.ObjID] = oplog
+
+	return nil
+}
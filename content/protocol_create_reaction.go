@@ -0,0 +1,162 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+type CreateReaction struct {
+	ArticleID    *types.PttID
+	CommentID    *types.PttID
+	ReactionType ReactionType
+}
+
+/*
+CreateReaction creates the reaction to the article (commentID as nil) or the comment.
+The existing reaction is returned if I already reacted to the target with the same reaction-type.
+*/
+func (pm *ProtocolManager) CreateReaction(articleID *types.PttID, commentID *types.PttID, reactionType ReactionType) (*Reaction, error) {
+
+	if !reactionType.IsValid() {
+		return nil, ErrInvalidReactionType
+	}
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	origReaction, err := pm.GetReactionByCreator(articleID, commentID, reactionType, myID)
+	if err != nil {
+		return nil, err
+	}
+	if origReaction != nil {
+		return origReaction, nil
+	}
+
+	data := &CreateReaction{
+		ArticleID:    articleID,
+		CommentID:    commentID,
+		ReactionType: reactionType,
+	}
+
+	theReaction, err := pm.CreateObject(
+		data,
+		BoardOpTypeCreateReaction,
+
+		pm.boardOplogMerkle,
+
+		pm.NewReaction,
+		pm.NewBoardOplogWithTS,
+		nil,
+
+		pm.SetBoardDB,
+		pm.broadcastBoardOplogsCore,
+		pm.broadcastBoardOplogCore,
+
+		pm.postcreateReaction,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reaction, ok := theReaction.(*Reaction)
+	if !ok {
+		return nil, pkgservice.ErrInvalidData
+	}
+
+	return reaction, nil
+}
+
+func (pm *ProtocolManager) NewReaction(theData pkgservice.CreateData) (pkgservice.Object, pkgservice.OpData, error) {
+
+	data, ok := theData.(*CreateReaction)
+	if !ok {
+		return nil, nil, pkgservice.ErrInvalidData
+	}
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	entityID := pm.Entity().GetID()
+
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// article
+	article := NewEmptyArticle()
+	pm.SetArticleDB(article)
+	article.SetID(data.ArticleID)
+
+	err = article.GetByID(false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if article.Status != types.StatusAlive {
+		return nil, nil, types.ErrInvalidStatus
+	}
+
+	// comment
+	if data.CommentID != nil {
+		comment, err := pm.GetComment(data.CommentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if comment.Status != types.StatusAlive || !reflect.DeepEqual(comment.ArticleID, data.ArticleID) {
+			return nil, nil, ErrInvalidOP
+		}
+	}
+
+	opData := &BoardOpCreateReaction{
+		ArticleID:    data.ArticleID,
+		CommentID:    data.CommentID,
+		ReactionType: data.ReactionType,
+	}
+
+	theReaction, err := NewReaction(ts, myID, entityID, nil, types.StatusInit, data.ArticleID, data.CommentID, data.ReactionType)
+	if err != nil {
+		return nil, nil, err
+	}
+	pm.SetReactionDB(theReaction)
+
+	return theReaction, opData, nil
+}
+
+func (pm *ProtocolManager) postcreateReaction(theObj pkgservice.Object, oplog *pkgservice.BaseOplog) error {
+
+	reaction, ok := theObj.(*Reaction)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	log.Debug("postcreateReaction: start", "reaction", reaction.ID, "type", reaction.ReactionType)
+
+	entityID := pm.Entity().GetID()
+	targetID := reaction.TargetID()
+
+	count, err := LoadReactionCount(entityID, targetID, reaction.ReactionType)
+	if err != nil {
+		count, err = NewReactionCount(entityID, targetID, reaction.ReactionType, true)
+		if err != nil {
+			return err
+		}
+	}
+	count.Add(reaction.CreatorID[:])
+
+	return count.Save()
+}
@@ -0,0 +1,120 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func (pm *ProtocolManager) handleCreateReactionLogs(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) ([]*pkgservice.BaseOplog, error) {
+	obj := NewEmptyReaction()
+	pm.SetReactionDB(obj)
+
+	opData := &BoardOpCreateReaction{}
+
+	return pm.HandleCreateObjectLog(
+		oplog, obj, opData, info,
+		pm.existsInInfoCreateReaction, pm.newReactionWithOplog, pm.postcreateReaction, pm.updateCreateReactionInfo)
+}
+
+func (pm *ProtocolManager) handlePendingCreateReactionLogs(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) (types.Bool, []*pkgservice.BaseOplog, error) {
+	obj := NewEmptyReaction()
+	pm.SetReactionDB(obj)
+
+	opData := &BoardOpCreateReaction{}
+
+	return pm.HandlePendingCreateObjectLog(
+		oplog, obj, opData, info,
+		pm.existsInInfoCreateReaction, pm.newReactionWithOplog, pm.postcreateReaction, pm.updateCreateReactionInfo)
+}
+
+func (pm *ProtocolManager) setNewestCreateReactionLog(oplog *pkgservice.BaseOplog) (types.Bool, error) {
+	obj := NewEmptyReaction()
+	pm.SetReactionDB(obj)
+
+	return pm.SetNewestCreateObjectLog(oplog, obj)
+}
+
+func (pm *ProtocolManager) handleFailedCreateReactionLog(oplog *pkgservice.BaseOplog) error {
+
+	obj := NewEmptyReaction()
+	pm.SetReactionDB(obj)
+
+	return pm.HandleFailedCreateObjectLog(oplog, obj, nil)
+}
+
+func (pm *ProtocolManager) handleFailedValidCreateReactionLog(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) error {
+
+	obj := NewEmptyReaction()
+	pm.SetReactionDB(obj)
+
+	return pm.HandleFailedValidCreateObjectLog(oplog, obj, nil)
+}
+
+/**********
+ * Customize
+ **********/
+
+func (pm *ProtocolManager) newReactionWithOplog(oplog *pkgservice.BaseOplog, theOpData pkgservice.OpData) pkgservice.Object {
+
+	opData, ok := theOpData.(*BoardOpCreateReaction)
+	if !ok {
+		return nil
+	}
+
+	if !opData.ReactionType.IsValid() {
+		return nil
+	}
+
+	obj := NewEmptyReaction()
+	pm.SetReactionDB(obj)
+	pkgservice.NewObjectWithOplog(obj, oplog)
+
+	obj.ArticleID = opData.ArticleID
+	obj.CommentID = opData.CommentID
+	obj.ReactionType = opData.ReactionType
+
+	return obj
+}
+
+func (pm *ProtocolManager) existsInInfoCreateReaction(oplog *pkgservice.BaseOplog, theInfo pkgservice.ProcessInfo) (bool, error) {
+	info, ok := theInfo.(*ProcessBoardInfo)
+	if !ok {
+		return false, pkgservice.ErrInvalidData
+	}
+
+	objID := oplog.ObjID
+	_, ok = info.CreateReactionInfo[*objID]
+	if ok {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (pm *ProtocolManager) updateCreateReactionInfo(obj pkgservice.Object, oplog *pkgservice.BaseOplog, theOpData pkgservice.OpData, theInfo pkgservice.ProcessInfo) error {
+	info, ok := theInfo.(*ProcessBoardInfo)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	info.CreateReactionInfo[*oplog.ObjID] = oplog
+
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+type CreateVote struct {
+	PollID  *types.PttID
+	Choices []int
+}
+
+/*
+Vote votes the poll with the choices (the indexes of the options).
+Voting again supersedes my previous vote.
+The earlier votes are kept in the db, and only the latest vote of each user is counted (CountLatestVotes).
+*/
+func (pm *ProtocolManager) Vote(pollID *types.PttID, choices []int) (*Vote, error) {
+
+	data := &CreateVote{
+		PollID:  pollID,
+		Choices: choices,
+	}
+
+	theVote, err := pm.CreateObject(
+		data,
+		BoardOpTypeCreateVote,
+
+		pm.boardOplogMerkle,
+
+		pm.NewVote,
+		pm.NewBoardOplogWithTS,
+		nil,
+
+		pm.SetBoardDB,
+		pm.broadcastBoardOplogsCore,
+		pm.broadcastBoardOplogCore,
+
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	vote, ok := theVote.(*Vote)
+	if !ok {
+		return nil, pkgservice.ErrInvalidData
+	}
+
+	return vote, nil
+}
+
+func (pm *ProtocolManager) NewVote(theData pkgservice.CreateData) (pkgservice.Object, pkgservice.OpData, error) {
+
+	data, ok := theData.(*CreateVote)
+	if !ok {
+		return nil, nil, pkgservice.ErrInvalidData
+	}
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	entityID := pm.Entity().GetID()
+
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// poll
+	poll, err := pm.GetPoll(data.PollID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if poll.Status != types.StatusAlive {
+		return nil, nil, types.ErrInvalidStatus
+	}
+	if poll.IsClosed(ts) {
+		return nil, nil, ErrPollClosed
+	}
+	if !poll.IsValidChoices(data.Choices) {
+		return nil, nil, ErrInvalidVote
+	}
+
+	opData := &BoardOpCreateVote{
+		PollID:  data.PollID,
+		Choices: data.Choices,
+	}
+
+	theVote, err := NewVote(ts, myID, entityID, nil, types.StatusInit, data.PollID, data.Choices)
+	if err != nil {
+		return nil, nil, err
+	}
+	pm.SetVoteDB(theVote)
+
+	return theVote, opData, nil
+}
+
+/*
+validatePendingVote checks the create-ts of the pending vote with the poll.
+
+The pending vote is signed by the master only if the vote is created before the deadline,
+and if received within MaxVoteDelaySeconds after the vote is created if the poll is already closed,
+so that the votes are not able to be back-dated after the poll is closed.
+*/
+func validatePendingVote(poll *Poll, createTS types.Timestamp, now types.Timestamp) error {
+	if poll.IsClosed(createTS) {
+		return ErrPollClosed
+	}
+
+	if poll.IsClosed(now) && createTS.Ts+MaxVoteDelaySeconds < now.Ts {
+		return ErrPollClosed
+	}
+
+	return nil
+}
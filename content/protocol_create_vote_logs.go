@@ -0,0 +1,163 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func (pm *ProtocolManager) handleCreateVoteLogs(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) ([]*pkgservice.BaseOplog, error) {
+	obj := NewEmptyVote()
+	pm.SetVoteDB(obj)
+
+	opData := &BoardOpCreateVote{}
+
+	return pm.HandleCreateObjectLog(
+		oplog, obj, opData, info,
+		pm.existsInInfoCreateVote, pm.newVoteWithOplog, nil, pm.updateCreateVoteInfo)
+}
+
+func (pm *ProtocolManager) handlePendingCreateVoteLogs(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) (types.Bool, []*pkgservice.BaseOplog, error) {
+	obj := NewEmptyVote()
+	pm.SetVoteDB(obj)
+
+	opData := &BoardOpCreateVote{}
+
+	err := pm.validatePendingVoteLog(oplog)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return pm.HandlePendingCreateObjectLog(
+		oplog, obj, opData, info,
+		pm.existsInInfoCreateVote, pm.newVoteWithOplog, nil, pm.updateCreateVoteInfo)
+}
+
+/*
+validatePendingVoteLog validates the pending vote with the poll before signing the vote.
+The vote is skipped (to be retried) if the poll is not synced yet.
+*/
+func (pm *ProtocolManager) validatePendingVoteLog(oplog *pkgservice.BaseOplog) error {
+	opData := &BoardOpCreateVote{}
+	err := oplog.GetData(opData)
+	if err != nil {
+		return err
+	}
+	if opData.PollID == nil {
+		return ErrInvalidVote
+	}
+
+	poll, err := pm.GetPoll(opData.PollID)
+	if err != nil {
+		return pkgservice.ErrNewerOplog
+	}
+
+	now, err := types.GetTimestamp()
+	if err != nil {
+		return err
+	}
+
+	err = validatePendingVote(poll, oplog.CreateTS, now)
+	if err != nil {
+		log.Warn("validatePendingVoteLog: invalid vote", "entity", pm.Entity().GetID(), "creator", oplog.CreatorID, "oplog", oplog.ID, "createTS", oplog.CreateTS, "e", err)
+		return pkgservice.ErrSkipOplog
+	}
+
+	return nil
+}
+
+func (pm *ProtocolManager) setNewestCreateVoteLog(oplog *pkgservice.BaseOplog) (types.Bool, error) {
+	obj := NewEmptyVote()
+	pm.SetVoteDB(obj)
+
+	return pm.SetNewestCreateObjectLog(oplog, obj)
+}
+
+func (pm *ProtocolManager) handleFailedCreateVoteLog(oplog *pkgservice.BaseOplog) error {
+
+	obj := NewEmptyVote()
+	pm.SetVoteDB(obj)
+
+	return pm.HandleFailedCreateObjectLog(oplog, obj, nil)
+}
+
+func (pm *ProtocolManager) handleFailedValidCreateVoteLog(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) error {
+
+	obj := NewEmptyVote()
+	pm.SetVoteDB(obj)
+
+	return pm.HandleFailedValidCreateObjectLog(oplog, obj, nil)
+}
+
+/**********
+ * Customize
+ **********/
+
+/*
+newVoteWithOplog creates the vote from the oplog.
+The choices are validated with the poll when counting the votes,
+because the poll may not be synced yet.
+*/
+func (pm *ProtocolManager) newVoteWithOplog(oplog *pkgservice.BaseOplog, theOpData pkgservice.OpData) pkgservice.Object {
+
+	opData, ok := theOpData.(*BoardOpCreateVote)
+	if !ok {
+		return nil
+	}
+
+	if opData.PollID == nil || len(opData.Choices) == 0 || len(opData.Choices) > MaxPollOptions {
+		return nil
+	}
+
+	obj := NewEmptyVote()
+	pm.SetVoteDB(obj)
+	pkgservice.NewObjectWithOplog(obj, oplog)
+
+	obj.PollID = opData.PollID
+	obj.Choices = opData.Choices
+
+	return obj
+}
+
+func (pm *ProtocolManager) existsInInfoCreateVote(oplog *pkgservice.BaseOplog, theInfo pkgservice.ProcessInfo) (bool, error) {
+	info, ok := theInfo.(*ProcessBoardInfo)
+	if !ok {
+		return false, pkgservice.ErrInvalidData
+	}
+
+	objID := oplog.ObjID
+	_, ok = info.CreateVoteInfo[*objID]
+	if ok {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (pm *ProtocolManager) updateCreateVoteInfo(obj pkgservice.Object, oplog *pkgservice.BaseOplog, theOpData pkgservice.OpData, theInfo pkgservice.ProcessInfo) error {
+	info, ok := theInfo.(*ProcessBoardInfo)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	info.CreateVoteInfo[*oplog.ObjID] = oplog
+
+	return nil
+}
@@ -18,6 +18,7 @@ package content
 
 import (
 	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
 	pkgservice "github.com/ailabstw/go-pttai/service"
 )
 
@@ -64,6 +65,12 @@ func (pm *ProtocolManager) postdeleteArticle(id *types.PttID, oplog *pkgservice.
 		return pkgservice.ErrInvalidData
 	}
 
+	// search-index
+	err := removeSearchDoc(id)
+	if err != nil {
+		log.Warn("postdeleteArticle: unable to remove search-doc", "e", err, "entity", pm.Entity().IDString())
+	}
+
 	// comment
 	comment := NewEmptyComment()
 	pm.SetCommentDB(comment)
@@ -71,5 +78,11 @@ func (pm *ProtocolManager) postdeleteArticle(id *types.PttID, oplog *pkgservice.
 	// postdelete
 	article.Postdelete(comment, true)
 
+	// reaction
+	err = pm.deleteReactions(id, nil)
+	if err != nil {
+		log.Warn("postdeleteArticle: unable to delete reactions", "e", err, "entity", pm.Entity().IDString())
+	}
+
 	return nil
 }
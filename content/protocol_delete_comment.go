@@ -18,6 +18,7 @@ package content
 
 import (
 	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
 	pkgservice "github.com/ailabstw/go-pttai/service"
 )
 
@@ -59,5 +60,22 @@ func (pm *ProtocolManager) setPendingDeleteCommentSyncInfo(obj pkgservice.Object
 
 func (pm *ProtocolManager) postdeleteComment(id *types.PttID, oplog *pkgservice.BaseOplog, opData pkgservice.OpData, obj pkgservice.Object, blockInfo *pkgservice.BlockInfo) error {
 
+	// search-index
+	err := removeSearchDoc(id)
+	if err != nil {
+		log.Warn("postdeleteComment: unable to remove search-doc", "e", err, "entity", pm.Entity().IDString())
+	}
+
+	// reaction
+	comment, ok := obj.(*Comment)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	err = pm.deleteReactions(comment.ArticleID, id)
+	if err != nil {
+		log.Warn("postdeleteComment: unable to delete reactions", "e", err, "entity", pm.Entity().IDString())
+	}
+
 	return nil
 }
@@ -0,0 +1,86 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func (pm *ProtocolManager) DeleteReaction(id *types.PttID) error {
+
+	reaction := NewEmptyReaction()
+	pm.SetReactionDB(reaction)
+
+	reaction.SetID(id)
+	err := reaction.GetByID(false)
+	if err != nil {
+		return err
+	}
+
+	opData := &BoardOpDeleteReaction{
+		ArticleID: reaction.ArticleID,
+	}
+
+	return pm.DeleteObject(
+		id,
+
+		BoardOpTypeDeleteReaction,
+		reaction,
+		opData,
+
+		pm.boardOplogMerkle,
+
+		pm.SetBoardDB,
+		pm.NewBoardOplog,
+		nil,
+		pm.setPendingDeleteReactionSyncInfo,
+
+		pm.broadcastBoardOplogCore,
+		pm.postdeleteReaction,
+	)
+}
+
+func (pm *ProtocolManager) setPendingDeleteReactionSyncInfo(obj pkgservice.Object, status types.Status, oplog *pkgservice.BaseOplog) error {
+
+	syncInfo := &pkgservice.BaseSyncInfo{}
+	syncInfo.InitWithOplog(status, oplog)
+
+	obj.SetSyncInfo(syncInfo)
+
+	return nil
+}
+
+/*
+postdeleteReaction re-counts the reactions of the target,
+because the deleted user can not be removed from the count.
+*/
+func (pm *ProtocolManager) postdeleteReaction(id *types.PttID, oplog *pkgservice.BaseOplog, opData pkgservice.OpData, obj pkgservice.Object, blockInfo *pkgservice.BlockInfo) error {
+
+	reaction, ok := obj.(*Reaction)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	err := pm.recountReaction(reaction.ArticleID, reaction.TargetID(), reaction.ReactionType, id)
+	if err != nil {
+		log.Warn("postdeleteReaction: unable to recount reaction", "e", err, "entity", pm.Entity().IDString())
+	}
+
+	return nil
+}
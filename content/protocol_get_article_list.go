@@ -24,15 +24,54 @@ import (
 )
 
 func (pm *ProtocolManager) GetArticleList(startID *types.PttID, limit int, listOrder pttdb.ListOrder, isLocked bool) ([]*Article, error) {
+	return pm.GetArticleListByTag(startID, limit, listOrder, "", isLocked)
+}
+
+/*
+GetArticleListByTag gets the list of the articles tagged with the tag (all the articles if tag is empty).
+*/
+func (pm *ProtocolManager) GetArticleListByTag(startID *types.PttID, limit int, listOrder pttdb.ListOrder, tag string, isLocked bool) ([]*Article, error) {
 	obj := NewEmptyArticle()
 	pm.SetArticleDB(obj)
 
-	objs, err := pkgservice.GetObjList(obj, startID, limit, listOrder, isLocked)
+	typedObjs, err := getArticleListByTag(obj, startID, limit, listOrder, tag, isLocked)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pm.loadArticleListInfo(typedObjs)
+	if err != nil {
+		return nil, err
+	}
+
+	return typedObjs, nil
+}
+
+/*
+getArticleListByTag gets the list of the articles tagged with the tag from the db of obj.
+The limit is applied to the tagged articles.
+*/
+func getArticleListByTag(obj *Article, startID *types.PttID, limit int, listOrder pttdb.ListOrder, tag string, isLocked bool) ([]*Article, error) {
+
+	var filter func(obj pkgservice.Object) bool
+	if tag != "" {
+		filter = func(obj pkgservice.Object) bool {
+			return ArticleHasTag(obj.(*Article), tag)
+		}
+	}
+
+	objs, err := pkgservice.GetObjListWithFilter(obj, startID, limit, listOrder, filter, isLocked)
 	if err != nil {
 		return nil, err
 	}
-	typedObjs := ObjsToArticles(objs)
 
+	return ObjsToArticles(objs), nil
+}
+
+/*
+loadArticleListInfo loads the last-seen, comment-create-ts and push / boo counts of the articles.
+*/
+func (pm *ProtocolManager) loadArticleListInfo(typedObjs []*Article) error {
 	for _, typedObj := range typedObjs {
 
 		ts, err := typedObj.LoadLastSeen()
@@ -45,7 +84,7 @@ func (pm *ProtocolManager) GetArticleList(startID *types.PttID, limit int, listO
 		ts, err = typedObj.LoadCommentCreateTS()
 		log.Debug("GetArticleList: after LoadCommentCreateTS", "e", err, "ts", ts)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		typedObj.CommentCreateTS = ts
 
@@ -53,5 +92,5 @@ func (pm *ProtocolManager) GetArticleList(startID *types.PttID, limit int, listO
 		typedObj.NBoo, _ = typedObj.LoadBoo()
 	}
 
-	return typedObjs, nil
+	return nil
 }
@@ -0,0 +1,113 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+)
+
+func setupArticleListTest(t *testing.T, tagsList ...[]string) (*Article, []*types.PttID, func()) {
+	dbCore, err := pttdb.NewLDBDatabase("article", "./test.out", 0, 0)
+	if err != nil {
+		t.Fatalf("NewLDBDatabase: e: %v", err)
+	}
+	db, _ := pttdb.NewLDBBatch(dbCore)
+	dbLock, _ := types.NewLockMap(10)
+	entityID, _ := types.NewPttID()
+
+	setDB := func(a *Article) {
+		a.SetDB(db, dbLock, entityID, []byte(".tart"), []byte(".tari"), nil, nil)
+	}
+
+	ids := make([]*types.PttID, len(tagsList))
+	for i, tags := range tagsList {
+		a, _ := NewArticle(types.Timestamp{Ts: int64(i + 1)}, entityID, entityID, nil, types.StatusAlive, []byte("title"))
+		a.Tags = tags
+		setDB(a)
+
+		err = a.Save(false)
+		if err != nil {
+			t.Fatalf("Save: e: %v", err)
+		}
+		ids[i] = a.ID
+	}
+
+	obj := NewEmptyArticle()
+	setDB(obj)
+
+	return obj, ids, func() {
+		dbCore.Close()
+		os.RemoveAll("./test.out")
+	}
+}
+
+func Test_getArticleListByTag(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	obj, ids, teardownArticleListTest := setupArticleListTest(
+		t,
+		[]string{"t1"},
+		[]string{"t2"},
+		[]string{"t1", "t2"},
+		nil,
+		[]string{"t1"},
+	)
+	defer teardownArticleListTest()
+
+	// prepare test-cases
+	tests := []struct {
+		name      string
+		startID   *types.PttID
+		limit     int
+		listOrder pttdb.ListOrder
+		tag       string
+		want      []*types.PttID
+	}{
+		{"all", nil, 0, pttdb.ListOrderNext, "", ids},
+		{"tag", nil, 0, pttdb.ListOrderNext, "t1", []*types.PttID{ids[0], ids[2], ids[4]}},
+		{"limit on the tagged", nil, 2, pttdb.ListOrderNext, "t1", []*types.PttID{ids[0], ids[2]}},
+		{"prev", nil, 0, pttdb.ListOrderPrev, "t1", []*types.PttID{ids[4], ids[2], ids[0]}},
+		{"start-id", ids[2], 0, pttdb.ListOrderNext, "t2", []*types.PttID{ids[2]}},
+		{"undefined tag", nil, 0, pttdb.ListOrderNext, "t3", []*types.PttID{}},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles, err := getArticleListByTag(obj, tt.startID, tt.limit, tt.listOrder, tt.tag, false)
+			if err != nil {
+				t.Errorf("getArticleListByTag() error = %v", err)
+				return
+			}
+
+			got := make([]*types.PttID, len(articles))
+			for i, article := range articles {
+				got[i] = article.ID
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getArticleListByTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,7 +16,12 @@
 
 package content
 
-import "github.com/ailabstw/go-pttai/common/types"
+import (
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+)
 
 func (pm *ProtocolManager) GetComment(commentID *types.PttID) (*Comment, error) {
 	comment := NewEmptyComment()
@@ -30,3 +35,90 @@ func (pm *ProtocolManager) GetComment(commentID *types.PttID) (*Comment, error)
 
 	return comment, nil
 }
+
+/*
+CommentThread is the comment with the replies to the comment.
+*/
+type CommentThread struct {
+	Comment *ArticleBlock    `json:"C"`
+	Replies []*CommentThread `json:"R,omitempty"`
+}
+
+/*
+GetCommentList gets the comment-threads of the article.
+
+The top-level comments are listed with the listOrder, starting from startID (inclusive), with at most limit threads (0 as no limit).
+The replies are always listed in the created order.
+*/
+func (pm *ProtocolManager) GetCommentList(articleID *types.PttID, startID *types.PttID, limit int, listOrder pttdb.ListOrder) ([]*CommentThread, error) {
+
+	blocks, _, err := pm.getArticleBlockListCommentAndReplyBlocks(articleID, nil, ContentTypeComment, 0, pttdb.ListOrderNext)
+	if err == ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	threads := buildCommentThreads(blocks)
+
+	return pageCommentThreads(threads, startID, limit, listOrder), nil
+}
+
+/*
+buildCommentThreads builds the comment-threads from the comment-blocks in the created order.
+The comment replying to the comment not in the blocks is treated as the top-level comment.
+*/
+func buildCommentThreads(blocks []*ArticleBlock) []*CommentThread {
+	threadMap := make(map[types.PttID]*CommentThread)
+	for _, block := range blocks {
+		threadMap[*block.RefID] = &CommentThread{Comment: block}
+	}
+
+	threads := make([]*CommentThread, 0)
+	for _, block := range blocks {
+		thread := threadMap[*block.RefID]
+
+		var parent *CommentThread
+		if block.ParentID != nil {
+			parent = threadMap[*block.ParentID]
+		}
+		if parent == nil || parent == thread {
+			threads = append(threads, thread)
+			continue
+		}
+
+		parent.Replies = append(parent.Replies, thread)
+	}
+
+	return threads
+}
+
+func pageCommentThreads(threads []*CommentThread, startID *types.PttID, limit int, listOrder pttdb.ListOrder) []*CommentThread {
+	lenThreads := len(threads)
+	ordered := make([]*CommentThread, lenThreads)
+	for i, thread := range threads {
+		if listOrder == pttdb.ListOrderPrev {
+			ordered[lenThreads-1-i] = thread
+		} else {
+			ordered[i] = thread
+		}
+	}
+
+	if startID != nil {
+		startIdx := len(ordered)
+		for i, thread := range ordered {
+			if reflect.DeepEqual(thread.Comment.RefID, startID) {
+				startIdx = i
+				break
+			}
+		}
+		ordered = ordered[startIdx:]
+	}
+
+	if limit > 0 && len(ordered) > limit {
+		ordered = ordered[:limit]
+	}
+
+	return ordered
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+)
+
+func tNewCommentBlock(id byte, parentID *types.PttID, creatorID *types.PttID) *ArticleBlock {
+	return &ArticleBlock{
+		RefID:       &types.PttID{id},
+		ContentType: ContentTypeComment,
+		ParentID:    parentID,
+		CreatorID:   creatorID,
+	}
+}
+
+func tCommentThreadIDs(threads []*CommentThread) []interface{} {
+	ids := make([]interface{}, len(threads))
+	for i, thread := range threads {
+		id := thread.Comment.RefID[0]
+		if len(thread.Replies) == 0 {
+			ids[i] = id
+			continue
+		}
+		ids[i] = []interface{}{id, tCommentThreadIDs(thread.Replies)}
+	}
+	return ids
+}
+
+func Test_buildCommentThreads(t *testing.T) {
+	// setup test
+	c1 := tNewCommentBlock(1, nil, nil)
+	c2 := tNewCommentBlock(2, nil, nil)
+	r3 := tNewCommentBlock(3, c1.RefID, nil)
+	r4 := tNewCommentBlock(4, r3.RefID, nil)
+	r5 := tNewCommentBlock(5, c1.RefID, nil)
+	orphan6 := tNewCommentBlock(6, &types.PttID{100}, nil)
+	self7 := tNewCommentBlock(7, &types.PttID{7}, nil)
+
+	// prepare test-cases
+	tests := []struct {
+		name   string
+		blocks []*ArticleBlock
+		want   []interface{}
+	}{
+		{"empty", nil, []interface{}{}},
+		{"top-level", []*ArticleBlock{c1, c2}, []interface{}{byte(1), byte(2)}},
+		{
+			"nested",
+			[]*ArticleBlock{c1, c2, r3, r4, r5},
+			[]interface{}{
+				[]interface{}{byte(1), []interface{}{[]interface{}{byte(3), []interface{}{byte(4)}}, byte(5)}},
+				byte(2),
+			},
+		},
+		{"orphan", []*ArticleBlock{c1, orphan6}, []interface{}{byte(1), byte(6)}},
+		{"self", []*ArticleBlock{self7}, []interface{}{byte(7)}},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tCommentThreadIDs(buildCommentThreads(tt.blocks)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildCommentThreads() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pageCommentThreads(t *testing.T) {
+	// setup test
+	threads := buildCommentThreads([]*ArticleBlock{
+		tNewCommentBlock(1, nil, nil),
+		tNewCommentBlock(2, nil, nil),
+		tNewCommentBlock(3, nil, nil),
+		tNewCommentBlock(4, &types.PttID{2}, nil),
+	})
+
+	// prepare test-cases
+	tests := []struct {
+		name      string
+		startID   *types.PttID
+		limit     int
+		listOrder pttdb.ListOrder
+		want      []interface{}
+	}{
+		{"all", nil, 0, pttdb.ListOrderNext, []interface{}{byte(1), []interface{}{byte(2), []interface{}{byte(4)}}, byte(3)}},
+		{"limit", nil, 1, pttdb.ListOrderNext, []interface{}{byte(1)}},
+		{"start", &types.PttID{2}, 0, pttdb.ListOrderNext, []interface{}{[]interface{}{byte(2), []interface{}{byte(4)}}, byte(3)}},
+		{"prev", nil, 2, pttdb.ListOrderPrev, []interface{}{byte(3), []interface{}{byte(2), []interface{}{byte(4)}}}},
+		{"prev start", &types.PttID{2}, 0, pttdb.ListOrderPrev, []interface{}{[]interface{}{byte(2), []interface{}{byte(4)}}, byte(1)}},
+		{"start is reply", &types.PttID{4}, 0, pttdb.ListOrderNext, []interface{}{}},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tCommentThreadIDs(pageCommentThreads(threads, tt.startID, tt.limit, tt.listOrder)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pageCommentThreads() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_filterCommentThreads(t *testing.T) {
+	// setup test
+	user1 := &types.PttID{11}
+	blockedUser := &types.PttID{12}
+
+	threads := buildCommentThreads([]*ArticleBlock{
+		tNewCommentBlock(1, nil, user1),
+		tNewCommentBlock(2, nil, blockedUser),
+		tNewCommentBlock(3, &types.PttID{1}, blockedUser),
+		tNewCommentBlock(4, &types.PttID{1}, user1),
+		tNewCommentBlock(5, &types.PttID{2}, user1),
+	})
+
+	isBlocked := func(id *types.PttID) bool {
+		return reflect.DeepEqual(id, blockedUser)
+	}
+
+	// run test
+	want := []interface{}{[]interface{}{byte(1), []interface{}{byte(4)}}}
+	if got := tCommentThreadIDs(filterCommentThreads(threads, isBlocked)); !reflect.DeepEqual(got, want) {
+		t.Errorf("filterCommentThreads() = %v, want %v", got, want)
+	}
+}
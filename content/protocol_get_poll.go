@@ -0,0 +1,192 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"bytes"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+/*
+PollResults is the results of the poll counted from the latest valid vote of each user.
+The votes are the signed oplogs, and are able to be verified with the log-ids.
+*/
+type PollResults struct {
+	Poll *Poll `json:"P"`
+
+	Counts   []int   `json:"C"`
+	NVoter   int     `json:"N"`
+	Votes    []*Vote `json:"V"`
+	MyVote   *Vote   `json:"M,omitempty"`
+	IsClosed bool    `json:"c"`
+}
+
+func (pm *ProtocolManager) GetPoll(pollID *types.PttID) (*Poll, error) {
+	poll := NewEmptyPoll()
+	pm.SetPollDB(poll)
+	poll.SetID(pollID)
+
+	err := poll.GetByID(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+func (pm *ProtocolManager) GetPollList(startID *types.PttID, limit int, listOrder pttdb.ListOrder) ([]*Poll, error) {
+	obj := NewEmptyPoll()
+	pm.SetPollDB(obj)
+
+	objs, err := pkgservice.GetObjList(obj, startID, limit, listOrder, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return ObjsToPolls(objs), nil
+}
+
+/*
+GetVoteList gets the votes of the poll.
+*/
+func (pm *ProtocolManager) GetVoteList(pollID *types.PttID) ([]*Vote, error) {
+	vote := NewEmptyVote()
+	pm.SetVoteDB(vote)
+
+	iter, err := vote.GetCrossObjIterWithObj(pollID[:], nil, pttdb.ListOrderNext, false)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+
+	votes := make([]*Vote, 0)
+	for iter.Next() {
+		eachVote := NewEmptyVote()
+		err = eachVote.Unmarshal(iter.Value())
+		if err != nil {
+			continue
+		}
+		pm.SetVoteDB(eachVote)
+
+		votes = append(votes, eachVote)
+	}
+
+	return votes, nil
+}
+
+/*
+GetPollResults counts the votes of the poll.
+
+ 1. only the alive votes with the valid choices before the deadline are counted.
+ 2. only the latest vote of each user is counted (deduplicated by the creator-id).
+ 3. the votes from the users blocked by me are not counted.
+*/
+func (pm *ProtocolManager) GetPollResults(pollID *types.PttID) (*PollResults, error) {
+	poll, err := pm.GetPoll(pollID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.Status != types.StatusAlive {
+		return nil, types.ErrInvalidStatus
+	}
+
+	votes, err := pm.GetVoteList(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	blockList := pm.Ptt().GetMyEntity().GetBlockList()
+	latestVotes := CountLatestVotes(poll, votes, blockList.IsBlocked)
+
+	results := &PollResults{
+		Poll:   poll,
+		Counts: make([]int, len(poll.Options)),
+		Votes:  make([]*Vote, 0, len(latestVotes)),
+	}
+
+	now, err := types.GetTimestamp()
+	if err != nil {
+		return nil, err
+	}
+	results.IsClosed = poll.IsClosed(now)
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	for _, vote := range votes {
+		if latestVotes[*vote.CreatorID] != vote {
+			continue
+		}
+
+		for _, choice := range vote.Choices {
+			results.Counts[choice]++
+		}
+		results.Votes = append(results.Votes, vote)
+
+		if *vote.CreatorID == *myID {
+			results.MyVote = vote
+		}
+	}
+	results.NVoter = len(results.Votes)
+
+	return results, nil
+}
+
+/*
+CountLatestVotes gets the latest valid vote of each user to the poll.
+The votes with the same create-ts are ordered by the ids to be deterministic among the nodes.
+
+isBlocked: the votes from the blocked users are skipped (all the votes are counted if nil).
+*/
+func CountLatestVotes(poll *Poll, votes []*Vote, isBlocked func(id *types.PttID) bool) map[types.PttID]*Vote {
+	latestVotes := make(map[types.PttID]*Vote)
+	for _, vote := range votes {
+		if vote.Status != types.StatusAlive {
+			continue
+		}
+		if isBlocked != nil && isBlocked(vote.CreatorID) {
+			continue
+		}
+		if vote.PollID == nil || *vote.PollID != *poll.ID {
+			continue
+		}
+		if poll.IsClosed(vote.CreateTS) || !poll.IsValidChoices(vote.Choices) {
+			continue
+		}
+
+		origVote, ok := latestVotes[*vote.CreatorID]
+		if ok && !isLaterVote(vote, origVote) {
+			continue
+		}
+
+		latestVotes[*vote.CreatorID] = vote
+	}
+
+	return latestVotes
+}
+
+func isLaterVote(vote *Vote, origVote *Vote) bool {
+	if origVote.CreateTS.IsLess(vote.CreateTS) {
+		return true
+	}
+	if vote.CreateTS.IsLess(origVote.CreateTS) {
+		return false
+	}
+
+	return bytes.Compare(origVote.ID[:], vote.ID[:]) < 0
+}
@@ -0,0 +1,198 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func tNewVote(id byte, creatorID *types.PttID, pollID *types.PttID, ts int64, status types.Status, choices ...int) *Vote {
+	return &Vote{
+		BaseObject: &pkgservice.BaseObject{
+			ID:        &types.PttID{id},
+			CreateTS:  types.Timestamp{Ts: ts},
+			CreatorID: creatorID,
+			Status:    status,
+		},
+		PollID:  pollID,
+		Choices: choices,
+	}
+}
+
+func Test_isLaterVote(t *testing.T) {
+	// setup test
+	userID := &types.PttID{1}
+	pollID := &types.PttID{2}
+
+	// prepare test-cases
+	tests := []struct {
+		name     string
+		vote     *Vote
+		origVote *Vote
+		want     bool
+	}{
+		{"later", tNewVote(1, userID, pollID, 20, types.StatusAlive), tNewVote(2, userID, pollID, 10, types.StatusAlive), true},
+		{"earlier", tNewVote(1, userID, pollID, 10, types.StatusAlive), tNewVote(2, userID, pollID, 20, types.StatusAlive), false},
+		{"same-ts larger-id", tNewVote(2, userID, pollID, 10, types.StatusAlive), tNewVote(1, userID, pollID, 10, types.StatusAlive), true},
+		{"same-ts smaller-id", tNewVote(1, userID, pollID, 10, types.StatusAlive), tNewVote(2, userID, pollID, 10, types.StatusAlive), false},
+		{"same", tNewVote(1, userID, pollID, 10, types.StatusAlive), tNewVote(1, userID, pollID, 10, types.StatusAlive), false},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLaterVote(tt.vote, tt.origVote); got != tt.want {
+				t.Errorf("isLaterVote() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountLatestVotes(t *testing.T) {
+	// setup test
+	user1 := &types.PttID{1}
+	user2 := &types.PttID{2}
+	user3 := &types.PttID{3}
+	pollID := &types.PttID{10}
+	otherPollID := &types.PttID{11}
+
+	poll := &Poll{
+		BaseObject: &pkgservice.BaseObject{ID: pollID},
+		Options:    [][]byte{[]byte("a"), []byte("b"), []byte("c")},
+		Deadline:   types.Timestamp{Ts: 100},
+	}
+	multiPoll := &Poll{
+		BaseObject:    &pkgservice.BaseObject{ID: pollID},
+		Options:       [][]byte{[]byte("a"), []byte("b"), []byte("c")},
+		IsMultiChoice: true,
+	}
+
+	vote1 := tNewVote(1, user1, pollID, 10, types.StatusAlive, 0)
+	vote1Later := tNewVote(2, user1, pollID, 20, types.StatusAlive, 1)
+	vote1SameTS := tNewVote(3, user1, pollID, 20, types.StatusAlive, 2)
+	vote1AfterDeadline := tNewVote(4, user1, pollID, 100, types.StatusAlive, 2)
+	vote2 := tNewVote(5, user2, pollID, 30, types.StatusAlive, 2)
+	vote2Deleted := tNewVote(6, user2, pollID, 40, types.StatusDeleted, 0)
+	vote3OtherPoll := tNewVote(7, user3, otherPollID, 10, types.StatusAlive, 0)
+	vote3Invalid := tNewVote(8, user3, pollID, 10, types.StatusAlive, 3)
+	vote3Multi := tNewVote(9, user3, pollID, 10, types.StatusAlive, 0, 1)
+
+	blockList := &pkgservice.BlockList{IDs: []*types.PttID{user2}}
+
+	// prepare test-cases
+	tests := []struct {
+		name      string
+		poll      *Poll
+		votes     []*Vote
+		blockList *pkgservice.BlockList
+		want      map[types.PttID]*Vote
+	}{
+		{
+			name:  "empty",
+			poll:  poll,
+			votes: nil,
+			want:  map[types.PttID]*Vote{},
+		},
+		{
+			name:  "latest",
+			poll:  poll,
+			votes: []*Vote{vote1Later, vote1, vote2},
+			want:  map[types.PttID]*Vote{*user1: vote1Later, *user2: vote2},
+		},
+		{
+			name:  "same-ts by id",
+			poll:  poll,
+			votes: []*Vote{vote1SameTS, vote1Later},
+			want:  map[types.PttID]*Vote{*user1: vote1SameTS},
+		},
+		{
+			name:  "after deadline",
+			poll:  poll,
+			votes: []*Vote{vote1, vote1AfterDeadline},
+			want:  map[types.PttID]*Vote{*user1: vote1},
+		},
+		{
+			name:  "deleted",
+			poll:  poll,
+			votes: []*Vote{vote2, vote2Deleted},
+			want:  map[types.PttID]*Vote{*user2: vote2},
+		},
+		{
+			name:  "other poll / invalid choices / multi-choice in single-choice poll",
+			poll:  poll,
+			votes: []*Vote{vote3OtherPoll, vote3Invalid, vote3Multi},
+			want:  map[types.PttID]*Vote{},
+		},
+		{
+			name:  "multi-choice",
+			poll:  multiPoll,
+			votes: []*Vote{vote3Multi, vote1AfterDeadline},
+			want:  map[types.PttID]*Vote{*user3: vote3Multi, *user1: vote1AfterDeadline},
+		},
+		{
+			name:      "blocked",
+			poll:      poll,
+			votes:     []*Vote{vote1Later, vote1, vote2},
+			blockList: blockList,
+			want:      map[types.PttID]*Vote{*user1: vote1Later},
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountLatestVotes(tt.poll, tt.votes, tt.blockList.IsBlocked); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CountLatestVotes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validatePendingVote(t *testing.T) {
+	// setup test
+	poll := &Poll{Deadline: types.Timestamp{Ts: 10000}}
+	openPoll := &Poll{}
+
+	// prepare test-cases
+	tests := []struct {
+		name     string
+		poll     *Poll
+		createTS int64
+		now      int64
+		wantErr  error
+	}{
+		{"before deadline", poll, 9000, 9500, nil},
+		{"before deadline received after close", poll, 9999, 10000 + MaxVoteDelaySeconds - 1, nil},
+		{"after deadline", poll, 10000, 10001, ErrPollClosed},
+		{"back-dated after close", poll, 9999, 9999 + MaxVoteDelaySeconds + 1, ErrPollClosed},
+		{"no deadline", openPoll, 1, 1 + MaxVoteDelaySeconds*10, nil},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePendingVote(tt.poll, types.Timestamp{Ts: tt.createTS}, types.Timestamp{Ts: tt.now})
+			if err != tt.wantErr {
+				t.Errorf("validatePendingVote() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
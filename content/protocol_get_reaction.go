@@ -0,0 +1,189 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+/*
+GetReactionList gets the reactions of the article and the comments of the article.
+*/
+func (pm *ProtocolManager) GetReactionList(articleID *types.PttID) ([]*Reaction, error) {
+	reaction := NewEmptyReaction()
+	pm.SetReactionDB(reaction)
+
+	iter, err := reaction.GetCrossObjIterWithObj(articleID[:], nil, pttdb.ListOrderNext, false)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+
+	reactions := make([]*Reaction, 0)
+	for iter.Next() {
+		eachReaction := NewEmptyReaction()
+		err = eachReaction.Unmarshal(iter.Value())
+		if err != nil {
+			continue
+		}
+		pm.SetReactionDB(eachReaction)
+
+		reactions = append(reactions, eachReaction)
+	}
+
+	return reactions, nil
+}
+
+/*
+GetReactionByCreator gets the not-deleted reaction of the creator to the target with the reaction-type.
+*/
+func (pm *ProtocolManager) GetReactionByCreator(articleID *types.PttID, commentID *types.PttID, reactionType ReactionType, creatorID *types.PttID) (*Reaction, error) {
+	reactions, err := pm.GetReactionList(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return findReactionByCreator(reactions, commentID, reactionType, creatorID), nil
+}
+
+func findReactionByCreator(reactions []*Reaction, commentID *types.PttID, reactionType ReactionType, creatorID *types.PttID) *Reaction {
+	for _, reaction := range reactions {
+		if reaction.Status > types.StatusAlive {
+			continue
+		}
+		if reaction.ReactionType != reactionType || !reflect.DeepEqual(reaction.CommentID, commentID) || !reflect.DeepEqual(reaction.CreatorID, creatorID) {
+			continue
+		}
+
+		return reaction
+	}
+
+	return nil
+}
+
+/*
+recountReaction re-counts the alive reactions to the target with the reaction-type (excluding the deleted reaction).
+*/
+func (pm *ProtocolManager) recountReaction(articleID *types.PttID, targetID *types.PttID, reactionType ReactionType, deletedID *types.PttID) error {
+	reactions, err := pm.GetReactionList(articleID)
+	if err != nil {
+		return err
+	}
+
+	count, err := NewReactionCount(pm.Entity().GetID(), targetID, reactionType, true)
+	if err != nil {
+		return err
+	}
+
+	countReactions(count, reactions, targetID, reactionType, deletedID, nil)
+
+	return count.Save()
+}
+
+/*
+countReactions adds the creators of the alive reactions to the target with the reaction-type to the count.
+The same creator is counted only once.
+
+isBlocked: the reactions from the blocked users are skipped (all the reactions are counted if nil).
+*/
+func countReactions(count *pkgservice.Count, reactions []*Reaction, targetID *types.PttID, reactionType ReactionType, deletedID *types.PttID, isBlocked func(id *types.PttID) bool) {
+	for _, reaction := range reactions {
+		if reaction.Status != types.StatusAlive || reaction.ReactionType != reactionType || reflect.DeepEqual(reaction.ID, deletedID) {
+			continue
+		}
+		if isBlocked != nil && isBlocked(reaction.CreatorID) {
+			continue
+		}
+		if !reflect.DeepEqual(reaction.TargetID(), targetID) {
+			continue
+		}
+
+		count.Add(reaction.CreatorID[:])
+	}
+}
+
+/*
+getReactionCount gets the number of the users with the reaction-type to the target.
+
+The saved count includes all the users.
+The count is re-counted (not saved) without the reactions from the blocked users if anyone is blocked by me.
+*/
+func (pm *ProtocolManager) getReactionCount(reactions []*Reaction, targetID *types.PttID, reactionType ReactionType, blockList *pkgservice.BlockList) uint64 {
+	entityID := pm.Entity().GetID()
+
+	if blockList == nil || len(blockList.IDs) == 0 {
+		count, err := LoadReactionCount(entityID, targetID, reactionType)
+		if err != nil {
+			return 0
+		}
+		return count.Count()
+	}
+
+	count, err := NewReactionCount(entityID, targetID, reactionType, true)
+	if err != nil {
+		return 0
+	}
+	countReactions(count, reactions, targetID, reactionType, nil, blockList.IsBlocked)
+
+	return count.Count()
+}
+
+/*
+deleteReactions deletes the reactions (and the counts) of the comment,
+or the reactions of the article and all the comments of the article if commentID is nil.
+*/
+func (pm *ProtocolManager) deleteReactions(articleID *types.PttID, commentID *types.PttID) error {
+	reactions, err := pm.GetReactionList(articleID)
+	if err != nil {
+		return err
+	}
+
+	entityID := pm.Entity().GetID()
+
+	targetIDs := make(map[types.PttID]bool)
+	if commentID != nil {
+		targetIDs[*commentID] = true
+	} else {
+		targetIDs[*articleID] = true
+	}
+
+	for _, reaction := range reactions {
+		if commentID != nil && !reflect.DeepEqual(reaction.CommentID, commentID) {
+			continue
+		}
+
+		targetIDs[*reaction.TargetID()] = true
+		reaction.GetAndDeleteAll(false)
+	}
+
+	for targetID := range targetIDs {
+		theTargetID := targetID
+		for reactionType := ReactionTypeLike; reactionType < NReactionType; reactionType++ {
+			count, err := NewReactionCount(entityID, &theTargetID, reactionType, false)
+			if err != nil {
+				continue
+			}
+			count.Delete()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func tNewReaction(id byte, creatorID *types.PttID, articleID *types.PttID, commentID *types.PttID, reactionType ReactionType, status types.Status) *Reaction {
+	return &Reaction{
+		BaseObject: &pkgservice.BaseObject{
+			ID:        &types.PttID{id},
+			CreatorID: creatorID,
+			Status:    status,
+		},
+		ArticleID:    articleID,
+		CommentID:    commentID,
+		ReactionType: reactionType,
+	}
+}
+
+func Test_countReactions(t *testing.T) {
+	// setup test
+	user1 := &types.PttID{1}
+	user2 := &types.PttID{2}
+	user3 := &types.PttID{3}
+	articleID := &types.PttID{10}
+	commentID := &types.PttID{11}
+
+	like1 := tNewReaction(1, user1, articleID, nil, ReactionTypeLike, types.StatusAlive)
+	like2 := tNewReaction(2, user2, articleID, nil, ReactionTypeLike, types.StatusAlive)
+	like3 := tNewReaction(3, user3, articleID, nil, ReactionTypeLike, types.StatusAlive)
+	like1Dup := tNewReaction(4, user1, articleID, nil, ReactionTypeLike, types.StatusAlive)
+	like3Deleted := tNewReaction(5, user3, articleID, nil, ReactionTypeLike, types.StatusDeleted)
+	love2 := tNewReaction(6, user2, articleID, nil, ReactionTypeLove, types.StatusAlive)
+	commentLike3 := tNewReaction(7, user3, articleID, commentID, ReactionTypeLike, types.StatusAlive)
+
+	blockList := &pkgservice.BlockList{IDs: []*types.PttID{user1}}
+
+	// prepare test-cases
+	tests := []struct {
+		name      string
+		reactions []*Reaction
+		targetID  *types.PttID
+		deletedID *types.PttID
+		blockList *pkgservice.BlockList
+		want      uint64
+	}{
+		{"empty", nil, articleID, nil, nil, 0},
+		{"add", []*Reaction{like1, like2, like3}, articleID, nil, nil, 3},
+		{"remove", []*Reaction{like1, like2, like3}, articleID, like3.ID, nil, 2},
+		{"remove all", []*Reaction{like1}, articleID, like1.ID, nil, 0},
+		{"deleted", []*Reaction{like1, like2, like3Deleted}, articleID, nil, nil, 2},
+		{"duplicate from same user", []*Reaction{like1, like1Dup, like2}, articleID, nil, nil, 2},
+		{"remove duplicate from same user", []*Reaction{like1, like1Dup}, articleID, like1Dup.ID, nil, 1},
+		{"other reaction-type", []*Reaction{like1, love2}, articleID, nil, nil, 1},
+		{"article and comment", []*Reaction{like1, commentLike3}, articleID, nil, nil, 1},
+		{"comment", []*Reaction{like1, commentLike3}, commentID, nil, nil, 1},
+		{"blocked", []*Reaction{like1, like1Dup, like2, like3}, articleID, nil, blockList, 2},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := NewReactionCount(articleID, tt.targetID, ReactionTypeLike, true)
+			if err != nil {
+				t.Fatalf("NewReactionCount: e: %v", err)
+			}
+
+			countReactions(count, tt.reactions, tt.targetID, ReactionTypeLike, tt.deletedID, tt.blockList.IsBlocked)
+			if got := count.Count(); got != tt.want {
+				t.Errorf("countReactions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findReactionByCreator(t *testing.T) {
+	// setup test
+	user1 := &types.PttID{1}
+	user2 := &types.PttID{2}
+	articleID := &types.PttID{10}
+	commentID := &types.PttID{11}
+
+	like1 := tNewReaction(1, user1, articleID, nil, ReactionTypeLike, types.StatusAlive)
+	like1Deleted := tNewReaction(2, user1, articleID, nil, ReactionTypeLike, types.StatusDeleted)
+	like1Pending := tNewReaction(3, user1, articleID, nil, ReactionTypeLike, types.StatusInternalPending)
+	commentLike1 := tNewReaction(4, user1, articleID, commentID, ReactionTypeLike, types.StatusAlive)
+
+	// prepare test-cases
+	tests := []struct {
+		name         string
+		reactions    []*Reaction
+		commentID    *types.PttID
+		reactionType ReactionType
+		creatorID    *types.PttID
+		want         *Reaction
+	}{
+		{"same user", []*Reaction{like1}, nil, ReactionTypeLike, user1, like1},
+		{"other user", []*Reaction{like1}, nil, ReactionTypeLike, user2, nil},
+		{"other reaction-type", []*Reaction{like1}, nil, ReactionTypeLove, user1, nil},
+		{"deleted", []*Reaction{like1Deleted}, nil, ReactionTypeLike, user1, nil},
+		{"pending", []*Reaction{like1Deleted, like1Pending}, nil, ReactionTypeLike, user1, like1Pending},
+		{"article only", []*Reaction{commentLike1}, nil, ReactionTypeLike, user1, nil},
+		{"comment", []*Reaction{like1, commentLike1}, commentID, ReactionTypeLike, user1, commentLike1},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findReactionByCreator(tt.reactions, tt.commentID, tt.reactionType, tt.creatorID); got != tt.want {
+				t.Errorf("findReactionByCreator() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -41,6 +41,12 @@ type ProcessBoardInfo struct {
 	MediaInfo       map[types.PttID]*pkgservice.BaseOplog
 	MediaBlockInfo  map[types.PttID]*pkgservice.BaseOplog
 
+	CreateReactionInfo map[types.PttID]*pkgservice.BaseOplog
+	ReactionInfo       map[types.PttID]*pkgservice.BaseOplog
+
+	CreatePollInfo map[types.PttID]*pkgservice.BaseOplog
+	CreateVoteInfo map[types.PttID]*pkgservice.BaseOplog
+
 	BoardInfo map[types.PttID]*pkgservice.BaseOplog
 }
 
@@ -65,6 +71,12 @@ func NewProcessBoardInfo() *ProcessBoardInfo {
 		MediaInfo:       make(map[types.PttID]*pkgservice.BaseOplog),
 		MediaBlockInfo:  make(map[types.PttID]*pkgservice.BaseOplog),
 
+		CreateReactionInfo: make(map[types.PttID]*pkgservice.BaseOplog),
+		ReactionInfo:       make(map[types.PttID]*pkgservice.BaseOplog),
+
+		CreatePollInfo: make(map[types.PttID]*pkgservice.BaseOplog),
+		CreateVoteInfo: make(map[types.PttID]*pkgservice.BaseOplog),
+
 		BoardInfo: make(map[types.PttID]*pkgservice.BaseOplog),
 	}
 }
@@ -106,6 +118,24 @@ func (pm *ProtocolManager) processBoardLog(oplog *pkgservice.BaseOplog, processI
 	case BoardOpTypeDeleteComment:
 		origLogs, err = pm.handleDeleteCommentLogs(oplog, info)
 
+	case BoardOpTypeCreateReaction:
+		origLogs, err = pm.handleCreateReactionLogs(oplog, info)
+	case BoardOpTypeDeleteReaction:
+		origLogs, err = pm.handleDeleteReactionLogs(oplog, info)
+
+	case BoardOpTypeSetRetention:
+		origLogs, err = pm.handleSetRetentionLogs(oplog, info)
+
+	case BoardOpTypeSetPins:
+		origLogs, err = pm.handleSetPinsLogs(oplog, info)
+	case BoardOpTypeSetTags:
+		origLogs, err = pm.handleSetTagsLogs(oplog, info)
+
+	case BoardOpTypeCreatePoll:
+		origLogs, err = pm.handleCreatePollLogs(oplog, info)
+	case BoardOpTypeCreateVote:
+		origLogs, err = pm.handleCreateVoteLogs(oplog, info)
+
 	case BoardOpTypeCreateReply:
 	case BoardOpTypeUpdateReply:
 	case BoardOpTypeDeleteReply:
@@ -150,6 +180,24 @@ func (pm *ProtocolManager) processPendingBoardLog(oplog *pkgservice.BaseOplog, p
 	case BoardOpTypeDeleteComment:
 		isToSign, origLogs, err = pm.handlePendingDeleteCommentLogs(oplog, info)
 
+	case BoardOpTypeCreateReaction:
+		isToSign, origLogs, err = pm.handlePendingCreateReactionLogs(oplog, info)
+	case BoardOpTypeDeleteReaction:
+		isToSign, origLogs, err = pm.handlePendingDeleteReactionLogs(oplog, info)
+
+	case BoardOpTypeSetRetention:
+		isToSign, origLogs, err = pm.handlePendingSetRetentionLogs(oplog, info)
+
+	case BoardOpTypeSetPins:
+		isToSign, origLogs, err = pm.handlePendingSetPinsLogs(oplog, info)
+	case BoardOpTypeSetTags:
+		isToSign, origLogs, err = pm.handlePendingSetTagsLogs(oplog, info)
+
+	case BoardOpTypeCreatePoll:
+		isToSign, origLogs, err = pm.handlePendingCreatePollLogs(oplog, info)
+	case BoardOpTypeCreateVote:
+		isToSign, origLogs, err = pm.handlePendingCreateVoteLogs(oplog, info)
+
 	case BoardOpTypeCreateReply:
 	case BoardOpTypeUpdateReply:
 	case BoardOpTypeDeleteReply:
@@ -215,6 +263,22 @@ func (pm *ProtocolManager) postprocessBoardOplogs(processInfo pkgservice.Process
 		deleteMediaLogs = pkgservice.ProcessInfoToLogs(info.MediaInfo, BoardOpTypeDeleteMedia)
 	}
 
+	// reaction
+	createReactionIDs := pkgservice.ProcessInfoToSyncIDList(info.CreateReactionInfo, BoardOpTypeCreateReaction)
+	pm.SyncReaction(SyncCreateReactionMsg, createReactionIDs, peer)
+
+	// poll
+	createPollIDs := pkgservice.ProcessInfoToSyncIDList(info.CreatePollInfo, BoardOpTypeCreatePoll)
+	pm.SyncPoll(SyncCreatePollMsg, createPollIDs, peer)
+
+	createVoteIDs := pkgservice.ProcessInfoToSyncIDList(info.CreateVoteInfo, BoardOpTypeCreateVote)
+	pm.SyncVote(SyncCreateVoteMsg, createVoteIDs, peer)
+
+	var deleteReactionLogs []*pkgservice.BaseOplog
+	if isPending {
+		deleteReactionLogs = pkgservice.ProcessInfoToLogs(info.ReactionInfo, BoardOpTypeDeleteReaction)
+	}
+
 	// broadcast
 	if isPending {
 		toBroadcastLogAry := [][]*pkgservice.BaseOplog{
@@ -222,6 +286,7 @@ func (pm *ProtocolManager) postprocessBoardOplogs(processInfo pkgservice.Process
 			deleteArticleLogs,
 			deleteCommentLogs,
 			deleteMediaLogs,
+			deleteReactionLogs,
 		}
 		toBroadcastLogs, err = pkgservice.ConcatLog(toBroadcastLogAry)
 		if err != nil {
@@ -229,6 +294,8 @@ func (pm *ProtocolManager) postprocessBoardOplogs(processInfo pkgservice.Process
 		}
 	}
 
+	pm.PostOplogEvents(pkgservice.OplogEventTypeLog0, toBroadcastLogs)
+
 	myID := pm.Ptt().GetMyEntity().GetID()
 	if isPending || pm.IsMaster(myID, false) {
 		pm.broadcastBoardOplogsCore(toBroadcastLogs)
@@ -275,6 +342,21 @@ func (pm *ProtocolManager) SetNewestBoardOplog(oplog *pkgservice.BaseOplog) (err
 	case BoardOpTypeDeleteComment:
 		isNewer, err = pm.setNewestDeleteCommentLog(oplog)
 
+	case BoardOpTypeCreateReaction:
+		isNewer, err = pm.setNewestCreateReactionLog(oplog)
+	case BoardOpTypeDeleteReaction:
+		isNewer, err = pm.setNewestDeleteReactionLog(oplog)
+
+	case BoardOpTypeSetRetention:
+
+	case BoardOpTypeSetPins:
+	case BoardOpTypeSetTags:
+
+	case BoardOpTypeCreatePoll:
+		isNewer, err = pm.setNewestCreatePollLog(oplog)
+	case BoardOpTypeCreateVote:
+		isNewer, err = pm.setNewestCreateVoteLog(oplog)
+
 	case BoardOpTypeCreateReply:
 	case BoardOpTypeUpdateReply:
 	case BoardOpTypeDeleteReply:
@@ -317,6 +399,21 @@ func (pm *ProtocolManager) HandleFailedBoardOplog(oplog *pkgservice.BaseOplog) (
 	case BoardOpTypeDeleteComment:
 		err = pm.handleFailedDeleteCommentLog(oplog)
 
+	case BoardOpTypeCreateReaction:
+		err = pm.handleFailedCreateReactionLog(oplog)
+	case BoardOpTypeDeleteReaction:
+		err = pm.handleFailedDeleteReactionLog(oplog)
+
+	case BoardOpTypeSetRetention:
+
+	case BoardOpTypeSetPins:
+	case BoardOpTypeSetTags:
+
+	case BoardOpTypeCreatePoll:
+		err = pm.handleFailedCreatePollLog(oplog)
+	case BoardOpTypeCreateVote:
+		err = pm.handleFailedCreateVoteLog(oplog)
+
 	case BoardOpTypeCreateReply:
 	case BoardOpTypeUpdateReply:
 	case BoardOpTypeDeleteReply:
@@ -362,6 +459,21 @@ func (pm *ProtocolManager) HandleFailedValidBoardOplog(oplog *pkgservice.BaseOpl
 	case BoardOpTypeDeleteComment:
 		err = pm.handleFailedValidDeleteCommentLog(oplog, info)
 
+	case BoardOpTypeCreateReaction:
+		err = pm.handleFailedValidCreateReactionLog(oplog, info)
+	case BoardOpTypeDeleteReaction:
+		err = pm.handleFailedValidDeleteReactionLog(oplog, info)
+
+	case BoardOpTypeSetRetention:
+
+	case BoardOpTypeSetPins:
+	case BoardOpTypeSetTags:
+
+	case BoardOpTypeCreatePoll:
+		err = pm.handleFailedValidCreatePollLog(oplog, info)
+	case BoardOpTypeCreateVote:
+		err = pm.handleFailedValidCreateVoteLog(oplog, info)
+
 	case BoardOpTypeCreateReply:
 	case BoardOpTypeUpdateReply:
 	case BoardOpTypeDeleteReply:
@@ -397,6 +509,11 @@ func (pm *ProtocolManager) postprocessFailedValidBoardOplogs(processInfo pkgserv
 
 	pm.ForceSyncMedia(mediaIDs, peer, ForceSyncMediaMsg)
 
+	// reaction
+	reactionIDs := pkgservice.ProcessInfoToForceSyncIDList(info.ReactionInfo)
+
+	pm.ForceSyncReaction(reactionIDs, peer)
+
 	return nil
 }
 
@@ -40,6 +40,18 @@ type ProtocolManager struct {
 	// comment
 	dbCommentPrefix    []byte
 	dbCommentIdxPrefix []byte
+
+	// reaction
+	dbReactionPrefix    []byte
+	dbReactionIdxPrefix []byte
+
+	// poll
+	dbPollPrefix    []byte
+	dbPollIdxPrefix []byte
+
+	// vote
+	dbVotePrefix    []byte
+	dbVoteIdxPrefix []byte
 }
 
 func newBaseProtocolManager(pm *ProtocolManager, ptt pkgservice.Ptt, entity pkgservice.Entity, svc pkgservice.Service) *pkgservice.BaseProtocolManager {
@@ -124,6 +136,18 @@ func NewProtocolManager(b *Board, ptt pkgservice.Ptt, svc pkgservice.Service) (*
 	pm.dbCommentPrefix = append(DBCommentPrefix, entityID[:]...)
 	pm.dbCommentIdxPrefix = append(DBCommentIdxPrefix, entityID[:]...)
 
+	// reaction
+	pm.dbReactionPrefix = append(DBReactionPrefix, entityID[:]...)
+	pm.dbReactionIdxPrefix = append(DBReactionIdxPrefix, entityID[:]...)
+
+	// poll
+	pm.dbPollPrefix = append(DBPollPrefix, entityID[:]...)
+	pm.dbPollIdxPrefix = append(DBPollIdxPrefix, entityID[:]...)
+
+	// vote
+	pm.dbVotePrefix = append(DBVotePrefix, entityID[:]...)
+	pm.dbVoteIdxPrefix = append(DBVoteIdxPrefix, entityID[:]...)
+
 	return pm, nil
 }
 
@@ -163,6 +187,13 @@ func (pm *ProtocolManager) Start() error {
 		pkgservice.PMOplogMerkleTreeLoop(pm, pm.boardOplogMerkle)
 	}()
 
+	// retention
+	syncWG.Add(1)
+	go func() {
+		defer syncWG.Done()
+		pkgservice.PMRetentionLoop(pm, pm.GCRetention)
+	}()
+
 	return nil
 }
 
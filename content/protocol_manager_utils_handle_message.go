@@ -152,6 +152,31 @@ func (pm *ProtocolManager) HandleMessage(op pkgservice.OpType, dataBytes []byte,
 			SyncCreateMediaBlockMsg,
 		)
 
+	case SyncCreateReactionMsg:
+		err = pm.HandleSyncCreateReaction(dataBytes, peer, SyncCreateReactionAckMsg)
+	case SyncCreateReactionAckMsg:
+		err = pm.HandleSyncCreateReactionAck(dataBytes, peer)
+	case ForceSyncReactionMsg:
+		err = pm.HandleForceSyncReaction(dataBytes, peer)
+	case ForceSyncReactionAckMsg:
+		err = pm.HandleForceSyncReactionAck(dataBytes, peer)
+
+	case SyncCreatePollMsg:
+		err = pm.HandleSyncCreatePoll(dataBytes, peer, SyncCreatePollAckMsg)
+	case SyncCreatePollAckMsg:
+		err = pm.HandleSyncCreatePollAck(dataBytes, peer)
+
+	case SyncCreateVoteMsg:
+		err = pm.HandleSyncCreateVote(dataBytes, peer, SyncCreateVoteAckMsg)
+	case SyncCreateVoteAckMsg:
+		err = pm.HandleSyncCreateVoteAck(dataBytes, peer)
+
+	// ephemeral
+	case ReadReceiptMsg:
+		err = pm.HandleReadReceipt(dataBytes, peer)
+	case TypingMsg:
+		err = pm.HandleTyping(dataBytes, peer)
+
 	default:
 		err = pkgservice.ErrInvalidMsgCode
 	}
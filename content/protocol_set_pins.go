@@ -0,0 +1,147 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+/*
+SetPins pins the articles to the top of the board (in order).
+The articles are required to be alive in the board.
+*/
+func (pm *ProtocolManager) SetPins(articleIDs []*types.PttID) error {
+
+	if len(articleIDs) > MaxPins {
+		return ErrInvalidPin
+	}
+
+	idMap := make(map[types.PttID]bool)
+	for _, articleID := range articleIDs {
+		if idMap[*articleID] {
+			return ErrInvalidPin
+		}
+		idMap[*articleID] = true
+
+		article := NewEmptyArticle()
+		pm.SetArticleDB(article)
+		article.SetID(articleID)
+		err := article.GetByID(false)
+		if err != nil {
+			return err
+		}
+		if article.Status != types.StatusAlive {
+			return types.ErrInvalidStatus
+		}
+	}
+
+	opData := &BoardOpSetPins{ArticleIDs: articleIDs}
+
+	return pm.setBoardSetting(BoardOpTypeSetPins, opData, pm.setPinsWithOplog)
+}
+
+func (pm *ProtocolManager) GetPins() (*BoardPins, error) {
+	board := pm.Entity().(*Board)
+
+	err := board.RLock()
+	if err != nil {
+		return nil, err
+	}
+	defer board.RUnlock()
+
+	if board.Pins == nil {
+		return &BoardPins{ArticleIDs: make([]*types.PttID, 0)}, nil
+	}
+
+	return board.Pins, nil
+}
+
+/*
+GetPinnedArticleList gets the alive pinned articles in the order of the pins.
+*/
+func (pm *ProtocolManager) GetPinnedArticleList() ([]*Article, error) {
+	pins, err := pm.GetPins()
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make([]*Article, 0, len(pins.ArticleIDs))
+	for _, articleID := range pins.ArticleIDs {
+		article := NewEmptyArticle()
+		pm.SetArticleDB(article)
+		article.SetID(articleID)
+		err = article.GetByID(false)
+		if err != nil {
+			continue
+		}
+		if article.Status != types.StatusAlive {
+			continue
+		}
+
+		articles = append(articles, article)
+	}
+
+	err = pm.loadArticleListInfo(articles)
+	if err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+func (pm *ProtocolManager) setPinsWithOplog(board *Board, oplog *pkgservice.BaseOplog) error {
+	opData := &BoardOpSetPins{}
+	err := oplog.GetData(opData)
+	if err != nil {
+		return err
+	}
+
+	if len(opData.ArticleIDs) > MaxPins {
+		return ErrInvalidPin
+	}
+
+	board.Pins = &BoardPins{
+		ArticleIDs: opData.ArticleIDs,
+
+		UpdateTS: oplog.UpdateTS,
+		LogID:    oplog.ID,
+	}
+
+	return nil
+}
+
+func getPinsUpdateTS(board *Board) types.Timestamp {
+	if board.Pins == nil {
+		return types.ZeroTimestamp
+	}
+	return board.Pins.UpdateTS
+}
+
+/**********
+ * Handle SetPinsLog
+ **********/
+
+func (pm *ProtocolManager) handleSetPinsLogs(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) ([]*pkgservice.BaseOplog, error) {
+
+	return pm.handleSetBoardSettingLog(oplog, &BoardOpSetPins{}, getPinsUpdateTS, pm.setPinsWithOplog)
+}
+
+func (pm *ProtocolManager) handlePendingSetPinsLogs(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) (types.Bool, []*pkgservice.BaseOplog, error) {
+
+	return pm.handlePendingSetBoardSettingLog(oplog)
+}
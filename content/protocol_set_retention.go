@@ -0,0 +1,184 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"encoding/json"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/pttdb"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func (pm *ProtocolManager) SetRetention(maxAgeSeconds int64, maxCount int) error {
+
+	return pm.BaseProtocolManager.SetRetention(
+		BoardOpTypeSetRetention,
+		maxAgeSeconds,
+		maxCount,
+
+		pm.boardOplogMerkle,
+
+		pm.NewBoardOplog,
+		pm.broadcastBoardOplogCore,
+		pm.postsetRetention,
+	)
+}
+
+func (pm *ProtocolManager) postsetRetention(policy *pkgservice.RetentionPolicy) error {
+	return pm.GCRetention()
+}
+
+/**********
+ * GC
+ **********/
+
+/*
+retentionOpData is the common op-data among the article / comment / media / reaction oplogs
+to determine the related objects of the expired articles.
+*/
+type retentionOpData struct {
+	ArticleID *types.PttID   `json:"AID"`
+	MediaIDs  []*types.PttID `json:"ms,omitempty"`
+}
+
+/*
+GCRetention removes the articles expired with the retention-policy of the board,
+including the comments, reactions, blocks, medias, and the corresponding oplogs.
+*/
+func (pm *ProtocolManager) GCRetention() error {
+	policy := pm.Entity().GetRetention()
+	if policy.IsEmpty() {
+		return nil
+	}
+
+	// expired articles
+	now, err := types.GetTimestamp()
+	if err != nil {
+		return err
+	}
+
+	objs, err := pm.getRetentionArticles()
+	if err != nil {
+		return err
+	}
+
+	expiredIDs := policy.ExpiredIDs(objs, now)
+	if len(expiredIDs) == 0 {
+		return nil
+	}
+
+	// related objects from oplogs
+	oplog := &pkgservice.BaseOplog{}
+	pm.SetBoardDB(oplog)
+	oplogs, err := pkgservice.GetOplogList(oplog, nil, 0, pttdb.ListOrderNext, types.StatusAlive, false)
+	if err != nil {
+		return err
+	}
+
+	mediaIDs := make([]*types.PttID, 0)
+	for _, eachLog := range oplogs {
+		if eachLog.Op == BoardOpTypeSetRetention || eachLog.ObjID == nil {
+			continue
+		}
+
+		opData := &retentionOpData{}
+		err = eachLog.GetData(opData)
+		if err != nil {
+			continue
+		}
+
+		if !expiredIDs[*eachLog.ObjID] && (opData.ArticleID == nil || !expiredIDs[*opData.ArticleID]) {
+			continue
+		}
+
+		expiredIDs[*eachLog.ObjID] = true
+		for _, mediaID := range opData.MediaIDs {
+			expiredIDs[*mediaID] = true
+			mediaIDs = append(mediaIDs, mediaID)
+		}
+	}
+
+	// objects
+	comment := NewEmptyComment()
+	pm.SetCommentDB(comment)
+	for _, obj := range objs {
+		if !expiredIDs[*obj.ID] {
+			continue
+		}
+
+		article := NewEmptyArticle()
+		pm.SetArticleDB(article)
+		article.SetID(obj.ID)
+		err = article.GetByID(false)
+		if err != nil {
+			continue
+		}
+
+		article.DeleteAll(comment, false)
+		pm.deleteReactions(obj.ID, nil)
+	}
+
+	media := pkgservice.NewEmptyMedia()
+	for _, mediaID := range mediaIDs {
+		pm.SetMediaDB(media)
+		media.SetID(mediaID)
+		media.DeleteAll(false)
+	}
+
+	// oplogs
+	nDeleted, err := pm.GCRetentionOplogs(
+		pm.boardOplogMerkle,
+		pm.SetBoardDB,
+		func(eachLog *pkgservice.BaseOplog) bool {
+			return eachLog.Op != BoardOpTypeSetRetention && eachLog.ObjID != nil && expiredIDs[*eachLog.ObjID]
+		},
+	)
+	log.Debug("GCRetention: done", "entity", pm.Entity().IDString(), "nDeleted", nDeleted, "e", err)
+
+	return err
+}
+
+func (pm *ProtocolManager) getRetentionArticles() ([]*pkgservice.RetentionObject, error) {
+	article := NewEmptyArticle()
+	pm.SetArticleDB(article)
+
+	iter, err := article.GetObjIterWithObj(nil, pttdb.ListOrderNext, false)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+
+	objs := make([]*pkgservice.RetentionObject, 0)
+	for iter.Next() {
+		eachArticle := NewEmptyArticle()
+		err = json.Unmarshal(iter.Value(), eachArticle)
+		if err != nil {
+			continue
+		}
+
+		// including the articles still in sync, to be the same as the other members.
+		if eachArticle.Status >= types.StatusFailed {
+			continue
+		}
+
+		objs = append(objs, &pkgservice.RetentionObject{ID: eachArticle.ID, CreateTS: eachArticle.CreateTS})
+	}
+
+	return objs, nil
+}
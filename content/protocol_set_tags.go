@@ -0,0 +1,97 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+/*
+SetTags sets the tag-vocabulary of the board.
+The articles are able to be tagged only with the tags in the vocabulary.
+*/
+func (pm *ProtocolManager) SetTags(tags []string) error {
+
+	err := ValidateTags(tags, MaxBoardTags)
+	if err != nil {
+		return err
+	}
+
+	opData := &BoardOpSetTags{Tags: tags}
+
+	return pm.setBoardSetting(BoardOpTypeSetTags, opData, setTagsWithOplog)
+}
+
+func (pm *ProtocolManager) GetTags() (*BoardTags, error) {
+	board := pm.Entity().(*Board)
+
+	err := board.RLock()
+	if err != nil {
+		return nil, err
+	}
+	defer board.RUnlock()
+
+	if board.Tags == nil {
+		return &BoardTags{Tags: make([]string, 0)}, nil
+	}
+
+	return board.Tags, nil
+}
+
+func setTagsWithOplog(board *Board, oplog *pkgservice.BaseOplog) error {
+	opData := &BoardOpSetTags{}
+	err := oplog.GetData(opData)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateTags(opData.Tags, MaxBoardTags)
+	if err != nil {
+		return err
+	}
+
+	board.Tags = &BoardTags{
+		Tags: opData.Tags,
+
+		UpdateTS: oplog.UpdateTS,
+		LogID:    oplog.ID,
+	}
+
+	return nil
+}
+
+func getTagsUpdateTS(board *Board) types.Timestamp {
+	if board.Tags == nil {
+		return types.ZeroTimestamp
+	}
+	return board.Tags.UpdateTS
+}
+
+/**********
+ * Handle SetTagsLog
+ **********/
+
+func (pm *ProtocolManager) handleSetTagsLogs(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) ([]*pkgservice.BaseOplog, error) {
+
+	return pm.handleSetBoardSettingLog(oplog, &BoardOpSetTags{}, getTagsUpdateTS, setTagsWithOplog)
+}
+
+func (pm *ProtocolManager) handlePendingSetTagsLogs(oplog *pkgservice.BaseOplog, info *ProcessBoardInfo) (types.Bool, []*pkgservice.BaseOplog, error) {
+
+	return pm.handlePendingSetBoardSettingLog(oplog)
+}
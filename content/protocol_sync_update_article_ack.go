@@ -50,7 +50,7 @@ func (pm *ProtocolManager) HandleSyncUpdateArticleAck(dataBytes []byte, peer *pk
 
 			pm.SetBoardDB,
 			pm.updateSyncArticle,
-			nil,
+			pm.postupdateArticle,
 			pm.broadcastBoardOplogCore,
 		)
 	}
@@ -106,5 +106,8 @@ func (pm *ProtocolManager) updateSyncArticle(theToSyncInfo pkgservice.SyncInfo,
 	// title
 	toSyncInfo.Title = fromObj.Title
 
+	// tags
+	toSyncInfo.Tags = opData.Tags
+
 	return nil
 }
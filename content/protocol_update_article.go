@@ -25,11 +25,24 @@ import (
 type UpdateArticle struct {
 	Article  [][]byte       `json:"a"`
 	MediaIDs []*types.PttID `json:"m"`
+	Tags     []string       `json:"t,omitempty"`
 }
 
-func (pm *ProtocolManager) UpdateArticle(articleID *types.PttID, articleBytes [][]byte, mediaIDs []*types.PttID) (*Article, error) {
+/*
+UpdateArticle updates the article.
 
-	data := &UpdateArticle{Article: articleBytes, MediaIDs: mediaIDs}
+The tags are kept as the original if tags is nil, and are required to be defined in the board.
+*/
+func (pm *ProtocolManager) UpdateArticle(articleID *types.PttID, articleBytes [][]byte, mediaIDs []*types.PttID, tags []string) (*Article, error) {
+
+	if tags != nil {
+		err := pm.validateArticleTags(tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data := &UpdateArticle{Article: articleBytes, MediaIDs: mediaIDs, Tags: tags}
 
 	origObj := NewEmptyArticle()
 	pm.SetArticleDB(origObj)
@@ -50,7 +63,7 @@ func (pm *ProtocolManager) UpdateArticle(articleID *types.PttID, articleBytes []
 		pm.inupdateArticle,
 		nil,
 		pm.broadcastBoardOplogCore,
-		nil,
+		pm.postupdateArticle,
 	)
 	if err != nil {
 		return nil, err
@@ -99,12 +112,58 @@ func (pm *ProtocolManager) inupdateArticle(theObj pkgservice.Object, theData pkg
 
 	opData.TitleHash = types.Hash(obj.Title)
 
+	tags := data.Tags
+	if tags == nil {
+		tags = obj.Tags
+	}
+	opData.Tags = tags
+
 	// sync-info
 	syncInfo := NewEmptySyncArticleInfo()
 	syncInfo.InitWithOplog(oplog.ToStatus(), oplog)
 	syncInfo.SetBlockInfo(blockInfo)
 
 	syncInfo.Title = obj.Title
+	syncInfo.Tags = tags
 
 	return syncInfo, nil
 }
+
+func (pm *ProtocolManager) postupdateArticle(theObj pkgservice.Object, oplog *pkgservice.BaseOplog) error {
+
+	article, ok := theObj.(*Article)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	// search-index
+	err := pm.indexArticle(article, true)
+	if err != nil {
+		log.Warn("postupdateArticle: unable to index article", "e", err, "entity", pm.Entity().IDString())
+	}
+
+	return nil
+}
+
+func (pm *ProtocolManager) validateArticleTags(tags []string) error {
+	err := ValidateTags(tags, MaxArticleTags)
+	if err != nil {
+		return err
+	}
+
+	board := pm.Entity().(*Board)
+
+	err = board.RLock()
+	if err != nil {
+		return err
+	}
+	defer board.RUnlock()
+
+	for _, tag := range tags {
+		if !board.Tags.IsDefined(tag) {
+			return ErrInvalidTag
+		}
+	}
+
+	return nil
+}
@@ -41,7 +41,7 @@ func (pm *ProtocolManager) handleUpdateArticleLogs(oplog *pkgservice.BaseOplog,
 		pm.syncArticleInfoFromOplog,
 		pm.SetBoardDB,
 		nil,
-		nil,
+		pm.postupdateArticle,
 		pm.updateUpdateArticleInfo,
 	)
 }
@@ -65,7 +65,7 @@ func (pm *ProtocolManager) handlePendingUpdateArticleLogs(oplog *pkgservice.Base
 		pm.syncArticleInfoFromOplog,
 		pm.SetBoardDB,
 		nil,
-		nil,
+		pm.postupdateArticle,
 		pm.updateUpdateArticleInfo,
 	)
 }
@@ -106,6 +106,7 @@ func (pm *ProtocolManager) syncArticleInfoFromOplog(oplog *pkgservice.BaseOplog,
 
 	syncInfo := NewEmptySyncArticleInfo()
 	syncInfo.InitWithOplog(types.StatusInternalSync, oplog)
+	syncInfo.Tags = opData.Tags
 
 	blockInfo, err := pkgservice.NewBlockInfo(opData.BlockInfoID, opData.Hashs, opData.MediaIDs, oplog.CreatorID)
 	if err != nil {
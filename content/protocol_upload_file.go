@@ -17,6 +17,9 @@
 package content
 
 import (
+	"bytes"
+	"io"
+
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/log"
 	pkgservice "github.com/ailabstw/go-pttai/service"
@@ -25,18 +28,28 @@ import (
 type UploadFile struct {
 	Filename []byte
 	Bytes    []byte
+	Reader   io.Reader
 }
 
 func (pm *ProtocolManager) UploadFile(filename []byte, theBytes []byte) (*pkgservice.Media, error) {
+	return pm.UploadFileFromReader(filename, bytes.NewReader(theBytes))
+}
+
+/*
+UploadFileFromReader creates the file-media with the blocks read from r.
+
+The blocks are split and saved while reading, so the file is not required to be fully loaded in memory.
+*/
+func (pm *ProtocolManager) UploadFileFromReader(filename []byte, r io.Reader) (*pkgservice.Media, error) {
 	myID := pm.Ptt().GetMyEntity().GetID()
 
-	if pm.Entity().GetEntityType() == pkgservice.EntityTypePersonal && !pm.IsMaster(myID, false) {
+	if !pm.IsUploadable(myID) {
 		return nil, types.ErrInvalidID
 	}
 
 	data := &UploadFile{
 		Filename: filename,
-		Bytes:    theBytes,
+		Reader:   r,
 	}
 
 	theMedia, err := pm.CreateObject(
@@ -92,7 +105,11 @@ func (pm *ProtocolManager) increateFile(theObj pkgservice.Object, theData pkgser
 	obj.MediaType = pkgservice.MediaTypeFile
 
 	// block-info
-	blockID, blockHashs, err := pm.SplitMediaBlocks(obj.ID, data.Bytes)
+	r := data.Reader
+	if r == nil {
+		r = bytes.NewReader(data.Bytes)
+	}
+	blockID, blockHashs, _, err := pm.SplitMediaBlocksFromReader(obj.ID, r)
 	if err != nil {
 		log.Error("increateFile: Unable to SplitMediaBlocks", "e", err)
 		return err
@@ -113,3 +130,7 @@ func (pm *ProtocolManager) increateFile(theObj pkgservice.Object, theData pkgser
 
 	return nil
 }
+
+func (pm *ProtocolManager) IsUploadable(myID *types.PttID) bool {
+	return pm.Entity().GetEntityType() != pkgservice.EntityTypePersonal || pm.IsMaster(myID, false)
+}
@@ -22,22 +22,56 @@ import (
 )
 
 type UploadImage struct {
-	FileType string
-	Bytes    []byte
+	FileType   string
+	Bytes      []byte
+	Thumbnails []*pkgservice.MediaThumbnail
 }
 
+/*
+UploadImage creates the image-media.
+
+The thumbnails are created as separated medias before the image,
+and the image refers to the thumbnails.
+*/
 func (pm *ProtocolManager) UploadImage(fileType string, theBytes []byte) (*pkgservice.Media, error) {
 	myID := pm.Ptt().GetMyEntity().GetID()
 
-	if pm.Entity().GetEntityType() == pkgservice.EntityTypePersonal && !pm.IsMaster(myID, false) {
+	if !pm.IsUploadable(myID) {
 		return nil, types.ErrInvalidID
 	}
 
+	imageThumbnails, err := pkgservice.NewImageThumbnails(theBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails := make([]*pkgservice.MediaThumbnail, len(imageThumbnails))
+	for i, imageThumbnail := range imageThumbnails {
+		thumbnailMedia, err := pm.createImage(&UploadImage{
+			FileType: "image/jpeg",
+			Bytes:    imageThumbnail.Bytes,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		thumbnails[i] = &pkgservice.MediaThumbnail{
+			ID:     thumbnailMedia.ID,
+			Width:  imageThumbnail.Width,
+			Height: imageThumbnail.Height,
+		}
+	}
+
 	data := &UploadImage{
-		FileType: fileType,
-		Bytes:    theBytes,
+		FileType:   fileType,
+		Bytes:      theBytes,
+		Thumbnails: thumbnails,
 	}
 
+	return pm.createImage(data)
+}
+
+func (pm *ProtocolManager) createImage(data *UploadImage) (*pkgservice.Media, error) {
 	theMedia, err := pm.CreateObject(
 		data,
 		BoardOpTypeCreateMedia,
@@ -92,6 +126,7 @@ func (pm *ProtocolManager) inuploadImage(theObj pkgservice.Object, theData pkgse
 	// media
 	obj.MediaData = newData
 	obj.MediaType = newMediaType
+	obj.Thumbnails = data.Thumbnails
 
 	// block-info
 	blockID, blockHashs, err := pm.SplitMediaBlocks(obj.ID, newBytes)
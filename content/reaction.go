@@ -0,0 +1,299 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"encoding/json"
+
+	"github.com/ailabstw/go-pttai/common"
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+/*
+Reaction is the reaction (like, love, ...) to the article (CommentID as nil) or the comment.
+
+The reactions are stored with the article-id in the key,
+so that the reactions of the article and the comments are deleted together with the article.
+*/
+type Reaction struct {
+	*pkgservice.BaseObject `json:"b"`
+
+	UpdateTS types.Timestamp `json:"UT"`
+
+	SyncInfo *pkgservice.BaseSyncInfo `json:"s,omitempty"`
+
+	ArticleID *types.PttID `json:"AID"`
+	CommentID *types.PttID `json:"CID,omitempty"`
+
+	ReactionType ReactionType `json:"t"`
+}
+
+func NewReaction(
+	createTS types.Timestamp,
+	creatorID *types.PttID,
+	entityID *types.PttID,
+
+	logID *types.PttID,
+
+	status types.Status,
+
+	articleID *types.PttID,
+	commentID *types.PttID,
+	reactionType ReactionType,
+
+) (*Reaction, error) {
+
+	id, err := types.NewPttID()
+	if err != nil {
+		return nil, err
+	}
+
+	o := pkgservice.NewObject(id, createTS, creatorID, entityID, logID, status)
+
+	return &Reaction{
+		BaseObject: o,
+
+		UpdateTS: createTS,
+
+		ArticleID:    articleID,
+		CommentID:    commentID,
+		ReactionType: reactionType,
+	}, nil
+}
+
+func NewEmptyReaction() *Reaction {
+	return &Reaction{BaseObject: &pkgservice.BaseObject{}}
+}
+
+func ReactionsToObjs(typedObjs []*Reaction) []pkgservice.Object {
+	objs := make([]pkgservice.Object, len(typedObjs))
+	for i, obj := range typedObjs {
+		objs[i] = obj
+	}
+	return objs
+}
+
+func ObjsToReactions(objs []pkgservice.Object) []*Reaction {
+	typedObjs := make([]*Reaction, len(objs))
+	for i, obj := range objs {
+		typedObjs[i] = obj.(*Reaction)
+	}
+	return typedObjs
+}
+
+func (pm *ProtocolManager) SetReactionDB(u *Reaction) {
+
+	u.SetDB(dbBoard, pm.DBObjLock(), pm.Entity().GetID(), pm.dbReactionPrefix, pm.dbReactionIdxPrefix, pm.SetBlockInfoDB, pm.SetMediaDB)
+}
+
+/*
+TargetID returns the id of the reacted object (the comment-id or the article-id).
+*/
+func (r *Reaction) TargetID() *types.PttID {
+	if r.CommentID != nil {
+		return r.CommentID
+	}
+	return r.ArticleID
+}
+
+func (r *Reaction) Save(isLocked bool) error {
+	var err error
+
+	if !isLocked {
+		err = r.Lock()
+		if err != nil {
+			return err
+		}
+		defer r.Unlock()
+	}
+
+	key, err := r.MarshalKey()
+	if err != nil {
+		return err
+	}
+	marshaled, err := r.Marshal()
+	if err != nil {
+		return err
+	}
+
+	idxKey, err := r.IdxKey()
+	if err != nil {
+		return err
+	}
+
+	idx := &pttdb.Index{Keys: [][]byte{key}, UpdateTS: r.UpdateTS}
+
+	kvs := []*pttdb.KeyVal{
+		&pttdb.KeyVal{K: key, V: marshaled},
+	}
+
+	_, err = r.DB().ForcePutAll(idxKey, idx, kvs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Reaction) NewEmptyObj() pkgservice.Object {
+	newObj := NewEmptyReaction()
+	newObj.CloneDB(r.BaseObject)
+	return newObj
+}
+
+func (r *Reaction) GetNewObjByID(id *types.PttID, isLocked bool) (pkgservice.Object, error) {
+	newU := r.NewEmptyObj()
+	newU.SetID(id)
+	err := newU.GetByID(isLocked)
+	if err != nil {
+		return nil, err
+	}
+	return newU, nil
+}
+
+func (r *Reaction) SetUpdateTS(ts types.Timestamp) {
+	r.UpdateTS = ts
+}
+
+func (r *Reaction) GetUpdateTS() types.Timestamp {
+	return r.UpdateTS
+}
+
+func (r *Reaction) Get(isLocked bool) error {
+	var err error
+
+	if !isLocked {
+		err = r.RLock()
+		if err != nil {
+			return err
+		}
+		defer r.RUnlock()
+	}
+
+	key, err := r.MarshalKey()
+	if err != nil {
+		return err
+	}
+
+	val, err := r.DB().DBGet(key)
+	if err != nil {
+		return err
+	}
+
+	return r.Unmarshal(val)
+}
+
+func (r *Reaction) GetByID(isLocked bool) error {
+	var err error
+
+	val, err := r.GetValueByID(isLocked)
+	if err != nil {
+		return err
+	}
+
+	return r.Unmarshal(val)
+}
+
+func (r *Reaction) MarshalKey() ([]byte, error) {
+	marshalTimestamp, err := r.CreateTS.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return common.Concat([][]byte{r.FullDBPrefix(), r.ArticleID[:], marshalTimestamp, r.ID[:]})
+}
+
+func (r *Reaction) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *Reaction) Unmarshal(theBytes []byte) error {
+	return json.Unmarshal(theBytes, r)
+}
+
+func (r *Reaction) GetSyncInfo() pkgservice.SyncInfo {
+	if r.SyncInfo == nil {
+		return nil
+	}
+	return r.SyncInfo
+}
+
+func (r *Reaction) SetSyncInfo(theSyncInfo pkgservice.SyncInfo) error {
+	if theSyncInfo == nil {
+		r.SyncInfo = nil
+		return nil
+	}
+
+	syncInfo, ok := theSyncInfo.(*pkgservice.BaseSyncInfo)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+	r.SyncInfo = syncInfo
+
+	return nil
+}
+
+func (r *Reaction) DeleteAll(isLocked bool) error {
+	return r.Delete(isLocked)
+}
+
+func (r *Reaction) GetAndDeleteAll(isLocked bool) error {
+	var err error
+	if !isLocked {
+		err = r.Lock()
+		if err != nil {
+			return err
+		}
+		defer r.Unlock()
+	}
+
+	err = r.GetByID(true)
+	if err != nil {
+		return err
+	}
+
+	return r.DeleteAll(true)
+}
+
+/**********
+ * Count
+ **********/
+
+/*
+NewReactionCount returns the count of the distinct users reacting to the target with the reaction-type.
+*/
+func NewReactionCount(entityID *types.PttID, targetID *types.PttID, reactionType ReactionType, isNewBits bool) (*pkgservice.Count, error) {
+	prefix := common.CloneBytes(DBReactionCountPrefix)
+	prefix = append(prefix, reactionType.Marshal()...)
+
+	return pkgservice.NewCount(dbBoard, entityID, targetID, prefix, PReactionCount, isNewBits)
+}
+
+func LoadReactionCount(entityID *types.PttID, targetID *types.PttID, reactionType ReactionType) (*pkgservice.Count, error) {
+	count, err := NewReactionCount(entityID, targetID, reactionType, false)
+	if err != nil {
+		return nil, err
+	}
+	err = count.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return count, nil
+}
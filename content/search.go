@@ -0,0 +1,166 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/pttdb"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+/*
+indexArticle indexes the title and the content of the article.
+
+isLocked: whether the article is already locked (ex: in postcreate / postupdate).
+*/
+func (pm *ProtocolManager) indexArticle(article *Article, isLocked bool) error {
+	if searchIndex == nil {
+		return nil
+	}
+
+	if article.Status != types.StatusAlive {
+		return searchIndex.Remove(article.ID)
+	}
+
+	texts := [][]byte{article.Title}
+
+	blockInfo := article.GetBlockInfo()
+	if blockInfo != nil {
+		pm.SetBlockInfoDB(blockInfo, article.ID)
+		contentBlockList, err := pkgservice.GetContentBlockList(blockInfo, 0, isLocked)
+		if err != nil {
+			return err
+		}
+		for _, contentBlock := range contentBlockList {
+			texts = append(texts, contentBlock.Buf...)
+		}
+	}
+
+	doc := &pkgservice.SearchDoc{
+		ID:        article.ID,
+		EntityID:  article.EntityID,
+		Type:      uint8(ContentTypeArticle),
+		CreatorID: article.CreatorID,
+		CreateTS:  article.CreateTS,
+		UpdateTS:  article.UpdateTS,
+	}
+
+	return searchIndex.Index(doc, texts)
+}
+
+/*
+indexComment indexes the content of the comment.
+
+isLocked: whether the comment is already locked (ex: in postcreate).
+*/
+func (pm *ProtocolManager) indexComment(comment *Comment, isLocked bool) error {
+	if searchIndex == nil {
+		return nil
+	}
+
+	if comment.Status != types.StatusAlive {
+		return searchIndex.Remove(comment.ID)
+	}
+
+	blockInfo := comment.GetBlockInfo()
+	if blockInfo == nil {
+		return pkgservice.ErrInvalidBlock
+	}
+	pm.SetBlockInfoDB(blockInfo, comment.ID)
+
+	contentBlockList, err := pkgservice.GetContentBlockList(blockInfo, 0, isLocked)
+	if err != nil {
+		return err
+	}
+
+	texts := make([][]byte, 0)
+	for _, contentBlock := range contentBlockList {
+		texts = append(texts, contentBlock.Buf...)
+	}
+
+	doc := &pkgservice.SearchDoc{
+		ID:        comment.ID,
+		EntityID:  comment.EntityID,
+		RefID:     comment.ArticleID,
+		Type:      uint8(ContentTypeComment),
+		CreatorID: comment.CreatorID,
+		CreateTS:  comment.CreateTS,
+		UpdateTS:  comment.UpdateTS,
+	}
+
+	return searchIndex.Index(doc, texts)
+}
+
+func removeSearchDoc(id *types.PttID) error {
+	if searchIndex == nil {
+		return nil
+	}
+
+	return searchIndex.Remove(id)
+}
+
+/*
+RebuildSearchIndex re-indexes all the articles and comments in the board (for the data before the search-index exists).
+*/
+func (pm *ProtocolManager) RebuildSearchIndex() error {
+	articles, err := pm.GetArticleList(nil, 0, pttdb.ListOrderNext, false)
+	if err != nil {
+		return err
+	}
+
+	comment := NewEmptyComment()
+	pm.SetCommentDB(comment)
+
+	for _, article := range articles {
+		err = pm.indexArticle(article, false)
+		if err != nil {
+			log.Warn("RebuildSearchIndex: unable to index article", "article", article.ID, "e", err, "entity", pm.Entity().IDString())
+		}
+
+		err = pm.rebuildSearchIndexComments(article, comment)
+		if err != nil {
+			log.Warn("RebuildSearchIndex: unable to index comments", "article", article.ID, "e", err, "entity", pm.Entity().IDString())
+		}
+	}
+
+	return nil
+}
+
+func (pm *ProtocolManager) rebuildSearchIndexComments(article *Article, comment *Comment) error {
+	iter, err := comment.GetCrossObjIterWithObj(article.ID[:], nil, pttdb.ListOrderNext, false)
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+
+	for iter.Next() {
+		eachComment := &Comment{}
+		err = eachComment.Unmarshal(iter.Value())
+		if err != nil {
+			continue
+		}
+		pm.SetCommentDB(eachComment)
+
+		err = pm.indexComment(eachComment, false)
+		if err != nil {
+			log.Warn("rebuildSearchIndexComments: unable to index comment", "comment", eachComment.ID, "e", err, "entity", pm.Entity().IDString())
+		}
+	}
+
+	return nil
+}
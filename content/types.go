@@ -46,6 +46,30 @@ func (c *CommentType) Marshal() []byte {
 	return theBytes[:]
 }
 
+// reaction type
+type ReactionType int
+
+const (
+	ReactionTypeLike ReactionType = iota
+	ReactionTypeLove
+	ReactionTypeLaugh
+	ReactionTypeWow
+	ReactionTypeSad
+	ReactionTypeAngry
+	NReactionType
+)
+
+func (r ReactionType) IsValid() bool {
+	return r >= ReactionTypeLike && r < NReactionType
+}
+
+func (r *ReactionType) Marshal() []byte {
+	theBytes := [1]byte{}
+	theBytes[0] = uint8(*r)
+
+	return theBytes[:]
+}
+
 type ReplyInfo struct {
 	Op pkgservice.OpType
 
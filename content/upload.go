@@ -0,0 +1,181 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+)
+
+/*
+The resumable uploads are kept as temporary files in uploadDir/{entityID}/{uploadID}.
+
+The size of the temporary file is the offset of the next chunk,
+so the upload can be resumed (even after restarting the node) by querying the offset.
+
+The upload is limited to MaxUploadSize, and the uploads not updated for ExpireUploadSeconds
+are removed while starting the service.
+*/
+
+func uploadPath(entityID *types.PttID, uploadID *types.PttID) string {
+	return filepath.Join(uploadDir, entityID.String(), uploadID.String())
+}
+
+func createUpload(entityID *types.PttID) (*types.PttID, error) {
+	uploadID, err := types.NewPttID()
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Join(uploadDir, entityID.String()), 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(uploadPath(entityID, uploadID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+
+	return uploadID, nil
+}
+
+func getUploadOffset(entityID *types.PttID, uploadID *types.PttID) (int64, error) {
+	info, err := os.Stat(uploadPath(entityID, uploadID))
+	if os.IsNotExist(err) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Size(), nil
+}
+
+/*
+writeUploadChunk appends the chunk to the upload.
+
+The offset is required to be the same as the current size of the upload,
+so that the re-sent chunks are not written twice.
+*/
+func writeUploadChunk(entityID *types.PttID, uploadID *types.PttID, offset int64, buf []byte) (int64, error) {
+	uploadLock.Lock()
+	defer uploadLock.Unlock()
+
+	size, err := getUploadOffset(entityID, uploadID)
+	if err != nil {
+		return 0, err
+	}
+	if offset != size {
+		return size, ErrInvalidUploadOffset
+	}
+	if size+int64(len(buf)) > MaxUploadSize {
+		return size, ErrInvalidUploadSize
+	}
+
+	f, err := os.OpenFile(uploadPath(entityID, uploadID), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return size, err
+	}
+	defer f.Close()
+
+	n, err := f.Write(buf)
+	if err != nil {
+		return size + int64(n), err
+	}
+
+	return size + int64(n), nil
+}
+
+func openUpload(entityID *types.PttID, uploadID *types.PttID) (io.ReadCloser, error) {
+	f, err := os.Open(uploadPath(entityID, uploadID))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func removeUpload(entityID *types.PttID, uploadID *types.PttID) error {
+	uploadLock.Lock()
+	defer uploadLock.Unlock()
+
+	err := os.Remove(uploadPath(entityID, uploadID))
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+/*
+removeExpiredUploads removes the uploads not updated since expireTS,
+and the entity-dirs without uploads.
+*/
+func removeExpiredUploads(expireTS types.Timestamp) error {
+	uploadLock.Lock()
+	defer uploadLock.Unlock()
+
+	entityDirs, err := ioutil.ReadDir(uploadDir)
+	if err != nil {
+		return err
+	}
+
+	expireTime := time.Unix(expireTS.Ts, int64(expireTS.NanoTs))
+	for _, entityDir := range entityDirs {
+		if !entityDir.IsDir() {
+			continue
+		}
+		dir := filepath.Join(uploadDir, entityDir.Name())
+
+		uploads, err := ioutil.ReadDir(dir)
+		if err != nil {
+			log.Warn("removeExpiredUploads: unable to read dir", "dir", dir, "e", err)
+			continue
+		}
+
+		nUploads := len(uploads)
+		for _, upload := range uploads {
+			if !upload.ModTime().Before(expireTime) {
+				continue
+			}
+
+			err = os.RemoveAll(filepath.Join(dir, upload.Name()))
+			if err != nil {
+				log.Warn("removeExpiredUploads: unable to remove upload", "dir", dir, "upload", upload.Name(), "e", err)
+				continue
+			}
+			nUploads--
+		}
+
+		if nUploads == 0 {
+			os.Remove(dir)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+func setupUploadTest(t *testing.T) func() {
+	origUploadDir, origMaxUploadSize := uploadDir, MaxUploadSize
+
+	uploadDir = filepath.Join("./test.out", "upload")
+	MaxUploadSize = 8
+
+	return func() {
+		os.RemoveAll("./test.out")
+		uploadDir, MaxUploadSize = origUploadDir, origMaxUploadSize
+	}
+}
+
+func Test_writeUploadChunk(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+	defer setupUploadTest(t)()
+
+	entityID, _ := types.NewPttID()
+	uploadID, err := createUpload(entityID)
+	if err != nil {
+		t.Fatalf("createUpload: e: %v", err)
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name       string
+		offset     int64
+		buf        []byte
+		wantOffset int64
+		wantErr    error
+	}{
+		{"first", 0, []byte("12345"), 5, nil},
+		{"resent", 0, []byte("12345"), 5, ErrInvalidUploadOffset},
+		{"exceed-max-size", 5, []byte("6789"), 5, ErrInvalidUploadSize},
+		{"max-size", 5, []byte("678"), 8, nil},
+		{"full", 8, []byte("9"), 8, ErrInvalidUploadSize},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := writeUploadChunk(entityID, uploadID, tt.offset, tt.buf)
+			if err != tt.wantErr {
+				t.Errorf("writeUploadChunk() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.wantOffset {
+				t.Errorf("writeUploadChunk() = %v, want %v", got, tt.wantOffset)
+			}
+		})
+	}
+
+	// teardown test
+}
+
+func Test_removeExpiredUploads(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+	defer setupUploadTest(t)()
+
+	entityID, _ := types.NewPttID()
+	entityID2, _ := types.NewPttID()
+
+	expiredID, _ := createUpload(entityID)
+	activeID, _ := createUpload(entityID)
+	expiredID2, _ := createUpload(entityID2)
+
+	oldTime := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(uploadPath(entityID, expiredID), oldTime, oldTime)
+	os.Chtimes(uploadPath(entityID2, expiredID2), oldTime, oldTime)
+
+	expireTS, _ := types.GetTimestamp()
+	expireTS.Ts -= 3600
+
+	// run test
+	err := removeExpiredUploads(expireTS)
+	if err != nil {
+		t.Errorf("removeExpiredUploads: e: %v", err)
+	}
+
+	if _, err := getUploadOffset(entityID, expiredID); err != ErrNotFound {
+		t.Errorf("expired upload: e: %v want: %v", err, ErrNotFound)
+	}
+	if _, err := getUploadOffset(entityID, activeID); err != nil {
+		t.Errorf("active upload: e: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(uploadDir, entityID2.String())); !os.IsNotExist(err) {
+		t.Errorf("empty entity-dir: e: %v", err)
+	}
+
+	// teardown test
+}
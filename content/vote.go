@@ -0,0 +1,259 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package content
+
+import (
+	"encoding/json"
+
+	"github.com/ailabstw/go-pttai/common"
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+/*
+Vote is the vote of the user to the poll, with the choices as the indexes of the options.
+
+The votes are stored with the poll-id in the key,
+so that the votes of the poll are able to be counted together.
+Only the latest vote of each user (before the deadline) is counted.
+*/
+type Vote struct {
+	*pkgservice.BaseObject `json:"b"`
+
+	UpdateTS types.Timestamp `json:"UT"`
+
+	SyncInfo *pkgservice.BaseSyncInfo `json:"s,omitempty"`
+
+	PollID  *types.PttID `json:"PID"`
+	Choices []int        `json:"C"`
+}
+
+func NewVote(
+	createTS types.Timestamp,
+	creatorID *types.PttID,
+	entityID *types.PttID,
+
+	logID *types.PttID,
+
+	status types.Status,
+
+	pollID *types.PttID,
+	choices []int,
+
+) (*Vote, error) {
+
+	id, err := types.NewPttID()
+	if err != nil {
+		return nil, err
+	}
+
+	o := pkgservice.NewObject(id, createTS, creatorID, entityID, logID, status)
+
+	return &Vote{
+		BaseObject: o,
+
+		UpdateTS: createTS,
+
+		PollID:  pollID,
+		Choices: choices,
+	}, nil
+}
+
+func NewEmptyVote() *Vote {
+	return &Vote{BaseObject: &pkgservice.BaseObject{}}
+}
+
+func VotesToObjs(typedObjs []*Vote) []pkgservice.Object {
+	objs := make([]pkgservice.Object, len(typedObjs))
+	for i, obj := range typedObjs {
+		objs[i] = obj
+	}
+	return objs
+}
+
+func ObjsToVotes(objs []pkgservice.Object) []*Vote {
+	typedObjs := make([]*Vote, len(objs))
+	for i, obj := range objs {
+		typedObjs[i] = obj.(*Vote)
+	}
+	return typedObjs
+}
+
+func (pm *ProtocolManager) SetVoteDB(u *Vote) {
+
+	u.SetDB(dbBoard, pm.DBObjLock(), pm.Entity().GetID(), pm.dbVotePrefix, pm.dbVoteIdxPrefix, pm.SetBlockInfoDB, pm.SetMediaDB)
+}
+
+func (v *Vote) Save(isLocked bool) error {
+	var err error
+
+	if !isLocked {
+		err = v.Lock()
+		if err != nil {
+			return err
+		}
+		defer v.Unlock()
+	}
+
+	key, err := v.MarshalKey()
+	if err != nil {
+		return err
+	}
+	marshaled, err := v.Marshal()
+	if err != nil {
+		return err
+	}
+
+	idxKey, err := v.IdxKey()
+	if err != nil {
+		return err
+	}
+
+	idx := &pttdb.Index{Keys: [][]byte{key}, UpdateTS: v.UpdateTS}
+
+	kvs := []*pttdb.KeyVal{
+		&pttdb.KeyVal{K: key, V: marshaled},
+	}
+
+	_, err = v.DB().ForcePutAll(idxKey, idx, kvs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (v *Vote) NewEmptyObj() pkgservice.Object {
+	newObj := NewEmptyVote()
+	newObj.CloneDB(v.BaseObject)
+	return newObj
+}
+
+func (v *Vote) GetNewObjByID(id *types.PttID, isLocked bool) (pkgservice.Object, error) {
+	newU := v.NewEmptyObj()
+	newU.SetID(id)
+	err := newU.GetByID(isLocked)
+	if err != nil {
+		return nil, err
+	}
+	return newU, nil
+}
+
+func (v *Vote) SetUpdateTS(ts types.Timestamp) {
+	v.UpdateTS = ts
+}
+
+func (v *Vote) GetUpdateTS() types.Timestamp {
+	return v.UpdateTS
+}
+
+func (v *Vote) Get(isLocked bool) error {
+	var err error
+
+	if !isLocked {
+		err = v.RLock()
+		if err != nil {
+			return err
+		}
+		defer v.RUnlock()
+	}
+
+	key, err := v.MarshalKey()
+	if err != nil {
+		return err
+	}
+
+	val, err := v.DB().DBGet(key)
+	if err != nil {
+		return err
+	}
+
+	return v.Unmarshal(val)
+}
+
+func (v *Vote) GetByID(isLocked bool) error {
+	var err error
+
+	val, err := v.GetValueByID(isLocked)
+	if err != nil {
+		return err
+	}
+
+	return v.Unmarshal(val)
+}
+
+func (v *Vote) MarshalKey() ([]byte, error) {
+	marshalTimestamp, err := v.CreateTS.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return common.Concat([][]byte{v.FullDBPrefix(), v.PollID[:], marshalTimestamp, v.ID[:]})
+}
+
+func (v *Vote) Marshal() ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (v *Vote) Unmarshal(theBytes []byte) error {
+	return json.Unmarshal(theBytes, v)
+}
+
+func (v *Vote) GetSyncInfo() pkgservice.SyncInfo {
+	if v.SyncInfo == nil {
+		return nil
+	}
+	return v.SyncInfo
+}
+
+func (v *Vote) SetSyncInfo(theSyncInfo pkgservice.SyncInfo) error {
+	if theSyncInfo == nil {
+		v.SyncInfo = nil
+		return nil
+	}
+
+	syncInfo, ok := theSyncInfo.(*pkgservice.BaseSyncInfo)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+	v.SyncInfo = syncInfo
+
+	return nil
+}
+
+func (v *Vote) DeleteAll(isLocked bool) error {
+	return v.Delete(isLocked)
+}
+
+func (v *Vote) GetAndDeleteAll(isLocked bool) error {
+	var err error
+	if !isLocked {
+		err = v.Lock()
+		if err != nil {
+			return err
+		}
+		defer v.Unlock()
+	}
+
+	err = v.GetByID(true)
+	if err != nil {
+		return err
+	}
+
+	return v.DeleteAll(true)
+}
@@ -17,8 +17,11 @@
 package friend
 
 import (
+	"context"
+
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/pttdb"
+	"github.com/ailabstw/go-pttai/rpc"
 	pkgservice "github.com/ailabstw/go-pttai/service"
 )
 
@@ -42,14 +45,69 @@ func (api *PrivateAPI) CreateMessage(entityID string, message [][]byte, mediaIDs
 	)
 }
 
+func (api *PrivateAPI) UpdateMessage(entityID string, messageID string, message [][]byte, mediaIDs []string) (*BackendUpdateMessage, error) {
+	return api.b.UpdateMessage(
+		[]byte(entityID),
+		[]byte(messageID),
+		message,
+		mediaIDs,
+	)
+}
+
+func (api *PrivateAPI) DeleteMessage(entityID string, messageID string) (bool, error) {
+	return api.b.DeleteMessage([]byte(entityID), []byte(messageID))
+}
+
 func (api *PrivateAPI) DeleteFriend(entityID string) (bool, error) {
 	return api.b.DeleteFriend([]byte(entityID))
 }
 
+func (api *PrivateAPI) SetRetention(entityID string, maxAgeSeconds int64, maxCount int) (*pkgservice.RetentionPolicy, error) {
+	return api.b.SetRetention([]byte(entityID), maxAgeSeconds, maxCount)
+}
+
+func (api *PrivateAPI) GetRetention(entityID string) (*pkgservice.RetentionPolicy, error) {
+	return api.b.GetRetention([]byte(entityID))
+}
+
 func (api *PrivateAPI) MarkFriendSeen(entityID string) (types.Timestamp, error) {
 	return api.b.MarkFriendSeen([]byte(entityID))
 }
 
+/**********
+ * Read-receipt / Typing
+ **********/
+
+/*
+GetReadReceipts gets the latest read-positions of the friend received in this session.
+*/
+func (api *PrivateAPI) GetReadReceipts(entityID string) ([]*pkgservice.ReadReceipt, error) {
+	return api.b.GetReadReceipts([]byte(entityID))
+}
+
+/*
+SetTyping sends my typing-state to the friend if I share the typing-states.
+*/
+func (api *PrivateAPI) SetTyping(entityID string, isTyping bool) (bool, error) {
+	return api.b.SetTyping([]byte(entityID), isTyping)
+}
+
+/*
+ReadReceipts creates a subscription (friend_subscribe("readReceipts", entityID)) receiving the read-receipts from the friends.
+Receiving the read-receipts of all the friends if entityID is empty.
+*/
+func (api *PrivateAPI) ReadReceipts(ctx context.Context, entityID string) (*rpc.Subscription, error) {
+	return api.b.SubscribeReadReceipts(ctx, []byte(entityID))
+}
+
+/*
+Typings creates a subscription (friend_subscribe("typings", entityID)) receiving the typing-states from the friends.
+Receiving the typing-states of all the friends if entityID is empty.
+*/
+func (api *PrivateAPI) Typings(ctx context.Context, entityID string) (*rpc.Subscription, error) {
+	return api.b.SubscribeTypings(ctx, []byte(entityID))
+}
+
 /**********
  * Get Friend
  **********/
@@ -108,6 +166,23 @@ func (api *PrivateAPI) GetMessageBlockList(entityID string, messageID string, du
 	return api.b.GetMessageBlockList([]byte(entityID), []byte(messageID), limit)
 }
 
+/*
+SearchMessages searches the messages by the query (with all the friends if entityID is empty).
+*/
+func (api *PrivateAPI) SearchMessages(entityID string, query string, startingMessageID string, limit int, listOrder pttdb.ListOrder) ([]*BackendGetMessage, error) {
+	return api.b.SearchMessages(
+		[]byte(entityID),
+		[]byte(query),
+		[]byte(startingMessageID),
+		limit,
+		listOrder,
+	)
+}
+
+func (api *PrivateAPI) RebuildSearchIndex(entityID string) (bool, error) {
+	return api.b.RebuildSearchIndex([]byte(entityID))
+}
+
 /**********
  * FriendOplog
  **********/
@@ -132,6 +207,14 @@ func (api *PrivateAPI) ForceSyncFriendMerkle(entityID string) (bool, error) {
 	return api.b.ForceSyncFriendMerkle([]byte(entityID))
 }
 
+/*
+FriendOplogs creates a subscription (friend_subscribe("friendOplogs", entityID)) receiving the friend-oplogs and member-oplogs when they are valid.
+Receiving the oplogs of all the friends if entityID is empty.
+*/
+func (api *PrivateAPI) FriendOplogs(ctx context.Context, entityID string) (*rpc.Subscription, error) {
+	return api.b.SubscribeFriendOplogs(ctx, []byte(entityID))
+}
+
 /**********
  * MasterOplog
  **********/
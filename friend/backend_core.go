@@ -17,10 +17,13 @@
 package friend
 
 import (
+	"context"
+
 	"github.com/ailabstw/go-pttai/account"
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/log"
 	"github.com/ailabstw/go-pttai/pttdb"
+	"github.com/ailabstw/go-pttai/rpc"
 	pkgservice "github.com/ailabstw/go-pttai/service"
 )
 
@@ -84,6 +87,31 @@ func (b *Backend) DeleteFriend(entityIDBytes []byte) (bool, error) {
 	return true, nil
 }
 
+func (b *Backend) SetRetention(entityIDBytes []byte, maxAgeSeconds int64, maxCount int) (*pkgservice.RetentionPolicy, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	err = pm.SetRetention(maxAgeSeconds, maxCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return pm.GetRetention()
+}
+
+func (b *Backend) GetRetention(entityIDBytes []byte) (*pkgservice.RetentionPolicy, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	return pm.GetRetention()
+}
+
 func (b *Backend) GetFriendList(startIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BackendGetFriend, error) {
 
 	startID, err := types.UnmarshalTextPttID(startIDBytes, true)
@@ -215,6 +243,15 @@ func (b *Backend) ForceSyncFriendMerkle(entityIDBytes []byte) (bool, error) {
 	return pm.ForceSyncFriendMerkle()
 }
 
+func (b *Backend) SubscribeFriendOplogs(ctx context.Context, entityIDBytes []byte) (*rpc.Subscription, error) {
+	entityID, err := types.UnmarshalTextPttID(entityIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgservice.SubscribeOplogEvents(ctx, b.SPM().EventMux(), entityID)
+}
+
 func (b *Backend) CreateMessage(entityIDBytes []byte, message [][]byte, mediaIDStrs []string) (*BackendCreateMessage, error) {
 
 	thePM, err := b.EntityIDToPM(entityIDBytes)
@@ -246,6 +283,65 @@ func (b *Backend) CreateMessage(entityIDBytes []byte, message [][]byte, mediaIDS
 	return messageToBackendCreateMessage(theMessage), nil
 }
 
+func (b *Backend) UpdateMessage(entityIDBytes []byte, messageIDBytes []byte, message [][]byte, mediaIDStrs []string) (*BackendUpdateMessage, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	messageID, err := types.UnmarshalTextPttID(messageIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+
+	lenMediaIDs := len(mediaIDStrs)
+	var mediaIDs []*types.PttID = nil
+	var eachMediaID *types.PttID
+	if len(mediaIDStrs) != 0 {
+		mediaIDs = make([]*types.PttID, lenMediaIDs)
+		for i, mediaIDStr := range mediaIDStrs {
+			eachMediaID, err = types.UnmarshalTextPttID([]byte(mediaIDStr), false)
+			if err != nil {
+				return nil, err
+			}
+			mediaIDs[i] = eachMediaID
+		}
+	}
+
+	theMessage, err := pm.UpdateMessage(messageID, message, mediaIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return messageToBackendUpdateMessage(theMessage), nil
+}
+
+func (b *Backend) DeleteMessage(entityIDBytes []byte, messageIDBytes []byte) (bool, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return false, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	messageID, err := types.UnmarshalTextPttID(messageIDBytes, false)
+	if err != nil {
+		return false, err
+	}
+	if messageID == nil {
+		return false, types.ErrInvalidID
+	}
+
+	err = pm.DeleteMessage(messageID)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (b *Backend) GetMessageList(entityIDBytes []byte, startIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BackendGetMessage, error) {
 
 	thePM, err := b.EntityIDToPM(entityIDBytes)
@@ -269,6 +365,80 @@ func (b *Backend) GetMessageList(entityIDBytes []byte, startIDBytes []byte, limi
 	return backendMessageList, nil
 }
 
+/*
+SearchMessages searches the messages containing all the tokens of the query.
+
+	entityIDBytes: search in all the friends if empty.
+	startingIDBytes: the message-id to start with (inclusive).
+
+The messages from the users blocked by me are skipped.
+*/
+func (b *Backend) SearchMessages(entityIDBytes []byte, query []byte, startingIDBytes []byte, limit int, listOrder pttdb.ListOrder) ([]*BackendGetMessage, error) {
+
+	entityID, err := types.UnmarshalTextPttID(entityIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+	if entityID != nil {
+		_, err = b.EntityIDToPM(entityIDBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	startID, err := types.UnmarshalTextPttID(startingIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	spm := b.SPM()
+	isValid := func(doc *pkgservice.SearchDoc) bool {
+		return spm.Entity(doc.EntityID) != nil
+	}
+	blockList := b.Ptt().GetMyEntity().GetBlockList()
+
+	docs, err := searchIndex.Search(query, entityID, startID, limit, listOrder, blockList.FilterSearchDoc(isValid))
+	if err != nil {
+		return nil, err
+	}
+
+	backendMessageList := make([]*BackendGetMessage, 0, len(docs))
+	for _, doc := range docs {
+		entity := spm.Entity(doc.EntityID)
+		if entity == nil {
+			continue
+		}
+		pm := entity.PM().(*ProtocolManager)
+
+		msg := NewEmptyMessage()
+		pm.SetMessageDB(msg)
+		msg.SetID(doc.ID)
+		err = msg.GetByID(false)
+		if err != nil {
+			continue
+		}
+
+		backendMessageList = append(backendMessageList, messageToBackendGetMessage(msg))
+	}
+
+	return backendMessageList, nil
+}
+
+func (b *Backend) RebuildSearchIndex(entityIDBytes []byte) (bool, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return false, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	err = pm.RebuildSearchIndex()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (b *Backend) GetMessageBlockList(entityIDBytes []byte, msgIDBytes []byte, limit uint32) ([]*BackendMessageBlock, error) {
 
 	thePM, err := b.EntityIDToPM(entityIDBytes)
@@ -312,7 +482,66 @@ func (b *Backend) MarkFriendSeen(entityIDBytes []byte) (types.Timestamp, error)
 	}
 	pm := thePM.(*ProtocolManager)
 
-	return pm.SaveLastSeen(types.ZeroTimestamp)
+	ts, err := pm.SaveLastSeen(types.ZeroTimestamp)
+	if err != nil {
+		return types.ZeroTimestamp, err
+	}
+
+	err = pm.SendReadReceipt(ReadReceiptMsg, nil, ts)
+	if err != nil {
+		log.Warn("MarkFriendSeen: unable to send read-receipt", "e", err, "entity", pm.Entity().IDString())
+	}
+
+	return ts, nil
+}
+
+/**********
+ * Read-receipt / Typing
+ **********/
+
+func (b *Backend) GetReadReceipts(entityIDBytes []byte) ([]*pkgservice.ReadReceipt, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	return pm.GetReadReceipts(nil), nil
+}
+
+func (b *Backend) SetTyping(entityIDBytes []byte, isTyping bool) (bool, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return false, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	err = pm.SendTyping(TypingMsg, nil, isTyping)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (b *Backend) SubscribeReadReceipts(ctx context.Context, entityIDBytes []byte) (*rpc.Subscription, error) {
+	entityID, err := types.UnmarshalTextPttID(entityIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgservice.SubscribeReadReceipts(ctx, b.SPM().EventMux(), entityID)
+}
+
+func (b *Backend) SubscribeTypings(ctx context.Context, entityIDBytes []byte) (*rpc.Subscription, error) {
+	entityID, err := types.UnmarshalTextPttID(entityIDBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgservice.SubscribeTypings(ctx, b.SPM().EventMux(), entityID)
 }
 
 func (b *Backend) MarkFriendListSeen() (types.Timestamp, error) {
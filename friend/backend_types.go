@@ -75,6 +75,28 @@ func messageToBackendCreateMessage(m *Message) *BackendCreateMessage {
 	}
 }
 
+type BackendUpdateMessage struct {
+	FriendID  *types.PttID `json:"FID"`
+	MessageID *types.PttID `json:"AID"`
+	BlockID   *types.PttID `json:"cID"`
+	NBlock    int          `json:"NB"`
+}
+
+func messageToBackendUpdateMessage(m *Message) *BackendUpdateMessage {
+	syncInfo := m.GetSyncInfo()
+	blockInfo := m.GetBlockInfo()
+	if syncInfo != nil && syncInfo.GetStatus() <= types.StatusAlive {
+		blockInfo = syncInfo.GetBlockInfo()
+	}
+
+	return &BackendUpdateMessage{
+		FriendID:  m.EntityID,
+		MessageID: m.ID,
+		BlockID:   blockInfo.ID,
+		NBlock:    blockInfo.NBlock,
+	}
+}
+
 type BackendGetMessage struct {
 	ID        *types.PttID
 	CreateTS  types.Timestamp //`json:"CT"`
@@ -84,6 +106,7 @@ type BackendGetMessage struct {
 	BlockID   *types.PttID    //`json:"cID"`
 	NBlock    int             //`json:"N"`
 	Status    types.Status    `json:"S"`
+	IsEdited  bool            `json:"E"`
 }
 
 func messageToBackendGetMessage(m *Message) *BackendGetMessage {
@@ -97,6 +120,7 @@ func messageToBackendGetMessage(m *Message) *BackendGetMessage {
 		BlockID:   m.BlockInfo.ID,
 		NBlock:    m.BlockInfo.NBlock,
 		Status:    m.Status,
+		IsEdited:  m.IsEdited(),
 	}
 }
 
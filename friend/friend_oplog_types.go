@@ -32,6 +32,11 @@ const (
 
 	FriendOpTypeCreateMedia
 
+	FriendOpTypeSetRetention
+
+	FriendOpTypeUpdateMessage
+	FriendOpTypeDeleteMessage
+
 	NFriendOpType
 )
 
@@ -50,6 +55,17 @@ type FriendOpCreateMessage struct {
 	MediaIDs []*types.PttID `json:"ms,omitempty"`
 }
 
+type FriendOpUpdateMessage struct {
+	BlockInfoID *types.PttID `json:"BID"`
+	Hashs       [][][]byte   `json:"H"`
+	NBlock      int          `json:"NB"`
+
+	MediaIDs []*types.PttID `json:"ms,omitempty"`
+}
+
+type FriendOpDeleteMessage struct {
+}
+
 type FriendOpCreateMedia struct {
 	BlockInfoID *types.PttID `json:"BID"` // resized content-block-id
 	Hashs       [][][]byte   `json:"H"`
@@ -36,11 +36,13 @@ var (
 
 // db
 var (
-	dbFriendCore *pttdb.LDBDatabase = nil
-	dbFriend     *pttdb.LDBBatch    = nil
-	dbKey        *pttdb.LDBDatabase = nil
+	dbFriendCore pttdb.DB         = nil
+	dbFriend     pttdb.IndexBatch = nil
+	dbKey        pttdb.DB         = nil
 
-	dbMeta *pttdb.LDBDatabase = nil
+	dbMeta pttdb.DB = nil
+
+	searchIndex *pkgservice.SearchIndex = nil
 
 	DBFriendIdxPrefix         = []byte(".frix")
 	DBFriendIdx2Prefix        = []byte(".fri2")
@@ -58,6 +60,9 @@ var (
 	DBMessageCreateTS2Prefix   = []byte(".mcdb")
 
 	DBFriendListSeenPrefix = []byte(".frsn")
+
+	DBSearchPrefix    = []byte(".srdb")
+	DBSearchDocPrefix = []byte(".srdc")
 )
 
 // protocol
@@ -95,6 +100,20 @@ const (
 	// init friend info
 	InitFriendInfoMsg
 	InitFriendInfoAckMsg
+
+	// ephemeral
+	ReadReceiptMsg
+	TypingMsg
+
+	// update / delete message
+	SyncUpdateMessageMsg
+	SyncUpdateMessageAckMsg
+
+	SyncUpdateMessageBlockMsg
+	SyncUpdateMessageBlockAckMsg
+
+	ForceSyncMessageMsg
+	ForceSyncMessageAckMsg
 )
 
 // max-masters
@@ -122,7 +141,7 @@ const (
 func InitFriend(dataDir string) error {
 	var err error
 
-	dbFriendCore, err = pttdb.NewLDBDatabase("friend", dataDir, 0, 0)
+	dbFriendCore, err = pttdb.NewDatabase("friend", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
@@ -131,12 +150,14 @@ func InitFriend(dataDir string) error {
 		return err
 	}
 
-	dbMeta, err = pttdb.NewLDBDatabase("friendmeta", dataDir, 0, 0)
+	searchIndex = pkgservice.NewSearchIndex(dbFriendCore, DBSearchPrefix, DBSearchDocPrefix)
+
+	dbMeta, err = pttdb.NewDatabase("friendmeta", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
 
-	dbKey, err = pttdb.NewLDBDatabase("friendkey", dataDir, 0, 0)
+	dbKey, err = pttdb.NewDatabase("friendkey", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
@@ -145,6 +166,10 @@ func InitFriend(dataDir string) error {
 }
 
 func TeardownFriend() {
+	if searchIndex != nil {
+		searchIndex = nil
+	}
+
 	if dbKey != nil {
 		dbKey.Close()
 		dbKey = nil
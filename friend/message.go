@@ -231,6 +231,13 @@ func (m *Message) SetSyncInfo(theSyncInfo pkgservice.SyncInfo) error {
 	return nil
 }
 
+/*
+IsEdited returns whether the alive message is updated after created.
+*/
+func (m *Message) IsEdited() bool {
+	return m.Status == types.StatusAlive && m.UpdateLogID != nil
+}
+
 func (m *Message) DeleteAll(isLocked bool) error {
 	var err error
 	if !isLocked {
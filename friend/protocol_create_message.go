@@ -137,11 +137,22 @@ func (pm *ProtocolManager) postcreateMessage(theObj pkgservice.Object, oplog *pk
 
 	log.Debug("postcreateMessage: start")
 
+	msg, ok := theObj.(*Message)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
 	entity := pm.Entity().(*Friend)
 	entity.SaveMessageCreateTS(oplog.UpdateTS)
 
+	// search-index
+	err := pm.indexMessage(msg, true)
+	if err != nil {
+		log.Warn("postcreateMessage: unable to index message", "e", err, "entity", pm.Entity().IDString())
+	}
+
 	myID := pm.Ptt().GetMyEntity().GetID()
-	creatorID := theObj.GetCreatorID()
+	creatorID := msg.GetCreatorID()
 
 	if reflect.DeepEqual(myID, creatorID) {
 		pm.SaveLastSeen(oplog.UpdateTS)
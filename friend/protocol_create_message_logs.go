@@ -29,6 +29,11 @@ func (pm *ProtocolManager) handleCreateMessageLogs(oplog *pkgservice.BaseOplog,
 
 	opData := &FriendOpCreateMessage{}
 
+	err := pm.ValidateRetentionCreateLog(oplog, pm.getRetentionMessages)
+	if err != nil {
+		return nil, err
+	}
+
 	return pm.HandleCreateObjectLog(
 		oplog, obj, opData, info,
 		pm.existsInInfoCreateMessage, pm.newMessageWithOplog, pm.postcreateMessage, pm.updateCreateMessageInfo)
@@ -42,6 +47,11 @@ func (pm *ProtocolManager) handlePendingCreateMessageLogs(oplog *pkgservice.Base
 
 	log.Debug("handlePendingCreateMessageLogs: start", "oplog", oplog.ID, "objID", oplog.ObjID)
 
+	err := pm.ValidateRetentionCreateLog(oplog, pm.getRetentionMessages)
+	if err != nil {
+		return false, nil, err
+	}
+
 	return pm.HandlePendingCreateObjectLog(
 		oplog, obj, opData, info,
 		pm.existsInInfoCreateMessage, pm.newMessageWithOplog, pm.postcreateMessage, pm.updateCreateMessageInfo)
@@ -0,0 +1,83 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package friend
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+/*
+DeleteMessage unsends the message. Only the creator of the message is able to unsend the message.
+*/
+func (pm *ProtocolManager) DeleteMessage(msgID *types.PttID) error {
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+
+	if pm.checkMessageCreator(msgID, myID) != nil {
+		return types.ErrInvalidID
+	}
+
+	msg := NewEmptyMessage()
+	pm.SetMessageDB(msg)
+
+	opData := &FriendOpDeleteMessage{}
+
+	return pm.DeleteObject(
+		msgID,
+
+		FriendOpTypeDeleteMessage,
+		msg,
+		opData,
+
+		pm.friendOplogMerkle,
+
+		pm.SetFriendDB,
+		pm.NewFriendOplog,
+		nil,
+		pm.setPendingDeleteMessageSyncInfo,
+
+		pm.broadcastFriendOplogCore,
+		pm.postdeleteMessage,
+	)
+}
+
+func (pm *ProtocolManager) setPendingDeleteMessageSyncInfo(obj pkgservice.Object, status types.Status, oplog *pkgservice.BaseOplog) error {
+
+	syncInfo := &pkgservice.BaseSyncInfo{}
+	syncInfo.InitWithOplog(status, oplog)
+
+	obj.SetSyncInfo(syncInfo)
+
+	return nil
+}
+
+func (pm *ProtocolManager) postdeleteMessage(id *types.PttID, oplog *pkgservice.BaseOplog, opData pkgservice.OpData, obj pkgservice.Object, blockInfo *pkgservice.BlockInfo) error {
+
+	// search-index
+	if searchIndex == nil {
+		return nil
+	}
+
+	err := searchIndex.Remove(id)
+	if err != nil {
+		log.Warn("postdeleteMessage: unable to remove search-doc", "e", err, "entity", pm.Entity().IDString())
+	}
+
+	return nil
+}
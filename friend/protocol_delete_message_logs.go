@@ -0,0 +1,114 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package friend
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func (pm *ProtocolManager) handleDeleteMessageLogs(oplog *pkgservice.BaseOplog, info *ProcessFriendInfo) ([]*pkgservice.BaseOplog, error) {
+	err := pm.checkMessageCreator(oplog.ObjID, oplog.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := NewEmptyMessage()
+	pm.SetMessageDB(obj)
+
+	opData := &FriendOpDeleteMessage{}
+
+	return pm.HandleDeleteObjectLog(
+		oplog,
+		info,
+		obj,
+		opData,
+
+		pm.friendOplogMerkle,
+
+		pm.SetFriendDB,
+		nil,
+		pm.postdeleteMessage,
+		pm.updateMessageDeleteInfo,
+	)
+}
+
+func (pm *ProtocolManager) handlePendingDeleteMessageLogs(oplog *pkgservice.BaseOplog, info *ProcessFriendInfo) (types.Bool, []*pkgservice.BaseOplog, error) {
+	err := pm.checkMessageCreator(oplog.ObjID, oplog.CreatorID)
+	if err != nil {
+		return false, nil, err
+	}
+
+	obj := NewEmptyMessage()
+	pm.SetMessageDB(obj)
+
+	opData := &FriendOpDeleteMessage{}
+
+	return pm.HandlePendingDeleteObjectLog(
+		oplog,
+		info, obj,
+		opData,
+
+		pm.friendOplogMerkle,
+
+		pm.SetFriendDB,
+		nil,
+		pm.setPendingDeleteMessageSyncInfo,
+		pm.updateMessageDeleteInfo,
+	)
+}
+
+func (pm *ProtocolManager) setNewestDeleteMessageLog(oplog *pkgservice.BaseOplog) (types.Bool, error) {
+
+	obj := NewEmptyMessage()
+	pm.SetMessageDB(obj)
+
+	return pm.SetNewestDeleteObjectLog(oplog, obj)
+}
+
+func (pm *ProtocolManager) handleFailedDeleteMessageLog(oplog *pkgservice.BaseOplog) error {
+
+	obj := NewEmptyMessage()
+	pm.SetMessageDB(obj)
+
+	return pm.HandleFailedDeleteObjectLog(oplog, obj)
+}
+
+func (pm *ProtocolManager) handleFailedValidDeleteMessageLog(oplog *pkgservice.BaseOplog, info *ProcessFriendInfo) error {
+
+	obj := NewEmptyMessage()
+	pm.SetMessageDB(obj)
+
+	return pm.HandleFailedValidDeleteObjectLog(oplog, obj, info, pm.updateMessageDeleteInfo)
+}
+
+/**********
+ * Customize
+ **********/
+
+func (pm *ProtocolManager) updateMessageDeleteInfo(obj pkgservice.Object, oplog *pkgservice.BaseOplog, theInfo pkgservice.ProcessInfo) error {
+
+	info, ok := theInfo.(*ProcessFriendInfo)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	info.MessageInfo[*oplog.ObjID] = oplog
+
+	return nil
+}
@@ -47,6 +47,8 @@ func (pm *ProtocolManager) BroadcastFriendOplog(oplog *FriendOplog) error {
 }
 
 func (pm *ProtocolManager) broadcastFriendOplogCore(oplog *pkgservice.BaseOplog) error {
+	pm.PostOplogEvents(pkgservice.OplogEventTypeLog0, []*pkgservice.BaseOplog{oplog})
+
 	return pm.BroadcastOplog(oplog, AddFriendOplogMsg, AddPendingFriendOplogMsg)
 }
 
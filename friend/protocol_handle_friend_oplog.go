@@ -24,6 +24,7 @@ import (
 
 type ProcessFriendInfo struct {
 	CreateMessageInfo map[types.PttID]*pkgservice.BaseOplog
+	MessageInfo       map[types.PttID]*pkgservice.BaseOplog
 
 	CreateMediaInfo map[types.PttID]*pkgservice.BaseOplog
 
@@ -35,6 +36,7 @@ type ProcessFriendInfo struct {
 func NewProcessFriendInfo() *ProcessFriendInfo {
 	return &ProcessFriendInfo{
 		CreateMessageInfo: make(map[types.PttID]*pkgservice.BaseOplog),
+		MessageInfo:       make(map[types.PttID]*pkgservice.BaseOplog),
 
 		CreateMediaInfo: make(map[types.PttID]*pkgservice.BaseOplog),
 
@@ -59,8 +61,15 @@ func (pm *ProtocolManager) processFriendLog(oplog *pkgservice.BaseOplog, process
 		origLogs, err = pm.handleDeleteFriendLogs(oplog, info)
 	case FriendOpTypeCreateMessage:
 		origLogs, err = pm.handleCreateMessageLogs(oplog, info)
+	case FriendOpTypeUpdateMessage:
+		origLogs, err = pm.handleUpdateMessageLogs(oplog, info)
+	case FriendOpTypeDeleteMessage:
+		origLogs, err = pm.handleDeleteMessageLogs(oplog, info)
 
 	case FriendOpTypeCreateMedia:
+
+	case FriendOpTypeSetRetention:
+		origLogs, err = pm.handleSetRetentionLogs(oplog, info)
 	}
 	return
 }
@@ -81,8 +90,15 @@ func (pm *ProtocolManager) processPendingFriendLog(oplog *pkgservice.BaseOplog,
 
 	case FriendOpTypeCreateMessage:
 		isToSign, origLogs, err = pm.handlePendingCreateMessageLogs(oplog, info)
+	case FriendOpTypeUpdateMessage:
+		isToSign, origLogs, err = pm.handlePendingUpdateMessageLogs(oplog, info)
+	case FriendOpTypeDeleteMessage:
+		isToSign, origLogs, err = pm.handlePendingDeleteMessageLogs(oplog, info)
 
 	case FriendOpTypeCreateMedia:
+
+	case FriendOpTypeSetRetention:
+		isToSign, origLogs, err = pm.handlePendingSetRetentionLogs(oplog, info)
 	}
 
 	return
@@ -112,6 +128,24 @@ func (pm *ProtocolManager) postprocessFriendOplogs(processInfo pkgservice.Proces
 
 	pm.SyncBlock(SyncCreateMessageBlockMsg, blockIDs, peer)
 
+	// update message
+	updateMessageIDs := pkgservice.ProcessInfoToSyncIDList(info.MessageInfo, FriendOpTypeUpdateMessage)
+	updateBlockIDs := pkgservice.ProcessInfoToSyncBlockIDList(info.BlockInfo, FriendOpTypeUpdateMessage)
+
+	pm.SyncMessage(SyncUpdateMessageMsg, updateMessageIDs, peer)
+	pm.SyncBlock(SyncUpdateMessageBlockMsg, updateBlockIDs, peer)
+
+	// delete message
+	if isPending {
+		deleteMessageLogs := pkgservice.ProcessInfoToLogs(info.MessageInfo, FriendOpTypeDeleteMessage)
+		toBroadcastLogs, err = pkgservice.ConcatLog([][]*pkgservice.BaseOplog{toBroadcastLogs, deleteMessageLogs})
+		if err != nil {
+			return
+		}
+	}
+
+	pm.PostOplogEvents(pkgservice.OplogEventTypeLog0, toBroadcastLogs)
+
 	pm.broadcastFriendOplogsCore(toBroadcastLogs)
 
 	// post-delete-friend
@@ -133,7 +167,12 @@ func (pm *ProtocolManager) SetNewestFriendOplog(oplog *pkgservice.BaseOplog) (er
 	case FriendOpTypeDeleteFriend:
 	case FriendOpTypeCreateMessage:
 		isNewer, err = pm.setNewestCreateMessageLog(oplog)
+	case FriendOpTypeUpdateMessage:
+		isNewer, err = pm.setNewestUpdateMessageLog(oplog)
+	case FriendOpTypeDeleteMessage:
+		isNewer, err = pm.setNewestDeleteMessageLog(oplog)
 	case FriendOpTypeCreateMedia:
+	case FriendOpTypeSetRetention:
 	}
 
 	oplog.IsNewer = isNewer
@@ -151,7 +190,12 @@ func (pm *ProtocolManager) HandleFailedFriendOplog(oplog *pkgservice.BaseOplog)
 	case FriendOpTypeDeleteFriend:
 	case FriendOpTypeCreateMessage:
 		err = pm.handleFailedCreateMessageLog(oplog)
+	case FriendOpTypeUpdateMessage:
+		err = pm.handleFailedUpdateMessageLog(oplog)
+	case FriendOpTypeDeleteMessage:
+		err = pm.handleFailedDeleteMessageLog(oplog)
 	case FriendOpTypeCreateMedia:
+	case FriendOpTypeSetRetention:
 	}
 
 	return
@@ -172,7 +216,12 @@ func (pm *ProtocolManager) HandleFailedValidFriendOplog(oplog *pkgservice.BaseOp
 	case FriendOpTypeDeleteFriend:
 	case FriendOpTypeCreateMessage:
 		err = pm.handleFailedValidCreateMessageLog(oplog, info)
+	case FriendOpTypeUpdateMessage:
+		err = pm.handleFailedValidUpdateMessageLog(oplog, info)
+	case FriendOpTypeDeleteMessage:
+		err = pm.handleFailedValidDeleteMessageLog(oplog, info)
 	case FriendOpTypeCreateMedia:
+	case FriendOpTypeSetRetention:
 	}
 
 	return
@@ -180,6 +229,16 @@ func (pm *ProtocolManager) HandleFailedValidFriendOplog(oplog *pkgservice.BaseOp
 
 func (pm *ProtocolManager) postprocessFailedValidFriendOplogs(processInfo pkgservice.ProcessInfo, peer *pkgservice.PttPeer) error {
 
+	info, ok := processInfo.(*ProcessFriendInfo)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	// message
+	messageIDs := pkgservice.ProcessInfoToForceSyncIDList(info.MessageInfo)
+
+	pm.ForceSyncMessage(messageIDs, peer)
+
 	return nil
 }
 
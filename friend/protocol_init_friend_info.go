@@ -179,5 +179,7 @@ func (pm *ProtocolManager) postcreateFriend(entity pkgservice.Entity) error {
 		return err
 	}
 
+	pm.Ptt().EventMux().Post(oplog)
+
 	return nil
 }
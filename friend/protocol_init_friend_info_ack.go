@@ -206,6 +206,8 @@ func (pm *ProtocolManager) HandleInitFriendInfoAckCore(
 		return err
 	}
 
+	pm.Ptt().EventMux().Post(pttOplog)
+
 	log.Debug("HandleInitFriendInfoAck: done")
 
 	return nil
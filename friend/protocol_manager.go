@@ -123,6 +123,13 @@ func (pm *ProtocolManager) Start() error {
 		pkgservice.PMOplogMerkleTreeLoop(pm, pm.friendOplogMerkle)
 	}()
 
+	// retention
+	syncWG.Add(1)
+	go func() {
+		defer syncWG.Done()
+		pkgservice.PMRetentionLoop(pm, pm.GCRetention)
+	}()
+
 	return nil
 }
 
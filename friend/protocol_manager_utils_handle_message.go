@@ -92,6 +92,25 @@ func (pm *ProtocolManager) HandleMessage(op pkgservice.OpType, dataBytes []byte,
 	case SyncCreateMessageBlockAckMsg:
 		err = pm.HandleSyncCreateMessageBlockAck(dataBytes, peer)
 
+	case SyncUpdateMessageMsg:
+		err = pm.HandleSyncUpdateMessage(dataBytes, peer, SyncUpdateMessageAckMsg)
+	case SyncUpdateMessageAckMsg:
+		err = pm.HandleSyncUpdateMessageAck(dataBytes, peer)
+	case SyncUpdateMessageBlockMsg:
+		err = pm.HandleSyncUpdateMessageBlock(dataBytes, peer)
+	case SyncUpdateMessageBlockAckMsg:
+		err = pm.HandleSyncUpdateMessageBlockAck(dataBytes, peer)
+	case ForceSyncMessageMsg:
+		err = pm.HandleForceSyncMessage(dataBytes, peer)
+	case ForceSyncMessageAckMsg:
+		err = pm.HandleForceSyncMessageAck(dataBytes, peer)
+
+	// ephemeral
+	case ReadReceiptMsg:
+		err = pm.HandleReadReceipt(dataBytes, peer)
+	case TypingMsg:
+		err = pm.HandleTyping(dataBytes, peer)
+
 	default:
 		log.Error("invalid op", "op", op, "InitFriendInfoMsg", InitFriendInfoMsg)
 		err = pkgservice.ErrInvalidMsgCode
@@ -0,0 +1,167 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package friend
+
+import (
+	"encoding/json"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/pttdb"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func (pm *ProtocolManager) SetRetention(maxAgeSeconds int64, maxCount int) error {
+
+	return pm.BaseProtocolManager.SetRetention(
+		FriendOpTypeSetRetention,
+		maxAgeSeconds,
+		maxCount,
+
+		pm.friendOplogMerkle,
+
+		pm.NewFriendOplog,
+		pm.broadcastFriendOplogCore,
+		pm.postsetRetention,
+	)
+}
+
+func (pm *ProtocolManager) postsetRetention(policy *pkgservice.RetentionPolicy) error {
+	return pm.GCRetention()
+}
+
+/**********
+ * GC
+ **********/
+
+/*
+GCRetention removes the messages expired with the retention-policy of the friend,
+including the blocks, medias, and the corresponding oplogs.
+*/
+func (pm *ProtocolManager) GCRetention() error {
+	policy := pm.Entity().GetRetention()
+	if policy.IsEmpty() {
+		return nil
+	}
+
+	// expired messages
+	now, err := types.GetTimestamp()
+	if err != nil {
+		return err
+	}
+
+	objs, err := pm.getRetentionMessages()
+	if err != nil {
+		return err
+	}
+
+	expiredIDs := policy.ExpiredIDs(objs, now)
+	if len(expiredIDs) == 0 {
+		return nil
+	}
+
+	// medias from oplogs
+	oplog := &pkgservice.BaseOplog{}
+	pm.SetFriendDB(oplog)
+	oplogs, err := pkgservice.GetOplogList(oplog, nil, 0, pttdb.ListOrderNext, types.StatusAlive, false)
+	if err != nil {
+		return err
+	}
+
+	mediaIDs := make([]*types.PttID, 0)
+	for _, eachLog := range oplogs {
+		if eachLog.Op != FriendOpTypeCreateMessage || !expiredIDs[*eachLog.ObjID] {
+			continue
+		}
+
+		opData := &FriendOpCreateMessage{}
+		err = eachLog.GetData(opData)
+		if err != nil {
+			continue
+		}
+
+		for _, mediaID := range opData.MediaIDs {
+			expiredIDs[*mediaID] = true
+			mediaIDs = append(mediaIDs, mediaID)
+		}
+	}
+
+	// objects
+	for _, obj := range objs {
+		if !expiredIDs[*obj.ID] {
+			continue
+		}
+
+		message := NewEmptyMessage()
+		pm.SetMessageDB(message)
+		message.SetID(obj.ID)
+		err = message.GetByID(false)
+		if err != nil {
+			continue
+		}
+
+		message.DeleteAll(false)
+	}
+
+	media := pkgservice.NewEmptyMedia()
+	for _, mediaID := range mediaIDs {
+		pm.SetMediaDB(media)
+		media.SetID(mediaID)
+		media.DeleteAll(false)
+	}
+
+	// oplogs
+	nDeleted, err := pm.GCRetentionOplogs(
+		pm.friendOplogMerkle,
+		pm.SetFriendDB,
+		func(eachLog *pkgservice.BaseOplog) bool {
+			return eachLog.Op != FriendOpTypeSetRetention && eachLog.ObjID != nil && expiredIDs[*eachLog.ObjID]
+		},
+	)
+	log.Debug("GCRetention: done", "entity", pm.Entity().IDString(), "nDeleted", nDeleted, "e", err)
+
+	return err
+}
+
+func (pm *ProtocolManager) getRetentionMessages() ([]*pkgservice.RetentionObject, error) {
+	message := NewEmptyMessage()
+	pm.SetMessageDB(message)
+
+	iter, err := message.GetObjIterWithObj(nil, pttdb.ListOrderNext, false)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+
+	objs := make([]*pkgservice.RetentionObject, 0)
+	for iter.Next() {
+		eachMessage := NewEmptyMessage()
+		err = json.Unmarshal(iter.Value(), eachMessage)
+		if err != nil {
+			continue
+		}
+
+		// including the messages still in sync, to be the same as the other members.
+		if eachMessage.Status >= types.StatusFailed {
+			continue
+		}
+
+		objs = append(objs, &pkgservice.RetentionObject{ID: eachMessage.ID, CreateTS: eachMessage.CreateTS})
+	}
+
+	return objs, nil
+}
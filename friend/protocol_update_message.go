@@ -0,0 +1,163 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package friend
+
+import (
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+type UpdateMessage struct {
+	Msg      [][]byte       `json:"m"`
+	MediaIDs []*types.PttID `json:"M"`
+}
+
+/*
+UpdateMessage edits the message. Only the creator of the message is able to edit the message.
+*/
+func (pm *ProtocolManager) UpdateMessage(msgID *types.PttID, msg [][]byte, mediaIDs []*types.PttID) (*Message, error) {
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+
+	if pm.checkMessageCreator(msgID, myID) != nil {
+		return nil, types.ErrInvalidID
+	}
+
+	data := &UpdateMessage{Msg: msg, MediaIDs: mediaIDs}
+
+	origObj := NewEmptyMessage()
+	pm.SetMessageDB(origObj)
+
+	opData := &FriendOpUpdateMessage{}
+
+	err := pm.UpdateObject(
+		msgID,
+		data,
+		FriendOpTypeUpdateMessage,
+		origObj,
+		opData,
+
+		pm.friendOplogMerkle,
+
+		pm.SetFriendDB,
+		pm.NewFriendOplog,
+		pm.inupdateMessage,
+		nil,
+		pm.broadcastFriendOplogCore,
+		pm.postupdateMessage,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug("UpdateMessage: done", "entity", pm.Entity().IDString())
+
+	return origObj, nil
+}
+
+func (pm *ProtocolManager) inupdateMessage(theObj pkgservice.Object, theData pkgservice.UpdateData, oplog *pkgservice.BaseOplog, theOpData pkgservice.OpData) (pkgservice.SyncInfo, error) {
+
+	data, ok := theData.(*UpdateMessage)
+	if !ok {
+		return nil, pkgservice.ErrInvalidData
+	}
+
+	opData, ok := theOpData.(*FriendOpUpdateMessage)
+	if !ok {
+		return nil, pkgservice.ErrInvalidData
+	}
+
+	// block-info
+	blockInfoID, blockHashs, err := pm.SplitContentBlocks(nil, oplog.ObjID, data.Msg, NFirstLineInBlock)
+	if err != nil {
+		log.Error("inupdateMessage: Unable to SplitContentBlocks", "e", err)
+		return nil, err
+	}
+
+	blockInfo, err := pkgservice.NewBlockInfo(blockInfoID, blockHashs, data.MediaIDs, oplog.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	blockInfo.SetIsAllGood()
+
+	// op-data
+	opData.BlockInfoID = blockInfoID
+	opData.NBlock = blockInfo.NBlock
+	opData.Hashs = blockHashs
+	opData.MediaIDs = data.MediaIDs
+
+	// sync-info
+	syncInfo := &pkgservice.BaseSyncInfo{}
+	syncInfo.InitWithOplog(oplog.ToStatus(), oplog)
+	syncInfo.SetBlockInfo(blockInfo)
+
+	return syncInfo, nil
+}
+
+func (pm *ProtocolManager) postupdateMessage(theObj pkgservice.Object, oplog *pkgservice.BaseOplog) error {
+
+	msg, ok := theObj.(*Message)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	// search-index
+	err := pm.indexMessage(msg, true)
+	if err != nil {
+		log.Warn("postupdateMessage: unable to index message", "e", err, "entity", pm.Entity().IDString())
+	}
+
+	return nil
+}
+
+/*
+checkMessageCreator checks that the id is the creator of the message.
+
+Both the friends are the masters of the friend-entity, so we need to
+make sure that the message is edited / unsent only by the creator.
+*/
+func (pm *ProtocolManager) checkMessageCreator(msgID *types.PttID, id *types.PttID) error {
+	msg := NewEmptyMessage()
+	pm.SetMessageDB(msg)
+	msg.SetID(msgID)
+
+	err := msg.GetByID(false)
+	if err != nil {
+		msg = nil
+	}
+
+	return validateMessageCreator(msg, id)
+}
+
+/*
+validateMessageCreator returns ErrNewerOplog if the message does not exist yet (not synced yet),
+so that the log is not applied (and the pending log is not signed) before the creator is able to be checked.
+*/
+func validateMessageCreator(msg *Message, id *types.PttID) error {
+	if msg == nil {
+		return pkgservice.ErrNewerOplog
+	}
+
+	if !reflect.DeepEqual(msg.CreatorID, id) {
+		return types.ErrInvalidID
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package friend
+
+import (
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func tNewMessage(creatorID *types.PttID, status types.Status, updateLogID *types.PttID) *Message {
+	return &Message{
+		BaseObject: &pkgservice.BaseObject{
+			ID:          &types.PttID{1},
+			CreatorID:   creatorID,
+			Status:      status,
+			UpdateLogID: updateLogID,
+			BlockInfo:   &pkgservice.BlockInfo{ID: &types.PttID{2}, NBlock: 1},
+		},
+	}
+}
+
+func Test_validateMessageCreator(t *testing.T) {
+	// setup test
+	creatorID := &types.PttID{1}
+	friendID := &types.PttID{2}
+
+	msg := tNewMessage(creatorID, types.StatusAlive, nil)
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		msg     *Message
+		id      *types.PttID
+		wantErr error
+	}{
+		{"edit / delete from creator", msg, creatorID, nil},
+		{"edit / delete from non-creator", msg, friendID, types.ErrInvalidID},
+		{"edit / delete from nil", msg, nil, types.ErrInvalidID},
+		{"message not synced yet", nil, creatorID, pkgservice.ErrNewerOplog},
+		{"message not synced yet from non-creator", nil, friendID, pkgservice.ErrNewerOplog},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateMessageCreator(tt.msg, tt.id); err != tt.wantErr {
+				t.Errorf("validateMessageCreator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMessage_IsEdited(t *testing.T) {
+	// setup test
+	creatorID := &types.PttID{1}
+	logID := &types.PttID{3}
+
+	// prepare test-cases
+	tests := []struct {
+		name string
+		msg  *Message
+		want bool
+	}{
+		{"created", tNewMessage(creatorID, types.StatusAlive, nil), false},
+		{"edited", tNewMessage(creatorID, types.StatusAlive, logID), true},
+		{"edit pending", tNewMessage(creatorID, types.StatusInternalPending, logID), false},
+		{"deleted after edited", tNewMessage(creatorID, types.StatusDeleted, logID), false},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.IsEdited(); got != tt.want {
+				t.Errorf("Message.IsEdited() = %v, want %v", got, tt.want)
+			}
+			if got := messageToBackendGetMessage(tt.msg).IsEdited; got != tt.want {
+				t.Errorf("messageToBackendGetMessage().IsEdited = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package friend
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/pttdb"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+/*
+indexMessage indexes the content of the message.
+
+isLocked: whether the message is already locked (ex: in postcreate).
+*/
+func (pm *ProtocolManager) indexMessage(msg *Message, isLocked bool) error {
+	if searchIndex == nil {
+		return nil
+	}
+
+	if msg.Status != types.StatusAlive {
+		return searchIndex.Remove(msg.ID)
+	}
+
+	blockInfo := msg.GetBlockInfo()
+	if blockInfo == nil {
+		return pkgservice.ErrInvalidBlock
+	}
+	pm.SetBlockInfoDB(blockInfo, msg.ID)
+
+	contentBlockList, err := pkgservice.GetContentBlockList(blockInfo, 0, isLocked)
+	if err != nil {
+		return err
+	}
+
+	texts := make([][]byte, 0)
+	for _, contentBlock := range contentBlockList {
+		texts = append(texts, contentBlock.Buf...)
+	}
+
+	doc := &pkgservice.SearchDoc{
+		ID:        msg.ID,
+		EntityID:  msg.EntityID,
+		CreatorID: msg.CreatorID,
+		CreateTS:  msg.CreateTS,
+		UpdateTS:  msg.UpdateTS,
+	}
+
+	return searchIndex.Index(doc, texts)
+}
+
+/*
+RebuildSearchIndex re-indexes all the messages with the friend (for the data before the search-index exists).
+*/
+func (pm *ProtocolManager) RebuildSearchIndex() error {
+	msgs, err := pm.GetMessageList(nil, 0, pttdb.ListOrderNext, false)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range msgs {
+		err = pm.indexMessage(msg, false)
+		if err != nil {
+			log.Warn("RebuildSearchIndex: unable to index message", "msg", msg.ID, "e", err, "entity", pm.Entity().IDString())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package prometheus exports the metrics in the registry in the prometheus text-format.
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	Prefix = "gptt_"
+
+	TypeCounter = "counter"
+	TypeGauge   = "gauge"
+	TypeSummary = "summary"
+
+	ContentType = "text/plain; version=0.0.4"
+)
+
+var (
+	Quantiles = []float64{0.5, 0.75, 0.95, 0.99}
+
+	invalidNameChars = regexp.MustCompile("[^a-zA-Z0-9_:]")
+)
+
+type family struct {
+	typ   string
+	lines []string
+}
+
+/*
+Handler returns the http-handler serving the metrics in the registry.
+*/
+func Handler(reg metrics.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		Write(w, reg)
+	})
+}
+
+/*
+Write writes the metrics in the registry in the prometheus text-format.
+
+The labels are parsed from the metric-name as name{key="value",...}.
+The timers are exported in seconds.
+The resetting-timers are not exported because taking the snapshots resets them.
+*/
+func Write(w io.Writer, reg metrics.Registry) error {
+	families := make(map[string]*family)
+
+	reg.Each(func(name string, i interface{}) {
+		name, labels := ParseName(name)
+
+		switch m := i.(type) {
+		case metrics.Counter:
+			addSample(families, name, TypeCounter, "", labels, float64(m.Count()))
+		case metrics.Meter:
+			addSample(families, name, TypeCounter, "", labels, float64(m.Snapshot().Count()))
+		case metrics.Gauge:
+			addSample(families, name, TypeGauge, "", labels, float64(m.Snapshot().Value()))
+		case metrics.GaugeFloat64:
+			addSample(families, name, TypeGauge, "", labels, m.Snapshot().Value())
+		case metrics.Histogram:
+			h := m.Snapshot()
+			addSummary(families, name, labels, h.Percentiles(Quantiles), float64(h.Sum()), h.Count(), 1)
+		case metrics.Timer:
+			t := m.Snapshot()
+			addSummary(families, name, labels, t.Percentiles(Quantiles), float64(t.Sum()), t.Count(), float64(time.Second))
+		}
+	})
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		f := families[name]
+		sort.Strings(f.lines)
+
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, f.typ)
+		for _, line := range f.lines {
+			buf.WriteString(line)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+/*
+ParseName parses the metric-name as the sanitized prometheus-name (with Prefix) and the labels.
+*/
+func ParseName(name string) (string, string) {
+	labels := ""
+	if idx := strings.IndexByte(name, '{'); idx >= 0 && strings.HasSuffix(name, "}") {
+		labels = name[idx+1 : len(name)-1]
+		name = name[:idx]
+	}
+
+	return Prefix + invalidNameChars.ReplaceAllString(name, "_"), labels
+}
+
+func addSample(families map[string]*family, name string, typ string, suffix string, labels string, value float64) {
+	f, ok := families[name]
+	if !ok {
+		f = &family{typ: typ}
+		families[name] = f
+	}
+
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+
+	f.lines = append(f.lines, fmt.Sprintf("%s%s%s %v\n", name, suffix, labels, value))
+}
+
+func addSummary(families map[string]*family, name string, labels string, quantiles []float64, sum float64, count int64, unit float64) {
+	for i, q := range Quantiles {
+		addSample(families, name, TypeSummary, "", joinLabels(labels, fmt.Sprintf("quantile=\"%v\"", q)), quantiles[i]/unit)
+	}
+
+	addSample(families, name, TypeSummary, "_sum", labels, sum/unit)
+	addSample(families, name, TypeSummary, "_count", labels, float64(count))
+}
+
+func joinLabels(labels string, label string) string {
+	if labels == "" {
+		return label
+	}
+	return labels + "," + label
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package prometheus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+func TestParseName(t *testing.T) {
+	name, labels := ParseName(`service/msg/in/packets{code="op",peer="me"}`)
+	if name != "gptt_service_msg_in_packets" {
+		t.Errorf("name: %v", name)
+	}
+	if labels != `code="op",peer="me"` {
+		t.Errorf("labels: %v", labels)
+	}
+
+	name, labels = ParseName("p2p/InboundTraffic")
+	if name != "gptt_p2p_InboundTraffic" || labels != "" {
+		t.Errorf("name: %v labels: %v", name, labels)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	origEnabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = origEnabled }()
+
+	reg := metrics.NewRegistry()
+	metrics.GetOrRegisterCounter(`service/msg/in/packets{code="op",peer="me"}`, reg).Inc(3)
+	metrics.GetOrRegisterCounter(`service/msg/in/packets{code="op-ack",peer="hub"}`, reg).Inc(1)
+	metrics.GetOrRegisterGauge(`service/oplog/pending{entity="e1",oplog="bdlg"}`, reg).Update(5)
+	metrics.GetOrRegisterTimer(`service/sync/oplog/duration{merkle="m1"}`, reg).Update(2 * time.Second)
+
+	var buf bytes.Buffer
+	err := Write(&buf, reg)
+	if err != nil {
+		t.Fatalf("unable to write: e: %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"# TYPE gptt_service_msg_in_packets counter\n" +
+			"gptt_service_msg_in_packets{code=\"op\",peer=\"me\"} 3\n" +
+			"gptt_service_msg_in_packets{code=\"op-ack\",peer=\"hub\"} 1\n",
+		"# TYPE gptt_service_oplog_pending gauge\ngptt_service_oplog_pending{entity=\"e1\",oplog=\"bdlg\"} 5\n",
+		"# TYPE gptt_service_sync_oplog_duration summary\n",
+		"gptt_service_sync_oplog_duration{merkle=\"m1\",quantile=\"0.5\"} 2\n",
+		"gptt_service_sync_oplog_duration_count{merkle=\"m1\"} 1\n",
+		"gptt_service_sync_oplog_duration_sum{merkle=\"m1\"} 2\n",
+	}
+	for _, each := range expected {
+		if !strings.Contains(out, each) {
+			t.Errorf("missing %q in:\n%v", each, out)
+		}
+	}
+
+	if strings.Count(out, "# TYPE gptt_service_msg_in_packets") != 1 {
+		t.Errorf("duplicated TYPE:\n%v", out)
+	}
+}
@@ -19,5 +19,11 @@ package key
 import "errors"
 
 var (
-	ErrInvalidKey = errors.New("invalid key")
+	ErrInvalidKey     = errors.New("invalid key")
+	ErrInvalidKeyType = errors.New("invalid key type")
+
+	ErrInvalidKeystore      = errors.New("invalid keystore")
+	ErrInvalidPassphrase    = errors.New("invalid passphrase")
+	ErrKeystoreLocked       = errors.New("keystore locked")
+	ErrKeystoreNotEncrypted = errors.New("keystore not encrypted")
 )
@@ -30,6 +30,27 @@ var (
 	BitSize = 256
 )
 
+// keystore
+const (
+	KeystoreVersion  = 1
+	KeystoreCipher   = "aes-256-gcm"
+	KeystoreKDF      = "scrypt"
+	KeystoreSizeSalt = 32
+	KeystoreSizeKey  = 32
+)
+
+// scrypt parameters. N is var for the tests.
+var (
+	KeystoreScryptN = 1 << 18
+	KeystoreScryptR = 8
+	KeystoreScryptP = 1
+)
+
+// libp2p
+var (
+	p2pEd25519Domain = []byte("pttai-p2p-ed25519")
+)
+
 func init() {
 	priv := new(ecdsa.PrivateKey)
 	priv.PublicKey.Curve = crypto.S256()
@@ -16,14 +16,32 @@
 
 package key
 
-import "testing"
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
 
 const ()
 
-var ()
+var (
+	tDataDir    string
+	origScryptN int
+)
 
 func setupTest(t *testing.T) {
+	var err error
+	tDataDir, err = ioutil.TempDir("", "key-test")
+	if err != nil {
+		t.Fatalf("unable to create data-dir: e: %v", err)
+	}
+
+	origScryptN = KeystoreScryptN
+	KeystoreScryptN = 1 << 10
 }
 
 func teardownTest(t *testing.T) {
+	KeystoreScryptN = origScryptN
+
+	os.RemoveAll(tDataDir)
 }
@@ -0,0 +1,135 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package key
+
+import (
+	"crypto/rand"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ed25519"
+)
+
+/*
+Type is the signature scheme of the key.
+TypeSecp256k1 is the zero-value to be backward-compatible with the existing keys and signatures.
+*/
+type Type uint8
+
+const (
+	TypeSecp256k1 Type = iota
+	TypeEd25519
+
+	NType
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeSecp256k1:
+		return "secp256k1"
+	case TypeEd25519:
+		return "ed25519"
+	}
+	return "unknown"
+}
+
+func ParseType(str string) (Type, error) {
+	switch strings.ToLower(str) {
+	case "", "secp256k1":
+		return TypeSecp256k1, nil
+	case "ed25519":
+		return TypeEd25519, nil
+	}
+	return NType, ErrInvalidKeyType
+}
+
+/**********
+ * Ed25519
+ **********/
+
+func GenerateEd25519Key() (ed25519.PrivateKey, error) {
+	_, privKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return privKey, nil
+}
+
+/*
+Ed25519KeyFromSeed gets the ed25519 private key from the 32-bytes seed (the stored key-bytes).
+*/
+func Ed25519KeyFromSeed(seed []byte) (ed25519.PrivateKey, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, ErrInvalidKey
+	}
+
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+func Ed25519KeyToSeed(privKey ed25519.PrivateKey) []byte {
+	return common.CopyBytes(privKey[:ed25519.SeedSize])
+}
+
+func Ed25519KeyToPubkeyBytes(privKey ed25519.PrivateKey) []byte {
+	return common.CopyBytes(privKey[ed25519.SeedSize:])
+}
+
+/**********
+ * Typed
+ **********/
+
+/*
+PubkeyBytesToAddressWithType gets the address of the public key based on the key-type.
+*/
+func PubkeyBytesToAddressWithType(keyType Type, pubBytes []byte) (common.Address, error) {
+	switch keyType {
+	case TypeSecp256k1:
+		if len(pubBytes) == 0 {
+			return common.Address{}, ErrInvalidKey
+		}
+		return PubkeyBytesToAddress(pubBytes), nil
+	case TypeEd25519:
+		if len(pubBytes) != ed25519.PublicKeySize {
+			return common.Address{}, ErrInvalidKey
+		}
+		return common.BytesToAddress(crypto.Keccak256(pubBytes)[12:]), nil
+	}
+
+	return common.Address{}, ErrInvalidKeyType
+}
+
+/*
+VerifySignature verifies the signature of the hash based on the key-type.
+*/
+func VerifySignature(keyType Type, pubBytes []byte, hash []byte, sig []byte) bool {
+	switch keyType {
+	case TypeSecp256k1:
+		if len(sig) < 64 {
+			return false
+		}
+		return crypto.VerifySignature(pubBytes, hash, sig[:64])
+	case TypeEd25519:
+		if len(pubBytes) != ed25519.PublicKeySize || len(sig) != ed25519.SignatureSize {
+			return false
+		}
+		return ed25519.Verify(ed25519.PublicKey(pubBytes), hash, sig)
+	}
+
+	return false
+}
@@ -0,0 +1,480 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package key
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/scrypt"
+)
+
+/*
+keystoreJSON is the passphrase-protected key-file format (in the spirit of the ethereum json-keystore):
+
+	the private-key is sealed with aes-256-gcm,
+	with the key derived from the passphrase by scrypt.
+*/
+type keystoreJSON struct {
+	Version int            `json:"version"`
+	Crypto  keystoreCrypto `json:"crypto"`
+}
+
+type keystoreCrypto struct {
+	Cipher     string            `json:"cipher"`
+	CipherText string            `json:"ciphertext"`
+	Nonce      string            `json:"nonce"`
+	KDF        string            `json:"kdf"`
+	KDFParams  keystoreKDFParams `json:"kdfparams"`
+}
+
+type keystoreKDFParams struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+/*
+EncryptKey encrypts the private-key with the passphrase to the keystore-json.
+*/
+func EncryptKey(privKey *ecdsa.PrivateKey, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, KeystoreSizeSalt)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	params := keystoreKDFParams{
+		N:     KeystoreScryptN,
+		R:     KeystoreScryptR,
+		P:     KeystoreScryptP,
+		DKLen: KeystoreSizeKey,
+		Salt:  hex.EncodeToString(salt),
+	}
+
+	aead, err := params.newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText := aead.Seal(nil, nonce, crypto.FromECDSA(privKey), nil)
+
+	k := &keystoreJSON{
+		Version: KeystoreVersion,
+		Crypto: keystoreCrypto{
+			Cipher:     KeystoreCipher,
+			CipherText: hex.EncodeToString(cipherText),
+			Nonce:      hex.EncodeToString(nonce),
+			KDF:        KeystoreKDF,
+			KDFParams:  params,
+		},
+	}
+
+	return json.MarshalIndent(k, "", "  ")
+}
+
+/*
+DecryptKey decrypts the keystore-json with the passphrase.
+*/
+func DecryptKey(keyJSON []byte, passphrase []byte) (*ecdsa.PrivateKey, error) {
+	k := &keystoreJSON{}
+	err := json.Unmarshal(keyJSON, k)
+	if err != nil {
+		return nil, ErrInvalidKeystore
+	}
+
+	if k.Version != KeystoreVersion || k.Crypto.Cipher != KeystoreCipher || k.Crypto.KDF != KeystoreKDF {
+		return nil, ErrInvalidKeystore
+	}
+
+	salt, err := hex.DecodeString(k.Crypto.KDFParams.Salt)
+	if err != nil {
+		return nil, ErrInvalidKeystore
+	}
+	nonce, err := hex.DecodeString(k.Crypto.Nonce)
+	if err != nil {
+		return nil, ErrInvalidKeystore
+	}
+	cipherText, err := hex.DecodeString(k.Crypto.CipherText)
+	if err != nil {
+		return nil, ErrInvalidKeystore
+	}
+
+	aead, err := k.Crypto.KDFParams.newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, ErrInvalidKeystore
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrInvalidKeystore
+	}
+
+	keyBytes, err := aead.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+
+	return crypto.ToECDSA(keyBytes)
+}
+
+func (p *keystoreKDFParams) newAEAD(passphrase []byte, salt []byte) (cipher.AEAD, error) {
+	if p.DKLen != KeystoreSizeKey {
+		return nil, ErrInvalidKeystore
+	}
+
+	derivedKey, err := scrypt.Key(passphrase, salt, p.N, p.R, p.P, p.DKLen)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+/*
+IsKeystoreJSON checks whether the content of the key-file is in the keystore-json format.
+The plaintext key-files (crypto.SaveECDSA) are in hex and never start with '{'.
+*/
+func IsKeystoreJSON(theBytes []byte) bool {
+	theBytes = bytes.TrimSpace(theBytes)
+
+	return len(theBytes) > 0 && theBytes[0] == '{'
+}
+
+/**********
+ * Keystore
+ **********/
+
+/*
+Keystore loads / saves the key-files of the node and me with the passphrase.
+
+ 1. Without the passphrase, the key-files are saved in plaintext (backward-compatible).
+ 2. With the passphrase, the key-files are saved in keystore-json,
+    and the plaintext key-files are migrated when loaded.
+ 3. Lock forgets the passphrase in memory. The encrypted key-files are not accessible until Unlock.
+
+A nil Keystore is valid and always works in plaintext.
+*/
+type Keystore struct {
+	lock sync.RWMutex
+
+	passphrase []byte
+	isLocked   bool
+
+	// the key-files loaded / saved through the keystore, re-encrypted in ChangePassphrase.
+	files map[string]bool
+}
+
+func NewKeystore(passphrase []byte) *Keystore {
+	return &Keystore{
+		passphrase: passphrase,
+		files:      make(map[string]bool),
+	}
+}
+
+func (ks *Keystore) IsEncrypted() bool {
+	if ks == nil {
+		return false
+	}
+
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
+	return ks.isLocked || len(ks.passphrase) != 0
+}
+
+func (ks *Keystore) IsLocked() bool {
+	if ks == nil {
+		return false
+	}
+
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
+	return ks.isLocked
+}
+
+/*
+LoadECDSA loads the private-key from the key-file, either in keystore-json or in plaintext.
+The plaintext key-file is migrated to keystore-json if we have the passphrase.
+*/
+func (ks *Keystore) LoadECDSA(filename string) (*ecdsa.PrivateKey, error) {
+	if ks == nil {
+		return crypto.LoadECDSA(filename)
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	privKey, isPlaintext, err := ks.loadECDSA(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	ks.files[filename] = true
+
+	if !isPlaintext || len(ks.passphrase) == 0 {
+		return privKey, nil
+	}
+
+	log.Info("Keystore.LoadECDSA: to migrate plaintext key-file", "file", filename)
+	err = ks.saveECDSA(filename, privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return privKey, nil
+}
+
+func (ks *Keystore) loadECDSA(filename string) (*ecdsa.PrivateKey, bool, error) {
+	theBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !IsKeystoreJSON(theBytes) {
+		privKey, err := crypto.LoadECDSA(filename)
+		if err != nil {
+			return nil, false, err
+		}
+		return privKey, true, nil
+	}
+
+	if ks.isLocked {
+		return nil, false, ErrKeystoreLocked
+	}
+
+	if len(ks.passphrase) == 0 {
+		return nil, false, ErrInvalidPassphrase
+	}
+
+	privKey, err := DecryptKey(theBytes, ks.passphrase)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return privKey, false, nil
+}
+
+/*
+SaveECDSA saves the private-key to the key-file, in keystore-json if we have the passphrase.
+*/
+func (ks *Keystore) SaveECDSA(filename string, privKey *ecdsa.PrivateKey) error {
+	if ks == nil {
+		return crypto.SaveECDSA(filename, privKey)
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	if ks.isLocked {
+		return ErrKeystoreLocked
+	}
+
+	err := ks.saveECDSA(filename, privKey)
+	if err != nil {
+		return err
+	}
+
+	ks.files[filename] = true
+
+	return nil
+}
+
+func (ks *Keystore) saveECDSA(filename string, privKey *ecdsa.PrivateKey) error {
+	if len(ks.passphrase) == 0 {
+		return crypto.SaveECDSA(filename, privKey)
+	}
+
+	theBytes, err := EncryptKey(privKey, ks.passphrase)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filename, theBytes)
+}
+
+/*
+Register adds the key-file to the keystore without loading it.
+*/
+func (ks *Keystore) Register(filename string) {
+	if ks == nil {
+		return
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.files[filename] = true
+}
+
+/*
+Forget removes the key-file from the keystore (the key-file is removed / revoked).
+*/
+func (ks *Keystore) Forget(filename string) {
+	if ks == nil {
+		return
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	delete(ks.files, filename)
+}
+
+/*
+ChangePassphrase re-encrypts all the key-files in the keystore with the new passphrase.
+
+Empty newPassphrase turns the key-files back to plaintext.
+*/
+func (ks *Keystore) ChangePassphrase(oldPassphrase []byte, newPassphrase []byte) error {
+	if ks == nil {
+		return ErrInvalidKeystore
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	if !bytes.Equal(oldPassphrase, ks.passphrase) && !ks.isLocked {
+		return ErrInvalidPassphrase
+	}
+
+	// load all the keys with the old passphrase before re-encrypting any of them.
+	origPassphrase, origIsLocked := ks.passphrase, ks.isLocked
+	ks.passphrase, ks.isLocked = oldPassphrase, false
+
+	privKeys := make(map[string]*ecdsa.PrivateKey)
+	for filename := range ks.files {
+		privKey, _, err := ks.loadECDSA(filename)
+		if os.IsNotExist(err) {
+			delete(ks.files, filename)
+			continue
+		}
+		if err != nil {
+			ks.passphrase, ks.isLocked = origPassphrase, origIsLocked
+			return err
+		}
+		privKeys[filename] = privKey
+	}
+
+	ks.passphrase = newPassphrase
+	for filename, privKey := range privKeys {
+		err := ks.saveECDSA(filename, privKey)
+		if err != nil {
+			log.Error("Keystore.ChangePassphrase: unable to save key-file", "file", filename, "e", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+Lock forgets the passphrase in memory.
+*/
+func (ks *Keystore) Lock() error {
+	if ks == nil {
+		return ErrInvalidKeystore
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	if ks.isLocked {
+		return nil
+	}
+
+	if len(ks.passphrase) == 0 {
+		return ErrKeystoreNotEncrypted
+	}
+
+	ks.passphrase = nil
+	ks.isLocked = true
+
+	return nil
+}
+
+/*
+Unlock sets the passphrase after verifying it with the key-files.
+*/
+func (ks *Keystore) Unlock(passphrase []byte) error {
+	if ks == nil {
+		return ErrInvalidKeystore
+	}
+
+	if len(passphrase) == 0 {
+		return ErrInvalidPassphrase
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	if !ks.isLocked {
+		if !bytes.Equal(passphrase, ks.passphrase) {
+			return ErrInvalidPassphrase
+		}
+		return nil
+	}
+
+	for filename := range ks.files {
+		theBytes, err := ioutil.ReadFile(filename)
+		if err != nil || !IsKeystoreJSON(theBytes) {
+			continue
+		}
+
+		_, err = DecryptKey(theBytes, passphrase)
+		if err != nil {
+			return err
+		}
+	}
+
+	ks.passphrase = passphrase
+	ks.isLocked = false
+
+	return nil
+}
+
+func writeFileAtomic(filename string, theBytes []byte) error {
+	tmpFilename := filename + ".tmp"
+	err := ioutil.WriteFile(tmpFilename, theBytes, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFilename, filename)
+}
@@ -0,0 +1,158 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package key
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestEncryptDecryptKey(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	privKey, _ := GenerateKey()
+
+	keyJSON, err := EncryptKey(privKey, []byte("passphrase"))
+	if err != nil {
+		t.Errorf("EncryptKey: e: %v", err)
+		return
+	}
+
+	// define test-structure
+	type args struct {
+		keyJSON    []byte
+		passphrase []byte
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			args: args{keyJSON: keyJSON, passphrase: []byte("passphrase")},
+		},
+		{
+			args:    args{keyJSON: keyJSON, passphrase: []byte("wrong")},
+			wantErr: ErrInvalidPassphrase,
+		},
+		{
+			args:    args{keyJSON: []byte("{}"), passphrase: []byte("passphrase")},
+			wantErr: ErrInvalidKeystore,
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecryptKey(tt.args.keyJSON, tt.args.passphrase)
+			if err != tt.wantErr {
+				t.Errorf("DecryptKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(crypto.FromECDSA(got), crypto.FromECDSA(privKey)) {
+				t.Errorf("DecryptKey() = %v, want %v", got, privKey)
+			}
+		})
+	}
+
+	// teardown test
+}
+
+func TestKeystore(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	privKey, _ := GenerateKey()
+	keyfile := filepath.Join(tDataDir, "mykey")
+
+	// plaintext key-file
+	err := crypto.SaveECDSA(keyfile, privKey)
+	if err != nil {
+		t.Errorf("SaveECDSA: e: %v", err)
+		return
+	}
+
+	// migrate
+	ks := NewKeystore([]byte("passphrase"))
+	_, err = ks.LoadECDSA(keyfile)
+	if err != nil {
+		t.Errorf("LoadECDSA: e: %v", err)
+		return
+	}
+
+	theBytes, _ := ioutil.ReadFile(keyfile)
+	if !IsKeystoreJSON(theBytes) {
+		t.Errorf("LoadECDSA: not migrated")
+	}
+
+	// change passphrase
+	err = ks.ChangePassphrase([]byte("wrong"), []byte("passphrase2"))
+	if err != ErrInvalidPassphrase {
+		t.Errorf("ChangePassphrase: e: %v want: %v", err, ErrInvalidPassphrase)
+	}
+
+	err = ks.ChangePassphrase([]byte("passphrase"), []byte("passphrase2"))
+	if err != nil {
+		t.Errorf("ChangePassphrase: e: %v", err)
+	}
+
+	_, err = NewKeystore([]byte("passphrase")).LoadECDSA(keyfile)
+	if err != ErrInvalidPassphrase {
+		t.Errorf("LoadECDSA: old passphrase: e: %v want: %v", err, ErrInvalidPassphrase)
+	}
+
+	// lock
+	err = ks.Lock()
+	if err != nil {
+		t.Errorf("Lock: e: %v", err)
+	}
+
+	_, err = ks.LoadECDSA(keyfile)
+	if err != ErrKeystoreLocked {
+		t.Errorf("LoadECDSA: locked: e: %v want: %v", err, ErrKeystoreLocked)
+	}
+
+	err = ks.Unlock([]byte("passphrase"))
+	if err != ErrInvalidPassphrase {
+		t.Errorf("Unlock: e: %v want: %v", err, ErrInvalidPassphrase)
+	}
+
+	err = ks.Unlock([]byte("passphrase2"))
+	if err != nil {
+		t.Errorf("Unlock: e: %v", err)
+	}
+
+	got, err := ks.LoadECDSA(keyfile)
+	if err != nil {
+		t.Errorf("LoadECDSA: e: %v", err)
+		return
+	}
+	if !reflect.DeepEqual(crypto.FromECDSA(got), crypto.FromECDSA(privKey)) {
+		t.Errorf("LoadECDSA: got: %v want: %v", got, privKey)
+	}
+
+	// teardown test
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/ailabstw/go-pttai/log"
 	"github.com/btcsuite/btcd/btcec"
 	p2pcrypto "github.com/libp2p/go-libp2p-crypto"
+	"golang.org/x/crypto/ed25519"
 
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -102,3 +103,57 @@ func P2PPubKeyBytesToPubKeyBytes(theBytes []byte) ([]byte, error) {
 	}
 	return crypto.FromECDSAPub(pubKey), nil
 }
+
+/*
+PrivateKeyToP2PPrivKeyWithType gets the libp2p identity of the node key based on the key-type.
+
+The ed25519 identity is derived from the node key (DeriveP2PEd25519Key),
+so that the identity is the same across restarts without another key-file.
+*/
+func PrivateKeyToP2PPrivKeyWithType(keyType Type, key *ecdsa.PrivateKey) (p2pcrypto.PrivKey, error) {
+	switch keyType {
+	case TypeSecp256k1:
+		return PrivateKeyToP2PPrivKey(key)
+	case TypeEd25519:
+		edKey, err := DeriveP2PEd25519Key(key)
+		if err != nil {
+			return nil, err
+		}
+		privKey, _, err := Ed25519KeyToP2PKey(edKey)
+		return privKey, err
+	}
+
+	return nil, ErrInvalidKeyType
+}
+
+/*
+DeriveP2PEd25519Key derives the ed25519 libp2p identity from the node key.
+The seed is keccak("pttai-p2p-ed25519" || node-key) to separate it from the other uses of the node key.
+*/
+func DeriveP2PEd25519Key(key *ecdsa.PrivateKey) (ed25519.PrivateKey, error) {
+	seed := crypto.Keccak256(p2pEd25519Domain, crypto.FromECDSA(key))
+
+	return Ed25519KeyFromSeed(seed)
+}
+
+func Ed25519KeyToP2PKey(key ed25519.PrivateKey) (p2pcrypto.PrivKey, p2pcrypto.PubKey, error) {
+	privKey, err := p2pcrypto.UnmarshalEd25519PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pubKey := privKey.GetPublic()
+	return privKey, pubKey, nil
+}
+
+func P2PPubkeyToEd25519Pubkey(theKey p2pcrypto.PubKey) (ed25519.PublicKey, error) {
+	if _, ok := theKey.(*p2pcrypto.Ed25519PublicKey); !ok {
+		return nil, ErrInvalidKey
+	}
+
+	pubBytes, err := theKey.Raw()
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.PublicKey(pubBytes), nil
+}
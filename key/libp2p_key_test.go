@@ -0,0 +1,86 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package key
+
+import (
+	"bytes"
+	"testing"
+
+	p2pcrypto "github.com/libp2p/go-libp2p-crypto"
+)
+
+func TestPrivateKeyToP2PPrivKeyWithType(t *testing.T) {
+	// setup test
+	nodeKey, _ := GenerateKey()
+	nodeKey2, _ := GenerateKey()
+
+	// prepare test-cases
+	tests := []struct {
+		name     string
+		keyType  Type
+		wantType interface{}
+		wantErr  bool
+	}{
+		{name: "secp256k1", keyType: TypeSecp256k1, wantType: &p2pcrypto.Secp256k1PrivateKey{}},
+		{name: "ed25519", keyType: TypeEd25519, wantType: &p2pcrypto.Ed25519PrivateKey{}},
+		{name: "invalid", keyType: NType, wantErr: true},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PrivateKeyToP2PPrivKeyWithType(tt.keyType, nodeKey)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PrivateKeyToP2PPrivKeyWithType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			switch tt.wantType.(type) {
+			case *p2pcrypto.Secp256k1PrivateKey:
+				_, ok := got.(*p2pcrypto.Secp256k1PrivateKey)
+				if !ok {
+					t.Errorf("PrivateKeyToP2PPrivKeyWithType() = %T, want %T", got, tt.wantType)
+				}
+			case *p2pcrypto.Ed25519PrivateKey:
+				_, ok := got.(*p2pcrypto.Ed25519PrivateKey)
+				if !ok {
+					t.Errorf("PrivateKeyToP2PPrivKeyWithType() = %T, want %T", got, tt.wantType)
+				}
+			}
+
+			// the same identity from the same node key.
+			got2, _ := PrivateKeyToP2PPrivKeyWithType(tt.keyType, nodeKey)
+			if !got.Equals(got2) {
+				t.Errorf("PrivateKeyToP2PPrivKeyWithType() not deterministic")
+			}
+
+			got3, _ := PrivateKeyToP2PPrivKeyWithType(tt.keyType, nodeKey2)
+			if got.Equals(got3) {
+				t.Errorf("PrivateKeyToP2PPrivKeyWithType() same identity for different node keys")
+			}
+		})
+	}
+
+	// the derived key is not the raw node key.
+	edKey, _ := DeriveP2PEd25519Key(nodeKey)
+	if bytes.Equal(Ed25519KeyToSeed(edKey), nodeKey.D.Bytes()) {
+		t.Errorf("DeriveP2PEd25519Key() seed is the node key")
+	}
+}
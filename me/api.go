@@ -17,10 +17,13 @@
 package me
 
 import (
+	"context"
+
 	"github.com/ailabstw/go-pttai/account"
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/content"
 	"github.com/ailabstw/go-pttai/pttdb"
+	"github.com/ailabstw/go-pttai/rpc"
 	pkgservice "github.com/ailabstw/go-pttai/service"
 )
 
@@ -52,6 +55,32 @@ func (api *PrivateAPI) SetMyImage(imgStr string) (*account.UserImg, error) {
 	return api.b.SetMyImage(imgStr)
 }
 
+/*
+SetPrivacySetting sets whether to share my read-receipts and my typing-states with friends and board members.
+*/
+func (api *PrivateAPI) SetPrivacySetting(isShareReadReceipt bool, isShareTyping bool) (*pkgservice.PrivacySetting, error) {
+	return api.b.SetPrivacySetting(isShareReadReceipt, isShareTyping)
+}
+
+func (api *PrivateAPI) GetPrivacySetting() (*pkgservice.PrivacySetting, error) {
+	return api.b.GetPrivacySetting()
+}
+
+/*
+BlockUser blocks the user locally. The contents from the user are hidden and the friend-requests from the user are dropped.
+*/
+func (api *PrivateAPI) BlockUser(userID string) (*pkgservice.BlockList, error) {
+	return api.b.BlockUser([]byte(userID))
+}
+
+func (api *PrivateAPI) UnblockUser(userID string) (*pkgservice.BlockList, error) {
+	return api.b.UnblockUser([]byte(userID))
+}
+
+func (api *PrivateAPI) GetBlockList() (*pkgservice.BlockList, error) {
+	return api.b.GetBlockList()
+}
+
 /**********
  * Revoke
  **********/
@@ -175,6 +204,39 @@ func (api *PrivateAPI) RemoveBoardRequests(entityID string, hash []byte) (bool,
 	return api.b.RemoveBoardRequests([]byte(entityID), hash)
 }
 
+/**********
+ * JoinChat
+ **********/
+
+func (api *PrivateAPI) JoinChat(chatURL string) (*pkgservice.BackendJoinRequest, error) {
+	return api.b.JoinChat([]byte(chatURL))
+}
+
+/*
+GetChatRequests get the chat-requests from me to the others.
+*/
+func (api *PrivateAPI) GetChatRequests(entityID string) ([]*pkgservice.BackendJoinRequest, error) {
+	var err error
+	if len(entityID) == 0 {
+		entityID, err = api.b.GetMyIDStr()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return api.b.GetChatRequests([]byte(entityID))
+}
+
+func (api *PrivateAPI) RemoveChatRequests(entityID string, hash []byte) (bool, error) {
+	var err error
+	if len(entityID) == 0 {
+		entityID, err = api.b.GetMyIDStr()
+		if err != nil {
+			return false, err
+		}
+	}
+	return api.b.RemoveChatRequests([]byte(entityID), hash)
+}
+
 /**********
  * Op
  **********/
@@ -313,6 +375,17 @@ func (api *PrivateAPI) RequestRaftLead() (bool, error) {
 	return api.b.RequestRaftLead()
 }
 
+/**********
+ * PttOplog
+ **********/
+
+/*
+PttOplogs creates a subscription (me_subscribe("pttOplogs")) receiving the ptt-oplogs (notifications of new articles / comments / friends) when they are saved.
+*/
+func (api *PrivateAPI) PttOplogs(ctx context.Context) (*rpc.Subscription, error) {
+	return api.b.SubscribePttOplogs(ctx)
+}
+
 /**********
  * MeOplog
  **********/
@@ -478,6 +551,22 @@ func (api *PrivateAPI) RefreshMyNodeSignKey() (*pkgservice.KeyInfo, error) {
 	return api.b.RefreshMyNodeSignKey()
 }
 
+func (api *PrivateAPI) ExportBackup(passphrase string) (string, error) {
+	return api.b.ExportBackup([]byte(passphrase))
+}
+
+func (api *PrivateAPI) ChangePassphrase(oldPassphrase string, newPassphrase string) (bool, error) {
+	return api.b.ChangePassphrase([]byte(oldPassphrase), []byte(newPassphrase))
+}
+
+func (api *PrivateAPI) Lock() (bool, error) {
+	return api.b.Lock()
+}
+
+func (api *PrivateAPI) Unlock(passphrase string) (bool, error) {
+	return api.b.Unlock([]byte(passphrase))
+}
+
 /**********
  * Misc
  **********/
@@ -18,6 +18,7 @@ package me
 
 import (
 	"github.com/ailabstw/go-pttai/account"
+	"github.com/ailabstw/go-pttai/chat"
 	"github.com/ailabstw/go-pttai/content"
 	"github.com/ailabstw/go-pttai/friend"
 	"github.com/ailabstw/go-pttai/log"
@@ -33,11 +34,12 @@ type Backend struct {
 	accountBackend *account.Backend
 	contentBackend *content.Backend
 	friendBackend  *friend.Backend
+	chatBackend    *chat.Backend
 
 	myPtt pkgservice.MyPtt
 }
 
-func NewBackend(ctx *pkgservice.ServiceContext, cfg *Config, ptt pkgservice.MyPtt, accountBackend *account.Backend, contentBackend *content.Backend, friendBacked *friend.Backend) (*Backend, error) {
+func NewBackend(ctx *pkgservice.ServiceContext, cfg *Config, ptt pkgservice.MyPtt, accountBackend *account.Backend, contentBackend *content.Backend, friendBacked *friend.Backend, chatBackend *chat.Backend) (*Backend, error) {
 	err := InitMe(cfg.DataDir)
 	if err != nil {
 		return nil, err
@@ -50,6 +52,7 @@ func NewBackend(ctx *pkgservice.ServiceContext, cfg *Config, ptt pkgservice.MyPt
 		accountBackend: accountBackend,
 		contentBackend: contentBackend,
 		friendBackend:  friendBacked,
+		chatBackend:    chatBackend,
 	}
 
 	spm, err := NewServiceProtocolManager(cfg.ID, ptt, backend, contentBackend)
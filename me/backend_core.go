@@ -17,14 +17,21 @@
 package me
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/ailabstw/go-pttai/account"
+	"github.com/ailabstw/go-pttai/backup"
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/content"
+	"github.com/ailabstw/go-pttai/key"
 	"github.com/ailabstw/go-pttai/log"
 	"github.com/ailabstw/go-pttai/p2p/discover"
 	"github.com/ailabstw/go-pttai/pttdb"
+	"github.com/ailabstw/go-pttai/rpc"
 	pkgservice "github.com/ailabstw/go-pttai/service"
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -59,11 +66,79 @@ func (b *Backend) SetMyImage(imgStr string) (*account.UserImg, error) {
 	return myProfilePM.UpdateUserImg(imgStr)
 }
 
+func (b *Backend) SetPrivacySetting(isShareReadReceipt bool, isShareTyping bool) (*pkgservice.PrivacySetting, error) {
+	myInfo := b.SPM().(*ServiceProtocolManager).MyInfo
+
+	setting := &pkgservice.PrivacySetting{
+		IsShareReadReceipt: isShareReadReceipt,
+		IsShareTyping:      isShareTyping,
+	}
+
+	err := myInfo.SavePrivacySetting(setting)
+	if err != nil {
+		return nil, err
+	}
+
+	return setting, nil
+}
+
+func (b *Backend) BlockUser(userIDBytes []byte) (*pkgservice.BlockList, error) {
+	return b.updateBlockList(userIDBytes, true)
+}
+
+func (b *Backend) UnblockUser(userIDBytes []byte) (*pkgservice.BlockList, error) {
+	return b.updateBlockList(userIDBytes, false)
+}
+
+func (b *Backend) updateBlockList(userIDBytes []byte, isBlock bool) (*pkgservice.BlockList, error) {
+	myInfo := b.SPM().(*ServiceProtocolManager).MyInfo
+
+	userID, err := types.UnmarshalTextPttID(userIDBytes, false)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(userID, myInfo.ID) {
+		return nil, types.ErrInvalidID
+	}
+
+	blockList, err := myInfo.LoadBlockList()
+	if err != nil {
+		return nil, err
+	}
+
+	var isUpdated bool
+	if isBlock {
+		isUpdated = blockList.Add(userID)
+	} else {
+		isUpdated = blockList.Remove(userID)
+	}
+	if !isUpdated {
+		return blockList, nil
+	}
+
+	err = myInfo.SaveBlockList(blockList)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockList, nil
+}
+
+func (b *Backend) GetBlockList() (*pkgservice.BlockList, error) {
+	myInfo := b.SPM().(*ServiceProtocolManager).MyInfo
+
+	return myInfo.LoadBlockList()
+}
+
 /**********
  * Key
  **********/
 
 func (b *Backend) ShowMyMasterKey() ([]byte, error) {
+	if b.Config.Keystore.IsLocked() {
+		return nil, key.ErrKeystoreLocked
+	}
+
 	myInfo := b.SPM().(*ServiceProtocolManager).MyInfo
 
 	masterKey := myInfo.GetMasterKey()
@@ -84,6 +159,10 @@ func (b *Backend) ValidateMyMasterKey(keyBytes []byte) (bool, error) {
 }
 
 func (b *Backend) ShowMyNodeKey() ([]byte, error) {
+	if b.Config.Keystore.IsLocked() {
+		return nil, key.ErrKeystoreLocked
+	}
+
 	myInfo := b.SPM().(*ServiceProtocolManager).MyInfo
 
 	key := myInfo.GetNodeKey()
@@ -148,6 +227,115 @@ func (b *Backend) RefreshMyNodeSignKey() (*pkgservice.KeyInfo, error) {
 	return key, nil
 }
 
+/**********
+ * Backup
+ **********/
+
+/*
+ExportBackup exports the passphrase-encrypted backup of the node-data-dir
+to <node-data-dir>/backups/gptt-<ts>.bak, and returns the filename.
+*/
+func (b *Backend) ExportBackup(passphrase []byte) (string, error) {
+	if len(passphrase) == 0 {
+		return "", ErrInvalidPassphrase
+	}
+
+	dataDir := b.Config.NodeDataDir
+	if dataDir == "" {
+		return "", ErrInvalidDataDir
+	}
+
+	backupDir := filepath.Join(dataDir, backup.BackupDirName)
+	err := os.MkdirAll(backupDir, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	tsStr := time.Now().UTC().Format("2006-01-02_15-04-05.000")
+	filename := filepath.Join(backupDir, "gptt-"+tsStr+".bak")
+	tmpFilename := filename + ".tmp"
+
+	f, err := os.OpenFile(tmpFilename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	err = backup.Export(f, dataDir, passphrase)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpFilename)
+		return "", err
+	}
+
+	err = os.Rename(tmpFilename, filename)
+	if err != nil {
+		return "", err
+	}
+
+	log.Info("ExportBackup: done", "filename", filename)
+
+	return filename, nil
+}
+
+/**********
+ * Keystore
+ **********/
+
+/*
+ChangePassphrase re-encrypts the key-files of me and the node with the new passphrase,
+and re-wraps the data-keys of the encrypted databases.
+The plaintext key-files are encrypted if the old passphrase is empty,
+and the key-files are turned back to plaintext if the new passphrase is empty
+(not allowed with the encrypted databases).
+*/
+func (b *Backend) ChangePassphrase(oldPassphrase []byte, newPassphrase []byte) (bool, error) {
+	if len(newPassphrase) == 0 {
+		isEncrypted, err := pttdb.HasEncryptedDatabases(b.Config.NodeDataDir)
+		if err != nil {
+			return false, err
+		}
+		if isEncrypted {
+			return false, pttdb.ErrDBLocked
+		}
+	}
+
+	// the key-files are re-encrypted only after all the data-keys are re-wrapped.
+	err := pttdb.ChangePassphrase(b.Config.NodeDataDir, oldPassphrase, newPassphrase, func() error {
+		return b.Config.ChangePassphrase(oldPassphrase, newPassphrase)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	log.Info("ChangePassphrase: done")
+
+	return true, nil
+}
+
+/*
+Lock forgets the passphrase in memory. The key-files and the private keys are not accessible until Unlock.
+*/
+func (b *Backend) Lock() (bool, error) {
+	err := b.Config.Keystore.Lock()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (b *Backend) Unlock(passphrase []byte) (bool, error) {
+	err := b.Config.Keystore.Unlock(passphrase)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 /**********
  * Join Me
  **********/
@@ -377,6 +565,63 @@ func (b *Backend) RemoveBoardRequests(entityIDBytes []byte, hash []byte) (bool,
 	return pm.RemoveBoardRequests(hash)
 }
 
+/**********
+ * JoinChat
+ **********/
+
+func (b *Backend) JoinChat(chatURL []byte) (*pkgservice.BackendJoinRequest, error) {
+	joinRequest, err := pkgservice.ParseBackendJoinURL(chatURL, pkgservice.PathJoinChat)
+	if err != nil {
+		return nil, err
+	}
+
+	myNodeID := b.myPtt.MyNodeID
+	if reflect.DeepEqual(myNodeID, joinRequest.NodeID) {
+		return nil, ErrInvalidNode
+	}
+
+	myInfo := b.SPM().(*ServiceProtocolManager).MyInfo
+	pm := myInfo.PM().(*ProtocolManager)
+	err = pm.JoinChat(joinRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	backendJoinRequest := pkgservice.JoinRequestToBackendJoinRequest(joinRequest)
+
+	return backendJoinRequest, nil
+}
+
+func (b *Backend) GetChatRequests(entityIDBytes []byte) ([]*pkgservice.BackendJoinRequest, error) {
+
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	joinChatRequests, err := pm.GetChatRequests()
+	if err != nil {
+		return nil, err
+	}
+
+	theList := make([]*pkgservice.BackendJoinRequest, len(joinChatRequests))
+	for i, request := range joinChatRequests {
+		theList[i] = pkgservice.JoinRequestToBackendJoinRequest(request)
+	}
+	return theList, nil
+}
+
+func (b *Backend) RemoveChatRequests(entityIDBytes []byte, hash []byte) (bool, error) {
+	thePM, err := b.EntityIDToPM(entityIDBytes)
+	if err != nil {
+		return false, err
+	}
+	pm := thePM.(*ProtocolManager)
+
+	return pm.RemoveChatRequests(hash)
+}
+
 /**********
  * MyInfo
  **********/
@@ -388,6 +633,13 @@ func (b *Backend) Get() (*BackendMyInfo, error) {
 	return MarshalBackendMyInfo(myInfo, b.myPtt), nil
 }
 
+func (b *Backend) GetPrivacySetting() (*pkgservice.PrivacySetting, error) {
+
+	myInfo := b.SPM().(*ServiceProtocolManager).MyInfo
+
+	return myInfo.LoadPrivacySetting()
+}
+
 func (b *Backend) GetRawMe(entityIDBytes []byte) (*MyInfo, error) {
 
 	entity, err := b.EntityIDToEntity(entityIDBytes)
@@ -538,6 +790,14 @@ func (b *Backend) ForceRemoveNode(nodeIDStr string) (bool, error) {
 	return false, err
 }
 
+/**********
+ * PttOplog
+ **********/
+
+func (b *Backend) SubscribePttOplogs(ctx context.Context) (*rpc.Subscription, error) {
+	return pkgservice.SubscribePttOplogs(ctx, b.Ptt().EventMux())
+}
+
 /**********
  * MeOplog
  **********/
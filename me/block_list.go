@@ -0,0 +1,79 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package me
+
+import (
+	"encoding/json"
+
+	"github.com/ailabstw/go-pttai/common"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+/*
+GetBlockList gets my block-list. Nobody is blocked if the block-list does not exist.
+*/
+func (m *MyInfo) GetBlockList() *pkgservice.BlockList {
+	blockList, err := m.LoadBlockList()
+	if err != nil {
+		return &pkgservice.BlockList{}
+	}
+
+	return blockList
+}
+
+func (m *MyInfo) LoadBlockList() (*pkgservice.BlockList, error) {
+	key, err := m.MarshalBlockListKey()
+	if err != nil {
+		return nil, err
+	}
+
+	blockList := &pkgservice.BlockList{}
+
+	val, err := dbMeta.Get(key)
+	if err == leveldb.ErrNotFound {
+		return blockList, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(val, blockList)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockList, nil
+}
+
+func (m *MyInfo) SaveBlockList(blockList *pkgservice.BlockList) error {
+	key, err := m.MarshalBlockListKey()
+	if err != nil {
+		return err
+	}
+
+	val, err := json.Marshal(blockList)
+	if err != nil {
+		return err
+	}
+
+	return dbMeta.Put(key, val)
+}
+
+func (m *MyInfo) MarshalBlockListKey() ([]byte, error) {
+	return common.Concat([][]byte{DBBlockListPrefix, m.ID[:]})
+}
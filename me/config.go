@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/ailabstw/go-pttai/common/types"
@@ -32,11 +33,15 @@ import (
 )
 
 type Config struct {
-	DataDir string
+	DataDir     string
+	NodeDataDir string `toml:"-"` // the data-dir of the node (for backup)
 
 	PrivateKey *ecdsa.PrivateKey `toml:"-"`
 	ID         *types.PttID      `toml:"-"` // we also need ID because other services need to know ID, but cannot directly acccess private-key and postfix.
 	Postfix    string
+
+	// Keystore loads / saves the key-files with the passphrase (shared with the node key).
+	Keystore *key.Keystore `toml:"-"`
 }
 
 func (c *Config) SetMyKey(hex string, file string, postfix string, isSave bool) error {
@@ -143,7 +148,10 @@ func (c *Config) myKey() (*ecdsa.PrivateKey, string, *types.PttID, error) {
 
 	// retrieve key / id from file
 	keyfile := c.ResolvePath(DataDirPrivateKey)
-	privKey, err := crypto.LoadECDSA(keyfile)
+	privKey, err := c.Keystore.LoadECDSA(keyfile)
+	if isKeystoreErr(err) {
+		return nil, "", nil, err
+	}
 	postfixBytes, err2 := ioutil.ReadFile(keyfile + ".postfix")
 	if err == nil && err2 == nil {
 		id, err := types.NewPttIDFromKeyPostfix(privKey, postfixBytes)
@@ -151,6 +159,13 @@ func (c *Config) myKey() (*ecdsa.PrivateKey, string, *types.PttID, error) {
 			return nil, "", nil, ErrInvalidMe
 		}
 
+		if c.Keystore.IsEncrypted() {
+			err = c.LoadKeyFiles()
+			if err != nil {
+				return nil, "", nil, err
+			}
+		}
+
 		return privKey, string(postfixBytes), id, nil
 	}
 
@@ -186,7 +201,7 @@ func (c *Config) GetDataPrivateKeyByID(myID *types.PttID) (*ecdsa.PrivateKey, er
 	if err != nil {
 		return nil, err
 	}
-	return crypto.LoadECDSA(keyfile)
+	return c.Keystore.LoadECDSA(keyfile)
 }
 
 func (c *Config) ResolvePrivateKeyWithIDPath(myID *types.PttID) (string, error) {
@@ -215,7 +230,7 @@ func (c *Config) ResolvePath(path string) string {
 }
 
 func (c *Config) SaveKey(filename string, key *ecdsa.PrivateKey, postfix string) error {
-	err := crypto.SaveECDSA(filename, key)
+	err := c.Keystore.SaveECDSA(filename, key)
 	if err != nil {
 		return err
 	}
@@ -230,7 +245,7 @@ func (c *Config) SaveKey(filename string, key *ecdsa.PrivateKey, postfix string)
 }
 
 func (c *Config) LoadKey(filename string) (*ecdsa.PrivateKey, *types.PttID, error) {
-	key, err := crypto.LoadECDSA(filename)
+	key, err := c.Keystore.LoadECDSA(filename)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -252,6 +267,8 @@ func (c *Config) DeleteKey() error {
 
 	log.Warn("to Remove keyfile", "keyfile", keyfile, "deleteFile", deleteFile)
 
+	c.Keystore.Forget(keyfile)
+
 	return os.Rename(keyfile, deleteFile)
 }
 
@@ -262,6 +279,7 @@ func (c *Config) RevokeMyKey(myID *types.PttID) error {
 	}
 
 	os.Remove(keyfile)
+	c.Keystore.Forget(keyfile)
 
 	postfixFilename := keyfile + ".postfix"
 	os.Remove(postfixFilename)
@@ -274,5 +292,81 @@ func (c *Config) RevokeKey() error {
 
 	log.Warn("to Revoke keyfile", "keyfile", keyfile)
 
+	c.Keystore.Forget(keyfile)
+
 	return os.Remove(keyfile)
 }
+
+/*
+KeyFiles returns all the key-files of me, including the key-files of the previous ids.
+*/
+func (c *Config) KeyFiles() ([]string, error) {
+	keyfile := c.ResolvePath(DataDirPrivateKey)
+	if keyfile == "" {
+		return nil, nil
+	}
+
+	filenames, err := filepath.Glob(keyfile + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	keyfiles := make([]string, 0, len(filenames))
+	for _, filename := range filenames {
+		if strings.HasSuffix(filename, ".postfix") || strings.HasSuffix(filename, ".deleted") || strings.HasSuffix(filename, ".tmp") {
+			continue
+		}
+		keyfiles = append(keyfiles, filename)
+	}
+
+	return keyfiles, nil
+}
+
+/*
+LoadKeyFiles loads all the key-files through the keystore,
+so the plaintext key-files are migrated and all the key-files are re-encrypted in ChangePassphrase.
+*/
+func (c *Config) LoadKeyFiles() error {
+	keyfiles, err := c.KeyFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, keyfile := range keyfiles {
+		_, err = c.Keystore.LoadECDSA(keyfile)
+		if err != nil {
+			log.Error("LoadKeyFiles: unable to load key-file", "keyfile", keyfile, "e", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+ChangePassphrase re-encrypts all the key-files of me and the node with the new passphrase.
+*/
+func (c *Config) ChangePassphrase(oldPassphrase []byte, newPassphrase []byte) error {
+	if c.Keystore == nil {
+		return key.ErrInvalidKeystore
+	}
+
+	keyfiles, err := c.KeyFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, keyfile := range keyfiles {
+		c.Keystore.Register(keyfile)
+	}
+
+	return c.Keystore.ChangePassphrase(oldPassphrase, newPassphrase)
+}
+
+func isKeystoreErr(err error) bool {
+	switch err {
+	case key.ErrInvalidKeystore, key.ErrInvalidPassphrase, key.ErrKeystoreLocked:
+		return true
+	}
+	return false
+}
@@ -37,4 +37,7 @@ var (
 	ErrUnableToBeLead = errors.New("unable to be lead")
 
 	ErrWithLead = errors.New("with lead")
+
+	ErrInvalidPassphrase = errors.New("invalid passphrase")
+	ErrInvalidDataDir    = errors.New("invalid data-dir")
 )
@@ -85,27 +85,34 @@ const (
 	// sync-friend
 	InternalSyncFriendMsg
 	InternalSyncFriendAckMsg
+
+	// sync-chat
+	InternalSyncChatMsg
+	InternalSyncChatAckMsg
 )
 
 // db
 var (
 	SleepTimeLock = 10
 
-	DBMePrefix                    = []byte(".medb")
-	dbMeCore   *pttdb.LDBDatabase = nil
-	dbMe       *pttdb.LDBBatch    = nil
+	DBMePrefix                  = []byte(".medb")
+	dbMeCore   pttdb.DB         = nil
+	dbMe       pttdb.IndexBatch = nil
 
 	DBMyNodePrefix = []byte(".mndb")
 
-	DBRaftPrefix                    = []byte(".rfdb")
-	dbRaft       *pttdb.LDBDatabase = nil
+	DBRaftPrefix          = []byte(".rfdb")
+	dbRaft       pttdb.DB = nil
+
+	dbMyNodes pttdb.DB = nil
 
-	dbMyNodes *pttdb.LDBDatabase = nil
+	dbMeta pttdb.DB = nil
 
-	dbMeta *pttdb.LDBDatabase = nil
+	DBPrivacySettingPrefix = []byte(".mepv")
+	DBBlockListPrefix      = []byte(".mebl")
 
-	dbKeyCore *pttdb.LDBDatabase = nil
-	dbKey     *pttdb.LDBBatch    = nil
+	dbKeyCore pttdb.DB         = nil
+	dbKey     pttdb.IndexBatch = nil
 
 	DBKeyRaftHardState = []byte(".rfhs")
 	DBKeyRaftSnapshot  = []byte(".rfsn")
@@ -208,7 +215,7 @@ func InitMe(dataDir string) error {
 	var err error
 
 	// db
-	dbMeCore, err = pttdb.NewLDBDatabase("me", dataDir, 0, 0)
+	dbMeCore, err = pttdb.NewDatabase("me", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
@@ -218,22 +225,22 @@ func InitMe(dataDir string) error {
 		return err
 	}
 
-	dbMyNodes, err = pttdb.NewLDBDatabase("mynodes", dataDir, 0, 0)
+	dbMyNodes, err = pttdb.NewDatabase("mynodes", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
 
-	dbRaft, err = pttdb.NewLDBDatabase("raft", dataDir, 0, 0)
+	dbRaft, err = pttdb.NewDatabase("raft", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
 
-	dbMeta, err = pttdb.NewLDBDatabase("memeta", dataDir, 0, 0)
+	dbMeta, err = pttdb.NewDatabase("memeta", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
 
-	dbKeyCore, err = pttdb.NewLDBDatabase("signkey", dataDir, 0, 0)
+	dbKeyCore, err = pttdb.NewDatabase("signkey", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,79 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package me
+
+import (
+	"encoding/json"
+
+	"github.com/ailabstw/go-pttai/common"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+/*
+GetPrivacySetting gets my privacy-setting. Nothing is shared if the setting does not exist.
+*/
+func (m *MyInfo) GetPrivacySetting() *pkgservice.PrivacySetting {
+	setting, err := m.LoadPrivacySetting()
+	if err != nil {
+		return &pkgservice.PrivacySetting{}
+	}
+
+	return setting
+}
+
+func (m *MyInfo) LoadPrivacySetting() (*pkgservice.PrivacySetting, error) {
+	key, err := m.MarshalPrivacySettingKey()
+	if err != nil {
+		return nil, err
+	}
+
+	setting := &pkgservice.PrivacySetting{}
+
+	val, err := dbMeta.Get(key)
+	if err == leveldb.ErrNotFound {
+		return setting, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(val, setting)
+	if err != nil {
+		return nil, err
+	}
+
+	return setting, nil
+}
+
+func (m *MyInfo) SavePrivacySetting(setting *pkgservice.PrivacySetting) error {
+	key, err := m.MarshalPrivacySettingKey()
+	if err != nil {
+		return err
+	}
+
+	val, err := json.Marshal(setting)
+	if err != nil {
+		return err
+	}
+
+	return dbMeta.Put(key, val)
+}
+
+func (m *MyInfo) MarshalPrivacySettingKey() ([]byte, error) {
+	return common.Concat([][]byte{DBPrivacySettingPrefix, m.ID[:]})
+}
@@ -53,6 +53,9 @@ func (pm *ProtocolManager) HandleApproveJoin(dataBytes []byte, hash *common.Addr
 	case pm.IsJoinBoardRequests(hash):
 		log.Debug("HandleApproveJoin: is join-board request", "hash", hash)
 		err = pm.HandleApproveJoinBoard(dataBytes, joinRequest, peer)
+	case pm.IsJoinChatRequests(hash):
+		log.Debug("HandleApproveJoin: is join-chat request", "hash", hash)
+		err = pm.HandleApproveJoinChat(dataBytes, joinRequest, peer)
 	}
 
 	return err
@@ -39,6 +39,13 @@ func (pm *ProtocolManager) HandleApproveJoinBoard(dataBytes []byte, joinRequest
 	// board
 	contentService := pm.Entity().Service().(*Backend).contentBackend
 	contentSPM := contentService.SPM().(*content.ServiceProtocolManager)
+
+	// register-peer: the approver is the creator of the join-key.
+	if peer.UserID == nil {
+		peer.UserID = joinRequest.CreatorID
+		pm.Ptt().FinishIdentifyPeer(peer, false, false)
+	}
+
 	_, err = contentSPM.CreateJoinEntity(boardData, peer, nil, true, true, false, false, true)
 	if err != nil {
 		return err
@@ -0,0 +1,58 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package me
+
+import (
+	"encoding/json"
+
+	"github.com/ailabstw/go-pttai/chat"
+	"github.com/ailabstw/go-pttai/log"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func (pm *ProtocolManager) HandleApproveJoinChat(dataBytes []byte, joinRequest *pkgservice.JoinRequest, peer *pkgservice.PttPeer) error {
+
+	theChatData := chat.NewEmptyApproveJoinChat()
+	approveJoin := &pkgservice.ApproveJoin{Data: theChatData}
+	err := json.Unmarshal(dataBytes, approveJoin)
+	if err != nil {
+		log.Error("HandleApproveJoinChat: unable to unmarshal", "e", err)
+		return err
+	}
+
+	// chat
+	chatService := pm.Entity().Service().(*Backend).chatBackend
+	chatSPM := chatService.SPM().(*chat.ServiceProtocolManager)
+
+	// register-peer: the approver is the creator of the join-key.
+	if peer.UserID == nil {
+		peer.UserID = joinRequest.CreatorID
+		pm.Ptt().FinishIdentifyPeer(peer, false, false)
+	}
+
+	_, err = chatSPM.CreateJoinEntity(theChatData, peer, nil, true, true, false, false, true)
+	if err != nil {
+		return err
+	}
+
+	// remove joinChatRequest
+	pm.lockJoinChatRequest.Lock()
+	defer pm.lockJoinChatRequest.Unlock()
+	delete(pm.joinChatRequests, *joinRequest.Hash)
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package me
+
+import (
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common/types"
+
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+func (pm *ProtocolManager) handleChatLog(
+	oplog *pkgservice.BaseOplog,
+
+	info *ProcessMeInfo,
+) ([]*pkgservice.BaseOplog, error) {
+
+	chatSPM := pm.Entity().Service().(*Backend).chatBackend.SPM()
+
+	opData := &MeOpEntity{}
+
+	return pm.HandleEntityLog(oplog, chatSPM, opData, info, pm.updateChatInfo)
+}
+
+func (pm *ProtocolManager) updateChatInfo(oplog *pkgservice.BaseOplog, info *ProcessMeInfo) {
+	info.ChatInfo[*oplog.ObjID] = oplog
+}
+
+func (pm *ProtocolManager) setNewestChatLog(
+	oplog *pkgservice.BaseOplog,
+) (types.Bool, error) {
+
+	opData := &MeOpEntity{}
+
+	err := oplog.GetData(opData)
+	if err != nil {
+		return true, err
+	}
+
+	chatSPM := pm.Entity().Service().(*Backend).chatBackend.SPM()
+
+	entity := chatSPM.Entity(oplog.ObjID)
+	if entity == nil {
+		return true, err
+	}
+
+	return !types.Bool(reflect.DeepEqual(opData.LogID, entity.GetLogID())), nil
+}
@@ -17,6 +17,7 @@
 package me
 
 import (
+	"github.com/ailabstw/go-pttai/chat"
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/content"
 	"github.com/ailabstw/go-pttai/friend"
@@ -30,6 +31,8 @@ func (m *MyInfo) CreateEntityOpType(entity pkgservice.Entity) (pkgservice.OpType
 		return MeOpTypeCreateBoard, nil
 	case *friend.Friend:
 		return MeOpTypeCreateFriend, nil
+	case *chat.Chat:
+		return MeOpTypeCreateChat, nil
 	}
 	return MeOpTypeInvalid, pkgservice.ErrInvalidEntity
 }
@@ -17,6 +17,7 @@
 package me
 
 import (
+	"github.com/ailabstw/go-pttai/chat"
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/content"
 	"github.com/ailabstw/go-pttai/friend"
@@ -30,6 +31,8 @@ func (m *MyInfo) JoinEntityOpType(entity pkgservice.Entity) (pkgservice.OpType,
 		return MeOpTypeJoinBoard, nil
 	case *friend.Friend:
 		return MeOpTypeJoinFriend, nil
+	case *chat.Chat:
+		return MeOpTypeJoinChat, nil
 	}
 	return MeOpTypeInvalid, pkgservice.ErrInvalidEntity
 }
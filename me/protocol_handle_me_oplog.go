@@ -27,6 +27,7 @@ type ProcessMeInfo struct {
 	MetaInfo     map[types.PttID]*pkgservice.BaseOplog
 	BoardInfo    map[types.PttID]*pkgservice.BaseOplog
 	FriendInfo   map[types.PttID]*pkgservice.BaseOplog
+	ChatInfo     map[types.PttID]*pkgservice.BaseOplog
 }
 
 func NewProcessMeInfo() *ProcessMeInfo {
@@ -35,6 +36,7 @@ func NewProcessMeInfo() *ProcessMeInfo {
 		MetaInfo:     make(map[types.PttID]*pkgservice.BaseOplog),
 		BoardInfo:    make(map[types.PttID]*pkgservice.BaseOplog),
 		FriendInfo:   make(map[types.PttID]*pkgservice.BaseOplog),
+		ChatInfo:     make(map[types.PttID]*pkgservice.BaseOplog),
 	}
 }
 
@@ -65,6 +67,11 @@ func (pm *ProtocolManager) processMeLog(oplog *pkgservice.BaseOplog, processInfo
 	case MeOpTypeJoinFriend:
 		origLogs, err = pm.handleFriendLog(oplog, info)
 
+	case MeOpTypeCreateChat:
+		origLogs, err = pm.handleChatLog(oplog, info)
+	case MeOpTypeJoinChat:
+		origLogs, err = pm.handleChatLog(oplog, info)
+
 	case MeOpTypeSetNodeName:
 	}
 	return
@@ -113,6 +120,11 @@ func (pm *ProtocolManager) postprocessMeOplogs(processInfo pkgservice.ProcessInf
 		pm.InternalSyncFriend(oplog, peer)
 	}
 
+	// chat
+	for _, oplog := range info.ChatInfo {
+		pm.InternalSyncChat(oplog, peer)
+	}
+
 	// delete-me
 
 	log.Debug("postprocessMeOplogs: to check delete-me", "isPending", isPending, "DeleteMeInfo", info.DeleteMeInfo)
@@ -149,6 +161,11 @@ func (pm *ProtocolManager) SetNewestMeOplog(oplog *pkgservice.BaseOplog) (err er
 	case MeOpTypeJoinFriend:
 		isNewer, err = pm.setNewestFriendLog(oplog)
 
+	case MeOpTypeCreateChat:
+		isNewer, err = pm.setNewestChatLog(oplog)
+	case MeOpTypeJoinChat:
+		isNewer, err = pm.setNewestChatLog(oplog)
+
 	case MeOpTypeSetNodeName:
 	}
 
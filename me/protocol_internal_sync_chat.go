@@ -0,0 +1,194 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package me
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/chat"
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+type InternalSyncChatAck struct {
+	LogID *types.PttID `json:"l"`
+
+	ChatData *pkgservice.ApproveJoinEntity `json:"C"`
+}
+
+func (pm *ProtocolManager) InternalSyncChat(
+	oplog *pkgservice.BaseOplog,
+	peer *pkgservice.PttPeer,
+) error {
+
+	syncID := &pkgservice.SyncID{ID: oplog.ObjID, LogID: oplog.ID}
+	log.Debug("InternalSyncChat: to SendDataToPeer", "syncID", syncID, "peer", peer)
+
+	return pm.SendDataToPeer(InternalSyncChatMsg, syncID, peer)
+}
+
+func (pm *ProtocolManager) HandleInternalSyncChat(
+	dataBytes []byte,
+	peer *pkgservice.PttPeer,
+) error {
+
+	syncID := &pkgservice.SyncID{}
+	err := json.Unmarshal(dataBytes, syncID)
+	if err != nil {
+		return err
+	}
+
+	chatSPM := pm.Entity().Service().(*Backend).chatBackend.SPM()
+	theChat := chatSPM.Entity(syncID.ID)
+	if theChat == nil {
+		return types.ErrInvalidID
+	}
+	chatPM := theChat.PM()
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	joinEntity := &pkgservice.JoinEntity{ID: myID}
+	_, theApproveJoinEntity, err := chatPM.ApproveJoin(joinEntity, nil, peer)
+	log.Debug("HandleInternalSyncChat: after ApproveJoin", "e", err)
+	if err != nil {
+		return err
+	}
+
+	approveJoinEntity, ok := theApproveJoinEntity.(*pkgservice.ApproveJoinEntity)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	ackData := &InternalSyncChatAck{LogID: syncID.LogID, ChatData: approveJoinEntity}
+
+	pm.SendDataToPeer(InternalSyncChatAckMsg, ackData, peer)
+
+	return nil
+}
+
+func (pm *ProtocolManager) HandleInternalSyncChatAck(
+	dataBytes []byte,
+	peer *pkgservice.PttPeer,
+
+) error {
+
+	// unmarshal data
+	theChatData := chat.NewEmptyApproveJoinChat()
+
+	data := &InternalSyncChatAck{ChatData: theChatData}
+	err := json.Unmarshal(dataBytes, data)
+	if err != nil {
+		return err
+	}
+
+	// oplog
+	oplog := &pkgservice.BaseOplog{ID: data.LogID}
+	pm.SetMeDB(oplog)
+
+	// lock
+	err = oplog.Lock()
+	if err != nil {
+		return err
+	}
+	defer oplog.Unlock()
+
+	// get
+	err = oplog.Get(data.LogID, true)
+	log.Debug("HandleInternalSyncChatAck: after oplog.Get", "e", err, "isSync", oplog.IsSync)
+	if oplog.IsSync {
+		return nil
+	}
+
+	// lock entity
+	chatSPM := pm.Entity().Service().(*Backend).chatBackend.SPM().(*chat.ServiceProtocolManager)
+
+	err = chatSPM.Lock(oplog.ObjID)
+	if err != nil {
+		return err
+	}
+	defer chatSPM.Unlock(oplog.ObjID)
+
+	theChat := chatSPM.Entity(oplog.ObjID)
+	if theChat == nil {
+		err = pm.handleInternalSyncChatAckNew(chatSPM, theChatData, oplog, peer)
+		if err != nil {
+			return err
+		}
+
+		oplog.IsSync = true
+		oplog.Save(true, pm.meOplogMerkle)
+
+		return nil
+	}
+	c, ok := theChat.(*chat.Chat)
+	if !ok {
+		return pkgservice.ErrInvalidData
+	}
+
+	// exists
+	chatStatus := c.Status
+
+	switch {
+	case chatStatus == types.StatusAlive && reflect.DeepEqual(c.LogID, oplog.ID):
+		err = pm.handleInternalSyncEntityAckSameLog(c, oplog, peer)
+	case chatStatus >= types.StatusTerminal:
+	case chatStatus == types.StatusAlive:
+		err = pm.handleInternalSyncEntityAckDiffAliveLog(c, oplog, peer)
+	default:
+		err = pm.handleInternalSyncChatAckDiffLog(chatSPM, theChatData, oplog, peer)
+	}
+	if err != nil {
+		return err
+	}
+
+	oplog.IsSync = true
+	oplog.Save(true, pm.meOplogMerkle)
+	return nil
+}
+
+func (pm *ProtocolManager) handleInternalSyncChatAckNew(
+	spm *chat.ServiceProtocolManager,
+	data *pkgservice.ApproveJoinEntity,
+	oplog *pkgservice.BaseOplog,
+	peer *pkgservice.PttPeer,
+) error {
+
+	_, err := spm.CreateJoinEntity(data, peer, oplog, true, true, true, true, false)
+	log.Debug("HandleInternalSyncChatAckNew: after CreateJoinEntity", "e", err)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (pm *ProtocolManager) handleInternalSyncChatAckDiffLog(
+	spm *chat.ServiceProtocolManager,
+	data *pkgservice.ApproveJoinEntity,
+	oplog *pkgservice.BaseOplog,
+	peer *pkgservice.PttPeer,
+) error {
+
+	_, err := spm.CreateJoinEntity(data, peer, oplog, false, false, true, true, false)
+	log.Debug("HandleInternalSyncChatAckDiffLog: after CreateJoinEntity", "e", err)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package me
+
+import (
+	"time"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ethereum/go-ethereum/common"
+
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+type JoinChatEvent struct {
+	JoinRequest *pkgservice.JoinRequest
+}
+
+func (pm *ProtocolManager) JoinChat(joinRequest *pkgservice.JoinRequest) error {
+
+	myInfo := pm.Entity().(*MyInfo)
+	if myInfo.Status != types.StatusAlive {
+		return nil
+	}
+
+	// lock
+	pm.lockJoinChatRequest.Lock()
+	defer pm.lockJoinChatRequest.Unlock()
+
+	// hash-val
+	hashVal := *joinRequest.Hash
+
+	_, ok := pm.joinChatRequests[hashVal]
+	if ok {
+		return types.ErrAlreadyExists
+	}
+
+	pm.joinChatRequests[hashVal] = joinRequest
+
+	pm.EventMux().Post(&JoinChatEvent{JoinRequest: joinRequest})
+
+	return nil
+}
+
+func (pm *ProtocolManager) SyncJoinChatLoop() error {
+	log.Debug("SyncJoinChatLoop: Start")
+	ticker := time.NewTicker(SyncJoinSeconds)
+	defer ticker.Stop()
+
+	pm.SyncJoinChat()
+
+loop:
+	for {
+		select {
+		case <-ticker.C:
+			pm.SyncJoinChat()
+		case <-pm.QuitSync():
+			log.Info("SyncJoinChatLoop: QuitSync", "entity", pm.Entity().IDString())
+			break loop
+		}
+	}
+
+	return nil
+}
+
+func (pm *ProtocolManager) SyncJoinChat() error {
+	pm.lockJoinChatRequest.Lock()
+	defer pm.lockJoinChatRequest.Unlock()
+
+	now, err := types.GetTimestamp()
+	if err != nil {
+		return err
+	}
+
+	toRemoveHashs := make([]*common.Address, 0)
+	for _, joinRequest := range pm.joinChatRequests {
+		if joinRequest.CreateTS.Ts < now.Ts-pkgservice.IntRenewJoinKeySeconds {
+			log.Warn("SyncJoinChat: expired", "joinRequest", joinRequest.CreateTS, "now", now)
+			toRemoveHashs = append(toRemoveHashs, joinRequest.Hash)
+			continue
+		}
+
+		if joinRequest.Status != pkgservice.JoinStatusPending {
+			continue
+		}
+
+		pm.processJoinChatEvent(joinRequest, true)
+	}
+
+	for _, hash := range toRemoveHashs {
+		delete(pm.joinChatRequests, *hash)
+	}
+
+	return nil
+}
+
+/**********
+ * BroadcastLoop
+ **********/
+
+func (pm *ProtocolManager) JoinChatLoop() {
+	for obj := range pm.joinChatSub.Chan() {
+		ev, ok := obj.Data.(*JoinChatEvent)
+		if !ok {
+			continue
+		}
+
+		err := pm.processJoinChatEvent(ev.JoinRequest, false)
+		if err != nil {
+			log.Error("Unable to process join chat event", "data", ev, "e", err)
+		}
+	}
+}
+
+func (pm *ProtocolManager) processJoinChatEvent(request *pkgservice.JoinRequest, isLocked bool) error {
+	if !isLocked {
+		pm.lockJoinChatRequest.Lock()
+		defer pm.lockJoinChatRequest.Unlock()
+	}
+
+	if request.Status != pkgservice.JoinStatusPending {
+		return pkgservice.ErrInvalidStatus
+	}
+
+	hash, key, challenge := request.Hash, request.Key, request.Challenge
+
+	ptt := pm.Ptt()
+	err := ptt.TryJoin(challenge, hash, key, request)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -49,6 +49,11 @@ type ProtocolManager struct {
 	joinBoardRequests    map[common.Address]*pkgservice.JoinRequest
 	joinBoardSub         *event.TypeMuxSubscription
 
+	// requests to join-chat
+	lockJoinChatRequest sync.RWMutex
+	joinChatRequests    map[common.Address]*pkgservice.JoinRequest
+	joinChatSub         *event.TypeMuxSubscription
+
 	// my-nodes
 	lockJoinMeRequest sync.RWMutex
 	joinMeRequests    map[common.Address]*pkgservice.JoinRequest
@@ -133,6 +138,8 @@ func NewProtocolManager(myInfo *MyInfo, ptt pkgservice.MyPtt, svc pkgservice.Ser
 
 		joinBoardRequests: make(map[common.Address]*pkgservice.JoinRequest),
 
+		joinChatRequests: make(map[common.Address]*pkgservice.JoinRequest),
+
 		// merkle
 		meOplogMerkle: meOplogMerkle,
 
@@ -296,6 +303,16 @@ func (pm *ProtocolManager) Start() error {
 		pm.SyncJoinBoardLoop()
 	}()
 
+	// join-chat
+	pm.joinChatSub = pm.EventMux().Subscribe(&JoinChatEvent{})
+	go pm.JoinChatLoop()
+
+	syncWG.Add(1)
+	go func() {
+		defer syncWG.Done()
+		pm.SyncJoinChatLoop()
+	}()
+
 	// oplog-merkle-tree
 	syncWG.Add(1)
 	go func() {
@@ -323,6 +340,7 @@ func (pm *ProtocolManager) Stop() error {
 	pm.joinFriendSub.Unsubscribe()
 	pm.joinMeSub.Unsubscribe()
 	pm.joinBoardSub.Unsubscribe()
+	pm.joinChatSub.Unsubscribe()
 
 	pm.StopRaft()
 
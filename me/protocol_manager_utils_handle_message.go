@@ -102,6 +102,12 @@ func (pm *ProtocolManager) HandleMessage(op pkgservice.OpType, dataBytes []byte,
 	case InternalSyncFriendAckMsg:
 		err = pm.HandleInternalSyncFriendAck(dataBytes, peer)
 
+	// internal-sync-chat
+	case InternalSyncChatMsg:
+		err = pm.HandleInternalSyncChat(dataBytes, peer)
+	case InternalSyncChatAckMsg:
+		err = pm.HandleInternalSyncChatAck(dataBytes, peer)
+
 	default:
 		err = pkgservice.ErrInvalidMsgCode
 	}
@@ -43,6 +43,12 @@ func (pm *ProtocolManager) GetJoinRequest(hash *common.Address) (*pkgservice.Joi
 		return joinRequest, nil
 	}
 
+	// chat
+	joinRequest, err = pm.getJoinRequestCore(hash, &pm.lockJoinChatRequest, pm.joinChatRequests)
+	if err == nil {
+		return joinRequest, nil
+	}
+
 	return nil, pkgservice.ErrInvalidMsg
 
 }
@@ -0,0 +1,61 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package me
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func (pm *ProtocolManager) IsJoinChatRequests(hash *common.Address) bool {
+	pm.lockJoinChatRequest.RLock()
+	defer pm.lockJoinChatRequest.RUnlock()
+
+	_, ok := pm.joinChatRequests[*hash]
+
+	return ok
+}
+
+func (pm *ProtocolManager) GetChatRequests() ([]*pkgservice.JoinRequest, error) {
+	pm.lockJoinChatRequest.RLock()
+	defer pm.lockJoinChatRequest.RUnlock()
+
+	theList := make([]*pkgservice.JoinRequest, len(pm.joinChatRequests))
+	i := 0
+	for _, request := range pm.joinChatRequests {
+		theList[i] = request
+		i++
+	}
+	return theList, nil
+}
+
+func (pm *ProtocolManager) RemoveChatRequests(hash []byte) (bool, error) {
+	pm.lockJoinChatRequest.Lock()
+	defer pm.lockJoinChatRequest.Unlock()
+
+	addr := &common.Address{}
+	copy(addr[:], hash)
+	_, ok := pm.joinChatRequests[*addr]
+	if !ok {
+		return false, types.ErrAlreadyDeleted
+	}
+
+	delete(pm.joinChatRequests, *addr)
+
+	return true, nil
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/p2p/discover"
 	pkgservice "github.com/ailabstw/go-pttai/service"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -147,6 +148,18 @@ func (pm *ProtocolManager) CleanJoinFriendKey() {
 	}
 }
 
+/*
+IsSuspiciousID drops the friend-requests from the users in my block-list.
+*/
+func (pm *ProtocolManager) IsSuspiciousID(id *types.PttID, nodeID *discover.NodeID) bool {
+	myInfo := pm.Entity().(*MyInfo)
+	if myInfo.GetBlockList().IsBlocked(id) {
+		return true
+	}
+
+	return pm.BaseProtocolManager.IsSuspiciousID(id, nodeID)
+}
+
 func (pm *ProtocolManager) IsJoinFriendRequests(hash *common.Address) bool {
 	pm.lockJoinFriendRequest.RLock()
 	defer pm.lockJoinFriendRequest.RUnlock()
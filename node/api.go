@@ -24,6 +24,7 @@ import (
 
 	"github.com/ailabstw/go-pttai/p2p"
 	"github.com/ailabstw/go-pttai/p2p/discover"
+	pttrpc "github.com/ailabstw/go-pttai/rpc"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -227,6 +228,52 @@ func (api *PrivateAdminAPI) StopWS() (bool, error) {
 	return true, nil
 }
 
+// CreatedAuthToken is the created auth-token with the secret (shown only once).
+type CreatedAuthToken struct {
+	*pttrpc.AuthToken
+	Secret string `json:"T"`
+}
+
+// CreateToken creates the auth-token with the scopes for the HTTP / websocket RPC and the http-server.
+func (api *PrivateAdminAPI) CreateToken(name string, scopes []string) (*CreatedAuthToken, error) {
+	tokens := api.node.tokens
+	if tokens == nil {
+		return nil, ErrNodeStopped
+	}
+
+	secret, token, err := tokens.Create(name, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreatedAuthToken{AuthToken: token, Secret: secret}, nil
+}
+
+// ListTokens lists the auth-tokens.
+func (api *PrivateAdminAPI) ListTokens() ([]*pttrpc.AuthToken, error) {
+	tokens := api.node.tokens
+	if tokens == nil {
+		return nil, ErrNodeStopped
+	}
+
+	return tokens.List(), nil
+}
+
+// RevokeToken revokes the auth-token.
+func (api *PrivateAdminAPI) RevokeToken(id string) (bool, error) {
+	tokens := api.node.tokens
+	if tokens == nil {
+		return false, ErrNodeStopped
+	}
+
+	err := tokens.Revoke(id)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 // PublicAdminAPI is the collection of administrative API methods exposed over
 // both secure and unsecure RPC channels.
 type PublicAdminAPI struct {
@@ -42,11 +42,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ailabstw/go-pttai/key"
 	"github.com/ailabstw/go-pttai/log"
 	"github.com/ailabstw/go-pttai/p2p"
 	"github.com/ailabstw/go-pttai/p2p/discover"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 )
 
 type Config struct {
@@ -76,6 +76,10 @@ type Config struct {
 	// is created by New and destroyed when the node is stopped.
 	KeyStoreDir string `toml:",omitempty"`
 
+	// Keystore loads / saves the node key with the passphrase (shared with the me key).
+	// The node key is saved in plaintext if Keystore is nil or without the passphrase.
+	Keystore *key.Keystore `toml:"-"`
+
 	// IPCPath is the requested location to place the IPC endpoint. If the path is
 	// a simple file name, it is placed inside the data directory (or on the root
 	// pipe path on Windows), whereas if it's a resolvable path name (absolute or
@@ -112,6 +116,12 @@ type Config struct {
 	// exposed.
 	HTTPModules []string `toml:",omitempty"`
 
+	// HTTPAuth requires the bearer-tokens (managed by gptt token) with the scopes
+	// of the modules on the HTTP / websocket RPC and the http-server.
+	// Without HTTPAuth, the browser-requests from the origins not in HTTPCors are
+	// allowed to call only the read-only methods (rpc.IsReadMethod).
+	HTTPAuth bool `toml:",omitempty"`
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string `toml:",omitempty"`
@@ -223,10 +233,13 @@ func (c *Config) NodeKey() *ecdsa.PrivateKey {
 
 	// retrieve key / postfix from file
 	keyfile := c.ResolvePath(DataDirPrivateKey)
-	key, err := discover.LoadECDSA(keyfile)
+	key, err := c.LoadKey(keyfile)
 	if err == nil {
 		return key
 	}
+	if isKeystoreErr(err) {
+		log.Crit(fmt.Sprintf("Failed to unlock node key: %v", err))
+	}
 
 	log.Warn(fmt.Sprintf("Failed to load key: %v. create a new one.", err))
 	// No persistent key found, generate and store a new one.
@@ -249,11 +262,17 @@ func (c *Config) NodeKey() *ecdsa.PrivateKey {
 }
 
 func (c *Config) SaveKey(filename string, key *ecdsa.PrivateKey) error {
-	return crypto.SaveECDSA(filename, key)
+	return c.Keystore.SaveECDSA(filename, key)
 }
 
 func (c *Config) LoadKey(filename string) (*ecdsa.PrivateKey, error) {
-	key, err := crypto.LoadECDSA(filename)
+	key, err := c.Keystore.LoadECDSA(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeID := discover.PubkeyID(&key.PublicKey)
+	_, err = nodeID.ToRaftID()
 	if err != nil {
 		return nil, err
 	}
@@ -261,6 +280,14 @@ func (c *Config) LoadKey(filename string) (*ecdsa.PrivateKey, error) {
 	return key, nil
 }
 
+func isKeystoreErr(err error) bool {
+	switch err {
+	case key.ErrInvalidKeystore, key.ErrInvalidPassphrase, key.ErrKeystoreLocked:
+		return true
+	}
+	return false
+}
+
 func (c *Config) RevokeKeyPath() error {
 	instanceDir := filepath.Join(c.DataDir, c.name())
 	keyfile := filepath.Join(instanceDir, DataDirPrivateKey)
@@ -275,9 +302,16 @@ func (c *Config) RevokeKeyPath() error {
 
 	err := os.Rename(keyfile, revokeFile)
 
+	c.Keystore.Forget(keyfile)
+
 	return err
 }
 
+// AuthTokensPath returns the path to the auth-tokens of the rpc / http-server.
+func (c *Config) AuthTokensPath() string {
+	return c.ResolvePath(DataDirAuthTokens)
+}
+
 // StaticNodes returns a list of node enode URLs configured as static nodes.
 func (c *Config) StaticNodes() []*discover.Node {
 	return c.parsePersistentNodes(c.ResolvePath(DataDirStaticNodes))
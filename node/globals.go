@@ -39,6 +39,7 @@ const (
 	DataDirStaticNodes     = "static-nodes.json"  // Path within the datadir to the static node list
 	DataDirTrustedNodes    = "trusted-nodes.json" // Path within the datadir to the trusted node list
 	DataDirNodeDatabase    = "nodes"              // Path within the datadir to store the node infos
+	DataDirAuthTokens      = "auth-tokens.json"   // Path within the datadir to the auth-tokens of the rpc / http-server
 
 	DefaultHTTPHost = ""    // Default host interface for the HTTP RPC server
 	DefaultHTTPPort = 14779 // Default TCP port for the HTTP RPC server
@@ -56,7 +57,7 @@ var (
 		HTTPPort:         DefaultHTTPPort,
 		HTTPCors:         []string{"localhost"},
 		HTTPVirtualHosts: []string{"localhost"},
-		HTTPModules:      []string{"debug", "net", "admin", "ptt", "account", "content", "me", "friend"},
+		HTTPModules:      []string{"debug", "net", "admin", "ptt", "account", "content", "me", "friend", "chat"},
 		WSPort:           DefaultWSPort,
 		P2P: p2p.Config{
 			ListenAddr:    ":29487",
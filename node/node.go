@@ -82,6 +82,8 @@ type Node struct {
 	wsListener net.Listener // Websocket RPC listener socket to server API requests
 	wsHandler  *rpc.Server  // Websocket RPC request handler to process the API requests
 
+	tokens *rpc.TokenStore // auth-tokens of the HTTP / websocket RPC and the http-server
+
 	lock     sync.RWMutex
 	StopChan chan error
 
@@ -159,6 +161,11 @@ func (n *Node) Start() error {
 		return err
 	}
 
+	n.tokens, err = rpc.NewTokenStore(n.Config.AuthTokensPath())
+	if err != nil {
+		return err
+	}
+
 	// Initialize the p2p server. This creates the node key and
 	// discovery databases.
 	n.serverConfig = n.Config.P2P
@@ -344,6 +351,15 @@ func (n *Node) RPCHandler() (*rpc.Server, error) {
 	return n.inprocHandler, nil
 }
 
+// AuthTokens returns the auth-tokens required by the HTTP / websocket RPC and the http-server,
+// or nil if HTTPAuth is not set.
+func (n *Node) AuthTokens() *rpc.TokenStore {
+	if !n.Config.HTTPAuth {
+		return nil
+	}
+	return n.tokens
+}
+
 // Server retrieves the currently running P2P network layer. This method is meant
 // only to inspect fields of the currently running server, life cycle management
 // should be left to this Node entity.
@@ -484,7 +500,7 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, httpServer, err := rpc.StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts)
+	listener, handler, httpServer, err := rpc.StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts, n.AuthTokens())
 	if err != nil {
 		return err
 	}
@@ -524,7 +540,7 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll)
+	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll, n.AuthTokens())
 	if err != nil {
 		return err
 	}
@@ -661,6 +661,12 @@ func PeerIDToNodeID(peerID peer.ID) (NodeID, error) {
 		return NodeID{}, err
 	}
 
+	// the ed25519 peer-id is not derived from the node-id.
+	// The node-id is known after the enc-handshake.
+	if _, err := key.P2PPubkeyToEd25519Pubkey(p2pPubKey); err == nil {
+		return NodeID{}, nil
+	}
+
 	pubKey, err := key.P2PPubkeyToPubkey(p2pPubKey)
 	if err != nil {
 		return NodeID{}, err
@@ -28,8 +28,10 @@ import (
 	"testing/quick"
 	"time"
 
+	"github.com/ailabstw/go-pttai/key"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	peer "github.com/libp2p/go-libp2p-peer"
 )
 
 func ExampleNewNode() {
@@ -248,6 +250,35 @@ func TestNodeID_recover(t *testing.T) {
 	}
 }
 
+func TestPeerIDToNodeID(t *testing.T) {
+	prv := newkey()
+
+	for _, keyType := range []key.Type{key.TypeSecp256k1, key.TypeEd25519} {
+		p2pKey, err := key.PrivateKeyToP2PPrivKeyWithType(keyType, prv)
+		if err != nil {
+			t.Fatalf("PrivateKeyToP2PPrivKeyWithType error: %v", err)
+		}
+		peerID, err := peer.IDFromPrivateKey(p2pKey)
+		if err != nil {
+			t.Fatalf("IDFromPrivateKey error: %v", err)
+		}
+
+		id, err := PeerIDToNodeID(peerID)
+		if err != nil {
+			t.Errorf("PeerIDToNodeID error: keyType: %v e: %v", keyType, err)
+		}
+
+		// the ed25519 peer-id does not carry the node-id.
+		want := NodeID{}
+		if keyType == key.TypeSecp256k1 {
+			want = PubkeyID(&prv.PublicKey)
+		}
+		if id != want {
+			t.Errorf("PeerIDToNodeID: keyType: %v got: %v want: %v", keyType, id, want)
+		}
+	}
+}
+
 func TestNodeID_pubkeyBad(t *testing.T) {
 	ecdsa, err := NodeID{}.Pubkey()
 	if err == nil {
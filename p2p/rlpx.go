@@ -510,6 +510,15 @@ func readHandshakeMsg(msg plainDecoder, plainSize int, prv *ecdsa.PrivateKey, r
 	if size < uint16(plainSize) {
 		return buf, fmt.Errorf("size underflow, need at least %d bytes", plainSize)
 	}
+	// stream-based conns (ex: pipes) may return partial handshake-msg.
+	if readSize < int(size)+2 {
+		fullBuf := make([]byte, int(size)+2)
+		copy(fullBuf, tmpBuf[:readSize])
+		if _, err := io.ReadFull(r, fullBuf[readSize:]); err != nil {
+			return buf, err
+		}
+		tmpBuf, readSize = fullBuf, len(fullBuf)
+	}
 	if uint16(readSize) != size+2 {
 		return buf, fmt.Errorf("readSize incorrect, readSize: %v size+2: %v", readSize, size+2)
 	}
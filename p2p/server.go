@@ -151,6 +151,10 @@ type Config struct {
 	P2PListenAddr string
 	P2PBootnodes  []*discover.Node
 
+	// P2PKeyType is the key-type of the libp2p identity.
+	// The node-id is always from the secp256k1 PrivateKey (verified in the enc-handshake).
+	P2PKeyType key.Type
+
 	// webrtc
 
 	SignalServerURL url.URL
@@ -316,7 +320,7 @@ func (srv *Server) InitP2P() error {
 	srv.p2pctx = p2pctx
 	srv.p2pcancel = p2pcancel
 
-	privKey, err := key.PrivateKeyToP2PPrivKey(cfg.PrivateKey)
+	privKey, err := key.PrivateKeyToP2PPrivKeyWithType(cfg.P2PKeyType, cfg.PrivateKey)
 	if err != nil {
 		return err
 	}
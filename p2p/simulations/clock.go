@@ -0,0 +1,44 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulations
+
+import (
+	"time"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+/*
+SetOffsetSecond sets the offset of the process-wide clock (types.GetTimestamp), as ptt_setOffsetSecond.
+*/
+func SetOffsetSecond(sec int64) {
+	types.OffsetSecond = sec
+}
+
+/*
+AdvanceClock moves the process-wide clock forward by d (in seconds).
+*/
+func AdvanceClock(d time.Duration) {
+	types.OffsetSecond += int64(d / time.Second)
+}
+
+/*
+ResetClock resets the process-wide clock to the system time.
+*/
+func ResetClock() {
+	types.OffsetSecond = 0
+}
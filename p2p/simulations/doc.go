@@ -0,0 +1,31 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+Package simulations provides the in-process harness for the integration tests.
+
+Network connects the p2p-servers with pipes (p2p/simulations/pipes) instead of sockets.
+NewNode runs the protocols given by the tests,
+and NewPttNode runs the full ptt-stack (node + ptt + me / account / content / friend / chat) as gptt.
+The services keep the dbs in the package-level variables, so only 1 ptt-node is able to run in the process.
+The tests are able to connect the nodes, partition and heal the network,
+control the process-wide clock (SetOffsetSecond / AdvanceClock),
+and wait until the merkle-trees of the same entity in different nodes converge (WaitMerkleConverged).
+
+The ptt-node needs the buffered pipes (pipes.BufferedPipe).
+net.Pipe is not buffered, and the ptt-protocols of both peers may be writing to each other at the same time.
+*/
+package simulations
@@ -16,4 +16,12 @@
 
 package simulations
 
-var ()
+import "errors"
+
+var (
+	ErrInvalidNode = errors.New("invalid node")
+	ErrPartitioned = errors.New("partitioned")
+	ErrTimeout     = errors.New("timeout")
+
+	ErrPttNodeRunning = errors.New("ptt-node is already running")
+)
@@ -16,9 +16,26 @@
 
 package simulations
 
-const ()
+import (
+	"sync"
+	"time"
+)
 
-var ()
+const (
+	DefaultMaxPeers = 50
+)
+
+var (
+	WaitInterval = 50 * time.Millisecond
+
+	WaitPttNodeTimeout = 30 * time.Second
+)
+
+// me / account / content / friend / chat keep the dbs in the package-level variables.
+var (
+	lockPttNode      sync.Mutex
+	isPttNodeRunning bool
+)
 
 func init() {
 }
@@ -0,0 +1,300 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulations
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/p2p"
+	"github.com/ailabstw/go-pttai/p2p/discover"
+	"github.com/ailabstw/go-pttai/p2p/simulations/pipes"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+/*
+Network is an in-process network connecting the p2p-servers with pipes instead of sockets.
+
+The connections between the nodes can be partitioned and healed.
+*/
+type Network struct {
+	lock sync.RWMutex
+
+	pipe func() (net.Conn, net.Conn, error)
+
+	nodes  map[discover.NodeID]*Node
+	groups map[discover.NodeID]int
+	conns  map[*pipeConn]struct{}
+}
+
+/*
+Node is a p2p-server in the network.
+PttNode is set if the node is running the full ptt-stack (NewPttNode).
+*/
+type Node struct {
+	ID     discover.NodeID
+	Server *p2p.Server
+
+	PttNode *PttNode
+
+	network *Network
+}
+
+/*
+NewNetwork creates the network. pipe is pipes.NetPipe if nil.
+*/
+func NewNetwork(pipe func() (net.Conn, net.Conn, error)) *Network {
+	if pipe == nil {
+		pipe = pipes.NetPipe
+	}
+
+	return &Network{
+		pipe:   pipe,
+		nodes:  make(map[discover.NodeID]*Node),
+		groups: make(map[discover.NodeID]int),
+		conns:  make(map[*pipeConn]struct{}),
+	}
+}
+
+/*
+NewNode creates and starts the node running the protocols.
+*/
+func (n *Network) NewNode(name string, protocols []p2p.Protocol) (*Node, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &Node{
+		ID:      discover.PubkeyID(&key.PublicKey),
+		network: n,
+	}
+
+	node.Server = &p2p.Server{
+		Config: p2p.Config{
+			PrivateKey:  key,
+			MaxPeers:    DefaultMaxPeers,
+			NoDiscovery: true,
+			Name:        name,
+			Protocols:   protocols,
+			Dialer:      &dialer{network: n, src: node.ID},
+		},
+	}
+
+	err = node.Server.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.nodes[node.ID] = node
+
+	return node, nil
+}
+
+func (n *Network) Node(id discover.NodeID) *Node {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	return n.nodes[id]
+}
+
+/*
+Connect connects the src to the dst synchronously.
+*/
+func (n *Network) Connect(src *Node, dst *Node) error {
+	srcConn, dstConn, err := n.dial(src.ID, dst.ID)
+	if err != nil {
+		return err
+	}
+
+	go dst.Server.SetupConn(dstConn, 0, nil)
+
+	return src.Server.SetupConn(srcConn, 0, dst.Server.Self())
+}
+
+/*
+Partition splits the network into the groups.
+The nodes not in any of the groups are in the same group.
+The existing connections across the groups are closed.
+*/
+func (n *Network) Partition(groups ...[]*Node) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.groups = make(map[discover.NodeID]int)
+	for i, group := range groups {
+		for _, node := range group {
+			n.groups[node.ID] = i + 1
+		}
+	}
+
+	for conn := range n.conns {
+		if n.isConnectable(conn.src, conn.dst) {
+			continue
+		}
+		log.Debug("Partition: to close conn", "src", conn.src, "dst", conn.dst)
+		delete(n.conns, conn)
+		conn.Conn.Close()
+	}
+}
+
+/*
+Heal removes the partitions. The nodes need to be connected again.
+*/
+func (n *Network) Heal() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.groups = make(map[discover.NodeID]int)
+}
+
+func (n *Network) IsConnectable(src *Node, dst *Node) bool {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	return n.isConnectable(src.ID, dst.ID)
+}
+
+func (n *Network) isConnectable(src discover.NodeID, dst discover.NodeID) bool {
+	return n.groups[src] == n.groups[dst]
+}
+
+/*
+IsConnected returns whether the src has the dst as the peer.
+*/
+func (n *Network) IsConnected(src *Node, dst *Node) bool {
+	for _, peer := range src.Server.Peers() {
+		if peer.ID() == dst.ID {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+WaitConnected waits until the src has the dst as the peer.
+*/
+func (n *Network) WaitConnected(src *Node, dst *Node, timeout time.Duration) error {
+	return WaitUntil(timeout, func() bool {
+		return n.IsConnected(src, dst)
+	})
+}
+
+/*
+WaitDisconnected waits until the src does not have the dst as the peer.
+*/
+func (n *Network) WaitDisconnected(src *Node, dst *Node, timeout time.Duration) error {
+	return WaitUntil(timeout, func() bool {
+		return !n.IsConnected(src, dst)
+	})
+}
+
+/*
+Stop stops all the nodes in the network.
+*/
+func (n *Network) Stop() {
+	n.lock.Lock()
+	nodes := make([]*Node, 0, len(n.nodes))
+	for _, node := range n.nodes {
+		nodes = append(nodes, node)
+	}
+	n.nodes = make(map[discover.NodeID]*Node)
+	n.lock.Unlock()
+
+	for _, node := range nodes {
+		if node.PttNode != nil {
+			node.PttNode.stop()
+			continue
+		}
+		node.Server.Stop()
+	}
+}
+
+func (n *Network) dial(src discover.NodeID, dst discover.NodeID) (net.Conn, net.Conn, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if _, ok := n.nodes[dst]; !ok {
+		return nil, nil, ErrInvalidNode
+	}
+
+	if !n.isConnectable(src, dst) {
+		return nil, nil, ErrPartitioned
+	}
+
+	srcConn, dstConn, err := n.pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	theSrcConn := &pipeConn{Conn: srcConn, network: n, src: src, dst: dst}
+	theDstConn := &pipeConn{Conn: dstConn, network: n, src: dst, dst: src}
+
+	n.conns[theSrcConn] = struct{}{}
+	n.conns[theDstConn] = struct{}{}
+
+	return theSrcConn, theDstConn, nil
+}
+
+/**********
+ * dialer
+ **********/
+
+/*
+dialer implements p2p.NodeDialer, connecting the dialing node through the network.
+*/
+type dialer struct {
+	network *Network
+	src     discover.NodeID
+}
+
+func (d *dialer) Dial(dest *discover.Node) (net.Conn, error) {
+	srcConn, dstConn, err := d.network.dial(d.src, dest.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	dst := d.network.Node(dest.ID)
+	go dst.Server.SetupConn(dstConn, 0, nil)
+
+	return srcConn, nil
+}
+
+/**********
+ * pipeConn
+ **********/
+
+type pipeConn struct {
+	net.Conn
+
+	network *Network
+	src     discover.NodeID
+	dst     discover.NodeID
+}
+
+func (c *pipeConn) Close() error {
+	c.network.lock.Lock()
+	delete(c.network.conns, c)
+	c.network.lock.Unlock()
+
+	return c.Conn.Close()
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/p2p"
+	"github.com/stretchr/testify/assert"
+)
+
+func tNewNetworkProtocols() []p2p.Protocol {
+	return []p2p.Protocol{
+		{
+			Name:    "sim",
+			Version: 1,
+			Length:  1,
+			Run: func(peer *p2p.Peer, rw p2p.MsgReadWriter) error {
+				for {
+					msg, err := rw.ReadMsg()
+					if err != nil {
+						return err
+					}
+					msg.Discard()
+				}
+			},
+		},
+	}
+}
+
+func TestNetworkPartitionHeal(t *testing.T) {
+	setupTest(t)
+	defer teardownTest(t)
+
+	network := NewNetwork(nil)
+	defer network.Stop()
+
+	nodeA, err := network.NewNode("a", tNewNetworkProtocols())
+	assert.NoError(t, err)
+	nodeB, err := network.NewNode("b", tNewNetworkProtocols())
+	assert.NoError(t, err)
+	nodeC, err := network.NewNode("c", tNewNetworkProtocols())
+	assert.NoError(t, err)
+
+	// connect
+	assert.NoError(t, network.Connect(nodeA, nodeB))
+	assert.NoError(t, network.Connect(nodeB, nodeC))
+
+	assert.NoError(t, network.WaitConnected(nodeA, nodeB, 5*time.Second))
+	assert.NoError(t, network.WaitConnected(nodeB, nodeA, 5*time.Second))
+	assert.NoError(t, network.WaitConnected(nodeC, nodeB, 5*time.Second))
+
+	// partition
+	network.Partition([]*Node{nodeA}, []*Node{nodeB, nodeC})
+
+	assert.NoError(t, network.WaitDisconnected(nodeA, nodeB, 5*time.Second))
+	assert.NoError(t, network.WaitDisconnected(nodeB, nodeA, 5*time.Second))
+	assert.True(t, network.IsConnected(nodeB, nodeC))
+	assert.Equal(t, ErrPartitioned, network.Connect(nodeA, nodeB))
+
+	// heal
+	network.Heal()
+
+	assert.True(t, network.IsConnectable(nodeA, nodeB))
+	assert.NoError(t, network.Connect(nodeA, nodeB))
+	assert.NoError(t, network.WaitConnected(nodeB, nodeA, 5*time.Second))
+}
+
+func TestNetworkDialer(t *testing.T) {
+	setupTest(t)
+	defer teardownTest(t)
+
+	network := NewNetwork(nil)
+	defer network.Stop()
+
+	nodeA, err := network.NewNode("a", tNewNetworkProtocols())
+	assert.NoError(t, err)
+	nodeB, err := network.NewNode("b", tNewNetworkProtocols())
+	assert.NoError(t, err)
+
+	// dialed by the p2p-server through the network.
+	nodeA.Server.AddPeer(nodeB.Server.Self())
+
+	assert.NoError(t, network.WaitConnected(nodeA, nodeB, 5*time.Second))
+	assert.NoError(t, network.WaitConnected(nodeB, nodeA, 5*time.Second))
+}
+
+func TestClock(t *testing.T) {
+	defer ResetClock()
+
+	ts0, _ := types.GetTimestamp()
+
+	AdvanceClock(time.Hour)
+
+	ts1, _ := types.GetTimestamp()
+	assert.True(t, ts1.Ts-ts0.Ts >= 3600)
+
+	SetOffsetSecond(-86400)
+	ts2, _ := types.GetTimestamp()
+	assert.True(t, ts2.Ts < ts0.Ts)
+
+	ResetClock()
+	assert.Equal(t, int64(0), types.OffsetSecond)
+}
+
+func TestWaitUntil(t *testing.T) {
+	assert.Equal(t, ErrTimeout, WaitUntil(100*time.Millisecond, func() bool { return false }))
+
+	count := 0
+	assert.NoError(t, WaitUntil(5*time.Second, func() bool {
+		count++
+		return count > 2
+	}))
+}
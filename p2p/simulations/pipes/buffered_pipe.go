@@ -0,0 +1,216 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package pipes
+
+import (
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+/*
+BufferedPipe creates the in-memory conns as net.Pipe, except that the writes are buffered.
+
+The writes are not blocked by the reads of the other end,
+so the peers are able to write to each other at the same time (as tcp).
+A read does not span the writes (as net.Pipe),
+so the message-based handshakes see exactly one message in each read.
+*/
+func BufferedPipe() (net.Conn, net.Conn, error) {
+	a2b := newBufferedQueue()
+	b2a := newBufferedQueue()
+
+	return &bufferedConn{r: b2a, w: a2b}, &bufferedConn{r: a2b, w: b2a}, nil
+}
+
+/**********
+ * bufferedQueue
+ **********/
+
+type bufferedQueue struct {
+	lock sync.Mutex
+
+	msgs [][]byte
+
+	notify chan struct{}
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newBufferedQueue() *bufferedQueue {
+	return &bufferedQueue{
+		notify: make(chan struct{}, 1),
+		closed: make(chan struct{}),
+	}
+}
+
+func (q *bufferedQueue) write(b []byte) (int, error) {
+	select {
+	case <-q.closed:
+		return 0, io.ErrClosedPipe
+	default:
+	}
+
+	msg := make([]byte, len(b))
+	copy(msg, b)
+
+	q.lock.Lock()
+	q.msgs = append(q.msgs, msg)
+	q.lock.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+
+	return len(b), nil
+}
+
+/*
+tryRead reads from the 1st msg in the queue. isOk is false if there is no msg.
+*/
+func (q *bufferedQueue) tryRead(b []byte) (int, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if len(q.msgs) == 0 {
+		return 0, false
+	}
+
+	n := copy(b, q.msgs[0])
+	if n == len(q.msgs[0]) {
+		q.msgs = q.msgs[1:]
+	} else {
+		q.msgs[0] = q.msgs[0][n:]
+	}
+
+	return n, true
+}
+
+func (q *bufferedQueue) read(b []byte, deadline time.Time) (int, error) {
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		n, ok := q.tryRead(b)
+		if ok {
+			return n, nil
+		}
+
+		select {
+		case <-q.notify:
+		case <-q.closed:
+			n, ok = q.tryRead(b)
+			if ok {
+				return n, nil
+			}
+			return 0, io.EOF
+		case <-timeout:
+			return 0, errTimeout
+		}
+	}
+}
+
+func (q *bufferedQueue) close() {
+	q.closeOnce.Do(func() {
+		close(q.closed)
+	})
+}
+
+/**********
+ * bufferedConn
+ **********/
+
+type bufferedConn struct {
+	r *bufferedQueue
+	w *bufferedQueue
+
+	lock         sync.RWMutex
+	readDeadline time.Time
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	c.lock.RLock()
+	deadline := c.readDeadline
+	c.lock.RUnlock()
+
+	return c.r.read(b, deadline)
+}
+
+func (c *bufferedConn) Write(b []byte) (int, error) {
+	return c.w.write(b)
+}
+
+/*
+Close closes both directions of the conn.
+The other end reads the buffered msgs and then io.EOF.
+*/
+func (c *bufferedConn) Close() error {
+	c.r.close()
+	c.w.close()
+	return nil
+}
+
+func (c *bufferedConn) LocalAddr() net.Addr {
+	return pipeAddr{}
+}
+
+func (c *bufferedConn) RemoteAddr() net.Addr {
+	return pipeAddr{}
+}
+
+func (c *bufferedConn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+func (c *bufferedConn) SetReadDeadline(t time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.readDeadline = t
+	return nil
+}
+
+/*
+SetWriteDeadline is no-op, because the writes are not blocked.
+*/
+func (c *bufferedConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+/**********
+ * pipeAddr / timeoutError
+ **********/
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var errTimeout net.Error = timeoutError{}
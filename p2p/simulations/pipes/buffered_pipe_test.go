@@ -0,0 +1,80 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package pipes
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferedPipe(t *testing.T) {
+	a, b, err := BufferedPipe()
+	assert.NoError(t, err)
+
+	// both ends write without the reads.
+	_, err = a.Write([]byte("hello"))
+	assert.NoError(t, err)
+	_, err = a.Write([]byte("world"))
+	assert.NoError(t, err)
+	_, err = b.Write([]byte("ack"))
+	assert.NoError(t, err)
+
+	// a read does not span the writes.
+	buf := make([]byte, 100)
+	n, err := b.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(buf[:n]))
+
+	n, err = b.Read(buf[:3])
+	assert.NoError(t, err)
+	assert.Equal(t, "wor", string(buf[:n]))
+
+	n, err = b.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "ld", string(buf[:n]))
+
+	n, err = a.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "ack", string(buf[:n]))
+
+	// deadline
+	b.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	_, err = b.Read(buf)
+	netErr, ok := err.(net.Error)
+	assert.True(t, ok)
+	assert.True(t, netErr.Timeout())
+	b.SetReadDeadline(time.Time{})
+
+	// close: the buffered msgs are still readable.
+	_, err = a.Write([]byte("bye"))
+	assert.NoError(t, err)
+	a.Close()
+
+	n, err = b.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "bye", string(buf[:n]))
+
+	_, err = b.Read(buf)
+	assert.Equal(t, io.EOF, err)
+
+	_, err = a.Write([]byte("closed"))
+	assert.Equal(t, io.ErrClosedPipe, err)
+}
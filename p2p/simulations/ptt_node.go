@@ -0,0 +1,244 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulations
+
+import (
+	"path/filepath"
+
+	"github.com/ailabstw/go-pttai/account"
+	"github.com/ailabstw/go-pttai/chat"
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/content"
+	"github.com/ailabstw/go-pttai/friend"
+	"github.com/ailabstw/go-pttai/me"
+	"github.com/ailabstw/go-pttai/node"
+	"github.com/ailabstw/go-pttai/p2p"
+	"github.com/ailabstw/go-pttai/p2p/discover"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+/*
+PttNode is the full ptt-stack (node + ptt + me / account / content / friend / chat) of the Node.
+*/
+type PttNode struct {
+	Stack *node.Node
+
+	Ptt *pkgservice.BasePtt
+
+	Me      *me.Backend
+	Account *account.Backend
+	Content *content.Backend
+	Friend  *friend.Backend
+	Chat    *chat.Backend
+}
+
+/*
+NewPttNode creates and starts the node running the full ptt-stack with the data in dataDir.
+
+The p2p-server of the node is dialing through the network,
+and the http / ipc / ws rpc and the discovery are disabled.
+NewPttNode returns after me is alive.
+
+The services keep the dbs in the package-level variables,
+so only 1 ptt-node is able to run in the process (ErrPttNodeRunning),
+and the other nodes in the network are the p2p-servers from NewNode.
+*/
+func (n *Network) NewPttNode(name string, dataDir string) (*Node, error) {
+	lockPttNode.Lock()
+	defer lockPttNode.Unlock()
+
+	if isPttNodeRunning {
+		return nil, ErrPttNodeRunning
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	theNode := &Node{
+		ID:      discover.PubkeyID(&key.PublicKey),
+		PttNode: &PttNode{},
+		network: n,
+	}
+
+	cfg := &node.Config{
+		Name:    name,
+		DataDir: dataDir,
+		P2P: p2p.Config{
+			PrivateKey:  key,
+			MaxPeers:    DefaultMaxPeers,
+			NoDiscovery: true,
+			Dialer:      &dialer{network: n, src: theNode.ID},
+		},
+	}
+
+	stack, err := node.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = stack.Register(func(ctx *pkgservice.ServiceContext) (pkgservice.PttService, error) {
+		return theNode.PttNode.registerServices(ctx, cfg)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = stack.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	theNode.PttNode.Stack = stack
+	theNode.Server = stack.Server()
+
+	// me is created asynchronously in the 1st start.
+	err = WaitUntil(WaitPttNodeTimeout, theNode.PttNode.isMeAlive)
+	if err != nil {
+		stack.Stop(false, false)
+		return nil, err
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.nodes[theNode.ID] = theNode
+
+	isPttNodeRunning = true
+
+	return theNode, nil
+}
+
+func (p *PttNode) stop() {
+	p.Stack.Stop(false, false)
+
+	lockPttNode.Lock()
+	defer lockPttNode.Unlock()
+
+	isPttNodeRunning = false
+}
+
+func (p *PttNode) isMeAlive() bool {
+	myInfo := p.Me.SPM().(*me.ServiceProtocolManager).MyInfo
+	return myInfo != nil && myInfo.Status == types.StatusAlive
+}
+
+/*
+registerServices creates the services in the same order as gptt.
+*/
+func (p *PttNode) registerServices(ctx *pkgservice.ServiceContext, cfgNode *node.Config) (pkgservice.PttService, error) {
+	dataDir := cfgNode.DataDir
+
+	cfgMe := &me.Config{
+		DataDir:     filepath.Join(dataDir, "me"),
+		NodeDataDir: dataDir,
+	}
+	err := cfgMe.SetMyKey("", "", "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	cfgPtt := pkgservice.DefaultConfig
+	cfgPtt.DataDir = filepath.Join(dataDir, "ptt")
+
+	cfgAccount := &account.Config{
+		DataDir: filepath.Join(dataDir, "account"),
+	}
+
+	cfgContent := &content.Config{
+		DataDir:     filepath.Join(dataDir, "content"),
+		KeystoreDir: filepath.Join(dataDir, ".keystore"),
+	}
+
+	cfgFriend := &friend.Config{
+		DataDir: filepath.Join(dataDir, "friend"),
+
+		MaxSyncRandomSeconds: friend.DefaultConfig.MaxSyncRandomSeconds,
+		MinSyncRandomSeconds: friend.DefaultConfig.MinSyncRandomSeconds,
+	}
+
+	cfgChat := &chat.Config{
+		DataDir: filepath.Join(dataDir, "chat"),
+	}
+
+	myNodeKey := cfgNode.NodeKey()
+	myNodeID := discover.PubkeyID(&myNodeKey.PublicKey)
+
+	p.Ptt, err = pkgservice.NewPtt(ctx, &cfgPtt, &myNodeID, myNodeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// account
+	p.Account, err = account.NewBackend(ctx, cfgAccount, p.Ptt)
+	if err != nil {
+		return nil, err
+	}
+	err = p.Ptt.RegisterService(p.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	// content
+	p.Content, err = content.NewBackend(ctx, cfgContent, cfgMe.ID, p.Ptt, p.Account)
+	if err != nil {
+		return nil, err
+	}
+	err = p.Ptt.RegisterService(p.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	// friend
+	p.Friend, err = friend.NewBackend(ctx, cfgFriend, cfgMe.ID, p.Ptt, p.Account, p.Content)
+	if err != nil {
+		return nil, err
+	}
+	err = p.Ptt.RegisterService(p.Friend)
+	if err != nil {
+		return nil, err
+	}
+
+	// chat
+	p.Chat, err = chat.NewBackend(ctx, cfgChat, cfgMe.ID, p.Ptt, p.Account)
+	if err != nil {
+		return nil, err
+	}
+	err = p.Ptt.RegisterService(p.Chat)
+	if err != nil {
+		return nil, err
+	}
+
+	// me
+	p.Me, err = me.NewBackend(ctx, cfgMe, p.Ptt, p.Account, p.Content, p.Friend, p.Chat)
+	if err != nil {
+		return nil, err
+	}
+	err = p.Ptt.RegisterService(p.Me)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.Ptt.Prestart()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Ptt, nil
+}
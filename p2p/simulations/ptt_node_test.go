@@ -0,0 +1,111 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulations
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/content"
+	"github.com/ailabstw/go-pttai/p2p/simulations/pipes"
+	"github.com/ailabstw/go-pttai/pttdb"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func tNewPttNode(t *testing.T, network *Network, name string) (*Node, func()) {
+	dataDir, err := ioutil.TempDir("", "simulations")
+	assert.NoError(t, err)
+
+	theNode, err := network.NewPttNode(name, filepath.Join(dataDir, name))
+	assert.NoError(t, err)
+
+	return theNode, func() {
+		network.Stop()
+		os.RemoveAll(dataDir)
+	}
+}
+
+func tBoardLog0Merkle(t *testing.T, theNode *Node, boardID *types.PttID) *pkgservice.Merkle {
+	entity := theNode.PttNode.Content.SPM().Entity(boardID)
+	if entity == nil {
+		return nil
+	}
+	return entity.PM().Log0Merkle()
+}
+
+func TestPttNodeBoardMerkle(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the ptt-node in short mode")
+	}
+
+	setupTest(t)
+	defer teardownTest(t)
+
+	network := NewNetwork(pipes.BufferedPipe)
+
+	nodeA, teardown := tNewPttNode(t, network, "a")
+	defer teardown()
+	if nodeA == nil {
+		return
+	}
+
+	// only 1 ptt-node in the process
+	_, err := network.NewPttNode("b", filepath.Join(os.TempDir(), "simulations-b"))
+	assert.Equal(t, ErrPttNodeRunning, err)
+
+	// create-board
+	board, err := nodeA.PttNode.Content.CreateBoard([]byte("simulations"), true)
+	assert.NoError(t, err)
+	boardIDBytes, _ := board.ID.MarshalText()
+
+	merkle := tBoardLog0Merkle(t, nodeA, board.ID)
+	if !assert.NotNil(t, merkle) {
+		return
+	}
+
+	ts, _ := types.GetTimestamp()
+	root0, err := merkle.GetMerkleRoot(ts)
+	assert.NoError(t, err)
+
+	// create-article
+	_, err = nodeA.PttNode.Content.CreateArticle(boardIDBytes, []byte("title"), [][]byte{[]byte("article")}, nil)
+	assert.NoError(t, err)
+
+	err = WaitUntil(30*time.Second, func() bool {
+		articles, _ := nodeA.PttNode.Content.GetArticleList(boardIDBytes, nil, 0, pttdb.ListOrderNext, nil)
+		return len(articles) == 1
+	})
+	assert.NoError(t, err)
+
+	ts, _ = types.GetTimestamp()
+	root1, err := merkle.GetMerkleRoot(ts)
+	assert.NoError(t, err)
+	assert.NotEqual(t, root0, root1)
+	assert.NotEqual(t, crypto.Keccak256(), root1)
+
+	// the same oplogs, the same root.
+	assert.NoError(t, WaitMerkleConverged(time.Second, merkle, merkle))
+
+	_, ok := nodeA.PttNode.Content.SPM().Entity(board.ID).(*content.Board)
+	assert.True(t, ok)
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulations
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	pkgservice "github.com/ailabstw/go-pttai/service"
+)
+
+/*
+WaitUntil polls isDone every WaitInterval until isDone or timeout.
+*/
+func WaitUntil(timeout time.Duration, isDone func() bool) error {
+	ticker := time.NewTicker(WaitInterval)
+	defer ticker.Stop()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		if isDone() {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			return ErrTimeout
+		}
+	}
+}
+
+/*
+WaitMerkleConverged waits until the merkle-roots of the merkles (ex: the board-oplog-merkles of the same board in different nodes) are the same.
+*/
+func WaitMerkleConverged(timeout time.Duration, merkles ...*pkgservice.Merkle) error {
+	return WaitUntil(timeout, func() bool {
+		return isMerkleConverged(merkles)
+	})
+}
+
+func isMerkleConverged(merkles []*pkgservice.Merkle) bool {
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return false
+	}
+
+	var root []byte
+	for i, merkle := range merkles {
+		eachRoot, err := merkle.GetMerkleRoot(ts)
+		if err != nil {
+			return false
+		}
+
+		if i != 0 && !reflect.DeepEqual(root, eachRoot) {
+			return false
+		}
+		root = eachRoot
+	}
+
+	return true
+}
@@ -0,0 +1,327 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package pttdb
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+baseDatabase implements DB on top of the engine,
+so that the lock-map / try-put / prefix-iterators are the same in all the engines.
+*/
+type baseDatabase struct {
+	name   string // filename not including data-dir
+	fn     string // filename for reporting
+	engine Engine
+
+	log log.Logger // Contextual logger tracking the database path
+
+	lockLockMap sync.Mutex
+	lockMap     map[string]int
+}
+
+func newBaseDatabase(file string, fullFilename string, engine Engine, logger log.Logger) *baseDatabase {
+	return &baseDatabase{
+		name:    file,
+		fn:      fullFilename,
+		engine:  engine,
+		log:     logger,
+		lockMap: make(map[string]int),
+	}
+}
+
+// Path returns the path to the database directory.
+func (db *baseDatabase) Path() string {
+	return db.fn
+}
+
+func (db *baseDatabase) Name() string {
+	return db.name
+}
+
+/*
+TryPut tries to put the key/val based on the updateTS of val.
+
+Value is the jsonified bytes. the obj of the bytes needs to include UpdateTS.
+*/
+func (db *baseDatabase) TryPut(key []byte, value []byte, updateTS types.Timestamp) ([]byte, error) {
+	//log.Debug("TryPut: start", "key", key)
+
+	err := db.TryLockMap(key)
+	if err != nil {
+		return nil, err
+	}
+	defer db.UnlockMap(key)
+
+	isHasKey, err := db.Has(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isHasKey { // new-one
+		err := db.Put(key, value)
+		return nil, err
+	}
+
+	v, err := db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &DBable{}
+
+	err = json.Unmarshal(v, d)
+	if err != nil {
+		return nil, ErrInvalidDBable
+	}
+
+	if updateTS.IsLess(d.UpdateTS) {
+		log.Warn("updateTS < d.UpdateTS", "updateTS", updateTS, "d.UpdateTS", d.UpdateTS, "key", key)
+		return v, ErrInvalidUpdateTS
+	}
+
+	// put to db
+
+	err = db.Put(key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (db *baseDatabase) TryLockMap(key []byte) error {
+	mapKey := string(key)
+
+	db.lockLockMap.Lock()
+	defer db.lockLockMap.Unlock()
+
+	//log.Debug("to TryLockMap", "key", key)
+
+	// try to get lock
+	val, ok := db.lockMap[mapKey]
+	if ok { // someone-else is using the map-key
+		log.Error("TryLockMap: busy", "mapKey", mapKey, "val", val)
+		return ErrBusy
+	}
+	db.lockMap[mapKey] = -1
+
+	return nil
+}
+
+func (db *baseDatabase) UnlockMap(key []byte) error {
+	mapKey := string(key)
+
+	db.lockLockMap.Lock()
+	defer db.lockLockMap.Unlock()
+
+	_, ok := db.lockMap[mapKey]
+	if !ok { // should not happen
+		return ErrInvalidLock
+	}
+
+	delete(db.lockMap, mapKey)
+
+	return nil
+}
+
+func (db *baseDatabase) TryRLockMap(key []byte) error {
+	mapKey := string(key)
+
+	db.lockLockMap.Lock()
+	defer db.lockLockMap.Unlock()
+
+	// try to get lock
+	i, ok := db.lockMap[mapKey]
+	if ok && i < 0 { // write-lock
+		log.Error("TryRLockMap: busy", "i", i, "ok", ok)
+		return ErrBusy
+	}
+
+	log.Debug("after TryRLockMap (pass lock)", "i", i, "ok", ok)
+
+	if !ok {
+		db.lockMap[mapKey] = 0
+	}
+	db.lockMap[mapKey]++
+
+	return nil
+}
+
+func (db *baseDatabase) RUnlockMap(key []byte) error {
+	mapKey := string(key)
+
+	db.lockLockMap.Lock()
+	defer db.lockLockMap.Unlock()
+
+	i, ok := db.lockMap[mapKey]
+	if !ok || i == 0 { // should not happen
+		panic("db invalid unlock")
+	}
+	db.lockMap[mapKey]--
+
+	if i == 1 {
+		delete(db.lockMap, mapKey)
+	}
+
+	return nil
+}
+
+// Put puts the given key / value to the queue
+func (db *baseDatabase) Put(key []byte, value []byte) error {
+	return db.engine.Put(key, value)
+}
+
+func (db *baseDatabase) Has(key []byte) (bool, error) {
+	return db.engine.Has(key)
+}
+
+// Get returns the given key if it's present.
+func (db *baseDatabase) Get(key []byte) ([]byte, error) {
+	dat, err := db.engine.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return dat, nil
+}
+
+// Delete deletes the key from the queue and database
+func (db *baseDatabase) Delete(key []byte) error {
+	err := db.engine.Delete(key)
+	if err == leveldb.ErrNotFound {
+		err = nil
+	}
+
+	return err
+}
+
+// Delete With Get
+func (db *baseDatabase) Pop(key []byte) ([]byte, error) {
+	err := db.TryLockMap(key)
+	if err != nil {
+		return nil, err
+	}
+	defer db.UnlockMap(key)
+
+	val, err := db.Get(key)
+	if err != nil {
+		// Unable to get key. possibly no key in the db. no need to do delete
+		return nil, err
+	}
+
+	err = db.Delete(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+func (db *baseDatabase) NewIterator(listOrder ListOrder) iterator.Iterator {
+	iter := db.engine.NewIterator(nil)
+	if listOrder == ListOrderPrev {
+		iter.Seek(dbLastKey)
+	}
+
+	return iter
+}
+
+func (db *baseDatabase) NewIteratorWithRange(r *util.Range, listOrder ListOrder) iterator.Iterator {
+	iter := db.engine.NewIterator(r)
+	if listOrder == ListOrderPrev {
+		iter.Seek(r.Limit)
+	}
+
+	return iter
+}
+
+// NewIteratorWithPrefix returns a iterator to iterate over subset of database content with a particular prefix.
+func (db *baseDatabase) NewIteratorWithPrefix(start []byte, prefix []byte, listOrder ListOrder) (iterator.Iterator, error) {
+	// both as nil
+	if len(start) == 0 && len(prefix) == 0 {
+		return db.NewIterator(listOrder), nil
+	}
+
+	// start as nil
+	if len(start) == 0 {
+		r := util.BytesPrefix(prefix)
+		return db.NewIteratorWithRange(r, listOrder), nil
+	}
+
+	// prefix as nil
+	if len(prefix) == 0 {
+		startRange := util.BytesPrefix(start)
+		var r *util.Range
+		switch listOrder {
+		case ListOrderPrev:
+			r = &util.Range{Limit: startRange.Limit}
+		case ListOrderNext:
+			r = &util.Range{Start: startRange.Start}
+		}
+
+		return db.NewIteratorWithRange(r, listOrder), nil
+	}
+
+	// both non-nil
+	if !strings.HasPrefix(string(start), string(prefix)) {
+		return nil, ErrInvalidPrefix
+	}
+
+	startRange := util.BytesPrefix(start)
+	prefixRange := util.BytesPrefix(prefix)
+	var r *util.Range
+	switch listOrder {
+	case ListOrderPrev:
+		r = &util.Range{Start: prefixRange.Start, Limit: startRange.Limit}
+	case ListOrderNext:
+		r = &util.Range{Start: startRange.Start, Limit: prefixRange.Limit}
+	}
+
+	return db.NewIteratorWithRange(r, listOrder), nil
+}
+
+func (db *baseDatabase) Write(batch *leveldb.Batch) error {
+	return db.engine.Write(batch)
+}
+
+func (db *baseDatabase) NewSnapshot() (Snapshot, error) {
+	return db.engine.NewSnapshot()
+}
+
+func (db *baseDatabase) NewBatch() Batch {
+	return &ldbBatch{db: db, b: new(leveldb.Batch)}
+}
+
+func (db *baseDatabase) Close() {
+	unregisterOpenedDB(db.fn)
+
+	err := db.engine.Close()
+	if err == nil {
+		db.log.Info("Database closed")
+	} else {
+		db.log.Error("Failed to close database", "err", err)
+	}
+}
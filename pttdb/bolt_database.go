@@ -0,0 +1,583 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+package pttdb
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/ailabstw/go-pttai/log"
+	bolt "github.com/coreos/bbolt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+BoltDatabase is DB with the bolt engine.
+
+The data is stored in <dataDir>/<file>/data.bolt with a single bucket.
+The keys are stored with a 0-byte prefix because bolt does not accept empty keys.
+*/
+type BoltDatabase struct {
+	*baseDatabase
+
+	db *bolt.DB
+}
+
+func NewBoltDatabase(file string, dataDir string) (*BoltDatabase, error) {
+	db, err := newBoltDatabase(file, dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	registerOpenedDB(db)
+
+	return db, nil
+}
+
+func newBoltDatabaseReadOnly(file string, dataDir string) (*BoltDatabase, error) {
+	return newBoltDatabase(file, dataDir, true)
+}
+
+func newBoltDatabase(file string, dataDir string, isReadOnly bool) (*BoltDatabase, error) {
+	fullFilename := filepath.Join(dataDir, file)
+
+	logger := log.New("database", fullFilename)
+
+	if !isReadOnly {
+		err := os.MkdirAll(fullFilename, 0700)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := bolt.Open(filepath.Join(fullFilename, BoltFilename), 0600, &bolt.Options{
+		Timeout:         TimeoutOpenBolt,
+		ReadOnly:        isReadOnly,
+		InitialMmapSize: BoltInitialMmapSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !isReadOnly {
+		err = db.Update(func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists([]byte(boltBucket))
+			return err
+		})
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return &BoltDatabase{
+		baseDatabase: newBaseDatabase(file, fullFilename, &boltEngine{db: db}, logger),
+		db:           db,
+	}, nil
+}
+
+/**********
+ * engine
+ **********/
+
+type boltEngine struct {
+	db *bolt.DB
+}
+
+func boltKey(key []byte) []byte {
+	theKey := make([]byte, len(key)+1)
+	copy(theKey[1:], key)
+	return theKey
+}
+
+func boltRange(r *util.Range) *util.Range {
+	if r == nil {
+		return &util.Range{}
+	}
+
+	theRange := &util.Range{}
+	if r.Start != nil {
+		theRange.Start = boltKey(r.Start)
+	}
+	if r.Limit != nil {
+		theRange.Limit = boltKey(r.Limit)
+	}
+
+	return theRange
+}
+
+func (e *boltEngine) get(tx *bolt.Tx, key []byte) []byte {
+	bucket := tx.Bucket([]byte(boltBucket))
+	if bucket == nil {
+		return nil
+	}
+
+	theKey := boltKey(key)
+	k, v := bucket.Cursor().Seek(theKey)
+	if k == nil || !bytes.Equal(k, theKey) {
+		return nil
+	}
+
+	if v == nil {
+		return []byte{}
+	}
+	return v
+}
+
+func (e *boltEngine) Get(key []byte) ([]byte, error) {
+	var val []byte
+	err := e.db.View(func(tx *bolt.Tx) error {
+		v := e.get(tx, key)
+		if v == nil {
+			return leveldb.ErrNotFound
+		}
+
+		val = common.CopyBytes(v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+func (e *boltEngine) Has(key []byte) (bool, error) {
+	isHas := false
+	err := e.db.View(func(tx *bolt.Tx) error {
+		isHas = e.get(tx, key) != nil
+		return nil
+	})
+
+	return isHas, err
+}
+
+func (e *boltEngine) Put(key []byte, value []byte) error {
+	return e.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(boltBucket)).Put(boltKey(key), common.CopyBytes(value))
+	})
+}
+
+func (e *boltEngine) Delete(key []byte) error {
+	return e.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(boltBucket)).Delete(boltKey(key))
+	})
+}
+
+func (e *boltEngine) Write(batch *leveldb.Batch) error {
+	return e.db.Update(func(tx *bolt.Tx) error {
+		r := &boltBatchReplay{bucket: tx.Bucket([]byte(boltBucket))}
+		err := batch.Replay(r)
+		if err != nil {
+			return err
+		}
+
+		return r.err
+	})
+}
+
+/*
+NewIterator iterates the key-vals in the range by pages (see boltPagedIterator).
+*/
+func (e *boltEngine) NewIterator(r *util.Range) iterator.Iterator {
+	return newBoltPagedIterator(e.db, r)
+}
+
+/*
+NewSnapshot opens a read-tx as the snapshot.
+
+The writes re-mapping the db-file (growing over BoltInitialMmapSize) wait until the snapshot is released,
+so the snapshot is not supposed to be kept while writing in the same goroutine.
+*/
+func (e *boltEngine) NewSnapshot() (Snapshot, error) {
+	tx, err := e.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &boltSnapshot{tx: tx}, nil
+}
+
+func (e *boltEngine) Close() error {
+	return e.db.Close()
+}
+
+type boltBatchReplay struct {
+	bucket *bolt.Bucket
+	err    error
+}
+
+func (r *boltBatchReplay) Put(key, value []byte) {
+	if r.err != nil {
+		return
+	}
+	r.err = r.bucket.Put(boltKey(key), common.CopyBytes(value))
+}
+
+func (r *boltBatchReplay) Delete(key []byte) {
+	if r.err != nil {
+		return
+	}
+	r.err = r.bucket.Delete(boltKey(key))
+}
+
+type boltSnapshot struct {
+	tx *bolt.Tx
+}
+
+func (s *boltSnapshot) NewIterator(r *util.Range) iterator.Iterator {
+	return newBoltIterator(s.tx, r)
+}
+
+func (s *boltSnapshot) Release() {
+	s.tx.Rollback()
+}
+
+/**********
+ * iterator
+ **********/
+
+type boltIteratorDir int
+
+const (
+	boltIteratorSOI boltIteratorDir = iota
+	boltIteratorValid
+	boltIteratorEOI
+	boltIteratorReleased
+)
+
+/*
+boltIterator iterates with the cursor of the tx, following the semantics of the goleveldb iterators.
+The keys / values are valid only until the next move, and the tx needs to be opened while iterating.
+*/
+type boltIterator struct {
+	util.BasicReleaser
+
+	cursor *bolt.Cursor
+	r      *util.Range
+	dir    boltIteratorDir
+
+	key   []byte
+	value []byte
+}
+
+func newBoltIterator(tx *bolt.Tx, r *util.Range) *boltIterator {
+	return &boltIterator{
+		cursor: tx.Bucket([]byte(boltBucket)).Cursor(),
+		r:      boltRange(r),
+	}
+}
+
+func (i *boltIterator) set(k []byte, v []byte, dirIfInvalid boltIteratorDir) bool {
+	if k == nil || (i.r.Start != nil && bytes.Compare(k, i.r.Start) < 0) || (i.r.Limit != nil && bytes.Compare(k, i.r.Limit) >= 0) {
+		i.dir = dirIfInvalid
+		i.key, i.value = nil, nil
+		return false
+	}
+
+	i.dir = boltIteratorValid
+	i.key, i.value = k[1:], v
+	if i.value == nil {
+		i.value = []byte{}
+	}
+	return true
+}
+
+func (i *boltIterator) First() bool {
+	if i.dir == boltIteratorReleased {
+		return false
+	}
+
+	var k, v []byte
+	if i.r.Start != nil {
+		k, v = i.cursor.Seek(i.r.Start)
+	} else {
+		k, v = i.cursor.First()
+	}
+
+	return i.set(k, v, boltIteratorEOI)
+}
+
+func (i *boltIterator) Last() bool {
+	if i.dir == boltIteratorReleased {
+		return false
+	}
+
+	var k, v []byte
+	if i.r.Limit != nil {
+		k, _ = i.cursor.Seek(i.r.Limit)
+		if k == nil {
+			k, v = i.cursor.Last()
+		} else {
+			k, v = i.cursor.Prev()
+		}
+	} else {
+		k, v = i.cursor.Last()
+	}
+
+	return i.set(k, v, boltIteratorSOI)
+}
+
+func (i *boltIterator) Seek(key []byte) bool {
+	if i.dir == boltIteratorReleased {
+		return false
+	}
+
+	theKey := boltKey(key)
+	if i.r.Start != nil && bytes.Compare(theKey, i.r.Start) < 0 {
+		theKey = i.r.Start
+	}
+
+	k, v := i.cursor.Seek(theKey)
+	return i.set(k, v, boltIteratorEOI)
+}
+
+func (i *boltIterator) Next() bool {
+	switch i.dir {
+	case boltIteratorSOI:
+		return i.First()
+	case boltIteratorValid:
+		k, v := i.cursor.Next()
+		return i.set(k, v, boltIteratorEOI)
+	}
+
+	return false
+}
+
+func (i *boltIterator) Prev() bool {
+	switch i.dir {
+	case boltIteratorEOI:
+		return i.Last()
+	case boltIteratorValid:
+		k, v := i.cursor.Prev()
+		return i.set(k, v, boltIteratorSOI)
+	}
+
+	return false
+}
+
+func (i *boltIterator) Valid() bool {
+	return i.dir == boltIteratorValid
+}
+
+func (i *boltIterator) Key() []byte {
+	return i.key
+}
+
+func (i *boltIterator) Value() []byte {
+	return i.value
+}
+
+func (i *boltIterator) Error() error {
+	return nil
+}
+
+func (i *boltIterator) Release() {
+	i.dir = boltIteratorReleased
+	i.key, i.value = nil, nil
+	i.BasicReleaser.Release()
+}
+
+/*
+boltPagedIterator iterates the range by pages of BoltIteratorPageSize key-vals, following the semantics of the goleveldb iterators.
+
+Each page is loaded with a read-tx of its own, and the read-tx is closed before returning to the caller:
+The read-tx of bolt cannot be kept open while iterating, because the writes in the same goroutine
+may wait for the read-tx to re-map the db-file.
+The keys / values are copied, and the writes after loading the page are seen from the next page.
+*/
+type boltPagedIterator struct {
+	util.BasicReleaser
+
+	db  *bolt.DB
+	r   *util.Range
+	dir boltIteratorDir
+
+	keys [][]byte
+	vals [][]byte
+	idx  int
+
+	err error
+}
+
+func newBoltPagedIterator(db *bolt.DB, r *util.Range) *boltPagedIterator {
+	return &boltPagedIterator{
+		db: db,
+		r:  r,
+	}
+}
+
+/*
+loadNext loads the page of the keys after fromKey (from the start of the range if fromKey is nil).
+*/
+func (i *boltPagedIterator) loadNext(fromKey []byte, isInclusive bool) bool {
+	if i.dir == boltIteratorReleased {
+		return false
+	}
+
+	keys, vals := make([][]byte, 0), make([][]byte, 0)
+	err := i.db.View(func(tx *bolt.Tx) error {
+		iter := newBoltIterator(tx, i.r)
+		defer iter.Release()
+
+		var isValid bool
+		if fromKey == nil {
+			isValid = iter.First()
+		} else {
+			isValid = iter.Seek(fromKey)
+			if isValid && !isInclusive && bytes.Equal(iter.Key(), fromKey) {
+				isValid = iter.Next()
+			}
+		}
+
+		for ; isValid && len(keys) < BoltIteratorPageSize; isValid = iter.Next() {
+			keys = append(keys, common.CopyBytes(iter.Key()))
+			vals = append(vals, common.CopyBytes(iter.Value()))
+		}
+
+		return nil
+	})
+
+	return i.setPage(keys, vals, 0, boltIteratorEOI, err)
+}
+
+/*
+loadPrev loads the page of the keys before fromKey (from the end of the range if fromKey is nil).
+*/
+func (i *boltPagedIterator) loadPrev(fromKey []byte) bool {
+	if i.dir == boltIteratorReleased {
+		return false
+	}
+
+	keys, vals := make([][]byte, 0), make([][]byte, 0)
+	err := i.db.View(func(tx *bolt.Tx) error {
+		iter := newBoltIterator(tx, i.r)
+		defer iter.Release()
+
+		var isValid bool
+		if fromKey == nil {
+			isValid = iter.Last()
+		} else {
+			iter.Seek(fromKey)
+			isValid = iter.Prev()
+		}
+
+		for ; isValid && len(keys) < BoltIteratorPageSize; isValid = iter.Prev() {
+			keys = append(keys, common.CopyBytes(iter.Key()))
+			vals = append(vals, common.CopyBytes(iter.Value()))
+		}
+
+		return nil
+	})
+
+	// reverse to the ascending order
+	for j, k := 0, len(keys)-1; j < k; j, k = j+1, k-1 {
+		keys[j], keys[k] = keys[k], keys[j]
+		vals[j], vals[k] = vals[k], vals[j]
+	}
+
+	return i.setPage(keys, vals, len(keys)-1, boltIteratorSOI, err)
+}
+
+func (i *boltPagedIterator) setPage(keys [][]byte, vals [][]byte, idx int, dirIfEmpty boltIteratorDir, err error) bool {
+	if err != nil {
+		i.err = err
+	}
+
+	if err != nil || len(keys) == 0 {
+		i.dir = dirIfEmpty
+		i.keys, i.vals = nil, nil
+		return false
+	}
+
+	i.dir = boltIteratorValid
+	i.keys, i.vals, i.idx = keys, vals, idx
+	return true
+}
+
+func (i *boltPagedIterator) First() bool {
+	return i.loadNext(nil, true)
+}
+
+func (i *boltPagedIterator) Last() bool {
+	return i.loadPrev(nil)
+}
+
+func (i *boltPagedIterator) Seek(key []byte) bool {
+	return i.loadNext(key, true)
+}
+
+func (i *boltPagedIterator) Next() bool {
+	switch i.dir {
+	case boltIteratorSOI:
+		return i.First()
+	case boltIteratorValid:
+		if i.idx+1 < len(i.keys) {
+			i.idx++
+			return true
+		}
+		return i.loadNext(i.keys[i.idx], false)
+	}
+
+	return false
+}
+
+func (i *boltPagedIterator) Prev() bool {
+	switch i.dir {
+	case boltIteratorEOI:
+		return i.Last()
+	case boltIteratorValid:
+		if i.idx > 0 {
+			i.idx--
+			return true
+		}
+		return i.loadPrev(i.keys[i.idx])
+	}
+
+	return false
+}
+
+func (i *boltPagedIterator) Valid() bool {
+	return i.dir == boltIteratorValid
+}
+
+func (i *boltPagedIterator) Key() []byte {
+	if i.dir != boltIteratorValid {
+		return nil
+	}
+	return i.keys[i.idx]
+}
+
+func (i *boltPagedIterator) Value() []byte {
+	if i.dir != boltIteratorValid {
+		return nil
+	}
+	return i.vals[i.idx]
+}
+
+func (i *boltPagedIterator) Error() error {
+	return i.err
+}
+
+func (i *boltPagedIterator) Release() {
+	i.dir = boltIteratorReleased
+	i.keys, i.vals = nil, nil
+	i.BasicReleaser.Release()
+}
@@ -16,6 +16,13 @@
 
 package pttdb
 
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
 // Putter wraps the database write operation supported by both batches and regular databases.
 type Putter interface {
 	Put(key []byte, value []byte) error
@@ -51,3 +58,60 @@ type Batch interface {
 	// Reset resets the batch for reuse
 	Reset()
 }
+
+/*
+DB is the database used by the services, with the lock-map, try-put, and the prefix-iterators.
+DB is implemented on top of the storage-engines (LDBDatabase, MemDatabase, BoltDatabase).
+
+Get returns leveldb.ErrNotFound if the key does not exist in all the implementations.
+*/
+type DB interface {
+	Database
+
+	Name() string
+	Path() string
+
+	TryPut(key []byte, value []byte, updateTS types.Timestamp) ([]byte, error)
+	Pop(key []byte) ([]byte, error)
+
+	TryLockMap(key []byte) error
+	UnlockMap(key []byte) error
+	TryRLockMap(key []byte) error
+	RUnlockMap(key []byte) error
+
+	NewIterator(listOrder ListOrder) iterator.Iterator
+	NewIteratorWithRange(r *util.Range, listOrder ListOrder) iterator.Iterator
+	NewIteratorWithPrefix(start []byte, prefix []byte, listOrder ListOrder) (iterator.Iterator, error)
+
+	Write(batch *leveldb.Batch) error
+	NewSnapshot() (Snapshot, error)
+}
+
+/*
+IndexBatch is the batch with the 2nd-indexing on top of DB.
+*/
+type IndexBatch interface {
+	Batch
+
+	DB() DB
+	DBGet(key []byte) ([]byte, error)
+	DBDelete(key []byte) error
+
+	TryPutAll(idxKey []byte, idx *Index, kvs []*KeyVal, isDeleteOrig bool, isGetOrig bool) ([]*KeyVal, error)
+	ForcePutAll(idxKey []byte, idx *Index, kvs []*KeyVal) ([][]byte, error)
+	DeleteAll(idxKey []byte) error
+	DeleteAllKeys(keys [][]byte) error
+
+	GetByIdxKey(idxKey []byte, idx int) ([]byte, error)
+	GetKeyByIdxKey(idxKey []byte, idx int) ([]byte, error)
+	GetBy2ndIdxKey(idxKey []byte) ([]byte, error)
+	GetKeyBy2ndIdxKey(idxKey []byte) ([]byte, error)
+}
+
+/*
+Snapshot is the read-only consistent view of DB (ex: for backup).
+*/
+type Snapshot interface {
+	NewIterator(r *util.Range) iterator.Iterator
+	Release()
+}
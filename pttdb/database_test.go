@@ -44,6 +44,22 @@ func newTestLDB() (*pttdb.LDBDatabase, func()) {
 	}
 }
 
+func newTestBoltDB() (*pttdb.BoltDatabase, func()) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "pttdb_test_")
+	if err != nil {
+		panic("failed to create test file: " + err.Error())
+	}
+	db, err := pttdb.NewBoltDatabase(dirname, "")
+	if err != nil {
+		panic("failed to create test database: " + err.Error())
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dirname)
+	}
+}
+
 var test_values = []string{"", "a", "1251", "\x00123\x00"}
 
 func TestLDB_PutGet(t *testing.T) {
@@ -56,6 +72,12 @@ func TestMemoryDB_PutGet(t *testing.T) {
 	testPutGet(pttdb.NewMemDatabase(), t)
 }
 
+func TestBoltDB_PutGet(t *testing.T) {
+	db, remove := newTestBoltDB()
+	defer remove()
+	testPutGet(db, t)
+}
+
 func testPutGet(db pttdb.Database, t *testing.T) {
 	t.Parallel()
 
@@ -133,6 +155,12 @@ func TestMemoryDB_ParallelPutGet(t *testing.T) {
 	testParallelPutGet(pttdb.NewMemDatabase(), t)
 }
 
+func TestBoltDB_ParallelPutGet(t *testing.T) {
+	db, remove := newTestBoltDB()
+	defer remove()
+	testParallelPutGet(db, t)
+}
+
 func testParallelPutGet(db pttdb.Database, t *testing.T) {
 	const n = 8
 	var pending sync.WaitGroup
@@ -0,0 +1,709 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package pttdb
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"golang.org/x/crypto/scrypt"
+)
+
+/*
+The values of the encrypted databases are sealed with the per-database data-key:
+
+	nonce | aes-256-gcm(data-key, nonce, value, key as the additional-data)
+
+The keys are kept in plaintext to preserve the ordering of the iterators.
+
+The data-key is in the DataKeyFilename of the database-dir, wrapped by the passphrase (the passphrase of the keystore).
+The kek (key-encryption-key) is derived by scrypt with the salt shared by all the data-keys wrapped in this process,
+so that we derive the kek only once while opening the databases.
+*/
+var (
+	dbPassphrase     []byte
+	dbDataKeySalt    []byte
+	dbKEKs           = make(map[[sha256.Size]byte][]byte)
+	lockDBPassphrase sync.Mutex
+)
+
+/*
+SetPassphrase sets the passphrase to unwrap the data-keys of the encrypted databases.
+The newly created leveldb databases are encrypted if the passphrase is set.
+*/
+func SetPassphrase(passphrase []byte) {
+	lockDBPassphrase.Lock()
+	defer lockDBPassphrase.Unlock()
+
+	dbPassphrase = passphrase
+	dbKEKs = make(map[[sha256.Size]byte][]byte)
+}
+
+func IsEncryptNewDatabase() bool {
+	lockDBPassphrase.Lock()
+	defer lockDBPassphrase.Unlock()
+
+	return len(dbPassphrase) != 0
+}
+
+type dataKeyFile struct {
+	Version int    `json:"version"`
+	N       int    `json:"n"`
+	R       int    `json:"r"`
+	P       int    `json:"p"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Key     []byte `json:"key"`
+}
+
+func newDataKey() ([]byte, error) {
+	dataKey := make([]byte, SizeDataKey)
+	_, err := rand.Read(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return dataKey, nil
+}
+
+/*
+kekAEAD gets the aead of the kek. Requires lockDBPassphrase.
+*/
+func kekAEAD(passphrase []byte, salt []byte, n int, r int, p int) (cipher.AEAD, error) {
+	h := sha256.New()
+	h.Write(passphrase)
+	h.Write(salt)
+	h.Write([]byte(strconv.Itoa(n) + "/" + strconv.Itoa(r) + "/" + strconv.Itoa(p)))
+	var cacheKey [sha256.Size]byte
+	copy(cacheKey[:], h.Sum(nil))
+
+	kek, ok := dbKEKs[cacheKey]
+	if !ok {
+		var err error
+		kek, err = scrypt.Key(passphrase, salt, n, r, p, SizeDataKey)
+		if err != nil {
+			return nil, err
+		}
+		dbKEKs[cacheKey] = kek
+	}
+
+	return newAEAD(kek)
+}
+
+func newAEAD(theKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(theKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+/*
+wrapDataKey wraps the data-key by the passphrase. Requires lockDBPassphrase.
+*/
+func wrapDataKey(dataKey []byte, passphrase []byte) (*dataKeyFile, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrDBLocked
+	}
+
+	if dbDataKeySalt == nil {
+		salt := make([]byte, SizeDataKeySalt)
+		_, err := rand.Read(salt)
+		if err != nil {
+			return nil, err
+		}
+		dbDataKeySalt = salt
+	}
+
+	f := &dataKeyFile{
+		Version: DataKeyVersion,
+		N:       DataKeyScryptN,
+		R:       DataKeyScryptR,
+		P:       DataKeyScryptP,
+		Salt:    dbDataKeySalt,
+	}
+
+	aead, err := kekAEAD(passphrase, f.Salt, f.N, f.R, f.P)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Nonce = make([]byte, aead.NonceSize())
+	_, err = rand.Read(f.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Key = aead.Seal(nil, f.Nonce, dataKey, nil)
+
+	return f, nil
+}
+
+/*
+unwrapDataKey unwraps the data-key by the passphrase. Requires lockDBPassphrase.
+*/
+func unwrapDataKey(f *dataKeyFile, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrDBLocked
+	}
+
+	if f.Version != DataKeyVersion {
+		return nil, ErrInvalidDataKey
+	}
+
+	aead, err := kekAEAD(passphrase, f.Salt, f.N, f.R, f.P)
+	if err != nil {
+		return nil, ErrInvalidDataKey
+	}
+	if len(f.Nonce) != aead.NonceSize() {
+		return nil, ErrInvalidDataKey
+	}
+
+	dataKey, err := aead.Open(nil, f.Nonce, f.Key, nil)
+	if err != nil {
+		return nil, ErrInvalidDataKey
+	}
+
+	return dataKey, nil
+}
+
+func readDataKeyFile(dir string) (*dataKeyFile, error) {
+	theBytes, err := ioutil.ReadFile(filepath.Join(dir, DataKeyFilename))
+	if err != nil {
+		return nil, err
+	}
+
+	f := &dataKeyFile{}
+	err = json.Unmarshal(theBytes, f)
+	if err != nil {
+		return nil, ErrInvalidDataKey
+	}
+
+	return f, nil
+}
+
+func writeDataKeyFile(dir string, f *dataKeyFile) error {
+	filename := filepath.Join(dir, DataKeyFilename)
+	tmpFilename := filename + ".tmp"
+	err := writeDataKeyTmpFile(tmpFilename, f)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFilename, filename)
+}
+
+func writeDataKeyTmpFile(tmpFilename string, f *dataKeyFile) error {
+	theBytes, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(tmpFilename, theBytes, 0600)
+}
+
+/*
+IsEncryptedDatabase checks whether the database at the path is encrypted (with the data-key).
+*/
+func IsEncryptedDatabase(path string) bool {
+	_, err := os.Stat(filepath.Join(path, DataKeyFilename))
+	return err == nil
+}
+
+/*
+loadDataKey loads the data-key of the database-dir with the passphrase.
+*/
+func loadDataKey(dir string) ([]byte, error) {
+	f, err := readDataKeyFile(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	lockDBPassphrase.Lock()
+	defer lockDBPassphrase.Unlock()
+
+	return unwrapDataKey(f, dbPassphrase)
+}
+
+/*
+saveDataKey saves the data-key to the database-dir wrapped by the passphrase.
+*/
+func saveDataKey(dir string, dataKey []byte) error {
+	lockDBPassphrase.Lock()
+	defer lockDBPassphrase.Unlock()
+
+	f, err := wrapDataKey(dataKey, dbPassphrase)
+	if err != nil {
+		return err
+	}
+
+	return writeDataKeyFile(dir, f)
+}
+
+/*
+newEncryptedEngineIfNeeded wraps the engine of the database-dir with the encryption
+if the database is encrypted, or if the database is new and the passphrase is set.
+*/
+func newEncryptedEngineIfNeeded(engine Engine, dir string, isNew bool) (Engine, error) {
+	if IsEncryptedDatabase(dir) {
+		dataKey, err := loadDataKey(dir)
+		if err != nil {
+			return nil, err
+		}
+		return newEncryptedEngine(engine, dataKey)
+	}
+
+	if !isNew || !IsEncryptNewDatabase() {
+		return engine, nil
+	}
+
+	dataKey, err := newDataKey()
+	if err != nil {
+		return nil, err
+	}
+
+	err = saveDataKey(dir, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEncryptedEngine(engine, dataKey)
+}
+
+/*
+ChangePassphrase re-wraps all the data-keys in the data-dir with the new passphrase,
+and sets the new passphrase to open the databases.
+The data-keys are not changed, so the opened databases are not affected.
+
+The change is all-or-nothing: all the re-wrapped data-keys are written to the tmp-files first,
+switchKeystore (if not nil) is called only after all the data-keys are re-wrapped,
+and the tmp-files replace the data-key-files only after switchKeystore succeeds.
+On any error the tmp-files are removed and the old passphrase still opens all the databases.
+*/
+func ChangePassphrase(dataDir string, oldPassphrase []byte, newPassphrase []byte, switchKeystore func() error) error {
+	lockDBPassphrase.Lock()
+	defer lockDBPassphrase.Unlock()
+
+	dirs, err := encryptedDatabaseDirs(dataDir)
+	if err != nil {
+		return err
+	}
+
+	if len(dirs) != 0 && len(newPassphrase) == 0 {
+		return ErrDBLocked
+	}
+
+	// 1. re-wrap all the data-keys to the tmp-files.
+	tmpFilenames := make([]string, 0, len(dirs))
+	removeTmpFiles := func() {
+		for _, tmpFilename := range tmpFilenames {
+			os.Remove(tmpFilename)
+		}
+	}
+
+	for _, dir := range dirs {
+		tmpFilename, err := rewrapDataKey(dir, oldPassphrase, newPassphrase)
+		if err != nil {
+			log.Error("ChangePassphrase: unable to re-wrap data-key", "dir", dir, "e", err)
+			removeTmpFiles()
+			return err
+		}
+		tmpFilenames = append(tmpFilenames, tmpFilename)
+	}
+
+	// 2. switch the keystore.
+	if switchKeystore != nil {
+		err = switchKeystore()
+		if err != nil {
+			removeTmpFiles()
+			return err
+		}
+	}
+
+	// 3. replace the data-key-files.
+	for i, dir := range dirs {
+		err = os.Rename(tmpFilenames[i], filepath.Join(dir, DataKeyFilename))
+		if err != nil {
+			log.Error("ChangePassphrase: unable to replace data-key", "dir", dir, "e", err)
+			removeTmpFiles()
+			return err
+		}
+	}
+
+	dbPassphrase = newPassphrase
+	dbKEKs = make(map[[sha256.Size]byte][]byte)
+
+	return nil
+}
+
+/*
+rewrapDataKey writes the data-key of the database-dir wrapped by the new passphrase to the tmp-file.
+*/
+func rewrapDataKey(dir string, oldPassphrase []byte, newPassphrase []byte) (string, error) {
+	f, err := readDataKeyFile(dir)
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := unwrapDataKey(f, oldPassphrase)
+	if err != nil {
+		return "", err
+	}
+
+	f, err = wrapDataKey(dataKey, newPassphrase)
+	if err != nil {
+		return "", err
+	}
+
+	tmpFilename := filepath.Join(dir, DataKeyFilename) + ".new"
+	err = writeDataKeyTmpFile(tmpFilename, f)
+	if err != nil {
+		return "", err
+	}
+
+	return tmpFilename, nil
+}
+
+/*
+HasEncryptedDatabases checks whether there are encrypted databases in the data-dir.
+*/
+func HasEncryptedDatabases(dataDir string) (bool, error) {
+	dirs, err := encryptedDatabaseDirs(dataDir)
+	if err != nil {
+		return false, err
+	}
+
+	return len(dirs) != 0, nil
+}
+
+func encryptedDatabaseDirs(dataDir string) ([]string, error) {
+	dirs := make([]string, 0)
+	err := filepath.Walk(dataDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() || !IsEncryptedDatabase(path) {
+			return nil
+		}
+
+		dirs = append(dirs, path)
+
+		return filepath.SkipDir
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return dirs, err
+}
+
+/**********
+ * Migration
+ **********/
+
+/*
+EncryptDatabase encrypts the existing plaintext leveldb database at the path (offline).
+*/
+func EncryptDatabase(path string) error {
+	return migrateDatabase(path, true)
+}
+
+/*
+DecryptDatabase decrypts the existing encrypted leveldb database at the path (offline).
+*/
+func DecryptDatabase(path string) error {
+	return migrateDatabase(path, false)
+}
+
+/*
+migrateDatabase copies the database to <path>.migrating with/without the encryption,
+and replaces the database after the copy, so that the database is never half-migrated.
+*/
+func migrateDatabase(path string, isEncrypt bool) error {
+	engineType, err := GetEngineType(path)
+	if err != nil {
+		return err
+	}
+	if engineType != EngineLevelDB {
+		return ErrInvalidEngine
+	}
+
+	isEncrypted := IsEncryptedDatabase(path)
+	if isEncrypted == isEncrypt {
+		return nil
+	}
+
+	var dataKey []byte
+	if isEncrypted {
+		dataKey, err = loadDataKey(path)
+	} else {
+		dataKey, err = newDataKey()
+	}
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".migrating"
+	err = os.RemoveAll(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	err = copyDatabase(path, tmpPath, dataKey, isEncrypt)
+	if err != nil {
+		os.RemoveAll(tmpPath)
+		return err
+	}
+
+	origPath := path + ".orig"
+	err = os.Rename(path, origPath)
+	if err != nil {
+		os.RemoveAll(tmpPath)
+		return err
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		os.Rename(origPath, path)
+		return err
+	}
+
+	return os.RemoveAll(origPath)
+}
+
+func copyDatabase(srcPath string, dstPath string, dataKey []byte, isEncrypt bool) error {
+	srcDB, err := leveldb.OpenFile(srcPath, nil)
+	if err != nil {
+		return err
+	}
+	defer srcDB.Close()
+
+	dstDB, err := leveldb.OpenFile(dstPath, nil)
+	if err != nil {
+		return err
+	}
+	defer dstDB.Close()
+
+	var src Engine = &ldbEngine{db: srcDB}
+	var dst Engine = &ldbEngine{db: dstDB}
+	if isEncrypt {
+		err = saveDataKey(dstPath, dataKey)
+		if err != nil {
+			return err
+		}
+		dst, err = newEncryptedEngine(dst, dataKey)
+	} else {
+		src, err = newEncryptedEngine(src, dataKey)
+	}
+	if err != nil {
+		return err
+	}
+
+	iter := src.NewIterator(nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		batch.Put(iter.Key(), iter.Value())
+		if len(batch.Dump()) < IdealBatchSize {
+			continue
+		}
+
+		err = dst.Write(batch)
+		if err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	err = iter.Error()
+	if err != nil {
+		return err
+	}
+
+	return dst.Write(batch)
+}
+
+/**********
+ * Engine
+ **********/
+
+/*
+encryptedEngine encrypts / decrypts the values on top of the engine.
+*/
+type encryptedEngine struct {
+	Engine
+
+	aead cipher.AEAD
+}
+
+func newEncryptedEngine(engine Engine, dataKey []byte) (*encryptedEngine, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedEngine{Engine: engine, aead: aead}, nil
+}
+
+func (e *encryptedEngine) encrypt(key []byte, value []byte) ([]byte, error) {
+	nonce := make([]byte, e.aead.NonceSize(), e.aead.NonceSize()+len(value)+e.aead.Overhead())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.aead.Seal(nonce, nonce, value, key), nil
+}
+
+func (e *encryptedEngine) decrypt(key []byte, value []byte) ([]byte, error) {
+	nonceSize := e.aead.NonceSize()
+	if len(value) < nonceSize {
+		return nil, ErrInvalidEncryptedValue
+	}
+
+	plaintext, err := e.aead.Open(nil, value[:nonceSize], value[nonceSize:], key)
+	if err != nil {
+		return nil, ErrInvalidEncryptedValue
+	}
+
+	return plaintext, nil
+}
+
+func (e *encryptedEngine) Get(key []byte) ([]byte, error) {
+	value, err := e.Engine.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.decrypt(key, value)
+}
+
+func (e *encryptedEngine) Put(key []byte, value []byte) error {
+	encrypted, err := e.encrypt(key, value)
+	if err != nil {
+		return err
+	}
+
+	return e.Engine.Put(key, encrypted)
+}
+
+func (e *encryptedEngine) Write(batch *leveldb.Batch) error {
+	encBatch := &encryptedBatch{engine: e, b: new(leveldb.Batch)}
+	err := batch.Replay(encBatch)
+	if err != nil {
+		return err
+	}
+	if encBatch.err != nil {
+		return encBatch.err
+	}
+
+	return e.Engine.Write(encBatch.b)
+}
+
+func (e *encryptedEngine) NewIterator(r *util.Range) iterator.Iterator {
+	return &encryptedIterator{Iterator: e.Engine.NewIterator(r), engine: e}
+}
+
+func (e *encryptedEngine) NewSnapshot() (Snapshot, error) {
+	snap, err := e.Engine.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedSnapshot{Snapshot: snap, engine: e}, nil
+}
+
+/*
+encryptedBatch re-builds the batch with the encrypted values (as leveldb.BatchReplay).
+*/
+type encryptedBatch struct {
+	engine *encryptedEngine
+	b      *leveldb.Batch
+	err    error
+}
+
+func (b *encryptedBatch) Put(key []byte, value []byte) {
+	if b.err != nil {
+		return
+	}
+
+	encrypted, err := b.engine.encrypt(key, value)
+	if err != nil {
+		b.err = err
+		return
+	}
+
+	b.b.Put(key, encrypted)
+}
+
+func (b *encryptedBatch) Delete(key []byte) {
+	b.b.Delete(key)
+}
+
+type encryptedIterator struct {
+	iterator.Iterator
+
+	engine *encryptedEngine
+	err    error
+}
+
+func (it *encryptedIterator) Value() []byte {
+	value := it.Iterator.Value()
+	if value == nil {
+		return nil
+	}
+
+	plaintext, err := it.engine.decrypt(it.Iterator.Key(), value)
+	if err != nil {
+		it.err = err
+		return nil
+	}
+
+	return plaintext
+}
+
+func (it *encryptedIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+
+	return it.Iterator.Error()
+}
+
+type encryptedSnapshot struct {
+	Snapshot
+
+	engine *encryptedEngine
+}
+
+func (s *encryptedSnapshot) NewIterator(r *util.Range) iterator.Iterator {
+	return &encryptedIterator{Iterator: s.Snapshot.NewIterator(r), engine: s.engine}
+}
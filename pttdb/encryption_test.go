@@ -0,0 +1,233 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package pttdb
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+func setupEncryptionTest(t *testing.T) func() {
+	origScryptN := DataKeyScryptN
+	DataKeyScryptN = 1 << 10
+
+	SetPassphrase([]byte("passphrase"))
+
+	return func() {
+		SetPassphrase(nil)
+		DataKeyScryptN = origScryptN
+	}
+}
+
+func TestEncryption_LDBDatabase(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+	defer setupEncryptionTest(t)()
+
+	db, err := NewLDBDatabase("encrypted", "./test.out", 0, 0)
+	if err != nil {
+		t.Fatalf("unable to create db: e: %v", err)
+	}
+	path := db.Path()
+	defer os.RemoveAll(path)
+
+	if !IsEncryptedDatabase(path) {
+		t.Errorf("IsEncryptedDatabase: false")
+	}
+
+	// put / batch / try-put-all
+	db.Put([]byte("test-key"), []byte("test-value"))
+
+	batch := new(leveldb.Batch)
+	batch.Put([]byte("test-key2"), []byte("test-value2"))
+	db.Write(batch)
+
+	dbBatch, _ := NewLDBBatch(db)
+	updateTS, _ := types.GetTimestamp()
+	idx := &Index{Keys: [][]byte{[]byte("test-key3")}, UpdateTS: updateTS}
+	kvs := []*KeyVal{&KeyVal{K: []byte("test-key3"), V: []byte("test-value3")}}
+	_, err = dbBatch.TryPutAll([]byte("test-idx-key"), idx, kvs, false, false)
+	if err != nil {
+		t.Errorf("TryPutAll: e: %v", err)
+	}
+
+	got, err := dbBatch.GetByIdxKey([]byte("test-idx-key"), 0)
+	if err != nil || !reflect.DeepEqual(got, []byte("test-value3")) {
+		t.Errorf("GetByIdxKey: got: %v e: %v", got, err)
+	}
+
+	// the values in the engine are encrypted.
+	raw, _ := db.LDB().Get([]byte("test-key"), nil)
+	if bytes.Contains(raw, []byte("test-value")) {
+		t.Errorf("raw value not encrypted: %v", raw)
+	}
+
+	// iterator
+	iter := db.NewIterator(ListOrderNext)
+	n := 0
+	for iter.Next() {
+		if !bytes.HasPrefix(iter.Value(), []byte("test-value")) && !bytes.Equal(iter.Key(), []byte("test-idx-key")) {
+			t.Errorf("iter: key: %v value: %v", iter.Key(), iter.Value())
+		}
+		n++
+	}
+	if iter.Error() != nil || n != 4 {
+		t.Errorf("iter: n: %v e: %v", n, iter.Error())
+	}
+	iter.Release()
+
+	db.Close()
+
+	// locked without the passphrase.
+	SetPassphrase(nil)
+	_, err = NewLDBDatabase("encrypted", "./test.out", 0, 0)
+	if err != ErrDBLocked {
+		t.Errorf("NewLDBDatabase: e: %v want: %v", err, ErrDBLocked)
+	}
+
+	// change passphrase
+	SetPassphrase([]byte("passphrase"))
+	err = ChangePassphrase("./test.out", []byte("passphrase"), []byte("passphrase2"), nil)
+	if err != nil {
+		t.Errorf("ChangePassphrase: e: %v", err)
+	}
+
+	// decrypt
+	err = DecryptDatabase(path)
+	if err != nil {
+		t.Errorf("DecryptDatabase: e: %v", err)
+	}
+	if IsEncryptedDatabase(path) {
+		t.Errorf("DecryptDatabase: still encrypted")
+	}
+
+	SetPassphrase(nil)
+	db, err = NewLDBDatabase("encrypted", "./test.out", 0, 0)
+	if err != nil {
+		t.Fatalf("NewLDBDatabase: e: %v", err)
+	}
+	got, err = db.Get([]byte("test-key2"))
+	if err != nil || !reflect.DeepEqual(got, []byte("test-value2")) {
+		t.Errorf("Get: after decrypt: got: %v e: %v", got, err)
+	}
+	db.Close()
+
+	// encrypt
+	SetPassphrase([]byte("passphrase2"))
+	err = EncryptDatabase(path)
+	if err != nil {
+		t.Errorf("EncryptDatabase: e: %v", err)
+	}
+
+	db, err = NewLDBDatabase("encrypted", "./test.out", 0, 0)
+	if err != nil {
+		t.Fatalf("NewLDBDatabase: e: %v", err)
+	}
+	got, err = db.Get([]byte("test-key2"))
+	if err != nil || !reflect.DeepEqual(got, []byte("test-value2")) {
+		t.Errorf("Get: after encrypt: got: %v e: %v", got, err)
+	}
+	raw, _ = db.LDB().Get([]byte("test-key2"), nil)
+	if bytes.Contains(raw, []byte("test-value2")) {
+		t.Errorf("raw value not encrypted after EncryptDatabase: %v", raw)
+	}
+	db.Close()
+
+	// teardown test
+}
+
+func TestEncryption_ChangePassphraseAtomic(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+	defer setupEncryptionTest(t)()
+
+	db, err := NewLDBDatabase("encrypted", "./test.out", 0, 0)
+	if err != nil {
+		t.Fatalf("unable to create db: e: %v", err)
+	}
+	db.Put([]byte("test-key"), []byte("test-value"))
+	db.Close()
+	defer os.RemoveAll(db.Path())
+
+	db2, err := NewLDBDatabase("encrypted2", "./test.out", 0, 0)
+	if err != nil {
+		t.Fatalf("unable to create db2: e: %v", err)
+	}
+	db2.Put([]byte("test-key"), []byte("test-value2"))
+	db2.Close()
+	defer os.RemoveAll(db2.Path())
+
+	errSwitch := errors.New("switch keystore failed")
+
+	// prepare test-cases
+	tests := []struct {
+		name           string
+		oldPassphrase  []byte
+		switchKeystore func() error
+		wantErr        error
+		wantSwitched   bool
+	}{
+		{"invalid-old-passphrase", []byte("invalid"), func() error { return nil }, ErrInvalidDataKey, false},
+		{"switch-keystore-failed", []byte("passphrase"), func() error { return errSwitch }, errSwitch, true},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isSwitched := false
+			err := ChangePassphrase("./test.out", tt.oldPassphrase, []byte("passphrase2"), func() error {
+				isSwitched = true
+				return tt.switchKeystore()
+			})
+			if err != tt.wantErr {
+				t.Errorf("ChangePassphrase: e: %v want: %v", err, tt.wantErr)
+			}
+			if isSwitched != tt.wantSwitched {
+				t.Errorf("ChangePassphrase: isSwitched: %v want: %v", isSwitched, tt.wantSwitched)
+			}
+
+			// no tmp-files left, and the old passphrase still opens all the databases.
+			for _, path := range []string{db.Path(), db2.Path()} {
+				_, err := os.Stat(filepath.Join(path, DataKeyFilename+".new"))
+				if !os.IsNotExist(err) {
+					t.Errorf("tmp-file left: path: %v e: %v", path, err)
+				}
+			}
+
+			SetPassphrase([]byte("passphrase"))
+			for _, name := range []string{"encrypted", "encrypted2"} {
+				theDB, err := NewLDBDatabase(name, "./test.out", 0, 0)
+				if err != nil {
+					t.Errorf("NewLDBDatabase: name: %v e: %v", name, err)
+					continue
+				}
+				theDB.Close()
+			}
+		})
+	}
+
+	// teardown test
+}
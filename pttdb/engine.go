@@ -0,0 +1,138 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+package pttdb
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+Engine is the key-value storage-engine of DB.
+
+Get returns leveldb.ErrNotFound if the key does not exist,
+and the iterators follow the semantics of the goleveldb iterators,
+so that the services are not aware of the engines.
+*/
+type Engine interface {
+	Get(key []byte) ([]byte, error)
+	Has(key []byte) (bool, error)
+	Put(key []byte, value []byte) error
+	Delete(key []byte) error
+
+	// Write writes the batch atomically.
+	Write(batch *leveldb.Batch) error
+
+	NewIterator(r *util.Range) iterator.Iterator
+	NewSnapshot() (Snapshot, error)
+
+	Close() error
+}
+
+type EngineType string
+
+const (
+	EngineLevelDB EngineType = "leveldb"
+	EngineMemory  EngineType = "memory"
+	EngineBolt    EngineType = "bolt"
+)
+
+func (e EngineType) IsValid() bool {
+	switch e {
+	case EngineLevelDB, EngineMemory, EngineBolt:
+		return true
+	}
+
+	return false
+}
+
+/*
+NewDatabase returns the database with CurrentEngine.
+*/
+func NewDatabase(file string, dataDir string, cache int, handles int) (DB, error) {
+	return NewDatabaseWithEngine(CurrentEngine, file, dataDir, cache, handles)
+}
+
+func NewDatabaseWithEngine(engine EngineType, file string, dataDir string, cache int, handles int) (DB, error) {
+	var db DB
+	var err error
+
+	switch engine {
+	case EngineLevelDB:
+		db, err = NewLDBDatabase(file, dataDir, cache, handles)
+	case EngineMemory:
+		db = NewMemDatabaseWithPath(file, dataDir)
+	case EngineBolt:
+		db, err = NewBoltDatabase(file, dataDir)
+	default:
+		err = ErrInvalidEngine
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+/*
+GetEngineType gets the engine of the database at the path (based on the files of the engines).
+*/
+func GetEngineType(path string) (EngineType, error) {
+	_, err := os.Stat(filepath.Join(path, ldbCurrentFile))
+	if err == nil {
+		return EngineLevelDB, nil
+	}
+
+	_, err = os.Stat(filepath.Join(path, BoltFilename))
+	if err == nil {
+		return EngineBolt, nil
+	}
+
+	return "", ErrInvalidEngine
+}
+
+/*
+OpenDatabaseReadOnly opens the existing database at the path read-only (ex: for backup while the database is not opened by the node).
+*/
+func OpenDatabaseReadOnly(path string) (DB, error) {
+	engine, err := GetEngineType(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := filepath.Base(path)
+	dataDir := filepath.Dir(path)
+
+	var db DB
+
+	switch engine {
+	case EngineLevelDB:
+		db, err = newLDBDatabaseReadOnly(file, dataDir)
+	case EngineBolt:
+		db, err = newBoltDatabaseReadOnly(file, dataDir)
+	default:
+		err = ErrInvalidEngine
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
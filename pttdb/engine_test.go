@@ -0,0 +1,257 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package pttdb
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+var tEngines = []EngineType{EngineLevelDB, EngineMemory, EngineBolt}
+
+func newTestEngineDB(t *testing.T, engine EngineType) (DB, func()) {
+	db, err := NewDatabaseWithEngine(engine, "engine-"+string(engine), "./test.out", 0, 0)
+	if err != nil {
+		t.Fatalf("unable to create db: engine: %v e: %v", engine, err)
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(db.Path())
+	}
+}
+
+func TestEngine_TryPutAll(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	for _, engine := range tEngines {
+		db, remove := newTestEngineDB(t, engine)
+		defer remove()
+
+		dbBatch, _ := NewLDBBatch(db)
+
+		updateTS, _ := types.GetTimestamp()
+		origUpdateTS := updateTS
+		origUpdateTS.Ts--
+
+		idx := &Index{Keys: [][]byte{[]byte("test-key"), []byte("test-2nd-key")}, UpdateTS: updateTS}
+		kvs := []*KeyVal{
+			&KeyVal{K: []byte("test-key"), V: []byte("test-value")},
+			&KeyVal{K: []byte("test-2nd-key"), V: []byte("test-key")},
+		}
+
+		_, err := dbBatch.TryPutAll([]byte("test-idx-key"), idx, kvs, false, false)
+		if err != nil {
+			t.Errorf("TryPutAll: engine: %v e: %v", engine, err)
+		}
+
+		// not updated with the older ts.
+		origIdx := &Index{Keys: [][]byte{[]byte("test-key")}, UpdateTS: origUpdateTS}
+		origKvs := []*KeyVal{&KeyVal{K: []byte("test-key"), V: []byte("test-orig-value")}}
+		_, err = dbBatch.TryPutAll([]byte("test-idx-key"), origIdx, origKvs, false, false)
+		if err != ErrInvalidUpdateTS {
+			t.Errorf("TryPutAll: engine: %v e: %v want: %v", engine, err, ErrInvalidUpdateTS)
+		}
+
+		got, err := dbBatch.GetByIdxKey([]byte("test-idx-key"), 0)
+		if err != nil || !reflect.DeepEqual(got, []byte("test-value")) {
+			t.Errorf("GetByIdxKey: engine: %v got: %v e: %v", engine, got, err)
+		}
+
+		got, err = dbBatch.GetBy2ndIdxKey([]byte("test-2nd-key"))
+		if err != nil || !reflect.DeepEqual(got, []byte("test-value")) {
+			t.Errorf("GetBy2ndIdxKey: engine: %v got: %v e: %v", engine, got, err)
+		}
+
+		err = dbBatch.DeleteAll([]byte("test-idx-key"))
+		if err != nil {
+			t.Errorf("DeleteAll: engine: %v e: %v", engine, err)
+		}
+
+		_, err = dbBatch.GetBy2ndIdxKey([]byte("test-2nd-key"))
+		if err == nil {
+			t.Errorf("GetBy2ndIdxKey: engine: %v got deleted value", engine)
+		}
+	}
+}
+
+func TestEngine_NewIteratorWithPrefix(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	for _, engine := range tEngines {
+		db, remove := newTestEngineDB(t, engine)
+		defer remove()
+
+		db.Put([]byte("test-a"), []byte("a"))
+		db.Put([]byte("test-z"), []byte("z"))
+		for i := 0; i < 10; i++ {
+			db.Put([]byte("test-prefix-"+strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+		}
+
+		// next
+		iter, err := db.NewIteratorWithPrefix(nil, []byte("test-prefix-"), ListOrderNext)
+		if err != nil {
+			t.Errorf("NewIteratorWithPrefix: engine: %v e: %v", engine, err)
+			continue
+		}
+		i := 0
+		for iter.Next() {
+			if !bytes.Equal(iter.Value(), []byte(strconv.Itoa(i))) {
+				t.Errorf("Next: engine: %v got: %v want: %v", engine, iter.Value(), i)
+			}
+			i++
+		}
+		iter.Release()
+		if i != 10 {
+			t.Errorf("Next: engine: %v count: %v", engine, i)
+		}
+
+		// prev with start
+		iter, err = db.NewIteratorWithPrefix([]byte("test-prefix-5"), []byte("test-prefix-"), ListOrderPrev)
+		if err != nil {
+			t.Errorf("NewIteratorWithPrefix: engine: %v e: %v", engine, err)
+			continue
+		}
+		i = 5
+		for iter.Prev() {
+			if !bytes.Equal(iter.Value(), []byte(strconv.Itoa(i))) {
+				t.Errorf("Prev: engine: %v got: %v want: %v", engine, iter.Value(), i)
+			}
+			i--
+		}
+		iter.Release()
+		if i != -1 {
+			t.Errorf("Prev: engine: %v last: %v", engine, i)
+		}
+	}
+}
+
+func TestEngine_Snapshot(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	for _, engine := range tEngines {
+		db, remove := newTestEngineDB(t, engine)
+		defer remove()
+
+		db.Put([]byte("test-1"), []byte("1"))
+		db.Put([]byte("test-2"), []byte("2"))
+
+		snap, err := db.NewSnapshot()
+		if err != nil {
+			t.Errorf("NewSnapshot: engine: %v e: %v", engine, err)
+			continue
+		}
+
+		db.Put([]byte("test-3"), []byte("3"))
+
+		iter := snap.NewIterator(nil)
+		keys := make([][]byte, 0)
+		for iter.Next() {
+			keys = append(keys, append([]byte{}, iter.Key()...))
+		}
+		iter.Release()
+		snap.Release()
+
+		want := [][]byte{[]byte("test-1"), []byte("test-2")}
+		if !reflect.DeepEqual(keys, want) {
+			t.Errorf("Snapshot: engine: %v got: %v want: %v", engine, keys, want)
+		}
+	}
+}
+
+func TestEngine_BoltPagedIterator(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	origPageSize := BoltIteratorPageSize
+	BoltIteratorPageSize = 3
+	defer func() {
+		BoltIteratorPageSize = origPageSize
+	}()
+
+	db, remove := newTestEngineDB(t, EngineBolt)
+	defer remove()
+
+	for i := 0; i < 10; i++ {
+		db.Put([]byte("test-"+strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+	}
+
+	// next over the pages, writing while iterating.
+	iter, err := db.NewIteratorWithPrefix(nil, []byte("test-"), ListOrderNext)
+	if err != nil {
+		t.Errorf("NewIteratorWithPrefix: e: %v", err)
+		return
+	}
+	i := 0
+	for iter.Next() {
+		if !bytes.Equal(iter.Value(), []byte(strconv.Itoa(i))) {
+			t.Errorf("Next: got: %v want: %v", iter.Value(), i)
+		}
+		db.Delete(iter.Key())
+		i++
+	}
+	iter.Release()
+	if i != 10 {
+		t.Errorf("Next: count: %v", i)
+	}
+
+	// prev over the pages, and back with next.
+	for i := 0; i < 10; i++ {
+		db.Put([]byte("test-"+strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+	}
+
+	iter, err = db.NewIteratorWithPrefix(nil, []byte("test-"), ListOrderPrev)
+	if err != nil {
+		t.Errorf("NewIteratorWithPrefix: e: %v", err)
+		return
+	}
+	defer iter.Release()
+
+	i = 9
+	for iter.Prev() {
+		if !bytes.Equal(iter.Value(), []byte(strconv.Itoa(i))) {
+			t.Errorf("Prev: got: %v want: %v", iter.Value(), i)
+		}
+		i--
+	}
+	if i != -1 {
+		t.Errorf("Prev: last: %v", i)
+	}
+
+	i = 0
+	for iter.Next() {
+		if !bytes.Equal(iter.Value(), []byte(strconv.Itoa(i))) {
+			t.Errorf("Next after Prev: got: %v want: %v", iter.Value(), i)
+		}
+		i++
+	}
+	if i != 10 {
+		t.Errorf("Next after Prev: count: %v", i)
+	}
+}
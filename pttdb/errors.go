@@ -26,4 +26,9 @@ var (
 	ErrBusy            = errors.New("db busy")
 	ErrInvalidKeys     = errors.New("invalid db keys")
 	ErrInvalidIndex    = errors.New("invalid db index")
+	ErrInvalidEngine   = errors.New("invalid db engine")
+
+	ErrDBLocked              = errors.New("db locked (no passphrase)")
+	ErrInvalidDataKey        = errors.New("invalid db data-key")
+	ErrInvalidEncryptedValue = errors.New("invalid encrypted value")
 )
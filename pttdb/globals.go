@@ -40,6 +40,44 @@ var (
 	ValueTrue = []byte{1}
 )
 
+// engine
+var (
+	CurrentEngine = EngineLevelDB
+)
+
+const (
+	ldbCurrentFile = "CURRENT"
+
+	BoltFilename = "data.bolt"
+	boltBucket   = "ptt"
+
+	TimeoutOpenBolt = 5 * time.Second
+
+	// the bolt db-file is re-mapped only while growing over the size.
+	BoltInitialMmapSize = 256 * 1024 * 1024
+)
+
+var (
+	// the key-vals loaded with each read-tx while iterating bolt. var for the tests.
+	BoltIteratorPageSize = 1024
+)
+
+// encryption
+const (
+	DataKeyFilename = "DATAKEY"
+	DataKeyVersion  = 1
+
+	SizeDataKey     = 32
+	SizeDataKeySalt = 32
+)
+
+// scrypt parameters. N is var for the tests.
+var (
+	DataKeyScryptN = 1 << 18
+	DataKeyScryptR = 8
+	DataKeyScryptP = 1
+)
+
 const (
 	minCache   = 16
 	minHandles = 16
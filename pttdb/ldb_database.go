@@ -17,15 +17,14 @@
 package pttdb
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -36,10 +35,15 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
+/*
+LDBDatabase is DB with the leveldb engine.
+
+The values are transparently encrypted if the database is encrypted (see encryption.go).
+*/
 type LDBDatabase struct {
-	name string      // filename not including data-dir
-	fn   string      // filename for reporting
-	db   *leveldb.DB // LevelDB instance
+	*baseDatabase
+
+	db *leveldb.DB // LevelDB instance
 
 	compTimeMeter    metrics.Meter // Meter for measuring the total time spent in database compaction
 	compReadMeter    metrics.Meter // Meter for measuring the data read during compaction
@@ -51,11 +55,6 @@ type LDBDatabase struct {
 
 	quitLock sync.Mutex      // Mutex protecting the quit channel access
 	quitChan chan chan error // Quit channel to stop the metrics collection before closing the database
-
-	log log.Logger // Contextual logger tracking the database path
-
-	lockLockMap sync.Mutex
-	lockMap     map[string]int
 }
 
 // NewLDBDatabase returns a LevelDB wrapped object.
@@ -74,6 +73,9 @@ func NewLDBDatabase(file string, dataDir string, cache int, handles int) (*LDBDa
 
 	logger.Info("Allocated cache and file handles", "cache", cache, "handles", handles)
 
+	_, err := os.Stat(filepath.Join(fullFilename, ldbCurrentFile))
+	isNew := os.IsNotExist(err)
+
 	// Open the db and recover any potential corruptions
 	db, err := leveldb.OpenFile(fullFilename, &opt.Options{
 		OpenFilesCacheCapacity: handles,
@@ -89,263 +91,44 @@ func NewLDBDatabase(file string, dataDir string, cache int, handles int) (*LDBDa
 	if err != nil {
 		return nil, err
 	}
-	return &LDBDatabase{
-		name:    file,
-		fn:      fullFilename,
-		db:      db,
-		log:     logger,
-		lockMap: make(map[string]int),
-	}, nil
-}
-
-// Path returns the path to the database directory.
-func (db *LDBDatabase) Path() string {
-	return db.fn
-}
-
-func (db *LDBDatabase) Name() string {
-	return db.name
-}
-
-/*
-TryPut tries to put the key/val based on the updateTS of val.
-
-Value is the jsonified bytes. the obj of the bytes needs to include UpdateTS.
-*/
-func (db *LDBDatabase) TryPut(key []byte, value []byte, updateTS types.Timestamp) ([]byte, error) {
-	//log.Debug("TryPut: start", "key", key)
-
-	err := db.TryLockMap(key)
-	if err != nil {
-		return nil, err
-	}
-	defer db.UnlockMap(key)
-
-	isHasKey, err := db.Has(key)
-	if err != nil {
-		return nil, err
-	}
-
-	if !isHasKey { // new-one
-		err := db.Put(key, value)
-		return nil, err
-	}
-
-	v, err := db.Get(key)
-	if err != nil {
-		return nil, err
-	}
-
-	d := &DBable{}
-
-	err = json.Unmarshal(v, d)
-	if err != nil {
-		return nil, ErrInvalidDBable
-	}
-
-	if updateTS.IsLess(d.UpdateTS) {
-		log.Warn("updateTS < d.UpdateTS", "updateTS", updateTS, "d.UpdateTS", d.UpdateTS, "key", key)
-		return v, ErrInvalidUpdateTS
-	}
-
-	// put to db
 
-	err = db.Put(key, value)
+	engine, err := newEncryptedEngineIfNeeded(&ldbEngine{db: db}, fullFilename, isNew)
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
-	return v, nil
-}
-
-func (db *LDBDatabase) TryLockMap(key []byte) error {
-	mapKey := string(key)
-
-	db.lockLockMap.Lock()
-	defer db.lockLockMap.Unlock()
-
-	//log.Debug("to TryLockMap", "key", key)
-
-	// try to get lock
-	val, ok := db.lockMap[mapKey]
-	if ok { // someone-else is using the map-key
-		log.Error("TryLockMap: busy", "mapKey", mapKey, "val", val)
-		return ErrBusy
-	}
-	db.lockMap[mapKey] = -1
-
-	return nil
-}
-
-func (db *LDBDatabase) UnlockMap(key []byte) error {
-	mapKey := string(key)
-
-	db.lockLockMap.Lock()
-	defer db.lockLockMap.Unlock()
-
-	_, ok := db.lockMap[mapKey]
-	if !ok { // should not happen
-		return ErrInvalidLock
-	}
-
-	delete(db.lockMap, mapKey)
-
-	return nil
-}
-
-func (db *LDBDatabase) TryRLockMap(key []byte) error {
-	mapKey := string(key)
-
-	db.lockLockMap.Lock()
-	defer db.lockLockMap.Unlock()
-
-	// try to get lock
-	i, ok := db.lockMap[mapKey]
-	if ok && i < 0 { // write-lock
-		log.Error("TryRLockMap: busy", "i", i, "ok", ok)
-		return ErrBusy
-	}
-
-	log.Debug("after TryRLockMap (pass lock)", "i", i, "ok", ok)
-
-	if !ok {
-		db.lockMap[mapKey] = 0
-	}
-	db.lockMap[mapKey]++
-
-	return nil
-}
-
-func (db *LDBDatabase) RUnlockMap(key []byte) error {
-	mapKey := string(key)
-
-	db.lockLockMap.Lock()
-	defer db.lockLockMap.Unlock()
-
-	i, ok := db.lockMap[mapKey]
-	if !ok || i == 0 { // should not happen
-		panic("db invalid unlock")
-	}
-	db.lockMap[mapKey]--
-
-	if i == 1 {
-		delete(db.lockMap, mapKey)
-	}
-
-	return nil
-}
+	ldb := newLDBDatabaseWithDB(file, fullFilename, db, engine, logger)
 
-// Put puts the given key / value to the queue
-func (db *LDBDatabase) Put(key []byte, value []byte) error {
-	return db.db.Put(key, value, nil)
-}
+	registerOpenedDB(ldb)
 
-func (db *LDBDatabase) Has(key []byte) (bool, error) {
-	return db.db.Has(key, nil)
+	return ldb, nil
 }
 
-// Get returns the given key if it's present.
-func (db *LDBDatabase) Get(key []byte) ([]byte, error) {
-	dat, err := db.db.Get(key, nil)
-	if err != nil {
-		return nil, err
-	}
-	return dat, nil
-}
-
-// Delete deletes the key from the queue and database
-func (db *LDBDatabase) Delete(key []byte) error {
-	err := db.db.Delete(key, nil)
-	if err == leveldb.ErrNotFound {
-		err = nil
-	}
-
-	return err
-}
+func newLDBDatabaseReadOnly(file string, dataDir string) (*LDBDatabase, error) {
+	fullFilename := filepath.Join(dataDir, file)
 
-// Delete With Get
-func (db *LDBDatabase) Pop(key []byte) ([]byte, error) {
-	err := db.TryLockMap(key)
-	if err != nil {
-		return nil, err
-	}
-	defer db.UnlockMap(key)
+	logger := log.New("database", fullFilename)
 
-	val, err := db.Get(key)
+	db, err := leveldb.OpenFile(fullFilename, &opt.Options{ReadOnly: true})
 	if err != nil {
-		// Unable to get key. possibly no key in the db. no need to do delete
 		return nil, err
 	}
 
-	err = db.Delete(key)
+	engine, err := newEncryptedEngineIfNeeded(&ldbEngine{db: db}, fullFilename, false)
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
-	return val, nil
-}
-
-func (db *LDBDatabase) NewIterator(listOrder ListOrder) iterator.Iterator {
-	iter := db.db.NewIterator(nil, nil)
-	if listOrder == ListOrderPrev {
-		iter.Seek(dbLastKey)
-	}
-
-	return iter
-}
-
-func (db *LDBDatabase) NewIteratorWithRange(r *util.Range, listOrder ListOrder) iterator.Iterator {
-	iter := db.db.NewIterator(r, nil)
-	if listOrder == ListOrderPrev {
-		iter.Seek(r.Limit)
-	}
-
-	return iter
+	return newLDBDatabaseWithDB(file, fullFilename, db, engine, logger), nil
 }
 
-// NewIteratorWithPrefix returns a iterator to iterate over subset of database content with a particular prefix.
-func (db *LDBDatabase) NewIteratorWithPrefix(start []byte, prefix []byte, listOrder ListOrder) (iterator.Iterator, error) {
-	// both as nil
-	if len(start) == 0 && len(prefix) == 0 {
-		return db.NewIterator(listOrder), nil
-	}
-
-	// start as nil
-	if len(start) == 0 {
-		r := util.BytesPrefix(prefix)
-		return db.NewIteratorWithRange(r, listOrder), nil
-	}
-
-	// prefix as nil
-	if len(prefix) == 0 {
-		startRange := util.BytesPrefix(start)
-		var r *util.Range
-		switch listOrder {
-		case ListOrderPrev:
-			r = &util.Range{Limit: startRange.Limit}
-		case ListOrderNext:
-			r = &util.Range{Start: startRange.Start}
-		}
-
-		return db.NewIteratorWithRange(r, listOrder), nil
-	}
-
-	// both non-nil
-	if !strings.HasPrefix(string(start), string(prefix)) {
-		return nil, ErrInvalidPrefix
-	}
-
-	startRange := util.BytesPrefix(start)
-	prefixRange := util.BytesPrefix(prefix)
-	var r *util.Range
-	switch listOrder {
-	case ListOrderPrev:
-		r = &util.Range{Start: prefixRange.Start, Limit: startRange.Limit}
-	case ListOrderNext:
-		r = &util.Range{Start: startRange.Start, Limit: prefixRange.Limit}
+func newLDBDatabaseWithDB(file string, fullFilename string, db *leveldb.DB, engine Engine, logger log.Logger) *LDBDatabase {
+	return &LDBDatabase{
+		baseDatabase: newBaseDatabase(file, fullFilename, engine, logger),
+		db:           db,
 	}
-
-	return db.NewIteratorWithRange(r, listOrder), nil
 }
 
 func (db *LDBDatabase) Close() {
@@ -361,12 +144,8 @@ func (db *LDBDatabase) Close() {
 		}
 		db.quitChan = nil
 	}
-	err := db.db.Close()
-	if err == nil {
-		db.log.Info("Database closed")
-	} else {
-		db.log.Error("Failed to close database", "err", err)
-	}
+
+	db.baseDatabase.Close()
 }
 
 func (db *LDBDatabase) LDB() *leveldb.DB {
@@ -590,12 +369,72 @@ func (db *LDBDatabase) meter(refresh time.Duration) {
 	errc <- merr
 }
 
-func (db *LDBDatabase) NewBatch() Batch {
-	return &ldbBatch{db: db, b: new(leveldb.Batch)}
+/**********
+ * engine
+ **********/
+
+type ldbEngine struct {
+	db *leveldb.DB
+}
+
+func (e *ldbEngine) Get(key []byte) ([]byte, error) {
+	return e.db.Get(key, nil)
+}
+
+func (e *ldbEngine) Has(key []byte) (bool, error) {
+	return e.db.Has(key, nil)
 }
 
+func (e *ldbEngine) Put(key []byte, value []byte) error {
+	return e.db.Put(key, value, nil)
+}
+
+func (e *ldbEngine) Delete(key []byte) error {
+	return e.db.Delete(key, nil)
+}
+
+func (e *ldbEngine) Write(batch *leveldb.Batch) error {
+	return e.db.Write(batch, nil)
+}
+
+func (e *ldbEngine) NewIterator(r *util.Range) iterator.Iterator {
+	return e.db.NewIterator(r, nil)
+}
+
+func (e *ldbEngine) NewSnapshot() (Snapshot, error) {
+	snap, err := e.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ldbSnapshot{snap: snap}, nil
+}
+
+func (e *ldbEngine) Close() error {
+	return e.db.Close()
+}
+
+type ldbSnapshot struct {
+	snap *leveldb.Snapshot
+}
+
+func (s *ldbSnapshot) NewIterator(r *util.Range) iterator.Iterator {
+	return s.snap.NewIterator(r, nil)
+}
+
+func (s *ldbSnapshot) Release() {
+	s.snap.Release()
+}
+
+/**********
+ * batch
+ **********/
+
+/*
+ldbBatch is the batch in the format of leveldb.Batch, which is applicable to all the engines.
+*/
 type ldbBatch struct {
-	db   *LDBDatabase
+	db   DB
 	b    *leveldb.Batch
 	size int
 }
@@ -621,8 +460,7 @@ func (b *ldbBatch) Delete(key []byte) error {
 }
 
 func (b *ldbBatch) Write() error {
-	db := b.db.LDB()
-	return db.Write(b.b, nil)
+	return b.db.Write(b.b)
 }
 
 func (b *ldbBatch) ValueSize() int {
@@ -634,15 +472,18 @@ func (b *ldbBatch) Reset() {
 	b.size = 0
 }
 
-func (b *ldbBatch) DB() *LDBDatabase {
+func (b *ldbBatch) DB() DB {
 	return b.db
 }
 
+/*
+LDBBatch implements IndexBatch for all the engines.
+*/
 type LDBBatch struct {
 	*ldbBatch
 }
 
-func NewLDBBatch(db *LDBDatabase) (*LDBBatch, error) {
+func NewLDBBatch(db DB) (*LDBBatch, error) {
 	batch := &ldbBatch{
 		db: db,
 		b:  new(leveldb.Batch),
@@ -1,140 +1,196 @@
-// Copyright 2014 The go-ethereum Authors
-// This file is part of the go-ethereum library.
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
 //
-// The go-ethereum library is free software: you can redistribute it and/or modify
+// The go-pttai library is free software: you can redistribute it and/or modify
 // it under the terms of the GNU Lesser General Public License as published by
 // the Free Software Foundation, either version 3 of the License, or
 // (at your option) any later version.
 //
-// The go-ethereum library is distributed in the hope that it will be useful,
+// The go-pttai library is distributed in the hope that it will be useful,
 // but WITHOUT ANY WARRANTY; without even the implied warranty of
 // MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
 // GNU Lesser General Public License for more details.
 //
 // You should have received a copy of the GNU Lesser General Public License
-// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
-
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
 package pttdb
 
 import (
-	"errors"
+	"path/filepath"
 	"sync"
 
+	"github.com/ailabstw/go-pttai/log"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/comparer"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/memdb"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 /*
- * This is a test memory database. Do not use for any production it does not get persisted
- */
+MemDatabase is DB with the memory engine (based on goleveldb memdb).
+
+The data is not persisted, and the memory of the overwritten / deleted values is not reclaimed until closed.
+This is for the tests and the ephemeral nodes.
+*/
 type MemDatabase struct {
-	db   map[string][]byte
-	lock sync.RWMutex
+	*baseDatabase
+
+	mem *memEngine
 }
 
 func NewMemDatabase() *MemDatabase {
-	return &MemDatabase{
-		db: make(map[string][]byte),
-	}
+	return NewMemDatabaseWithCap(0)
 }
 
 func NewMemDatabaseWithCap(size int) *MemDatabase {
-	return &MemDatabase{
-		db: make(map[string][]byte, size),
-	}
+	return newMemDatabase("", "", size)
 }
 
-func (db *MemDatabase) Put(key []byte, value []byte) error {
-	db.lock.Lock()
-	defer db.lock.Unlock()
-
-	db.db[string(key)] = common.CopyBytes(value)
-	return nil
+func NewMemDatabaseWithPath(file string, dataDir string) *MemDatabase {
+	return newMemDatabase(file, filepath.Join(dataDir, file), 0)
 }
 
-func (db *MemDatabase) Has(key []byte) (bool, error) {
-	db.lock.RLock()
-	defer db.lock.RUnlock()
-
-	_, ok := db.db[string(key)]
-	return ok, nil
-}
+func newMemDatabase(file string, fullFilename string, size int) *MemDatabase {
+	logger := log.New("database", fullFilename)
 
-func (db *MemDatabase) Get(key []byte) ([]byte, error) {
-	db.lock.RLock()
-	defer db.lock.RUnlock()
+	mem := &memEngine{db: memdb.New(comparer.DefaultComparer, size)}
 
-	if entry, ok := db.db[string(key)]; ok {
-		return common.CopyBytes(entry), nil
+	return &MemDatabase{
+		baseDatabase: newBaseDatabase(file, fullFilename, mem, logger),
+		mem:          mem,
 	}
-	return nil, errors.New("not found")
 }
 
 func (db *MemDatabase) Keys() [][]byte {
-	db.lock.RLock()
-	defer db.lock.RUnlock()
+	iter := db.mem.NewIterator(nil)
+	defer iter.Release()
 
 	keys := [][]byte{}
-	for key := range db.db {
-		keys = append(keys, []byte(key))
+	for iter.Next() {
+		keys = append(keys, common.CopyBytes(iter.Key()))
 	}
 	return keys
 }
 
-func (db *MemDatabase) Delete(key []byte) error {
-	db.lock.Lock()
-	defer db.lock.Unlock()
+func (db *MemDatabase) Len() int {
+	return db.mem.db.Len()
+}
+
+/**********
+ * engine
+ **********/
 
-	delete(db.db, string(key))
-	return nil
+/*
+memEngine is the engine based on memdb.
+
+memdb is safe for concurrent use, the lock is to have the batch-write atomic to the readers.
+*/
+type memEngine struct {
+	lock sync.RWMutex
+
+	db *memdb.DB
 }
 
-func (db *MemDatabase) Close() {}
+func (e *memEngine) Get(key []byte) ([]byte, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	val, err := e.db.Get(key)
+	if err != nil {
+		return nil, leveldb.ErrNotFound
+	}
 
-func (db *MemDatabase) NewBatch() Batch {
-	return &memBatch{db: db}
+	return common.CopyBytes(val), nil
 }
 
-func (db *MemDatabase) Len() int { return len(db.db) }
+func (e *memEngine) Has(key []byte) (bool, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
 
-type kv struct{ k, v []byte }
+	return e.db.Contains(key), nil
+}
+
+func (e *memEngine) Put(key []byte, value []byte) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
 
-type memBatch struct {
-	db      *MemDatabase
-	writes  []kv
-	deletes [][]byte
-	size    int
+	return e.db.Put(key, value)
 }
 
-func (b *memBatch) Put(key, value []byte) error {
-	b.writes = append(b.writes, kv{common.CopyBytes(key), common.CopyBytes(value)})
-	b.size += len(value)
+func (e *memEngine) Delete(key []byte) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.db.Delete(key)
+
 	return nil
 }
 
-func (b *memBatch) Write() error {
-	b.db.lock.Lock()
-	defer b.db.lock.Unlock()
+func (e *memEngine) Write(batch *leveldb.Batch) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
 
-	for _, kv := range b.writes {
-		b.db.db[string(kv.k)] = kv.v
-	}
+	return batch.Replay(&memBatchReplay{db: e.db})
+}
 
-	for _, key := range b.deletes {
-		delete(b.db.db, string(key))
+func (e *memEngine) NewIterator(r *util.Range) iterator.Iterator {
+	return e.db.NewIterator(r)
+}
+
+/*
+NewSnapshot copies the data as the snapshot.
+*/
+func (e *memEngine) NewSnapshot() (Snapshot, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	snap := memdb.New(comparer.DefaultComparer, e.db.Size())
+
+	iter := e.db.NewIterator(nil)
+	defer iter.Release()
+
+	var err error
+	for iter.Next() {
+		err = snap.Put(iter.Key(), iter.Value())
+		if err != nil {
+			return nil, err
+		}
 	}
-	return nil
+
+	return &memSnapshot{db: snap}, nil
 }
 
-func (b *memBatch) Delete(key []byte) error {
-	b.deletes = append(b.deletes, key)
+func (e *memEngine) Close() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.db.Reset()
+
 	return nil
 }
 
-func (b *memBatch) ValueSize() int {
-	return b.size
+type memBatchReplay struct {
+	db *memdb.DB
+}
+
+func (r *memBatchReplay) Put(key, value []byte) {
+	r.db.Put(key, value)
+}
+
+func (r *memBatchReplay) Delete(key []byte) {
+	r.db.Delete(key)
+}
+
+type memSnapshot struct {
+	db *memdb.DB
+}
+
+func (s *memSnapshot) NewIterator(r *util.Range) iterator.Iterator {
+	return s.db.NewIterator(r)
 }
 
-func (b *memBatch) Reset() {
-	b.writes = b.writes[:0]
-	b.size = 0
+func (s *memSnapshot) Release() {
+	s.db.Reset()
 }
@@ -0,0 +1,70 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package pttdb
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+/*
+openedDBs keeps the databases opened by this process (by the absolute path),
+so that we can take consistent snapshots of the databases while the node is running (ex: backup).
+*/
+var (
+	openedDBs     = make(map[string]DB)
+	lockOpenedDBs sync.RWMutex
+)
+
+func registerOpenedDB(db DB) {
+	path, err := filepath.Abs(db.Path())
+	if err != nil {
+		return
+	}
+
+	lockOpenedDBs.Lock()
+	defer lockOpenedDBs.Unlock()
+
+	openedDBs[path] = db
+}
+
+func unregisterOpenedDB(path string) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+
+	lockOpenedDBs.Lock()
+	defer lockOpenedDBs.Unlock()
+
+	delete(openedDBs, path)
+}
+
+/*
+GetOpenedDB returns the database opened by this process with the path (nil if not opened).
+*/
+func GetOpenedDB(path string) DB {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil
+	}
+
+	lockOpenedDBs.RLock()
+	defer lockOpenedDBs.RUnlock()
+
+	return openedDBs[path]
+}
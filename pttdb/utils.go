@@ -22,7 +22,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 )
 
-func GetIterByID(db *LDBDatabase, prefix []byte, idxPrefix []byte, startID *types.PttID, listOrder ListOrder) (iterator.Iterator, error) {
+func GetIterByID(db DB, prefix []byte, idxPrefix []byte, startID *types.PttID, listOrder ListOrder) (iterator.Iterator, error) {
 	if startID == nil {
 		return db.NewIteratorWithPrefix(nil, prefix, listOrder)
 	}
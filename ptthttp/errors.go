@@ -16,4 +16,8 @@
 
 package ptthttp
 
-var ()
+import "errors"
+
+var (
+	ErrInvalidContentRange = errors.New("invalid content range")
+)
@@ -0,0 +1,96 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package ptthttp
+
+import (
+	"errors"
+	"io"
+
+	"github.com/ailabstw/go-pttai/content"
+	"github.com/ailabstw/go-pttai/rpc"
+)
+
+/*
+fileReader reads the file through content_getFileChunk,
+so that http.ServeContent can serve the range-requests without loading the whole file.
+*/
+type fileReader struct {
+	rpcClient *rpc.Client
+
+	boardIDStr string
+	mediaIDStr string
+
+	size   int64
+	offset int64
+
+	bufOffset int64
+	buf       []byte
+}
+
+func newFileReader(rpcClient *rpc.Client, boardIDStr string, mediaIDStr string, size int64) *fileReader {
+	return &fileReader{
+		rpcClient:  rpcClient,
+		boardIDStr: boardIDStr,
+		mediaIDStr: mediaIDStr,
+		size:       size,
+	}
+}
+
+func (f *fileReader) Read(p []byte) (int, error) {
+	if f.offset >= f.size {
+		return 0, io.EOF
+	}
+
+	if f.offset < f.bufOffset || f.offset >= f.bufOffset+int64(len(f.buf)) {
+		chunk := &content.BackendFileChunk{}
+		err := f.rpcClient.Call(chunk, "content_getFileChunk", f.boardIDStr, f.mediaIDStr, f.offset, ReadFileChunkSize)
+		if err != nil {
+			return 0, err
+		}
+		if len(chunk.Buf) == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+
+		f.bufOffset = chunk.Offset
+		f.buf = chunk.Buf
+	}
+
+	n := copy(p, f.buf[f.offset-f.bufOffset:])
+	f.offset += int64(n)
+
+	return n, nil
+}
+
+func (f *fileReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.size
+	default:
+		return 0, errors.New("fileReader.Seek: invalid whence")
+	}
+
+	if offset < 0 {
+		return 0, errors.New("fileReader.Seek: negative position")
+	}
+
+	f.offset = offset
+
+	return offset, nil
+}
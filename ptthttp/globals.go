@@ -20,6 +20,8 @@ import "regexp"
 
 const (
 	MaxUploadSize = 10000000 // 10MB
+
+	ReadFileChunkSize = 1048576 // 1MB
 )
 
 // re
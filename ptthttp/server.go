@@ -25,7 +25,9 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ailabstw/go-pttai/content"
 	"github.com/ailabstw/go-pttai/log"
@@ -41,6 +43,9 @@ type Server struct {
 	rpcServer *rpc.Server
 	rpcClient *rpc.Client
 	srv       *http.Server
+
+	origins []string        // allowed origins with the credentials
+	tokens  *rpc.TokenStore // requires the auth-tokens if set
 }
 
 type MyDir http.Dir
@@ -93,6 +98,9 @@ func NewServer(dir string, addr string, rpcPort int, extAddr string, extRPCAddrS
 		rpcServer: rpcServer,
 		srv:       srv,
 		rpcClient: client,
+
+		origins: node.Config.HTTPCors,
+		tokens:  node.AuthTokens(),
 	}
 
 	fs := http.FileServer(MyDir(s.dir))
@@ -105,6 +113,22 @@ func NewServer(dir string, addr string, rpcPort int, extAddr string, extRPCAddrS
 		Methods("POST")
 	r.HandleFunc("/api/uploadfile/{boardID}", s.optionHandler).
 		Methods("OPTIONS")
+	r.HandleFunc("/api/uploadfile/{boardID}/session", s.createUploadHandler).
+		Methods("POST")
+	r.HandleFunc("/api/uploadfile/{boardID}/session", s.optionHandler).
+		Methods("OPTIONS")
+	r.HandleFunc("/api/uploadfile/{boardID}/session/{uploadID}", s.getUploadHandler).
+		Methods("GET")
+	r.HandleFunc("/api/uploadfile/{boardID}/session/{uploadID}", s.uploadChunkHandler).
+		Methods("PUT")
+	r.HandleFunc("/api/uploadfile/{boardID}/session/{uploadID}", s.cancelUploadHandler).
+		Methods("DELETE")
+	r.HandleFunc("/api/uploadfile/{boardID}/session/{uploadID}", s.optionHandler).
+		Methods("OPTIONS")
+	r.HandleFunc("/api/uploadfile/{boardID}/session/{uploadID}/commit", s.commitUploadHandler).
+		Methods("POST")
+	r.HandleFunc("/api/uploadfile/{boardID}/session/{uploadID}/commit", s.optionHandler).
+		Methods("OPTIONS")
 	r.HandleFunc("/api/img/{boardID}/{imgID}", s.imgHandler).
 		Methods("GET")
 	r.HandleFunc("/api/img/{boardID}/{imgID}", s.optionHandler).
@@ -143,6 +167,7 @@ func (s *Server) SetRPCServer(n *node.Node) error {
 
 	s.rpcServer = rpcServer
 	s.rpcClient = client
+	s.tokens = n.AuthTokens()
 
 	return nil
 }
@@ -151,14 +176,69 @@ func (s *Server) optionHandler(w http.ResponseWriter, r *http.Request) {
 	origin := r.Header.Get("Origin")
 	log.Debug("optionHandler: start", "origin", origin, "method", r.Method)
 
+	s.setAccessControl(w, r)
+}
+
+func (s *Server) setAccessControl(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Accept", "*")
+	s.setAllowOrigin(w, r)
+	w.Header().Set("Access-Control-Allow-Methods", "OPTIONS,GET,POST,PUT,DELETE")
+	w.Header().Set("Access-Control-Allow-Headers", "X-CSRFToken,Content-Range,Authorization")
+}
+
+/*
+setAllowOrigin echoes back the origin with the credentials only if the origin is allowed (HTTPCors).
+*/
+func (s *Server) setAllowOrigin(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if !rpc.IsAllowedOrigin(s.origins, origin) {
+		return
+	}
+
 	w.Header().Set("Access-Control-Allow-Origin", origin)
 	w.Header().Set("Access-Control-Allow-Credentials", "true")
-	w.Header().Set("Access-Control-Allow-Methods", "OPTIONS,GET,POST")
-	w.Header().Set("Access-Control-Allow-Headers", "X-CSRFToken")
+}
+
+/*
+authorize checks the auth-token (if required) of the request with the scope of the rpc-method called by the handler.
+
+If the auth-tokens are not required,
+the requests from the origins not in the allowed-origins are allowed to call only the read-only methods.
+*/
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, rpcMethod string) bool {
+	module, method := splitRPCMethod(rpcMethod)
+
+	if s.tokens == nil {
+		origin := r.Header.Get("Origin")
+		if origin != "" && !rpc.IsAllowedOrigin(s.origins, origin) && !rpc.IsReadMethod(module, method) {
+			s.renderError(w, "FORBIDDEN", http.StatusForbidden)
+			return false
+		}
+		return true
+	}
+
+	token, err := s.tokens.AuthenticateRequest(r)
+	if err != nil {
+		s.setAllowOrigin(w, r)
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		s.renderError(w, "UNAUTHORIZED", http.StatusUnauthorized)
+		return false
+	}
+
+	if !token.IsAllowed(module, method) {
+		s.setAllowOrigin(w, r)
+		s.renderError(w, "FORBIDDEN", http.StatusForbidden)
+		return false
+	}
+
+	return true
 }
 
 func (s *Server) uploadHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r, "content_uploadImage") {
+		return
+	}
+
 	vars := mux.Vars(r)
 	boardIDStr := vars["boardID"]
 
@@ -169,7 +249,8 @@ func (s *Server) uploadHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if filetype != "image/jpeg" && filetype != "image/jpg" &&
-		filetype != "image/gif" && filetype != "image/png" {
+		filetype != "image/gif" && filetype != "image/png" &&
+		filetype != "image/webp" {
 		s.renderError(w, "INVALID_FILE_TYPE", http.StatusBadRequest)
 		return
 	}
@@ -195,6 +276,10 @@ func (s *Server) uploadHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) uploadFileHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r, "content_uploadFile") {
+		return
+	}
+
 	vars := mux.Vars(r)
 	boardIDStr := vars["boardID"]
 	filename := r.FormValue("filename")
@@ -225,13 +310,10 @@ func (s *Server) uploadFileHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) uploadPreprocess(w http.ResponseWriter, r *http.Request) ([]byte, string, error) {
-	origin := r.Header.Get("Origin")
-
 	w.Header().Set("Accept", "*")
-	w.Header().Set("Access-Control-Allow-Origin", origin)
-	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	s.setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "OPTIONS,GET,POST")
-	w.Header().Set("Access-Control-Allow-Headers", "X-CSRFToken")
+	w.Header().Set("Access-Control-Allow-Headers", "X-CSRFToken,Authorization")
 	w.Header().Set("Content-Type", "application/json")
 
 	r.Body = http.MaxBytesReader(w, r.Body, MaxUploadSize)
@@ -256,13 +338,14 @@ func (s *Server) uploadPreprocess(w http.ResponseWriter, r *http.Request) ([]byt
 }
 
 func (s *Server) imgHandler(w http.ResponseWriter, r *http.Request) {
-	origin := r.Header.Get("Origin")
+	if !s.authorize(w, r, "content_getImage") {
+		return
+	}
 
 	w.Header().Set("Accept", "*")
-	w.Header().Set("Access-Control-Allow-Origin", origin)
-	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	s.setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "OPTIONS,GET,POST")
-	w.Header().Set("Access-Control-Allow-Headers", "X-CSRFToken")
+	w.Header().Set("Access-Control-Allow-Headers", "X-CSRFToken,Authorization")
 
 	vars := mux.Vars(r)
 	boardIDStr := vars["boardID"]
@@ -270,8 +353,20 @@ func (s *Server) imgHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Debug("imgHandler: to backend", "boardIDStr", boardIDStr, "imgIDStr", imgIDStr)
 
+	// ?size={size}: getting the thumbnail with the max-width / max-height at least size.
+	var err error
 	backendGetImg := &content.BackendGetImg{}
-	err := s.rpcClient.Call(backendGetImg, "content_getImage", boardIDStr, imgIDStr)
+	sizeStr := r.URL.Query().Get("size")
+	if sizeStr == "" {
+		err = s.rpcClient.Call(backendGetImg, "content_getImage", boardIDStr, imgIDStr)
+	} else {
+		size, sizeErr := strconv.Atoi(sizeStr)
+		if sizeErr != nil {
+			s.renderError(w, "INVALID_SIZE", http.StatusBadRequest)
+			return
+		}
+		err = s.rpcClient.Call(backendGetImg, "content_getImageThumbnail", boardIDStr, imgIDStr, size)
+	}
 	if err != nil {
 		s.renderError(w, "UNABLE_TO_MARSHAL", http.StatusBadRequest)
 		return
@@ -289,13 +384,14 @@ func (s *Server) imgHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) fileHandler(w http.ResponseWriter, r *http.Request) {
-	origin := r.Header.Get("Origin")
+	if !s.authorize(w, r, "content_getFileInfo") {
+		return
+	}
 
 	w.Header().Set("Accept", "*")
-	w.Header().Set("Access-Control-Allow-Origin", origin)
-	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	s.setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "OPTIONS,GET,POST")
-	w.Header().Set("Access-Control-Allow-Headers", "X-CSRFToken")
+	w.Header().Set("Access-Control-Allow-Headers", "X-CSRFToken,Authorization")
 
 	vars := mux.Vars(r)
 	boardIDStr := vars["boardID"]
@@ -303,25 +399,170 @@ func (s *Server) fileHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Debug("attachHandler: to backend", "boardIDStr", boardIDStr, "mediaIDStr", mediaIDStr)
 
-	backendGetFile := &content.BackendGetFile{}
-	err := s.rpcClient.Call(backendGetFile, "content_getFile", boardIDStr, mediaIDStr)
+	backendFileInfo := &content.BackendFileInfo{}
+	err := s.rpcClient.Call(backendFileInfo, "content_getFileInfo", boardIDStr, mediaIDStr)
 	if err != nil {
 		s.renderError(w, "UNABLE_TO_MARSHAL", http.StatusBadRequest)
 		return
 	}
 
+	// ServeContent handles the range-requests, reading only the requested chunks.
 	w.Header().Set("Content-Type", "application/octet-stream")
-	w.Write(backendGetFile.Buf)
+	reader := newFileReader(s.rpcClient, boardIDStr, mediaIDStr, backendFileInfo.Size)
+	http.ServeContent(w, r, "", time.Time{}, reader)
+}
+
+func (s *Server) createUploadHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r, "content_createUpload") {
+		return
+	}
+
+	s.setAccessControl(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	boardIDStr := vars["boardID"]
+
+	backendUpload := &content.BackendUpload{}
+	err := s.rpcClient.Call(backendUpload, "content_createUpload", boardIDStr)
+	if err != nil {
+		s.renderError(w, fmt.Sprintf(`{"success": false, "errorMsg": "%v"}`, err), http.StatusBadRequest)
+		return
+	}
+
+	s.renderResult(w, backendUpload, http.StatusOK)
+}
+
+func (s *Server) getUploadHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r, "content_getUpload") {
+		return
+	}
+
+	s.setAccessControl(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	boardIDStr := vars["boardID"]
+	uploadIDStr := vars["uploadID"]
+
+	backendUpload := &content.BackendUpload{}
+	err := s.rpcClient.Call(backendUpload, "content_getUpload", boardIDStr, uploadIDStr)
+	if err != nil {
+		s.renderError(w, fmt.Sprintf(`{"success": false, "errorMsg": "%v"}`, err), http.StatusNotFound)
+		return
+	}
+
+	s.renderResult(w, backendUpload, http.StatusOK)
+}
+
+/*
+uploadChunkHandler appends the request-body to the upload.
+
+The Content-Range header ("bytes {start}-{end}/{total}") is required,
+and start needs to be the current offset of the upload.
+Returns 409 with the current offset if start is not the current offset,
+so that the client can resume from there.
+*/
+func (s *Server) uploadChunkHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r, "content_uploadChunk") {
+		return
+	}
+
+	s.setAccessControl(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	boardIDStr := vars["boardID"]
+	uploadIDStr := vars["uploadID"]
+
+	start, end, err := parseContentRange(r.Header.Get("Content-Range"))
+	if err != nil {
+		s.renderError(w, "INVALID_CONTENT_RANGE", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, content.MaxFileChunkSize)
+	chunk, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.renderError(w, "INVALID_FILE", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if int64(len(chunk)) != end-start+1 {
+		s.renderError(w, "INVALID_CONTENT_RANGE", http.StatusBadRequest)
+		return
+	}
+
+	backendUpload := &content.BackendUpload{}
+	err = s.rpcClient.Call(backendUpload, "content_uploadChunk", boardIDStr, uploadIDStr, start, chunk)
+	if err != nil && err.Error() == content.ErrInvalidUploadOffset.Error() {
+		err = s.rpcClient.Call(backendUpload, "content_getUpload", boardIDStr, uploadIDStr)
+		if err == nil {
+			s.renderResult(w, backendUpload, http.StatusConflict)
+			return
+		}
+	}
+	if err != nil {
+		s.renderError(w, fmt.Sprintf(`{"success": false, "errorMsg": "%v"}`, err), http.StatusBadRequest)
+		return
+	}
+
+	s.renderResult(w, backendUpload, http.StatusOK)
+}
+
+func (s *Server) commitUploadHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r, "content_commitUpload") {
+		return
+	}
+
+	s.setAccessControl(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	boardIDStr := vars["boardID"]
+	uploadIDStr := vars["uploadID"]
+	filename := r.FormValue("filename")
+
+	backendUploadFile := &content.BackendUploadFile{}
+	err := s.rpcClient.Call(backendUploadFile, "content_commitUpload", boardIDStr, uploadIDStr, filename)
+	if err != nil {
+		s.renderError(w, fmt.Sprintf(`{"success": false, "errorMsg": "%v"}`, err), http.StatusBadRequest)
+		return
+	}
+
+	s.renderResult(w, backendUploadFile, http.StatusOK)
+}
+
+func (s *Server) cancelUploadHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r, "content_cancelUpload") {
+		return
+	}
+
+	s.setAccessControl(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	boardIDStr := vars["boardID"]
+	uploadIDStr := vars["uploadID"]
+
+	var isOk bool
+	err := s.rpcClient.Call(&isOk, "content_cancelUpload", boardIDStr, uploadIDStr)
+	if err != nil {
+		s.renderError(w, fmt.Sprintf(`{"success": false, "errorMsg": "%v"}`, err), http.StatusBadRequest)
+		return
+	}
+
+	s.renderResult(w, isOk, http.StatusOK)
 }
 
 func (s *Server) origImgHandler(w http.ResponseWriter, r *http.Request) {
-	origin := r.Header.Get("Origin")
+	if !s.authorize(w, r, "content_getOrigImage") {
+		return
+	}
 
 	w.Header().Set("Accept", "*")
-	w.Header().Set("Access-Control-Allow-Origin", origin)
-	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	s.setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "OPTIONS,GET,POST")
-	w.Header().Set("Access-Control-Allow-Headers", "X-CSRFToken")
+	w.Header().Set("Access-Control-Allow-Headers", "X-CSRFToken,Authorization")
 
 	vars := mux.Vars(r)
 	boardIDStr := vars["boardID"]
@@ -372,6 +613,20 @@ func (s *Server) jsHandler(w http.ResponseWriter, r *http.Request, dir string) {
 }
 
 func (s *Server) renderError(w http.ResponseWriter, message string, statusCode int) {
-	w.WriteHeader(http.StatusBadRequest)
+	w.WriteHeader(statusCode)
 	w.Write([]byte(message))
 }
+
+func (s *Server) renderResult(w http.ResponseWriter, theResult interface{}, statusCode int) {
+	result := struct {
+		Result interface{} `json:"result"`
+	}{Result: theResult}
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		s.renderError(w, "UNABLE_TO_MARSHAL", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(statusCode)
+	w.Write(resultBytes)
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package ptthttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServer_authorize(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	s := &Server{origins: []string{"localhost"}}
+
+	// prepare test-cases
+	tests := []struct {
+		name      string
+		origin    string
+		rpcMethod string
+		want      bool
+		wantCode  int
+	}{
+		{name: "no origin", origin: "", rpcMethod: "content_uploadImage", want: true, wantCode: http.StatusOK},
+		{name: "allowed origin", origin: "http://localhost:9774", rpcMethod: "content_uploadImage", want: true, wantCode: http.StatusOK},
+		{name: "read-only from other origin", origin: "http://evil.com", rpcMethod: "content_getImage", want: true, wantCode: http.StatusOK},
+		{name: "upload from other origin", origin: "http://evil.com", rpcMethod: "content_uploadImage", want: false, wantCode: http.StatusForbidden},
+		{name: "commit-upload from other origin", origin: "http://evil.com", rpcMethod: "content_commitUpload", want: false, wantCode: http.StatusForbidden},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "http://localhost:9774/api/upload", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+
+			if got := s.authorize(w, r, tt.rpcMethod); got != tt.want {
+				t.Errorf("Server.authorize() = %v, want %v", got, tt.want)
+			}
+			if w.Code != tt.wantCode {
+				t.Errorf("Server.authorize() code = %v, want %v", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
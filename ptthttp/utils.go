@@ -0,0 +1,76 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package ptthttp
+
+import (
+	"strconv"
+	"strings"
+)
+
+/*
+parseContentRange parses the Content-Range header of the chunk ("bytes {start}-{end}/{total}" or "bytes {start}-{end}/*").
+
+Returns the start and the end (inclusive) of the chunk.
+*/
+func parseContentRange(contentRange string) (int64, int64, error) {
+	if !strings.HasPrefix(contentRange, "bytes ") {
+		return 0, 0, ErrInvalidContentRange
+	}
+	contentRange = strings.TrimPrefix(contentRange, "bytes ")
+
+	idx := strings.Index(contentRange, "/")
+	if idx < 0 {
+		return 0, 0, ErrInvalidContentRange
+	}
+	theRange, total := contentRange[:idx], contentRange[idx+1:]
+
+	idx = strings.Index(theRange, "-")
+	if idx < 0 {
+		return 0, 0, ErrInvalidContentRange
+	}
+
+	start, err := strconv.ParseInt(theRange[:idx], 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, ErrInvalidContentRange
+	}
+
+	end, err := strconv.ParseInt(theRange[idx+1:], 10, 64)
+	if err != nil || end < start {
+		return 0, 0, ErrInvalidContentRange
+	}
+
+	if total != "*" {
+		totalSize, err := strconv.ParseInt(total, 10, 64)
+		if err != nil || end >= totalSize {
+			return 0, 0, ErrInvalidContentRange
+		}
+	}
+
+	return start, end, nil
+}
+
+/*
+splitRPCMethod splits the rpc-method (ex: content_uploadImage) to the module and the method.
+*/
+func splitRPCMethod(rpcMethod string) (string, string) {
+	idx := strings.Index(rpcMethod, "_")
+	if idx < 0 {
+		return rpcMethod, ""
+	}
+
+	return rpcMethod[:idx], rpcMethod[idx+1:]
+}
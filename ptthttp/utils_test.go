@@ -0,0 +1,96 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package ptthttp
+
+import "testing"
+
+func Test_parseContentRange(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	// define test-structure
+	type args struct {
+		contentRange string
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name      string
+		args      args
+		wantStart int64
+		wantEnd   int64
+		wantErr   bool
+	}{
+		{args: args{contentRange: "bytes 0-99/1000"}, wantStart: 0, wantEnd: 99},
+		{args: args{contentRange: "bytes 100-199/*"}, wantStart: 100, wantEnd: 199},
+		{args: args{contentRange: "bytes 0-1000/1000"}, wantErr: true},
+		{args: args{contentRange: "bytes 99-0/1000"}, wantErr: true},
+		{args: args{contentRange: "0-99/1000"}, wantErr: true},
+		{args: args{contentRange: ""}, wantErr: true},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotEnd, err := parseContentRange(tt.args.contentRange)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseContentRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotStart != tt.wantStart || gotEnd != tt.wantEnd {
+				t.Errorf("parseContentRange() = (%v, %v), want (%v, %v)", gotStart, gotEnd, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+
+	// teardown test
+}
+
+func Test_splitRPCMethod(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	// define test-structure
+	type args struct {
+		rpcMethod string
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name       string
+		args       args
+		wantModule string
+		wantMethod string
+	}{
+		{args: args{rpcMethod: "content_uploadImage"}, wantModule: "content", wantMethod: "uploadImage"},
+		{args: args{rpcMethod: "content"}, wantModule: "content", wantMethod: ""},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotModule, gotMethod := splitRPCMethod(tt.args.rpcMethod)
+			if gotModule != tt.wantModule || gotMethod != tt.wantMethod {
+				t.Errorf("splitRPCMethod() = (%v, %v), want (%v, %v)", gotModule, gotMethod, tt.wantModule, tt.wantMethod)
+			}
+		})
+	}
+
+	// teardown test
+}
@@ -0,0 +1,334 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ScopeAll grants the access to all the modules.
+	ScopeAll = "*"
+
+	// ScopeReadSuffix restricts the scope of the module to the read-only methods (ex: content:read).
+	ScopeReadSuffix = ":read"
+
+	authTokenSecretLength = 32
+	authTokenIDLength     = 8
+
+	authHeaderPrefix = "Bearer "
+	authQueryKey     = "access_token"
+)
+
+var (
+	ErrInvalidAuthToken = errors.New("invalid auth token")
+	ErrInvalidScope     = errors.New("invalid scope")
+)
+
+type authTokenKey struct{}
+
+type untrustedOriginKey struct{}
+
+/*
+AuthToken is the bearer-token to access the HTTP / websocket RPC and the http-server.
+
+Only the sha256 hash of the secret is stored. The secret is shown once when the token is created.
+*/
+type AuthToken struct {
+	ID       string    `json:"ID"`
+	Name     string    `json:"N"`
+	Hash     string    `json:"H,omitempty"`
+	Scopes   []string  `json:"S"`
+	CreateTS time.Time `json:"CT"`
+}
+
+/*
+IsAllowed checks whether the token is allowed to call the method (in the formatted-name) of the module.
+
+Scopes:
+
+	*: all the modules.
+	{module}: all the methods in the module.
+	{module}:read: the read-only methods (readMethods) in the module.
+*/
+func (t *AuthToken) IsAllowed(module string, method string) bool {
+	for _, scope := range t.Scopes {
+		switch scope {
+		case ScopeAll, module:
+			return true
+		case module + ScopeReadSuffix:
+			if IsReadMethod(module, method) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hashAuthTokenSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func validateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		module := strings.TrimSuffix(scope, ScopeReadSuffix)
+		if module == "" || strings.ContainsAny(module, ":, ") {
+			return ErrInvalidScope
+		}
+		if module == ScopeAll && scope != ScopeAll {
+			return ErrInvalidScope
+		}
+	}
+	return nil
+}
+
+/*
+TokenStore keeps the auth-tokens in the json-file in the data-dir.
+*/
+type TokenStore struct {
+	lock   sync.RWMutex
+	path   string
+	tokens []*AuthToken
+}
+
+// NewTokenStore loads the token-store from path. The file is created with the first token.
+func NewTokenStore(path string) (*TokenStore, error) {
+	s := &TokenStore{path: path}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &s.tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *TokenStore) save() error {
+	data, err := json.MarshalIndent(s.tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path), 0700)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".tmp"
+	err = ioutil.WriteFile(tmpPath, data, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, s.path)
+}
+
+/*
+Create creates a new token with the scopes, and returns the secret with the token.
+*/
+func (s *TokenStore) Create(name string, scopes []string) (string, *AuthToken, error) {
+	err := validateScopes(scopes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	id, err := randomHex(authTokenIDLength)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomHex(authTokenSecretLength)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := &AuthToken{
+		ID:       id,
+		Name:     name,
+		Hash:     hashAuthTokenSecret(secret),
+		Scopes:   scopes,
+		CreateTS: time.Now().UTC(),
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.tokens = append(s.tokens, token)
+	err = s.save()
+	if err != nil {
+		s.tokens = s.tokens[:len(s.tokens)-1]
+		return "", nil, err
+	}
+
+	return secret, token.withoutHash(), nil
+}
+
+// List lists the tokens (without the hashes).
+func (s *TokenStore) List() []*AuthToken {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	tokens := make([]*AuthToken, len(s.tokens))
+	for i, token := range s.tokens {
+		tokens[i] = token.withoutHash()
+	}
+	return tokens
+}
+
+// Revoke removes the token with the id.
+func (s *TokenStore) Revoke(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for i, token := range s.tokens {
+		if token.ID != id {
+			continue
+		}
+
+		origTokens := s.tokens
+		s.tokens = append(append(make([]*AuthToken, 0, len(origTokens)-1), origTokens[:i]...), origTokens[i+1:]...)
+		err := s.save()
+		if err != nil {
+			s.tokens = origTokens
+		}
+		return err
+	}
+
+	return ErrInvalidAuthToken
+}
+
+// Authenticate returns the token matching the secret.
+func (s *TokenStore) Authenticate(secret string) (*AuthToken, error) {
+	if secret == "" {
+		return nil, ErrInvalidAuthToken
+	}
+
+	hash := []byte(hashAuthTokenSecret(secret))
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, token := range s.tokens {
+		if subtle.ConstantTimeCompare(hash, []byte(token.Hash)) == 1 {
+			return token, nil
+		}
+	}
+
+	return nil, ErrInvalidAuthToken
+}
+
+/*
+AuthenticateRequest authenticates the request with the bearer-token in the Authorization header,
+or in the access_token query (browsers are unable to set the headers for websocket / img).
+*/
+func (s *TokenStore) AuthenticateRequest(r *http.Request) (*AuthToken, error) {
+	return s.Authenticate(authTokenSecretFromRequest(r))
+}
+
+func (t *AuthToken) withoutHash() *AuthToken {
+	newToken := *t
+	newToken.Hash = ""
+	return &newToken
+}
+
+func authTokenSecretFromRequest(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, authHeaderPrefix) {
+		return strings.TrimSpace(strings.TrimPrefix(header, authHeaderPrefix))
+	}
+
+	return r.URL.Query().Get(authQueryKey)
+}
+
+/*
+IsAllowedOrigin checks whether the origin is in the allowed-origins.
+The allowed-origin can be the full origin (http://localhost:9774), the hostname (localhost), or *.
+*/
+func IsAllowedOrigin(allowedOrigins []string, origin string) bool {
+	if origin == "" {
+		return false
+	}
+	origin = strings.ToLower(origin)
+
+	hostname := ""
+	if u, err := url.Parse(origin); err == nil {
+		hostname = u.Hostname()
+	}
+
+	for _, allowedOrigin := range allowedOrigins {
+		allowedOrigin = strings.ToLower(allowedOrigin)
+		if allowedOrigin == "*" || allowedOrigin == origin || (hostname != "" && allowedOrigin == hostname) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewAuthContext returns the context with the authenticated token.
+func NewAuthContext(ctx context.Context, token *AuthToken) context.Context {
+	return context.WithValue(ctx, authTokenKey{}, token)
+}
+
+// newUntrustedOriginContext returns the context with the origin not in the allowed-origins.
+func newUntrustedOriginContext(ctx context.Context, origin string) context.Context {
+	return context.WithValue(ctx, untrustedOriginKey{}, origin)
+}
+
+// untrustedOriginFromContext returns the origin not in the allowed-origins in the context.
+func untrustedOriginFromContext(ctx context.Context) (string, bool) {
+	origin, ok := ctx.Value(untrustedOriginKey{}).(string)
+	return origin, ok
+}
+
+// AuthTokenFromContext returns the authenticated token in the context.
+func AuthTokenFromContext(ctx context.Context) (*AuthToken, bool) {
+	token, ok := ctx.Value(authTokenKey{}).(*AuthToken)
+	return token, ok
+}
@@ -0,0 +1,242 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+/*
+readMethods are the read-only methods (in the formatted-name) of the modules allowed by the read-only scopes ({module}:read),
+and allowed from the origins not in the allowed-origins if the auth-tokens are not required.
+
+The methods revealing the keys / join-urls / invites / join-hashes are not included.
+The new read-only methods need to be added explicitly.
+*/
+var readMethods = map[string][]string{
+	"admin": {
+		"datadir",
+		"nodeInfo",
+		"peers",
+	},
+	"debug": {
+		"metrics",
+	},
+	"ptt": {
+		"countEntities",
+		"countPeers",
+		"getGitCommit",
+		"getPeers",
+		"getPttOplogList",
+		"getVersion",
+	},
+	"me": {
+		"countPeers",
+		"get",
+		"getBlockList",
+		"getBoard",
+		"getBoardRequests",
+		"getChatRequests",
+		"getFriendRequests",
+		"getMeList",
+		"getMeOplogList",
+		"getMeOplogMerkleNodeList",
+		"getMeRequests",
+		"getMyBoard",
+		"getMyMasterOplogList",
+		"getMyNodes",
+		"getOpKeyOplogList",
+		"getPeers",
+		"getPendingMeOplogInternalList",
+		"getPendingMeOplogMasterList",
+		"getPendingOpKeyOplogInternalList",
+		"getPendingOpKeyOplogMasterList",
+		"getPrivacySetting",
+		"getRaftStatus",
+		"getRawMe",
+		"getRawMeOplogList",
+		"getRawMyNodes",
+		"getTotalWeight",
+
+		// subscriptions
+		"pttOplogs",
+	},
+	"account": {
+		"countPeers",
+		"getMasterList",
+		"getMasterListFromCache",
+		"getMasterOplogList",
+		"getMasterOplogMerkleNodeList",
+		"getMemberList",
+		"getMemberOplogList",
+		"getMemberOplogMerkleNodeList",
+		"getMyMemberLog",
+		"getNameCard",
+		"getNameCardByIDs",
+		"getOpKeyOplogList",
+		"getPeers",
+		"getPendingMasterOplogInternalList",
+		"getPendingMasterOplogMasterList",
+		"getPendingMemberOplogInternalList",
+		"getPendingMemberOplogMasterList",
+		"getPendingOpKeyOplogInternalList",
+		"getPendingOpKeyOplogMasterList",
+		"getPendingUserOplogInternalList",
+		"getPendingUserOplogMasterList",
+		"getRawNameCard",
+		"getRawProfile",
+		"getRawUserImg",
+		"getRawUserName",
+		"getUserImg",
+		"getUserImgByIDs",
+		"getUserName",
+		"getUserNameByIDs",
+		"getUserNodeInfo",
+		"getUserNodeList",
+		"getUserOplogList",
+		"getUserOplogMerkleNodeList",
+	},
+	"content": {
+		"countPeers",
+		"getArticle",
+		"getArticleBlockList",
+		"getArticleList",
+		"getArticleSummary",
+		"getArticleSummaryByIDs",
+		"getBanList",
+		"getBoard",
+		"getBoardList",
+		"getBoardOplogList",
+		"getBoardOplogMerkle",
+		"getBoardOplogMerkleNodeList",
+		"getCommentList",
+		"getFile",
+		"getFileChunk",
+		"getFileInfo",
+		"getImage",
+		"getImageThumbnail",
+		"getMasterList",
+		"getMasterListFromCache",
+		"getMasterOplogList",
+		"getMasterOplogMerkleNodeList",
+		"getMemberList",
+		"getMemberOplogList",
+		"getMemberOplogMerkleNodeList",
+		"getMyMemberLog",
+		"getOpKeyOplogList",
+		"getOrigImage",
+		"getPeers",
+		"getPendingBoardOplogInternalList",
+		"getPendingBoardOplogMasterList",
+		"getPendingMasterOplogInternalList",
+		"getPendingMasterOplogMasterList",
+		"getPendingMemberOplogInternalList",
+		"getPendingMemberOplogMasterList",
+		"getPendingOpKeyOplogInternalList",
+		"getPendingOpKeyOplogMasterList",
+		"getPinnedArticleList",
+		"getPins",
+		"getPokedArticleList",
+		"getPollList",
+		"getPollResults",
+		"getRawArticle",
+		"getRawBoard",
+		"getRawComment",
+		"getRawReply",
+		"getRawTitle",
+		"getReactions",
+		"getReadReceipts",
+		"getRetention",
+		"getRoles",
+		"getTags",
+		"getUpload",
+		"search",
+
+		// subscriptions
+		"boardOplogs",
+		"readReceipts",
+		"typings",
+	},
+	"friend": {
+		"countPeers",
+		"getFriend",
+		"getFriendByFriendID",
+		"getFriendList",
+		"getFriendListByMsgCreateTS",
+		"getFriendListSeen",
+		"getFriendOplogList",
+		"getFriendOplogMerkleNodeList",
+		"getMasterList",
+		"getMasterListFromCache",
+		"getMasterOplogList",
+		"getMasterOplogMerkleNodeList",
+		"getMemberList",
+		"getMemberOplogList",
+		"getMemberOplogMerkleNodeList",
+		"getMessageBlockList",
+		"getMessageList",
+		"getMyMemberLog",
+		"getOpKeyOplogList",
+		"getPeers",
+		"getPendingFriendOplogInternalList",
+		"getPendingFriendOplogMasterList",
+		"getPendingMasterOplogInternalList",
+		"getPendingMasterOplogMasterList",
+		"getPendingMemberOplogInternalList",
+		"getPendingMemberOplogMasterList",
+		"getPendingOpKeyOplogInternalList",
+		"getPendingOpKeyOplogMasterList",
+		"getRawFriend",
+		"getReadReceipts",
+		"getRetention",
+		"searchMessages",
+
+		// subscriptions
+		"friendOplogs",
+		"readReceipts",
+		"typings",
+	},
+	"chat": {
+		"countPeers",
+		"getChat",
+		"getChatList",
+		"getChatOplogList",
+		"getMasterListFromCache",
+		"getMemberList",
+		"getMessageBlockList",
+		"getMessageList",
+		"getPeers",
+		"getRawChat",
+
+		// subscriptions
+		"chatOplogs",
+	},
+}
+
+var readMethodSet = make(map[string]map[string]bool)
+
+func init() {
+	for module, methods := range readMethods {
+		readMethodSet[module] = make(map[string]bool)
+		for _, method := range methods {
+			readMethodSet[module][method] = true
+		}
+	}
+}
+
+/*
+IsReadMethod checks whether the method (in the formatted-name) of the module is in the read-only methods.
+*/
+func IsReadMethod(module string, method string) bool {
+	return readMethodSet[module][method]
+}
@@ -0,0 +1,237 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuthTokenIsAllowed(t *testing.T) {
+	tests := []struct {
+		scopes []string
+		module string
+		method string
+		want   bool
+	}{
+		{[]string{"*"}, "me", "revoke", true},
+		{[]string{"me"}, "me", "revoke", true},
+		{[]string{"me"}, "content", "getBoardList", false},
+		{[]string{"content:read"}, "content", "getBoardList", true},
+		{[]string{"content:read"}, "content", "countPeers", true},
+		{[]string{"content:read"}, "content", "createArticle", false},
+		{[]string{"content:read"}, "content", "showBoardURL", false},
+		{[]string{"content:read"}, "content", "getInvites", false},
+		{[]string{"content:read"}, "content", "getJoinKeyInfos", false},
+		{[]string{"content:read"}, "content", "getNotListed", false},
+		{[]string{"content:read"}, "content", "boardOplogs", true},
+		{[]string{"content:read"}, "friend", "friendOplogs", false},
+		{[]string{"friend:read"}, "friend", "friendOplogs", true},
+		{[]string{"friend:read"}, "friend", "searchMessages", true},
+		{[]string{"chat:read"}, "chat", "chatOplogs", true},
+		{[]string{"me:read"}, "me", "pttOplogs", true},
+		{[]string{"me:read"}, "me", "showMyMasterKey", false},
+		{[]string{"me:read"}, "me", "showURL", false},
+		{[]string{"ptt:read"}, "ptt", "getJoins", false},
+		{[]string{"content:read", "friend"}, "friend", "deleteMessage", true},
+	}
+
+	for _, tt := range tests {
+		token := &AuthToken{Scopes: tt.scopes}
+		if got := token.IsAllowed(tt.module, tt.method); got != tt.want {
+			t.Errorf("IsAllowed(%v, %v) with %v = %v, want %v", tt.module, tt.method, tt.scopes, got, tt.want)
+		}
+	}
+}
+
+func TestTokenStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpc-auth-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "auth-tokens.json")
+
+	store, err := NewTokenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := store.Create("invalid", []string{"content:"}); err != ErrInvalidScope {
+		t.Fatalf("Create with invalid scope: err = %v, want %v", err, ErrInvalidScope)
+	}
+
+	secret, token, err := store.Create("reader", []string{"content:read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Hash != "" {
+		t.Errorf("Create returns the hash of the secret")
+	}
+
+	// reload from the file
+	store, err = NewTokenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Authenticate(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != token.ID {
+		t.Errorf("Authenticate = %v, want %v", got.ID, token.ID)
+	}
+	if _, err := store.Authenticate(secret + "0"); err != ErrInvalidAuthToken {
+		t.Errorf("Authenticate with invalid secret: err = %v, want %v", err, ErrInvalidAuthToken)
+	}
+
+	if err := store.Revoke(token.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Authenticate(secret); err != ErrInvalidAuthToken {
+		t.Errorf("Authenticate after Revoke: err = %v, want %v", err, ErrInvalidAuthToken)
+	}
+	if len(store.List()) != 0 {
+		t.Errorf("List after Revoke = %v, want empty", store.List())
+	}
+}
+
+func TestIsAllowedOrigin(t *testing.T) {
+	tests := []struct {
+		allowedOrigins []string
+		origin         string
+		want           bool
+	}{
+		{[]string{"localhost"}, "http://localhost:9774", true},
+		{[]string{"localhost"}, "http://evil.com", false},
+		{[]string{"http://localhost:9774"}, "http://localhost:9774", true},
+		{[]string{"http://localhost:9774"}, "http://localhost:8080", false},
+		{[]string{"*"}, "http://evil.com", true},
+		{[]string{"localhost"}, "", false},
+		{nil, "http://localhost:9774", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsAllowedOrigin(tt.allowedOrigins, tt.origin); got != tt.want {
+			t.Errorf("IsAllowedOrigin(%v, %v) = %v, want %v", tt.allowedOrigins, tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestServerAuthorize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpc-auth-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := NewTokenStore(filepath.Join(dir, "auth-tokens.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, token, err := store.Create("tester", []string{"content:read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer()
+	server.SetTokenStore(store)
+
+	if err := server.authorize(context.Background(), "content", "getBoardList"); err == nil {
+		t.Errorf("authorize without token: expected error")
+	}
+	ctx := NewAuthContext(context.Background(), token)
+	if err := server.authorize(ctx, "content", "getBoardList"); err != nil {
+		t.Errorf("authorize in scope: err = %v", err)
+	}
+	if err := server.authorize(ctx, "content", "createArticle"); err == nil {
+		t.Errorf("authorize out of scope: expected error")
+	}
+	if err := server.authorize(context.Background(), MetadataApi, "modules"); err != nil {
+		t.Errorf("authorize %v: err = %v", MetadataApi, err)
+	}
+
+	// http
+	body := `{"jsonrpc":"2.0","id":1,"method":"rpc_modules"}`
+	request := httptest.NewRequest(http.MethodPost, "http://url.com", strings.NewReader(body))
+	request.Header.Set("content-type", contentType)
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("ServeHTTP without token: code = %v, want %v", recorder.Code, http.StatusUnauthorized)
+	}
+
+	request = httptest.NewRequest(http.MethodPost, "http://url.com", strings.NewReader(body))
+	request.Header.Set("content-type", contentType)
+	request.Header.Set("Authorization", "Bearer "+secret)
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("ServeHTTP with token: code = %v, want %v", recorder.Code, http.StatusOK)
+	}
+}
+
+func TestServerAuthorizeOrigin(t *testing.T) {
+	server := NewServer()
+	server.SetAllowedOrigins([]string{"localhost"})
+
+	tests := []struct {
+		origin  string
+		module  string
+		method  string
+		wantErr bool
+	}{
+		{"", "content", "createArticle", false},
+		{"http://localhost:9774", "content", "createArticle", false},
+		{"http://evil.com", "content", "getBoardList", false},
+		{"http://evil.com", "content", "boardOplogs", false},
+		{"http://evil.com", "content", "createArticle", true},
+		{"http://evil.com", "content", "showBoardURL", true},
+		{"http://evil.com", "me", "showMyKey", true},
+		{"http://evil.com", MetadataApi, "modules", false},
+	}
+
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.origin != "" && !IsAllowedOrigin(server.origins, tt.origin) {
+			ctx = newUntrustedOriginContext(ctx, tt.origin)
+		}
+		if err := server.authorize(ctx, tt.module, tt.method); (err != nil) != tt.wantErr {
+			t.Errorf("authorize(%v, %v) from %v: err = %v, wantErr %v", tt.module, tt.method, tt.origin, err, tt.wantErr)
+		}
+	}
+
+	// http
+	if err := server.RegisterName("content", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	body := `{"jsonrpc":"2.0","id":1,"method":"content_rets"}`
+	request := httptest.NewRequest(http.MethodPost, "http://url.com", strings.NewReader(body))
+	request.Header.Set("content-type", contentType)
+	request.Header.Set("Origin", "http://evil.com")
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	if !strings.Contains(recorder.Body.String(), "content_rets is not allowed from http://evil.com") {
+		t.Errorf("ServeHTTP from untrusted origin: body = %v", recorder.Body.String())
+	}
+}
@@ -58,6 +58,13 @@ func (e *callbackError) ErrorCode() int { return -32000 }
 
 func (e *callbackError) Error() string { return e.message }
 
+// request without the valid auth-token, or out of the scopes of the token
+type unauthorizedError struct{ message string }
+
+func (e *unauthorizedError) ErrorCode() int { return -32001 }
+
+func (e *unauthorizedError) Error() string { return e.message }
+
 // issued when a request is received after the server is issued to stop.
 type shutdownError struct{}
 
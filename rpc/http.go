@@ -165,7 +165,7 @@ func NewHTTPServer(cors []string, vhosts []string, srv *Server) *http.Server {
 	// Wrap the CORS-handler within a host-handler
 	//log.Debug("NewHTTPServer", "cors", cors)
 	handler := newCorsHandler(srv, cors)
-	handler = newVHostHandler(vhosts, handler)
+	handler = newVHostHandler(vhosts, cors, handler)
 	return &http.Server{
 		Handler:      handler,
 		ReadTimeout:  5 * time.Second,
@@ -195,6 +195,18 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx = context.WithValue(ctx, "scheme", r.Proto)
 	ctx = context.WithValue(ctx, "local", r.Host)
 
+	if srv.tokens != nil {
+		token, err := srv.tokens.AuthenticateRequest(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		ctx = NewAuthContext(ctx, token)
+	} else if origin := r.Header.Get("Origin"); origin != "" && !IsAllowedOrigin(srv.origins, origin) {
+		ctx = newUntrustedOriginContext(ctx, origin)
+	}
+
 	body := io.LimitReader(r.Body, maxRequestContentLength)
 	codec := NewJSONCodec(&httpReadWriteNopCloser{body, w})
 	defer codec.Close()
@@ -240,8 +252,9 @@ func newCorsHandler(srv *Server, allowedOrigins []string) http.Handler {
 // since they do in-domain requests against the RPC api. Instead, we can see on the Host-header
 // which domain was used, and validate that against a whitelist.
 type virtualHostHandler struct {
-	vhosts map[string]struct{}
-	next   http.Handler
+	vhosts  map[string]struct{}
+	origins []string
+	next    http.Handler
 }
 
 // ServeHTTP serves JSON-RPC requests over HTTP, implements http.Handler
@@ -264,12 +277,13 @@ func (h *virtualHostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	//csrftoken, _ := uuid.NewUUID().MarshalText()
 	//w.Header().Set("x-csrftoken", string(csrftoken))
 
-	if origin := r.Header.Get("Origin"); origin != "" {
+	// only the allowed origins are echoed back with the credentials.
+	if origin := r.Header.Get("Origin"); IsAllowedOrigin(h.origins, origin) {
 		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
 	}
-	w.Header().Set("Access-Control-Allow-Credentials", "true")
 	w.Header().Set("Access-Control-Allow-Method", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "X-CSRFToken, Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "X-CSRFToken, Content-Type, Authorization")
 
 	if ipAddr := net.ParseIP(host); ipAddr != nil {
 		// It's an IP address, we can serve that
@@ -290,10 +304,10 @@ func (h *virtualHostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, "invalid host specified", http.StatusForbidden)
 }
 
-func newVHostHandler(vhosts []string, next http.Handler) http.Handler {
+func newVHostHandler(vhosts []string, origins []string, next http.Handler) http.Handler {
 	vhostMap := make(map[string]struct{})
 	for _, allowedHost := range vhosts {
 		vhostMap[strings.ToLower(allowedHost)] = struct{}{}
 	}
-	return &virtualHostHandler{vhostMap, next}
+	return &virtualHostHandler{vhostMap, origins, next}
 }
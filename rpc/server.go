@@ -128,6 +128,47 @@ func (s *Server) RegisterName(name string, rcvr interface{}) error {
 	return nil
 }
 
+// SetTokenStore requires the auth-tokens (in the context of the requests) with the scopes to call the methods.
+func (s *Server) SetTokenStore(tokens *TokenStore) {
+	s.tokens = tokens
+}
+
+/*
+SetAllowedOrigins sets the origins allowed to call all the methods if the auth-tokens are not required.
+The requests from the other origins are allowed to call only the read-only methods (IsReadMethod).
+*/
+func (s *Server) SetAllowedOrigins(origins []string) {
+	s.origins = origins
+}
+
+/*
+authorize checks whether the auth-token in the context is allowed to call the method.
+If the auth-tokens are not required, checks whether the origin of the request is allowed to call the method.
+*/
+func (s *Server) authorize(ctx context.Context, svcname string, method string) Error {
+	if svcname == MetadataApi {
+		return nil
+	}
+
+	if s.tokens == nil {
+		origin, ok := untrustedOriginFromContext(ctx)
+		if ok && !IsReadMethod(svcname, method) {
+			return &unauthorizedError{fmt.Sprintf("unauthorized: %s%s%s is not allowed from %s", svcname, serviceMethodSeparator, method, origin)}
+		}
+		return nil
+	}
+
+	token, ok := AuthTokenFromContext(ctx)
+	if !ok || token == nil {
+		return &unauthorizedError{"unauthorized: auth token required"}
+	}
+	if !token.IsAllowed(svcname, method) {
+		return &unauthorizedError{fmt.Sprintf("unauthorized: %s%s%s is out of the scopes of the token", svcname, serviceMethodSeparator, method)}
+	}
+
+	return nil
+}
+
 // serveRequest will reads requests from the codec, calls the RPC callback and
 // writes the response to the given codec.
 //
@@ -289,6 +330,10 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		return codec.CreateErrorResponse(&req.id, &invalidParamsError{"Expected subscription id as first argument"}), nil
 	}
 
+	if err := s.authorize(ctx, req.svcname, formatName(req.callb.method.Name)); err != nil {
+		return codec.CreateErrorResponse(&req.id, err), nil
+	}
+
 	if req.callb.isSubscribe {
 		subid, err := s.createSubscription(ctx, codec, req)
 		if err != nil {
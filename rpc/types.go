@@ -74,6 +74,9 @@ type Server struct {
 	run      int32
 	codecsMu sync.Mutex
 	codecs   *set.Set
+
+	tokens  *TokenStore // requires the auth-tokens if set
+	origins []string    // allowed origins of all the methods if the auth-tokens are not required
 }
 
 // rpcRequest represents a raw incoming RPC request
@@ -59,6 +59,17 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 	return websocket.Server{
 		Handshake: wsHandshakeValidator(allowedOrigins),
 		Handler: func(conn *websocket.Conn) {
+			ctx := context.Background()
+			if srv.tokens != nil {
+				token, err := srv.tokens.AuthenticateRequest(conn.Request())
+				if err != nil {
+					log.Warn("WebsocketHandler: unable to authenticate", "e", err)
+					conn.Close()
+					return
+				}
+				ctx = NewAuthContext(ctx, token)
+			}
+
 			// Create a custom encode/decode pair to enforce payload size and number encoding
 			conn.MaxPayloadBytes = maxRequestContentLength
 
@@ -68,7 +79,9 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			decoder := func(v interface{}) error {
 				return websocketJSONCodec.Receive(conn, v)
 			}
-			srv.ServeCodec(NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
+			codec := NewCodec(conn, encoder, decoder)
+			defer codec.Close()
+			srv.serveRequest(ctx, codec, false, OptionMethodInvocation|OptionSubscriptions)
 		},
 	}
 }
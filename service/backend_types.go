@@ -34,6 +34,7 @@ const (
 	PathJoinMe     = "/joinme"
 	PathJoinFriend = "/joinfriend"
 	PathJoinBoard  = "/joinboard"
+	PathJoinChat   = "/joinchat"
 )
 
 type BackendCountPeers struct {
@@ -86,9 +87,24 @@ type BackendJoinRequest struct {
 	Hash      []byte           `json:"H"`
 	Name      []byte           `json:"N"`
 	Status    JoinStatus       `json:"S"`
+	IsFollow  bool             `json:"F,omitempty"`
 }
 
 func MarshalBackendJoinURL(id *types.PttID, nodeID *discover.NodeID, keyInfo *KeyInfo, name []byte, path string) (*BackendJoinURL, error) {
+	expireTS := keyInfo.UpdateTS
+	expireTS.Ts += IntRenewJoinKeySeconds
+
+	return marshalBackendJoinURL(id, nodeID, keyInfo, expireTS, name, path)
+}
+
+/*
+MarshalBackendInviteURL marshals the join-url with the join-key and the expire-ts of the invite.
+*/
+func MarshalBackendInviteURL(id *types.PttID, nodeID *discover.NodeID, invite *Invite, name []byte, path string) (*BackendJoinURL, error) {
+	return marshalBackendJoinURL(id, nodeID, invite.KeyInfo, invite.ExpireTS, name, path)
+}
+
+func marshalBackendJoinURL(id *types.PttID, nodeID *discover.NodeID, keyInfo *KeyInfo, expireTS types.Timestamp, name []byte, path string) (*BackendJoinURL, error) {
 	nodeIDBytes, err := nodeID.MarshalText()
 	if err != nil {
 		return nil, err
@@ -114,7 +130,7 @@ func MarshalBackendJoinURL(id *types.PttID, nodeID *discover.NodeID, keyInfo *Ke
 	v.Add("h", keyHashStr)
 	v.Add("k", keyStr)
 	v.Add("n", nameStr)
-	v.Add("t", strconv.FormatInt(keyInfo.UpdateTS.Ts+IntRenewJoinKeySeconds, 10))
+	v.Add("t", strconv.FormatInt(expireTS.Ts, 10))
 
 	return &BackendJoinURL{
 		CreatorID:    creatorIDStr,
@@ -123,7 +139,7 @@ func MarshalBackendJoinURL(id *types.PttID, nodeID *discover.NodeID, keyInfo *Ke
 		Pn:           nodeIDStr,
 		URL:          "pnode://" + nodeIDStr + path + "?" + v.Encode(),
 		UpdateTS:     keyInfo.UpdateTS,
-		ExpireSecond: IntRenewJoinKeySeconds,
+		ExpireSecond: uint(expireTS.Ts - keyInfo.UpdateTS.Ts),
 	}, nil
 }
 
@@ -239,6 +255,7 @@ func JoinRequestToBackendJoinRequest(joinRequest *JoinRequest) *BackendJoinReque
 		Hash:      joinRequest.Hash[:],
 		Name:      joinRequest.Name,
 		Status:    joinRequest.Status,
+		IsFollow:  joinRequest.IsFollow,
 	}
 }
 
@@ -0,0 +1,87 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/p2p/discover"
+)
+
+/*
+BanInfo is the ban of the user (and the known nodes of the user) in the entity.
+
+The bans are replicated via master-signed member-oplogs. The banned users are not able
+to join the entity, and the pending oplogs from the banned users are not signed.
+The unbanned users are kept with IsBanned as false to compare the update-ts.
+*/
+type BanInfo struct {
+	ID       *types.PttID       `json:"ID"`
+	NodeIDs  []*discover.NodeID `json:"N,omitempty"`
+	IsBanned bool               `json:"B"`
+
+	UpdateTS types.Timestamp `json:"UT"`
+	LogID    *types.PttID    `json:"l,omitempty"`
+}
+
+func GetBanInfo(bans []*BanInfo, id *types.PttID) *BanInfo {
+	for _, ban := range bans {
+		if reflect.DeepEqual(ban.ID, id) {
+			return ban
+		}
+	}
+	return nil
+}
+
+/*
+IsBannedInList returns whether the id or the node-id is banned in the bans.
+*/
+func IsBannedInList(bans []*BanInfo, id *types.PttID, nodeID *discover.NodeID) bool {
+	for _, ban := range bans {
+		if !ban.IsBanned {
+			continue
+		}
+		if id != nil && reflect.DeepEqual(ban.ID, id) {
+			return true
+		}
+		if nodeID == nil {
+			continue
+		}
+		for _, eachNodeID := range ban.NodeIDs {
+			if reflect.DeepEqual(eachNodeID, nodeID) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/*
+setBanInList replaces the ban of the same id in the bans, or appends the ban if not exists.
+*/
+func setBanInList(bans []*BanInfo, ban *BanInfo) []*BanInfo {
+	newBans := make([]*BanInfo, 0, len(bans)+1)
+	for _, each := range bans {
+		if reflect.DeepEqual(each.ID, ban.ID) {
+			continue
+		}
+		newBans = append(newBans, each)
+	}
+
+	return append(newBans, ban)
+}
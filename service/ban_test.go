@@ -0,0 +1,156 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/p2p/discover"
+)
+
+func TestIsBannedInList(t *testing.T) {
+	// setup test
+	id0 := &types.PttID{1}
+	id1 := &types.PttID{2}
+	id2 := &types.PttID{3}
+
+	nodeID0 := &discover.NodeID{1}
+	nodeID1 := &discover.NodeID{2}
+
+	bans := []*BanInfo{
+		&BanInfo{ID: id0, NodeIDs: []*discover.NodeID{nodeID0}, IsBanned: true, UpdateTS: types.Timestamp{Ts: 100}},
+		&BanInfo{ID: id1, NodeIDs: []*discover.NodeID{nodeID1}, IsBanned: false, UpdateTS: types.Timestamp{Ts: 200}},
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name   string
+		id     *types.PttID
+		nodeID *discover.NodeID
+		want   bool
+	}{
+		{name: "banned id", id: id0, want: true},
+		{name: "banned node", id: id2, nodeID: nodeID0, want: true},
+		{name: "unbanned id", id: id1, want: false},
+		{name: "unbanned node", id: id2, nodeID: nodeID1, want: false},
+		{name: "not in list", id: id2, want: false},
+		{name: "nil", want: false},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBannedInList(bans, tt.id, tt.nodeID); got != tt.want {
+				t.Errorf("IsBannedInList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_setBanInList(t *testing.T) {
+	// setup test
+	id0 := &types.PttID{1}
+	id1 := &types.PttID{2}
+
+	bans := []*BanInfo{
+		&BanInfo{ID: id0, IsBanned: true, UpdateTS: types.Timestamp{Ts: 100}},
+	}
+
+	// run test
+	bans = setBanInList(bans, &BanInfo{ID: id1, IsBanned: true, UpdateTS: types.Timestamp{Ts: 200}})
+	if len(bans) != 2 {
+		t.Errorf("setBanInList() len = %v, want 2", len(bans))
+	}
+
+	bans = setBanInList(bans, &BanInfo{ID: id0, IsBanned: false, UpdateTS: types.Timestamp{Ts: 300}})
+	if len(bans) != 2 {
+		t.Errorf("setBanInList() len = %v, want 2", len(bans))
+	}
+
+	ban := GetBanInfo(bans, id0)
+	if ban == nil || ban.IsBanned || ban.UpdateTS.Ts != 300 {
+		t.Errorf("GetBanInfo() = %v, want unbanned at 300", ban)
+	}
+}
+
+func TestBlockList(t *testing.T) {
+	// setup test
+	id0 := &types.PttID{1}
+	id1 := &types.PttID{2}
+
+	var nilList *BlockList
+	if nilList.IsBlocked(id0) {
+		t.Errorf("BlockList.IsBlocked() on nil = true, want false")
+	}
+
+	l := &BlockList{}
+
+	// run test
+	if !l.Add(id0) {
+		t.Errorf("BlockList.Add() = false, want true")
+	}
+	if l.Add(id0) {
+		t.Errorf("BlockList.Add() again = true, want false")
+	}
+	if !l.IsBlocked(id0) || l.IsBlocked(id1) {
+		t.Errorf("BlockList.IsBlocked() = %v/%v, want true/false", l.IsBlocked(id0), l.IsBlocked(id1))
+	}
+	if l.Remove(id1) {
+		t.Errorf("BlockList.Remove() not-blocked = true, want false")
+	}
+	if !l.Remove(id0) || l.IsBlocked(id0) {
+		t.Errorf("BlockList.Remove() failed")
+	}
+}
+
+func TestBlockList_FilterSearchDoc(t *testing.T) {
+	// setup test
+	id0 := &types.PttID{1}
+	id1 := &types.PttID{2}
+	entityID0 := &types.PttID{3}
+	entityID1 := &types.PttID{4}
+
+	l := &BlockList{IDs: []*types.PttID{id0}}
+	isValid := func(doc *SearchDoc) bool {
+		return *doc.EntityID == *entityID0
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		l       *BlockList
+		isValid func(doc *SearchDoc) bool
+		doc     *SearchDoc
+		want    bool
+	}{
+		{name: "blocked", l: l, isValid: isValid, doc: &SearchDoc{EntityID: entityID0, CreatorID: id0}, want: false},
+		{name: "not blocked", l: l, isValid: isValid, doc: &SearchDoc{EntityID: entityID0, CreatorID: id1}, want: true},
+		{name: "not valid", l: l, isValid: isValid, doc: &SearchDoc{EntityID: entityID1, CreatorID: id1}, want: false},
+		{name: "nil isValid", l: l, doc: &SearchDoc{EntityID: entityID1, CreatorID: id1}, want: true},
+		{name: "nil list", isValid: isValid, doc: &SearchDoc{EntityID: entityID0, CreatorID: id0}, want: true},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.FilterSearchDoc(tt.isValid)(tt.doc); got != tt.want {
+				t.Errorf("BlockList.FilterSearchDoc() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/ailabstw/go-pttai/common"
 	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/key"
 	"github.com/ailabstw/go-pttai/log"
 	"github.com/ailabstw/go-pttai/pttdb"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
@@ -54,8 +55,9 @@ type Block struct {
 	Sig      []byte        `json:"S,omitempty"`
 	Pub      []byte        `json:"K,omitempty"`
 	KeyExtra *KeyExtraInfo `json:"k,omitempty"`
+	KeyType  key.Type      `json:"kT,omitempty"`
 
-	db           *pttdb.LDBBatch
+	db           pttdb.IndexBatch
 	fullDBPrefix []byte
 }
 
@@ -80,7 +82,7 @@ func NewBlock(
 }
 
 func (b *Block) SetDB(
-	db *pttdb.LDBBatch,
+	db pttdb.IndexBatch,
 	fullDBPrefix []byte,
 
 	objID *types.PttID,
@@ -166,19 +168,20 @@ func (b *Block) Unmarshal(theBytes []byte) error {
 	return json.Unmarshal(theBytes, b)
 }
 
-func (b *Block) Sign(key *KeyInfo) error {
+func (b *Block) Sign(keyInfo *KeyInfo) error {
 	b.Hash = nil
 	b.Salt = types.Salt{}
 	b.Sig = nil
 	b.Pub = nil
 	b.KeyExtra = nil
+	b.KeyType = key.TypeSecp256k1
 
 	marshaled, err := b.Marshal()
 	if err != nil {
 		return err
 	}
 
-	bytesWithSalts, hash, sig, pubBytes, err := SignData(marshaled, key)
+	bytesWithSalts, hash, sig, pubBytes, err := SignData(marshaled, keyInfo)
 	if err != nil {
 		return err
 	}
@@ -187,7 +190,8 @@ func (b *Block) Sign(key *KeyInfo) error {
 	copy(b.Salt[:], bytesWithSalts[len(marshaled):])
 	b.Sig = sig
 	b.Pub = pubBytes
-	b.KeyExtra = key.Extra
+	b.KeyExtra = keyInfo.Extra
+	b.KeyType = keyInfo.KeyType
 
 	log.Debug("Block.Sign: to return", "blockID", b.BlockID, "subBlockID", b.SubBlockID, "hash", hash, "sig", sig, "salt", b.Salt)
 
@@ -195,9 +199,9 @@ func (b *Block) Sign(key *KeyInfo) error {
 }
 
 func (b *Block) Verify(expectedHash []byte, creatorID *types.PttID) error {
-	origHash, origSalt, origSig, origPub, origKeyExtra := b.Hash, b.Salt, b.Sig, b.Pub, b.KeyExtra
+	origHash, origSalt, origSig, origPub, origKeyExtra, origKeyType := b.Hash, b.Salt, b.Sig, b.Pub, b.KeyExtra, b.KeyType
 	defer func() {
-		b.Hash, b.Salt, b.Sig, b.Pub, b.KeyExtra = origHash, origSalt, origSig, origPub, origKeyExtra
+		b.Hash, b.Salt, b.Sig, b.Pub, b.KeyExtra, b.KeyType = origHash, origSalt, origSig, origPub, origKeyExtra, origKeyType
 	}()
 
 	b.Hash = nil
@@ -205,6 +209,7 @@ func (b *Block) Verify(expectedHash []byte, creatorID *types.PttID) error {
 	b.Sig = nil
 	b.Pub = nil
 	b.KeyExtra = nil
+	b.KeyType = key.TypeSecp256k1
 
 	marshaled, err := b.Marshal()
 	if err != nil {
@@ -215,5 +220,5 @@ func (b *Block) Verify(expectedHash []byte, creatorID *types.PttID) error {
 
 	log.Debug("Block.Verify: to verify", "blockID", b.BlockID, "subBlockID", b.SubBlockID, "hash", origHash, "expectedHash", expectedHash, "sig", origSig, "salt", origSalt)
 
-	return VerifyData(bytesWithSalt, expectedHash, origSig, origPub, creatorID, origKeyExtra)
+	return VerifyData(bytesWithSalt, expectedHash, origSig, origKeyType, origPub, creatorID, origKeyExtra)
 }
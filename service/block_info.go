@@ -36,7 +36,7 @@ type BlockInfo struct {
 
 	UpdaterID *types.PttID `json:"U"`
 
-	db           *pttdb.LDBBatch
+	db           pttdb.IndexBatch
 	dbLock       *types.LockMap
 	fullDBPrefix []byte
 
@@ -95,7 +95,7 @@ func (b *BlockInfo) Init(nBlock int) {
 }
 
 func (b *BlockInfo) SetDB(
-	db *pttdb.LDBBatch,
+	db pttdb.IndexBatch,
 	dbLock *types.LockMap,
 	fullDBPrefix []byte,
 
@@ -148,7 +148,7 @@ func (b *BlockInfo) VerifyBlock(block *Block) bool {
 
 	bytesWithSalt := append(marshaled, block.Salt[:]...)
 
-	err = VerifyData(bytesWithSalt, block.Hash, block.Sig, block.Pub, b.UpdaterID, block.KeyExtra)
+	err = VerifyData(bytesWithSalt, block.Hash, block.Sig, block.KeyType, block.Pub, b.UpdaterID, block.KeyExtra)
 	if err != nil {
 		return false
 	}
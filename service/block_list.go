@@ -0,0 +1,83 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+/*
+BlockList is the list of the users blocked by me. The block-list is local (not replicated).
+The contents from the blocked users are hidden, and the friend-requests from the blocked users are dropped.
+*/
+type BlockList struct {
+	IDs []*types.PttID `json:"IDs"`
+}
+
+func (l *BlockList) IsBlocked(id *types.PttID) bool {
+	if l == nil || id == nil {
+		return false
+	}
+
+	for _, eachID := range l.IDs {
+		if reflect.DeepEqual(eachID, id) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Add adds the id to the block-list. Return false if the id is already blocked.
+*/
+func (l *BlockList) Add(id *types.PttID) bool {
+	if l.IsBlocked(id) {
+		return false
+	}
+
+	l.IDs = append(l.IDs, id)
+	return true
+}
+
+/*
+Remove removes the id from the block-list. Return false if the id is not blocked.
+*/
+func (l *BlockList) Remove(id *types.PttID) bool {
+	for i, eachID := range l.IDs {
+		if reflect.DeepEqual(eachID, id) {
+			l.IDs = append(l.IDs[:i], l.IDs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+/*
+FilterSearchDoc wraps isValid to skip the search-docs created by the blocked users,
+so the limit of the search is applied after the filtering.
+*/
+func (l *BlockList) FilterSearchDoc(isValid func(doc *SearchDoc) bool) func(doc *SearchDoc) bool {
+	return func(doc *SearchDoc) bool {
+		if l.IsBlocked(doc.CreatorID) {
+			return false
+		}
+
+		return isValid == nil || isValid(doc)
+	}
+}
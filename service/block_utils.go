@@ -17,6 +17,7 @@
 package service
 
 import (
+	"github.com/ailabstw/go-pttai/common"
 	"github.com/ailabstw/go-pttai/log"
 	"github.com/ailabstw/go-pttai/pttdb"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
@@ -153,6 +154,49 @@ func GetContentBlockList(blockInfo *BlockInfo, limit uint32, isLocked bool) ([]*
 	return contentBlocks, nil
 }
 
+/*
+GetMediaBlockBuf gets the unscrambled buf of the blockID-th block based on the information of block-info.
+
+This is used for reading the media without loading all the blocks.
+*/
+func GetMediaBlockBuf(blockInfo *BlockInfo, blockID uint32) ([]byte, error) {
+	if int(blockID) >= blockInfo.NBlock {
+		return nil, ErrInvalidBlock
+	}
+
+	bufs := make([][]byte, NSubBlock)
+	for subBlockID := 0; subBlockID < NSubBlock; subBlockID++ {
+		each := NewEmptyBlock()
+		blockInfo.SetBlockDB(each)
+		each.BlockID = blockID
+		each.SubBlockID = uint8(subBlockID)
+
+		key, err := each.MarshalKey()
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := each.db.DB().Get(key)
+		if err != nil {
+			return nil, ErrInvalidBlock
+		}
+
+		err = each.Unmarshal(v)
+		if err != nil {
+			return nil, ErrInvalidBlock
+		}
+
+		bufs[subBlockID] = each.Buf
+	}
+
+	unscrambledBuf, err := UnscrambleBuf(bufs)
+	if err != nil {
+		return nil, ErrInvalidBlock
+	}
+
+	return common.Concat(unscrambledBuf)
+}
+
 func (b *BlockInfo) GetBlockIterWithBlockInfo(isLocked bool) (iterator.Iterator, error) {
 	block := NewEmptyBlock()
 	b.SetBlockDB(block)
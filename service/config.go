@@ -16,6 +16,10 @@
 
 package service
 
+import (
+	"github.com/ailabstw/go-pttai/pttdb"
+)
+
 type Config struct {
 	MaxPeers          int
 	MaxHubPeers       int
@@ -35,4 +39,8 @@ type Config struct {
 	IsE2E bool
 
 	IsPrivateAsPublic bool
+
+	DBEngine pttdb.EngineType
+
+	SignKeyType string
 }
@@ -38,13 +38,13 @@ type Count struct {
 	p uint
 	m uint64
 
-	db         *pttdb.LDBBatch
+	db         pttdb.IndexBatch
 	dbPrefixID *types.PttID
 	dbID       *types.PttID
 	dbPrefix   []byte
 }
 
-func NewCount(db *pttdb.LDBBatch, dbPrefixID *types.PttID, dbID *types.PttID, dbPrefix []byte, p uint, isNewBits bool) (*Count, error) {
+func NewCount(db pttdb.IndexBatch, dbPrefixID *types.PttID, dbID *types.PttID, dbPrefix []byte, p uint, isNewBits bool) (*Count, error) {
 	c := &Count{}
 	m := uint64(1 << p)
 	c.m = m
@@ -72,7 +72,7 @@ func (c *Count) SetHash() {
 	c.hash = murmur3.New64()
 }
 
-func (c *Count) SetDB(db *pttdb.LDBBatch, dbPrefixID *types.PttID, dbID *types.PttID, dbPrefix []byte) {
+func (c *Count) SetDB(db pttdb.IndexBatch, dbPrefixID *types.PttID, dbID *types.PttID, dbPrefix []byte) {
 	c.db = db
 	c.dbPrefixID = dbPrefixID
 	c.dbID = dbID
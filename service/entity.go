@@ -92,9 +92,9 @@ type Entity interface {
 	Name() string
 	SetName(name string)
 
-	DB() *pttdb.LDBBatch
+	DB() pttdb.IndexBatch
 	DBLock() *types.LockMap
-	SetDB(db *pttdb.LDBBatch, dbLock *types.LockMap)
+	SetDB(db pttdb.IndexBatch, dbLock *types.LockMap)
 
 	MustLock() error
 	Lock() error
@@ -105,6 +105,12 @@ type Entity interface {
 	SetSyncInfo(syncInfo SyncInfo)
 	GetSyncInfo() SyncInfo
 
+	GetRetention() *RetentionPolicy
+	SetRetention(policy *RetentionPolicy)
+
+	GetBans() []*BanInfo
+	SetBans(bans []*BanInfo)
+
 	IDString() string
 }
 
@@ -134,15 +140,19 @@ type BaseEntity struct {
 	ptt     Ptt
 	service Service
 
-	db     *pttdb.LDBBatch
+	db     pttdb.IndexBatch
 	dbLock *types.LockMap
 
 	SyncInfo SyncInfo
 
+	Retention *RetentionPolicy `json:"rt,omitempty"`
+
+	Bans []*BanInfo `json:"bn,omitempty"`
+
 	idString string
 }
 
-func NewBaseEntity(id *types.PttID, createTS types.Timestamp, creatorID *types.PttID, status types.Status, db *pttdb.LDBBatch, dbLock *types.LockMap) *BaseEntity {
+func NewBaseEntity(id *types.PttID, createTS types.Timestamp, creatorID *types.PttID, status types.Status, db pttdb.IndexBatch, dbLock *types.LockMap) *BaseEntity {
 
 	e := &BaseEntity{
 		V:         types.CurrentVersion,
@@ -170,7 +180,7 @@ func (e *BaseEntity) Init(pm ProtocolManager, ptt Ptt, service Service) {
 	e.idString = "(" + e.ID.String() + "/" + service.Name() + ")"
 }
 
-func (e *BaseEntity) SetDB(db *pttdb.LDBBatch, dbLock *types.LockMap) {
+func (e *BaseEntity) SetDB(db pttdb.IndexBatch, dbLock *types.LockMap) {
 	e.db = db
 	e.dbLock = dbLock
 }
@@ -361,7 +371,7 @@ func (e *BaseEntity) GetEntityType() EntityType {
 func (e *BaseEntity) SetEntityType(t EntityType) {
 	e.EntityType = t
 }
-func (e *BaseEntity) DB() *pttdb.LDBBatch {
+func (e *BaseEntity) DB() pttdb.IndexBatch {
 	return e.db
 }
 
@@ -405,6 +415,22 @@ func (e *BaseEntity) GetSyncInfo() SyncInfo {
 	return e.SyncInfo
 }
 
+func (e *BaseEntity) GetRetention() *RetentionPolicy {
+	return e.Retention
+}
+
+func (e *BaseEntity) SetRetention(policy *RetentionPolicy) {
+	e.Retention = policy
+}
+
+func (e *BaseEntity) GetBans() []*BanInfo {
+	return e.Bans
+}
+
+func (e *BaseEntity) SetBans(bans []*BanInfo) {
+	e.Bans = bans
+}
+
 func (e *BaseEntity) ResetJoinMeta() {}
 
 func (e *BaseEntity) SetJoinTS(ts types.Timestamp) {
@@ -49,12 +49,18 @@ var (
 	ErrInvalidMsgCode = errors.New("invalid msg code")
 	ErrInvalidMsg     = errors.New("invalid msg")
 
+	ErrInvalidPttDataVersion = errors.New("invalid ptt-data version")
+
 	ErrNotSent = errors.New("not sent")
 
 	ErrInvalidData = errors.New("invalid data")
 
 	ErrInvalidObject = errors.New("invalid object")
 
+	ErrInvalidRetention = errors.New("invalid retention")
+
+	ErrInvalidMemberRole = errors.New("invalid member role")
+
 	ErrTimeout = errors.New("timeout")
 
 	ErrInvalidEntity = errors.New("invalid entity")
@@ -70,6 +76,8 @@ var (
 
 	ErrInvalidKeyInfo = errors.New("invalid key info")
 
+	ErrInvalidInvite = errors.New("invalid invite")
+
 	ErrNotFound = errors.New("not found")
 
 	ErrNoPeer = errors.New("no peer")
@@ -0,0 +1,101 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"encoding/json"
+
+	"github.com/ailabstw/go-pttai/common"
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+)
+
+/*
+Follower is the user reading the public entity without being a member.
+
+The followers are local to the node approving the follows (not synced).
+*/
+type Follower struct {
+	ID       *types.PttID    `json:"ID"`
+	EntityID *types.PttID    `json:"EID"`
+	CreateTS types.Timestamp `json:"CT"`
+}
+
+func NewFollower(entityID *types.PttID, id *types.PttID) (*Follower, error) {
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Follower{
+		ID:       id,
+		EntityID: entityID,
+		CreateTS: ts,
+	}, nil
+}
+
+func (f *Follower) Save(db pttdb.DB) error {
+	key, err := f.MarshalKey()
+	if err != nil {
+		return err
+	}
+
+	val, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	return db.Put(key, val)
+}
+
+func (f *Follower) Delete(db pttdb.DB) error {
+	key, err := f.MarshalKey()
+	if err != nil {
+		return err
+	}
+
+	return db.Delete(key)
+}
+
+func (f *Follower) MarshalKey() ([]byte, error) {
+	return common.Concat([][]byte{DBFollowerPrefix, f.EntityID[:], f.ID[:]})
+}
+
+func GetFollowerList(db pttdb.DB, entityID *types.PttID) ([]*Follower, error) {
+	prefix, err := DBPrefix(DBFollowerPrefix, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := db.NewIteratorWithPrefix(nil, prefix, pttdb.ListOrderNext)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+
+	followers := make([]*Follower, 0)
+	for iter.Next() {
+		follower := &Follower{}
+		err = json.Unmarshal(iter.Value(), follower)
+		if err != nil || follower.ID == nil {
+			continue
+		}
+		followers = append(followers, follower)
+	}
+
+	return followers, nil
+}
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/key"
 	"github.com/ailabstw/go-pttai/pttdb"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -41,6 +42,10 @@ var (
 		IsE2E: false,
 
 		IsPrivateAsPublic: false,
+
+		DBEngine: pttdb.EngineLevelDB,
+
+		SignKeyType: key.TypeSecp256k1.String(),
 	}
 )
 
@@ -48,12 +53,18 @@ var (
 const (
 	_ uint = iota + 3
 	Ptt4
+	Ptt5 // authenticated-encryption (AES-GCM) for ptt-data
 )
 
+// ProtocolVersions are in the order of preference. The highest common version is negotiated with each peer.
+//
+// ProtocolName stays ptt4 for all the versions.
+// The p2p-capabilities are matched by both the name and the version,
+// so the ptt4-only nodes (advertising ptt4/4) are still matched by the ptt4/5 nodes.
 var (
-	ProtocolVersions = [1]uint{Ptt4}
+	ProtocolVersions = [2]uint{Ptt5, Ptt4}
 	ProtocolName     = "ptt4"
-	ProtocolLengths  = [1]uint64{uint64(NCodeType)}
+	ProtocolLengths  = [2]uint64{uint64(NCodeType), uint64(NCodeType)}
 )
 
 // ptt-layer
@@ -69,10 +80,31 @@ const (
 	IdentifyPeerTimeout = 10 * time.Second
 )
 
+// key
+var (
+	// SignKeyType is the key-type of the newly generated sign-keys and op-keys.
+	// secp256k1 is kept as the default to be compatible with the peers not supporting ed25519.
+	SignKeyType = key.TypeSecp256k1
+
+	// Ed25519CertDomain is prepended to the ed25519 public key signed by the parent key.
+	Ed25519CertDomain = []byte("pttai-ed25519-cert")
+)
+
 // join
 const (
 	IntRenewJoinKeySeconds = 86400 // 1 day for now
 	RenewJoinKeySeconds    = time.Duration(IntRenewJoinKeySeconds) * time.Second
+
+	MaxInviteExpireSeconds = 30 * 86400 // 30 days
+)
+
+var (
+	DBInvitePrefix = []byte(".ivdb")
+)
+
+// follower
+var (
+	DBFollowerPrefix = []byte(".fwdb")
 )
 
 // msg
@@ -239,11 +271,21 @@ const (
 	MaxUploadImageHeight = 8192
 )
 
+var (
+	// ThumbnailSizes are the max-width / max-height (in ascending order) of the thumbnails of the uploaded images.
+	ThumbnailSizes = []int{160, 320, 640}
+)
+
 var (
 	DBMediaPrefix    = []byte(".mddb")
 	DBMediaIdxPrefix = []byte(".mdix")
 )
 
+// search
+const (
+	MaxSearchTokenLength = 64 // bytes
+)
+
 // db
 const (
 	SleepTimePttLock = 10
@@ -255,10 +297,10 @@ const (
 )
 
 var (
-	dbOplog     *pttdb.LDBBatch
-	dbOplogCore *pttdb.LDBDatabase
+	dbOplog     pttdb.IndexBatch
+	dbOplogCore pttdb.DB
 
-	dbMeta *pttdb.LDBDatabase
+	dbMeta pttdb.DB
 
 	DBNewestMasterLogIDPrefix = []byte(".nmld")
 	DBMasterLog0HashPrefix    = []byte(".ml0h")
@@ -278,6 +320,17 @@ var (
 	ExpireOplogSeconds = 300 // expire oplog circulation as 5 minutes for now.
 )
 
+// event
+var (
+	SizeEventSubscriptionBuffer = 256  // events buffered for each rpc-subscription before dropping.
+	SizePostedOplogs            = 1024 // recently posted oplog-events of each entity, to dedup.
+)
+
+// read-receipt
+var (
+	SizeReadReceipts = 4096 // the recently updated read-receipts kept in each entity.
+)
+
 // oplog-merkle-tree
 var (
 	SizeMerkleTreeLevel     = 1 // uint8
@@ -300,6 +353,17 @@ var (
 
 	GenerateOplogMerkleTreeSeconds             = 900 * time.Second // 15 mins
 	ExpireGenerateOplogMerkleTreeSeconds int64 = 450               // 7.5 mins
+
+	// live merkle-tree: generate the updated merkle-tree after the oplogs are settled.
+	GenerateOplogMerkleTreeDelay = 5 * time.Second
+
+	// the digests of the recent hours in sync-oplog.
+	MaxSyncOplogRanges = 72
+)
+
+// retention
+var (
+	RetentionGCSeconds = 600 * time.Second // 10 mins
 )
 
 // dial-history
@@ -327,7 +391,7 @@ var (
 )
 
 func InitService(dataDir string) error {
-	dbOplogCore, err := pttdb.NewLDBDatabase("oplog", dataDir, 0, 0)
+	dbOplogCore, err := pttdb.NewDatabase("oplog", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
@@ -337,7 +401,7 @@ func InitService(dataDir string) error {
 		return err
 	}
 
-	dbMeta, err = pttdb.NewLDBDatabase("meta", dataDir, 0, 0)
+	dbMeta, err = pttdb.NewDatabase("meta", dataDir, 0, 0)
 	if err != nil {
 		return err
 	}
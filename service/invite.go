@@ -0,0 +1,200 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common"
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+)
+
+/*
+Invite is the join-key with the limited uses and the custom expire-ts.
+
+The invites are local to the node issuing the invites (not synced), and the join-key is
+removed from the joins as soon as the invite is revoked, expired, or used up.
+*/
+type Invite struct {
+	ID        *types.PttID    `json:"ID"`
+	EntityID  *types.PttID    `json:"EID"`
+	CreatorID *types.PttID    `json:"CID"`
+	CreateTS  types.Timestamp `json:"CT"`
+	ExpireTS  types.Timestamp `json:"ET"`
+
+	// MaxUses is the max number of the users able to join with the invite. 0 as unlimited.
+	MaxUses   int          `json:"M"`
+	UsedBy    []*InviteUse `json:"U,omitempty"`
+	IsRevoked bool         `json:"R,omitempty"`
+
+	KeyInfo *KeyInfo `json:"K"`
+}
+
+/*
+InviteUse records the user consuming the invite.
+*/
+type InviteUse struct {
+	ID       *types.PttID    `json:"ID"`
+	UpdateTS types.Timestamp `json:"UT"`
+}
+
+func NewInvite(entityID *types.PttID, creatorID *types.PttID, maxUses int, expireSeconds int64) (*Invite, error) {
+	if maxUses < 0 || expireSeconds <= 0 || expireSeconds > MaxInviteExpireSeconds {
+		return nil, ErrInvalidInvite
+	}
+
+	keyInfo, err := NewJoinKeyInfo(entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := keyInfo.UpdateTS
+	expireTS := ts
+	expireTS.Ts += expireSeconds
+
+	return &Invite{
+		ID:        keyInfo.ID,
+		EntityID:  entityID,
+		CreatorID: creatorID,
+		CreateTS:  ts,
+		ExpireTS:  expireTS,
+		MaxUses:   maxUses,
+		KeyInfo:   keyInfo,
+	}, nil
+}
+
+func (i *Invite) NUses() int {
+	return len(i.UsedBy)
+}
+
+func (i *Invite) IsUsedBy(id *types.PttID) bool {
+	for _, use := range i.UsedBy {
+		if reflect.DeepEqual(use.ID, id) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+IsValid returns whether the invite is able to be used at ts.
+*/
+func (i *Invite) IsValid(ts types.Timestamp) bool {
+	if i.IsRevoked {
+		return false
+	}
+
+	if !ts.IsLess(i.ExpireTS) {
+		return false
+	}
+
+	return i.MaxUses == 0 || i.NUses() < i.MaxUses
+}
+
+/*
+IsValidFor returns whether the id is able to join with the invite at ts.
+The user already consuming the invite is able to retry the join without consuming another use.
+*/
+func (i *Invite) IsValidFor(id *types.PttID, ts types.Timestamp) bool {
+	if i.IsUsedBy(id) {
+		return !i.IsRevoked && ts.IsLess(i.ExpireTS)
+	}
+
+	return i.IsValid(ts)
+}
+
+/*
+Use records the use of the invite by id. Returns false if id already used the invite.
+*/
+func (i *Invite) Use(id *types.PttID, ts types.Timestamp) bool {
+	if i.IsUsedBy(id) {
+		return false
+	}
+
+	i.UsedBy = append(i.UsedBy, &InviteUse{ID: id, UpdateTS: ts})
+	return true
+}
+
+/*
+Unuse removes the use of the invite by id. Returns false if id did not use the invite.
+*/
+func (i *Invite) Unuse(id *types.PttID) bool {
+	for idx, use := range i.UsedBy {
+		if reflect.DeepEqual(use.ID, id) {
+			i.UsedBy = append(i.UsedBy[:idx], i.UsedBy[idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Invite) Save(db pttdb.DB) error {
+	key, err := i.MarshalKey()
+	if err != nil {
+		return err
+	}
+
+	val, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	return db.Put(key, val)
+}
+
+func (i *Invite) Unmarshal(theBytes []byte) error {
+	err := json.Unmarshal(theBytes, i)
+	if err != nil {
+		return err
+	}
+	if i.KeyInfo == nil {
+		return ErrInvalidInvite
+	}
+
+	return i.KeyInfo.loadKey()
+}
+
+func (i *Invite) MarshalKey() ([]byte, error) {
+	return common.Concat([][]byte{DBInvitePrefix, i.EntityID[:], i.ID[:]})
+}
+
+func GetInviteList(db pttdb.DB, entityID *types.PttID) ([]*Invite, error) {
+	prefix, err := DBPrefix(DBInvitePrefix, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := db.NewIteratorWithPrefix(nil, prefix, pttdb.ListOrderNext)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+
+	invites := make([]*Invite, 0)
+	for iter.Next() {
+		invite := &Invite{}
+		err = invite.Unmarshal(iter.Value())
+		if err != nil {
+			continue
+		}
+		invites = append(invites, invite)
+	}
+
+	return invites, nil
+}
@@ -0,0 +1,285 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNewInvite(t *testing.T) {
+	// setup test
+	entityID, _ := types.NewPttID()
+	creatorID, _ := types.NewPttID()
+
+	// prepare test-cases
+	tests := []struct {
+		name          string
+		maxUses       int
+		expireSeconds int64
+		wantErr       bool
+	}{
+		{name: "single-use", maxUses: 1, expireSeconds: 3600},
+		{name: "unlimited", maxUses: 0, expireSeconds: 3600},
+		{name: "negative uses", maxUses: -1, expireSeconds: 3600, wantErr: true},
+		{name: "no expire", maxUses: 1, expireSeconds: 0, wantErr: true},
+		{name: "too long expire", maxUses: 1, expireSeconds: MaxInviteExpireSeconds + 1, wantErr: true},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewInvite(entityID, creatorID, tt.maxUses, tt.expireSeconds)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewInvite() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.ExpireTS.Ts != got.CreateTS.Ts+tt.expireSeconds {
+				t.Errorf("NewInvite() ExpireTS = %v, want %v", got.ExpireTS.Ts, got.CreateTS.Ts+tt.expireSeconds)
+			}
+			if !got.IsValid(got.CreateTS) {
+				t.Errorf("NewInvite() not valid at CreateTS")
+			}
+		})
+	}
+}
+
+func TestInvite_Use(t *testing.T) {
+	// setup test
+	entityID, _ := types.NewPttID()
+	creatorID, _ := types.NewPttID()
+	id1, _ := types.NewPttID()
+	id2, _ := types.NewPttID()
+	id3, _ := types.NewPttID()
+
+	invite, err := NewInvite(entityID, creatorID, 2, 3600)
+	if err != nil {
+		t.Errorf("NewInvite() error = %v", err)
+		return
+	}
+	ts := invite.CreateTS
+	expiredTS := invite.ExpireTS
+
+	// prepare test-cases
+	tests := []struct {
+		name        string
+		id          *types.PttID
+		wantUse     bool
+		wantValid   bool
+		wantValidID bool
+	}{
+		{name: "id1", id: id1, wantUse: true, wantValid: true, wantValidID: true},
+		{name: "id1 again", id: id1, wantUse: false, wantValid: true, wantValidID: true},
+		{name: "id2", id: id2, wantUse: true, wantValid: false, wantValidID: true},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := invite.Use(tt.id, ts); got != tt.wantUse {
+				t.Errorf("Invite.Use() = %v, want %v", got, tt.wantUse)
+			}
+			if got := invite.IsValid(ts); got != tt.wantValid {
+				t.Errorf("Invite.IsValid() = %v, want %v", got, tt.wantValid)
+			}
+			if got := invite.IsValidFor(tt.id, ts); got != tt.wantValidID {
+				t.Errorf("Invite.IsValidFor() = %v, want %v", got, tt.wantValidID)
+			}
+		})
+	}
+
+	if invite.IsValidFor(id3, ts) {
+		t.Errorf("Invite.IsValidFor() used-up invite is valid for new user")
+	}
+
+	if invite.IsValidFor(id1, expiredTS) {
+		t.Errorf("Invite.IsValidFor() expired invite is valid")
+	}
+
+	invite.IsRevoked = true
+	if invite.IsValidFor(id1, ts) {
+		t.Errorf("Invite.IsValidFor() revoked invite is valid")
+	}
+}
+
+/*
+tInviteEntity is the entity with only the id of the invites.
+*/
+type tInviteEntity struct {
+	Entity
+	id *types.PttID
+}
+
+func (e *tInviteEntity) GetID() *types.PttID {
+	return e.id
+}
+
+func (e *tInviteEntity) IDString() string {
+	return e.id.String()
+}
+
+/*
+tInvitePtt is the ptt counting the join-keys of the invites.
+*/
+type tInvitePtt struct {
+	Ptt
+	lock     sync.Mutex
+	joinKeys map[common.Address]bool
+}
+
+func (p *tInvitePtt) AddJoinKey(hash *common.Address, entityID *types.PttID, isLocked bool) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.joinKeys[*hash] = true
+	return nil
+}
+
+func (p *tInvitePtt) RemoveJoinKey(hash *common.Address, entityID *types.PttID, isLocked bool) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.joinKeys, *hash)
+	return nil
+}
+
+func setupInviteTest(t *testing.T, maxUses int) (*BaseProtocolManager, *Invite) {
+	entityID, _ := types.NewPttID()
+
+	pm := &BaseProtocolManager{
+		db:      tDBOplog,
+		entity:  &tInviteEntity{id: entityID},
+		ptt:     &tInvitePtt{joinKeys: make(map[common.Address]bool)},
+		invites: make(map[common.Address]*Invite),
+	}
+
+	invite, err := NewInvite(entityID, tUserIDMe, maxUses, 3600)
+	if err != nil {
+		t.Fatalf("NewInvite() error = %v", err)
+	}
+	err = invite.Save(pm.DB().DB())
+	if err != nil {
+		t.Fatalf("Invite.Save() error = %v", err)
+	}
+	pm.invites[*invite.KeyInfo.Hash] = invite
+	pm.ptt.AddJoinKey(invite.KeyInfo.Hash, entityID, false)
+
+	return pm, invite
+}
+
+func TestBaseProtocolManager_useInviteConcurrent(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	maxUses := 3
+	nJoins := 20
+	pm, invite := setupInviteTest(t, maxUses)
+	hash := invite.KeyInfo.Hash
+
+	// run test
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	nUsed := 0
+	nInvalid := 0
+	for i := 0; i < nJoins; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			isUsed, err := pm.useInvite(hash, &types.PttID{byte(i + 1)})
+
+			lock.Lock()
+			defer lock.Unlock()
+			switch {
+			case err == ErrInvalidInvite:
+				nInvalid++
+			case err != nil:
+				t.Errorf("useInvite() error = %v", err)
+			case isUsed:
+				nUsed++
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if nUsed != maxUses || nInvalid != nJoins-maxUses {
+		t.Errorf("useInvite() nUsed = %v nInvalid = %v, want %v %v", nUsed, nInvalid, maxUses, nJoins-maxUses)
+	}
+
+	got, err := pm.GetInvite(invite.ID)
+	if err != nil || got.NUses() != maxUses {
+		t.Errorf("GetInvite() nUses = %v e = %v, want %v", got.NUses(), err, maxUses)
+	}
+
+	if _, ok := pm.ptt.(*tInvitePtt).joinKeys[*hash]; ok {
+		t.Errorf("useInvite() used-up join-key not removed")
+	}
+
+	// teardown test
+}
+
+func TestBaseProtocolManager_unuseInvite(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	pm, invite := setupInviteTest(t, 1)
+	hash := invite.KeyInfo.Hash
+	id1 := &types.PttID{1}
+	id2 := &types.PttID{2}
+
+	// run test
+	isUsed, err := pm.useInvite(hash, id1)
+	if err != nil || !isUsed {
+		t.Errorf("useInvite() = %v e = %v, want true", isUsed, err)
+	}
+
+	// retry by the same user does not consume another use.
+	isUsed, err = pm.useInvite(hash, id1)
+	if err != nil || isUsed {
+		t.Errorf("useInvite() retry = %v e = %v, want false", isUsed, err)
+	}
+
+	_, err = pm.useInvite(hash, id2)
+	if err != ErrInvalidInvite {
+		t.Errorf("useInvite() used-up e = %v, want %v", err, ErrInvalidInvite)
+	}
+
+	// the join of id1 failed.
+	err = pm.unuseInvite(hash, id1)
+	if err != nil {
+		t.Errorf("unuseInvite() e = %v", err)
+	}
+
+	if _, ok := pm.ptt.(*tInvitePtt).joinKeys[*hash]; !ok {
+		t.Errorf("unuseInvite() join-key not added back")
+	}
+
+	isUsed, err = pm.useInvite(hash, id2)
+	if err != nil || !isUsed {
+		t.Errorf("useInvite() after unuse = %v e = %v, want true", isUsed, err)
+	}
+
+	// teardown test
+}
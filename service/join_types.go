@@ -33,6 +33,7 @@ const (
 	JoinTypeMe
 	JoinTypeFriend
 	JoinTypeBoard
+	JoinTypeChat
 )
 
 // JoinStatus
@@ -64,6 +65,8 @@ type JoinRequest struct {
 
 	Challenge []byte `json:"C"`
 	ID        *types.PttID
+
+	IsFollow bool `json:"F,omitempty"`
 }
 
 // JoinRequestEvent
@@ -111,6 +114,7 @@ type JoinEntity struct {
 	ID          *types.PttID
 	Name        []byte `json:"N"`
 	Master0Hash []byte `json:"M"`
+	IsFollow    bool   `json:"F,omitempty"`
 }
 
 // ConfirmJoin
@@ -24,6 +24,7 @@ import (
 	"github.com/ailabstw/go-pttai/key/bip32"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ed25519"
 )
 
 // KeyInfo
@@ -32,9 +33,11 @@ type KeyInfo struct {
 
 	Hash *common.Address `json:"H"`
 
-	Key         *ecdsa.PrivateKey `json:"-"`
-	KeyBytes    []byte            `json:"K"`
-	PubKeyBytes []byte            `json:"-"`
+	KeyType     key.Type           `json:"T,omitempty"`
+	Key         *ecdsa.PrivateKey  `json:"-"`
+	EdKey       ed25519.PrivateKey `json:"-"`
+	KeyBytes    []byte             `json:"K"`
+	PubKeyBytes []byte             `json:"-"`
 
 	UpdateTS types.Timestamp `json:"UT"`
 
@@ -61,6 +64,10 @@ func NewJoinKeyInfo(entityID *types.PttID) (*KeyInfo, error) {
 }
 
 func NewOpKeyInfo(entityID *types.PttID, doerID *types.PttID, masterKey *ecdsa.PrivateKey) (*KeyInfo, error) {
+	if SignKeyType == key.TypeEd25519 {
+		return newEd25519KeyInfo(masterKey, entityID, doerID)
+	}
+
 	key, extra, err := deriveOpKey(masterKey)
 	if err != nil {
 		return nil, err
@@ -70,6 +77,10 @@ func NewOpKeyInfo(entityID *types.PttID, doerID *types.PttID, masterKey *ecdsa.P
 }
 
 func NewSignKeyInfo(doerID *types.PttID, masterKey *ecdsa.PrivateKey) (*KeyInfo, error) {
+	if SignKeyType == key.TypeEd25519 {
+		return newEd25519KeyInfo(masterKey, nil, doerID)
+	}
+
 	key, extra, err := deriveSignKey(masterKey)
 	if err != nil {
 		return nil, err
@@ -110,6 +121,67 @@ func newKeyInfo(extendedKey *bip32.ExtendedKey, extra *KeyExtraInfo, entityID *t
 	}, nil
 }
 
+/*
+newEd25519KeyInfo generates the ed25519 key certified by the master key.
+*/
+func newEd25519KeyInfo(masterKey *ecdsa.PrivateKey, entityID *types.PttID, doerID *types.PttID) (*KeyInfo, error) {
+	edKey, extra, err := deriveKeyEd25519Cert(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	pubBytes := key.Ed25519KeyToPubkeyBytes(edKey)
+	hash, err := key.PubkeyBytesToAddressWithType(key.TypeEd25519, pubBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return nil, err
+	}
+
+	id := keyInfoHashToID(&hash)
+
+	return &KeyInfo{
+		BaseObject: NewObject(id, ts, doerID, entityID, nil, types.StatusInvalid),
+
+		Hash:        &hash,
+		KeyType:     key.TypeEd25519,
+		EdKey:       edKey,
+		KeyBytes:    key.Ed25519KeyToSeed(edKey),
+		PubKeyBytes: pubBytes,
+		UpdateTS:    ts,
+		Extra:       extra,
+	}, nil
+}
+
+/*
+loadKey loads the private key and the public key from KeyBytes based on KeyType.
+*/
+func (k *KeyInfo) loadKey() error {
+	switch k.KeyType {
+	case key.TypeSecp256k1:
+		privKey, err := crypto.ToECDSA(k.KeyBytes)
+		if err != nil {
+			return err
+		}
+		k.Key = privKey
+		k.PubKeyBytes = crypto.FromECDSAPub(&privKey.PublicKey)
+	case key.TypeEd25519:
+		edKey, err := key.Ed25519KeyFromSeed(k.KeyBytes)
+		if err != nil {
+			return err
+		}
+		k.EdKey = edKey
+		k.PubKeyBytes = key.Ed25519KeyToPubkeyBytes(edKey)
+	default:
+		return key.ErrInvalidKeyType
+	}
+
+	return nil
+}
+
 func keyInfoIDToHash(id *types.PttID) *common.Address {
 	hash := &common.Address{}
 	copy(hash[:], id[:common.AddressLength])
@@ -136,6 +208,32 @@ func deriveSignKey(masterKey *ecdsa.PrivateKey) (*bip32.ExtendedKey, *KeyExtraIn
 	return deriveKeyBIP32(masterKey)
 }
 
+func deriveKeyEd25519Cert(masterKey *ecdsa.PrivateKey) (ed25519.PrivateKey, *KeyExtraInfo, error) {
+	edKey, err := key.GenerateEd25519Key()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hash := ed25519CertHash(key.Ed25519KeyToPubkeyBytes(edKey))
+	sig, err := crypto.Sign(hash, masterKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	extraCert := &KeyEd25519Cert{
+		Parent:     crypto.FromECDSAPub(&masterKey.PublicKey),
+		ParentType: key.TypeSecp256k1,
+		Sig:        sig,
+	}
+
+	extra := &KeyExtraInfo{
+		KeyType: KeyTypeEd25519Cert,
+		Data:    extraCert,
+	}
+
+	return edKey, extra, nil
+}
+
 func deriveKeyBIP32(masterKey *ecdsa.PrivateKey) (*bip32.ExtendedKey, *KeyExtraInfo, error) {
 	var err error
 	var extendedKey *bip32.ExtendedKey
@@ -21,7 +21,9 @@ import (
 	"reflect"
 
 	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/key"
 	"github.com/ailabstw/go-pttai/key/bip32"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -30,6 +32,7 @@ type KeyType uint8
 const (
 	_ KeyType = iota
 	KeyTypeBIP32
+	KeyTypeEd25519Cert
 )
 
 type KeyExtraInfo struct {
@@ -50,10 +53,22 @@ type KeyBIP32 struct {
 	Child  uint32      `json:"c"`
 }
 
-func (k *KeyExtraInfo) IsValid(pubKeyBytes []byte, doerID *types.PttID) bool {
+/*
+KeyEd25519Cert represents the extra data for the ed25519 key certified by the parent (master) key.
+The parent signs the hash of the child public key.
+*/
+type KeyEd25519Cert struct {
+	Parent     []byte   `json:"P"`
+	ParentType key.Type `json:"t,omitempty"`
+	Sig        []byte   `json:"S"`
+}
+
+func (k *KeyExtraInfo) IsValid(keyType key.Type, pubKeyBytes []byte, doerID *types.PttID) bool {
 	switch k.KeyType {
 	case KeyTypeBIP32:
-		return k.IsValidBIP32(pubKeyBytes, doerID)
+		return keyType == key.TypeSecp256k1 && k.IsValidBIP32(pubKeyBytes, doerID)
+	case KeyTypeEd25519Cert:
+		return keyType == key.TypeEd25519 && k.IsValidEd25519Cert(pubKeyBytes, doerID)
 	default:
 		return false
 	}
@@ -90,6 +105,35 @@ func (k *KeyExtraInfo) IsValidBIP32(pubKeyBytes []byte, doerID *types.PttID) boo
 	return reflect.DeepEqual(childPubKeyBytes, pubKeyBytes)
 }
 
+func (k *KeyExtraInfo) IsValidEd25519Cert(pubKeyBytes []byte, doerID *types.PttID) bool {
+	keyCert := &KeyEd25519Cert{}
+	err := k.GetData(keyCert)
+	if err != nil {
+		return false
+	}
+
+	parentAddr, err := key.PubkeyBytesToAddressWithType(keyCert.ParentType, keyCert.Parent)
+	if err != nil {
+		return false
+	}
+
+	if !reflect.DeepEqual(doerID[:common.AddressLength], parentAddr[:]) {
+		return false
+	}
+
+	hash := ed25519CertHash(pubKeyBytes)
+
+	return key.VerifySignature(keyCert.ParentType, keyCert.Parent, hash, keyCert.Sig)
+}
+
+/*
+ed25519CertHash is the hash signed by the parent key to certify the ed25519 key,
+with the domain separated from the other signatures of the parent key (ex: the oplogs).
+*/
+func ed25519CertHash(pubKeyBytes []byte) []byte {
+	return crypto.Keccak256(Ed25519CertDomain, pubKeyBytes)
+}
+
 func (k *KeyExtraInfo) GetData(data interface{}) error {
 	marshaled, err := json.Marshal(k.Data)
 	if err != nil {
@@ -34,6 +34,8 @@ type Media struct {
 	MediaType MediaType `json:"T"`
 	MediaData MediaData `json:"D,omitempty"`
 
+	Thumbnails []*MediaThumbnail `json:"t,omitempty"`
+
 	Buf []byte `json:"-"`
 }
 
@@ -265,6 +267,25 @@ func (m *Media) SetSyncInfo(theSyncInfo SyncInfo) error {
 	return nil
 }
 
+/*
+Thumbnail gets the smallest thumbnail with the max-width / max-height at least size.
+
+Returns nil if there is no such thumbnail (the original image is the most suitable).
+*/
+func (m *Media) Thumbnail(size int) *MediaThumbnail {
+	var theThumbnail *MediaThumbnail
+	for _, thumbnail := range m.Thumbnails {
+		if int(thumbnail.Width) < size && int(thumbnail.Height) < size {
+			continue
+		}
+		if theThumbnail == nil || thumbnail.Width < theThumbnail.Width {
+			theThumbnail = thumbnail
+		}
+	}
+
+	return theThumbnail
+}
+
 // get buf
 func (m *Media) GetBuf() error {
 
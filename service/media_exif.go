@@ -0,0 +1,202 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+)
+
+// jpeg markers
+const (
+	jpegMarkerSOI   = 0xd8
+	jpegMarkerEOI   = 0xd9
+	jpegMarkerSOS   = 0xda
+	jpegMarkerAPP1  = 0xe1 // exif / xmp
+	jpegMarkerAPP13 = 0xed // iptc
+	jpegMarkerCOM   = 0xfe
+)
+
+const exifTagOrientation = 0x0112
+
+/*
+StripJPEGMetadata removes the exif / xmp (APP1), iptc (APP13) and comment segments from the jpeg without re-encoding.
+
+The exif contains the GPS-location, the device and the taken-time of the photo,
+which should not be broadcast with the media.
+*/
+func StripJPEGMetadata(theBytes []byte) ([]byte, error) {
+	if len(theBytes) < 2 || theBytes[0] != 0xff || theBytes[1] != jpegMarkerSOI {
+		return nil, ErrInvalidData
+	}
+
+	buf := &bytes.Buffer{}
+	buf.Write(theBytes[:2])
+
+	for p := theBytes[2:]; ; {
+		// fill-bytes
+		for len(p) > 1 && p[0] == 0xff && p[1] == 0xff {
+			p = p[1:]
+		}
+		if len(p) < 2 || p[0] != 0xff {
+			return nil, ErrInvalidData
+		}
+
+		marker := p[1]
+		if marker == jpegMarkerEOI {
+			buf.Write(p)
+			return buf.Bytes(), nil
+		}
+
+		if len(p) < 4 {
+			return nil, ErrInvalidData
+		}
+		lenSegment := int(binary.BigEndian.Uint16(p[2:4])) + 2
+		if lenSegment < 4 || lenSegment > len(p) {
+			return nil, ErrInvalidData
+		}
+
+		// start of scan: the rest is the entropy-coded data.
+		if marker == jpegMarkerSOS {
+			buf.Write(p)
+			return buf.Bytes(), nil
+		}
+
+		if marker != jpegMarkerAPP1 && marker != jpegMarkerAPP13 && marker != jpegMarkerCOM {
+			buf.Write(p[:lenSegment])
+		}
+
+		p = p[lenSegment:]
+	}
+}
+
+/*
+JPEGOrientation gets the exif-orientation (1-8) of the jpeg.
+
+Returns 1 (normal) if there is no orientation in the exif.
+*/
+func JPEGOrientation(theBytes []byte) int {
+	if len(theBytes) < 2 || theBytes[0] != 0xff || theBytes[1] != jpegMarkerSOI {
+		return 1
+	}
+
+	for p := theBytes[2:]; len(p) >= 4 && p[0] == 0xff; {
+		marker := p[1]
+		if marker == jpegMarkerSOS || marker == jpegMarkerEOI {
+			break
+		}
+
+		lenSegment := int(binary.BigEndian.Uint16(p[2:4])) + 2
+		if lenSegment < 4 || lenSegment > len(p) {
+			break
+		}
+
+		if marker == jpegMarkerAPP1 && bytes.HasPrefix(p[4:lenSegment], []byte("Exif\x00\x00")) {
+			return exifOrientation(p[10:lenSegment])
+		}
+
+		p = p[lenSegment:]
+	}
+
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifdOffset := int(order.Uint32(tiff[4:8]))
+	if ifdOffset+2 > len(tiff) {
+		return 1
+	}
+
+	nEntry := int(order.Uint16(tiff[ifdOffset:]))
+	for i, p := 0, ifdOffset+2; i < nEntry && p+12 <= len(tiff); i, p = i+1, p+12 {
+		if order.Uint16(tiff[p:]) != exifTagOrientation {
+			continue
+		}
+
+		orientation := int(order.Uint16(tiff[p+8:]))
+		if orientation < 1 || orientation > 8 {
+			return 1
+		}
+		return orientation
+	}
+
+	return 1
+}
+
+/*
+ApplyOrientation rotates / flips the image based on the exif-orientation,
+so that the image is displayed correctly after the exif is removed.
+*/
+func ApplyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+
+	src := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	srcX, srcY := 0, 0
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			switch orientation {
+			case 2: // flip horizontal
+				srcX, srcY = width-1-x, y
+			case 3: // rotate 180
+				srcX, srcY = width-1-x, height-1-y
+			case 4: // flip vertical
+				srcX, srcY = x, height-1-y
+			case 5: // transpose
+				srcX, srcY = y, x
+			case 6: // rotate 90 cw
+				srcX, srcY = y, height-1-x
+			case 7: // transverse
+				srcX, srcY = width-1-y, height-1-x
+			case 8: // rotate 90 ccw
+				srcX, srcY = width-1-y, x
+			}
+			dst.SetRGBA(x, y, src.RGBAAt(srcX, srcY))
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,265 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"reflect"
+	"testing"
+)
+
+func tNewJPEG(width int, height int, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, color.RGBA{uint8(x * 255 / width), uint8(y * 255 / height), 0, 255})
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	jpeg.Encode(buf, img, nil)
+	theBytes := buf.Bytes()
+
+	// exif: tiff-header (II) + ifd0 with 1 entry (orientation)
+	tiff := []byte{'I', 'I', 42, 0, 8, 0, 0, 0, 1, 0}
+	entry := make([]byte, 12)
+	binary.LittleEndian.PutUint16(entry[0:], exifTagOrientation)
+	binary.LittleEndian.PutUint16(entry[2:], 3) // short
+	binary.LittleEndian.PutUint32(entry[4:], 1)
+	binary.LittleEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xff, jpegMarkerAPP1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(app1)+2))
+	segment = append(segment, app1...)
+
+	com := []byte{0xff, jpegMarkerCOM, 0, 6, 'g', 'p', 's', '!'}
+
+	newBytes := append([]byte{}, theBytes[:2]...)
+	newBytes = append(newBytes, segment...)
+	newBytes = append(newBytes, com...)
+	newBytes = append(newBytes, theBytes[2:]...)
+
+	return newBytes
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	// define test-structure
+	type args struct {
+		theBytes []byte
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{args: args{theBytes: tNewJPEG(4, 2, 6)}, want: 6},
+		{args: args{theBytes: tNewJPEG(4, 2, 1)}, want: 1},
+		{args: args{theBytes: []byte("not-jpeg")}, want: 1},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JPEGOrientation(tt.args.theBytes); got != tt.want {
+				t.Errorf("JPEGOrientation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripJPEGMetadata(t *testing.T) {
+	theBytes := tNewJPEG(4, 2, 6)
+
+	got, err := StripJPEGMetadata(theBytes)
+	if err != nil {
+		t.Errorf("StripJPEGMetadata() error = %v", err)
+		return
+	}
+
+	if bytes.Contains(got, []byte("Exif")) || bytes.Contains(got, []byte("gps!")) {
+		t.Errorf("StripJPEGMetadata() still contains the metadata")
+	}
+	if len(got) >= len(theBytes) {
+		t.Errorf("StripJPEGMetadata() len = %v, want < %v", len(got), len(theBytes))
+	}
+
+	_, err = jpeg.Decode(bytes.NewReader(got))
+	if err != nil {
+		t.Errorf("StripJPEGMetadata() unable to decode: %v", err)
+	}
+
+	_, err = StripJPEGMetadata([]byte("not-jpeg"))
+	if err == nil {
+		t.Errorf("StripJPEGMetadata() expected error on non-jpeg")
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// 2x1: red, green
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	red := color.RGBA{255, 0, 0, 255}
+	green := color.RGBA{0, 255, 0, 255}
+	img.SetRGBA(0, 0, red)
+	img.SetRGBA(1, 0, green)
+
+	// define test-structure
+	type args struct {
+		orientation int
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name string
+		args args
+		want []color.RGBA
+		size image.Point
+	}{
+		{args: args{orientation: 1}, want: []color.RGBA{red, green}, size: image.Point{2, 1}},
+		{args: args{orientation: 2}, want: []color.RGBA{green, red}, size: image.Point{2, 1}},
+		{args: args{orientation: 6}, want: []color.RGBA{red, green}, size: image.Point{1, 2}},
+		{args: args{orientation: 8}, want: []color.RGBA{green, red}, size: image.Point{1, 2}},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ApplyOrientation(img, tt.args.orientation)
+			if got.Bounds().Size() != tt.size {
+				t.Errorf("ApplyOrientation() size = %v, want %v", got.Bounds().Size(), tt.size)
+				return
+			}
+			pixels := []color.RGBA{}
+			for y := 0; y < tt.size.Y; y++ {
+				for x := 0; x < tt.size.X; x++ {
+					pixels = append(pixels, color.RGBAModel.Convert(got.At(x, y)).(color.RGBA))
+				}
+			}
+			if !reflect.DeepEqual(pixels, tt.want) {
+				t.Errorf("ApplyOrientation() = %v, want %v", pixels, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeImage(t *testing.T) {
+	// define test-structure
+	type args struct {
+		theBytes []byte
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name string
+		args args
+		want *MediaDataJPEG
+	}{
+		{args: args{theBytes: tNewJPEG(40, 20, 1)}, want: &MediaDataJPEG{Width: 40, Height: 20}},
+		{args: args{theBytes: tNewJPEG(40, 20, 6)}, want: &MediaDataJPEG{Width: 20, Height: 40}},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, gotData, gotBytes, err := NormalizeImage(tt.args.theBytes)
+			if err != nil {
+				t.Errorf("NormalizeImage() error = %v", err)
+				return
+			}
+			if gotType != MediaTypeJPEG {
+				t.Errorf("NormalizeImage() type = %v, want %v", gotType, MediaTypeJPEG)
+			}
+			if !reflect.DeepEqual(gotData, tt.want) {
+				t.Errorf("NormalizeImage() data = %v, want %v", gotData, tt.want)
+			}
+			if bytes.Contains(gotBytes, []byte("Exif")) {
+				t.Errorf("NormalizeImage() still contains the exif")
+			}
+		})
+	}
+}
+
+func TestNewImageThumbnails(t *testing.T) {
+	// define test-structure
+	type args struct {
+		theBytes []byte
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name string
+		args args
+		want [][2]uint16
+	}{
+		{args: args{theBytes: tNewJPEG(800, 400, 1)}, want: [][2]uint16{{160, 80}, {320, 160}, {640, 320}}},
+		{args: args{theBytes: tNewJPEG(200, 100, 1)}, want: [][2]uint16{{160, 80}}},
+		{args: args{theBytes: tNewJPEG(100, 50, 1)}, want: [][2]uint16{}},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewImageThumbnails(tt.args.theBytes)
+			if err != nil {
+				t.Errorf("NewImageThumbnails() error = %v", err)
+				return
+			}
+			gotSizes := make([][2]uint16, len(got))
+			for i, each := range got {
+				gotSizes[i] = [2]uint16{each.Width, each.Height}
+			}
+			if !reflect.DeepEqual(gotSizes, tt.want) {
+				t.Errorf("NewImageThumbnails() = %v, want %v", gotSizes, tt.want)
+			}
+		})
+	}
+}
+
+func TestMedia_Thumbnail(t *testing.T) {
+	small := &MediaThumbnail{Width: 160, Height: 80}
+	medium := &MediaThumbnail{Width: 320, Height: 160}
+	m := &Media{Thumbnails: []*MediaThumbnail{small, medium}}
+
+	// prepare test-cases
+	tests := []struct {
+		name string
+		size int
+		want *MediaThumbnail
+	}{
+		{size: 100, want: small},
+		{size: 160, want: small},
+		{size: 200, want: medium},
+		{size: 1000, want: nil},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Thumbnail(tt.size); got != tt.want {
+				t.Errorf("Media.Thumbnail() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
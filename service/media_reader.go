@@ -0,0 +1,155 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"io"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+/*
+MediaReader reads the media block-by-block.
+
+All the blocks except the last one contain NByteInBlock bytes,
+so only the block containing the current offset is kept in memory.
+*/
+type MediaReader struct {
+	blockInfo *BlockInfo
+
+	size   int64
+	offset int64
+
+	blockID uint32
+	buf     []byte
+}
+
+func (pm *BaseProtocolManager) GetMediaReader(mediaID *types.PttID) (*Media, *MediaReader, error) {
+	media := NewEmptyMedia()
+	pm.SetMediaDB(media)
+	media.SetID(mediaID)
+
+	err := media.GetByID(false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := media.NewReader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return media, r, nil
+}
+
+func (m *Media) NewReader() (*MediaReader, error) {
+	blockInfo := m.GetBlockInfo()
+	if blockInfo == nil {
+		return nil, ErrInvalidBlock
+	}
+	if !blockInfo.IsAllGood {
+		return nil, ErrInvalidBlock
+	}
+	setBlockInfoDB := m.SetBlockInfoDB()
+	setBlockInfoDB(blockInfo, m.ID)
+
+	return newMediaReader(blockInfo)
+}
+
+func newMediaReader(blockInfo *BlockInfo) (*MediaReader, error) {
+	r := &MediaReader{blockInfo: blockInfo}
+	if blockInfo.NBlock == 0 {
+		return r, nil
+	}
+
+	// size: the full blocks + the last block
+	lastBlockID := uint32(blockInfo.NBlock - 1)
+	buf, err := GetMediaBlockBuf(blockInfo, lastBlockID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.size = int64(lastBlockID)*NByteInBlock + int64(len(buf))
+	r.blockID = lastBlockID
+	r.buf = buf
+
+	return r, nil
+}
+
+func (r *MediaReader) Size() int64 {
+	return r.size
+}
+
+func (r *MediaReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	blockID := mediaOffsetToBlockID(r.offset, r.blockInfo.NBlock)
+	if r.buf == nil || blockID != r.blockID {
+		buf, err := GetMediaBlockBuf(r.blockInfo, blockID)
+		if err != nil {
+			return 0, err
+		}
+		r.blockID = blockID
+		r.buf = buf
+	}
+
+	offsetInBlock := r.offset - int64(blockID)*NByteInBlock
+	if offsetInBlock >= int64(len(r.buf)) {
+		return 0, ErrInvalidBlock
+	}
+
+	n := copy(p, r.buf[offsetInBlock:])
+	r.offset += int64(n)
+
+	return n, nil
+}
+
+func (r *MediaReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, ErrInvalidData
+	}
+
+	if offset < 0 {
+		return 0, ErrInvalidData
+	}
+
+	r.offset = offset
+
+	return offset, nil
+}
+
+/*
+mediaOffsetToBlockID returns the block containing the offset.
+
+The last block may contain NByteInBlock+1 bytes (see SplitMediaBlocksFromReader).
+*/
+func mediaOffsetToBlockID(offset int64, nBlock int) uint32 {
+	blockID := offset / NByteInBlock
+	if nBlock > 0 && blockID >= int64(nBlock) {
+		blockID = int64(nBlock - 1)
+	}
+
+	return uint32(blockID)
+}
@@ -0,0 +1,135 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+func TestMediaReader(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	// 3 blocks with the last-char squeezed to the last block.
+	buf := make([]byte, 3*NByteInBlock+1)
+	for i := range buf {
+		buf[i] = byte(i % 251)
+	}
+
+	blockInfoID, _ := types.NewPttID()
+	blockInfo, _ := NewBlockInfo(blockInfoID, make([][][]byte, 3), nil, tUserIDMe)
+	blockInfo.SetDB(tDBOplog, tDBLock, []byte(".tmbk"), tDefaultID, nil)
+	blockInfo.SetIsAllGood()
+
+	for blockID, pBuf := 0, buf; len(pBuf) != 0; blockID++ {
+		lenBuf := NByteInBlock
+		if len(pBuf) == NByteInBlock+1 {
+			lenBuf = len(pBuf)
+		}
+		half := (lenBuf + 1) / 2
+		scrambledBufs, _ := ScrambleBuf([][]byte{pBuf[:half], pBuf[half:lenBuf]})
+		for subBlockID, scrambledBuf := range scrambledBufs {
+			block, _ := NewBlock(uint32(blockID), uint8(subBlockID), scrambledBuf)
+			blockInfo.SetBlockDB(block)
+			block.Save()
+		}
+		pBuf = pBuf[lenBuf:]
+	}
+
+	r, err := newMediaReader(blockInfo)
+	if err != nil {
+		t.Errorf("newMediaReader: e: %v", err)
+		return
+	}
+	if r.Size() != int64(len(buf)) {
+		t.Errorf("Size() = %v, want %v", r.Size(), len(buf))
+	}
+
+	// define test-structure
+	type args struct {
+		offset int64
+		length int64
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name string
+		args args
+		want []byte
+	}{
+		{name: "all", args: args{offset: 0, length: int64(len(buf))}, want: buf},
+		{name: "cross-block", args: args{offset: NByteInBlock - 5, length: 10}, want: buf[NByteInBlock-5 : NByteInBlock+5]},
+		{name: "last-block", args: args{offset: 3*NByteInBlock - 1, length: 2}, want: buf[3*NByteInBlock-1:]},
+		{name: "beyond", args: args{offset: int64(len(buf)) + 10, length: 10}, want: []byte{}},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Seek(tt.args.offset, io.SeekStart)
+			if err != nil {
+				t.Errorf("Seek() error = %v", err)
+				return
+			}
+			got, err := ioutil.ReadAll(io.LimitReader(r, tt.args.length))
+			if err != nil {
+				t.Errorf("Read() error = %v", err)
+				return
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Read() len = %v, want len %v", len(got), len(tt.want))
+			}
+		})
+	}
+
+	// teardown test
+}
+
+func Test_mediaOffsetToBlockID(t *testing.T) {
+	// define test-structure
+	type args struct {
+		offset int64
+		nBlock int
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name string
+		args args
+		want uint32
+	}{
+		{args: args{offset: 0, nBlock: 2}, want: 0},
+		{args: args{offset: NByteInBlock - 1, nBlock: 2}, want: 0},
+		{args: args{offset: NByteInBlock, nBlock: 2}, want: 1},
+		{args: args{offset: 2 * NByteInBlock, nBlock: 2}, want: 1},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mediaOffsetToBlockID(tt.args.offset, tt.args.nBlock); got != tt.want {
+				t.Errorf("mediaOffsetToBlockID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@
 
 package service
 
+import "github.com/ailabstw/go-pttai/common/types"
+
 type MediaType uint8
 
 const (
@@ -45,3 +47,15 @@ type MediaDataGIF struct {
 type MediaDataFile struct {
 	Filename []byte `json:"f"`
 }
+
+/*
+MediaThumbnail refers to the thumbnail of the image.
+
+The thumbnail is a separated media (with its own blocks),
+so that the peers can get the thumbnail without the original image.
+*/
+type MediaThumbnail struct {
+	ID     *types.PttID `json:"ID"`
+	Width  uint16       `json:"W"`
+	Height uint16       `json:"H"`
+}
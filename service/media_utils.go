@@ -24,6 +24,7 @@ import (
 	"github.com/ailabstw/go-pttai/common"
 	"github.com/ailabstw/go-pttai/log"
 	"github.com/nfnt/resize"
+	_ "golang.org/x/image/webp"
 )
 
 func NormalizeImage(theBytes []byte) (MediaType, interface{}, []byte, error) {
@@ -46,16 +47,32 @@ func NormalizeImage(theBytes []byte) (MediaType, interface{}, []byte, error) {
 		return MediaTypeGIF, &MediaDataGIF{Width: uint16(width), Height: uint16(height)}, theBytes, nil
 	}
 
+	// the orientation is lost after stripping the exif, rotate the image first.
+	orientation := 1
+	if format == "jpeg" {
+		orientation = JPEGOrientation(theBytes)
+		img = ApplyOrientation(img, orientation)
+
+		bounds = img.Bounds()
+		width = bounds.Dx()
+		height = bounds.Dy()
+	}
+
 	// good width and height
 	if width <= maxWidth && height <= maxHeight {
-		if format == "png" {
-			newBytes, err := imgToJPEG(img)
-			if err != nil {
-				return MediaTypeJPEG, nil, nil, err
+		if format == "jpeg" && orientation == 1 {
+			newBytes, err := StripJPEGMetadata(theBytes)
+			if err == nil {
+				return MediaTypeJPEG, &MediaDataJPEG{Width: uint16(width), Height: uint16(height)}, newBytes, nil
 			}
-			theBytes = newBytes
 		}
-		return MediaTypeJPEG, &MediaDataJPEG{Width: uint16(width), Height: uint16(height)}, theBytes, nil
+
+		// png / webp / rotated jpeg: to jpeg
+		newBytes, err := imgToJPEG(img)
+		if err != nil {
+			return MediaTypeJPEG, nil, nil, err
+		}
+		return MediaTypeJPEG, &MediaDataJPEG{Width: uint16(width), Height: uint16(height)}, newBytes, nil
 	}
 
 	// normalize width / height
@@ -71,6 +88,55 @@ func NormalizeImage(theBytes []byte) (MediaType, interface{}, []byte, error) {
 	return MediaTypeJPEG, &MediaDataJPEG{Width: uint16(normalizedWidth), Height: uint16(normalizedHeight)}, newBytes, nil
 }
 
+type ImageThumbnail struct {
+	Width  uint16
+	Height uint16
+	Bytes  []byte
+}
+
+/*
+NewImageThumbnails creates the jpeg-thumbnails of the image for each of the ThumbnailSizes smaller than the image.
+
+The thumbnails are re-encoded, so there is no exif in the thumbnails.
+*/
+func NewImageThumbnails(theBytes []byte) ([]*ImageThumbnail, error) {
+	reader := bytes.NewReader(theBytes)
+	img, format, err := image.Decode(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "jpeg" {
+		img = ApplyOrientation(img, JPEGOrientation(theBytes))
+	}
+
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+
+	thumbnails := make([]*ImageThumbnail, 0, len(ThumbnailSizes))
+	for _, size := range ThumbnailSizes {
+		if width <= size && height <= size {
+			break
+		}
+
+		normalizedWidth, normalizedHeight := normalizeSize(width, height, size, size)
+		newImage := resize.Resize(uint(normalizedWidth), uint(normalizedHeight), img, resize.Lanczos3)
+		newBytes, err := imgToJPEG(newImage)
+		if err != nil {
+			return nil, err
+		}
+
+		thumbnails = append(thumbnails, &ImageThumbnail{
+			Width:  uint16(normalizedWidth),
+			Height: uint16(normalizedHeight),
+			Bytes:  newBytes,
+		})
+	}
+
+	return thumbnails, nil
+}
+
 func imgToJPEG(img image.Image) ([]byte, error) {
 	buffer := &bytes.Buffer{}
 	err := jpeg.Encode(buffer, img, nil)
@@ -33,6 +33,42 @@ type Member struct {
 	TransferToID *types.PttID `json:"t,omitempty"`
 
 	SyncInfo *SyncPersonInfo `json:"s,omitempty"`
+
+	RoleInfo *MemberRoleInfo `json:"r,omitempty"`
+}
+
+/*
+MemberRole is the role of the member in the entity.
+The moderators are able to delete the objects of the others and to add / delete the members,
+but not able to update the title, delete the entity or add the masters.
+*/
+type MemberRole uint8
+
+const (
+	MemberRoleMember MemberRole = iota
+	MemberRoleModerator
+	NMemberRole
+)
+
+var (
+	memberRoleStr = map[MemberRole]string{
+		MemberRoleMember:    "member",
+		MemberRoleModerator: "moderator",
+	}
+)
+
+func (r MemberRole) String() string {
+	return memberRoleStr[r]
+}
+
+func (r MemberRole) IsValid() bool {
+	return r < NMemberRole
+}
+
+type MemberRoleInfo struct {
+	Role     MemberRole      `json:"R"`
+	UpdateTS types.Timestamp `json:"UT"`
+	LogID    *types.PttID    `json:"l"`
 }
 
 func NewMember(
@@ -176,6 +212,13 @@ func (m *Member) Unmarshal(data []byte) error {
 	return nil
 }
 
+func (m *Member) GetRole() MemberRole {
+	if m.RoleInfo == nil {
+		return MemberRoleMember
+	}
+	return m.RoleInfo.Role
+}
+
 /**********
  * Sync Info
  **********/
@@ -29,7 +29,7 @@ func (o *MemberOplog) GetBaseOplog() *BaseOplog {
 	return o.BaseOplog
 }
 
-func NewMemberOplog(keyID *types.PttID, ts types.Timestamp, doerID *types.PttID, op OpType, opData OpData, db *pttdb.LDBBatch, entityID *types.PttID, dbLock *types.LockMap) (*MemberOplog, error) {
+func NewMemberOplog(keyID *types.PttID, ts types.Timestamp, doerID *types.PttID, op OpType, opData OpData, db pttdb.IndexBatch, entityID *types.PttID, dbLock *types.LockMap) (*MemberOplog, error) {
 
 	oplog, err := NewOplog(keyID, ts, doerID, op, opData, db, entityID, DBMemberOplogPrefix, DBMemberIdxOplogPrefix, nil, dbLock)
 	if err != nil {
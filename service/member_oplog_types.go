@@ -16,11 +16,15 @@
 
 package service
 
+import "github.com/ailabstw/go-pttai/p2p/discover"
+
 const (
 	_ OpType = iota
 	MemberOpTypeAddMember
 	MemberOpTypeDeleteMember
 	MemberOpTypeMigrateMember
+	MemberOpTypeSetMemberRole
+	MemberOpTypeBanMember
 )
 
 type MemberOpAddMember struct {
@@ -28,3 +32,12 @@ type MemberOpAddMember struct {
 
 type MemberOpDeleteMember struct {
 }
+
+type MemberOpSetMemberRole struct {
+	Role MemberRole `json:"R"`
+}
+
+type MemberOpBanMember struct {
+	NodeIDs  []*discover.NodeID `json:"N,omitempty"`
+	IsBanned bool               `json:"B"`
+}
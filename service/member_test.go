@@ -0,0 +1,72 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+func TestMember_GetRole(t *testing.T) {
+	// prepare test-cases
+	tests := []struct {
+		name      string
+		m         *Member
+		want      MemberRole
+		wantStr   string
+		wantValid bool
+	}{
+		{
+			name:      "no role",
+			m:         NewEmptyMember(),
+			want:      MemberRoleMember,
+			wantStr:   "member",
+			wantValid: true,
+		},
+		{
+			name:      "moderator",
+			m:         &Member{BaseObject: &BaseObject{}, RoleInfo: &MemberRoleInfo{Role: MemberRoleModerator, UpdateTS: types.Timestamp{Ts: 100}}},
+			want:      MemberRoleModerator,
+			wantStr:   "moderator",
+			wantValid: true,
+		},
+		{
+			name:      "invalid",
+			m:         &Member{BaseObject: &BaseObject{}, RoleInfo: &MemberRoleInfo{Role: NMemberRole}},
+			want:      NMemberRole,
+			wantStr:   "",
+			wantValid: false,
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.m.GetRole()
+			if got != tt.want {
+				t.Errorf("Member.GetRole() = %v, want %v", got, tt.want)
+			}
+			if got.String() != tt.wantStr {
+				t.Errorf("MemberRole.String() = %v, want %v", got.String(), tt.wantStr)
+			}
+			if got.IsValid() != tt.wantValid {
+				t.Errorf("MemberRole.IsValid() = %v, want %v", got.IsValid(), tt.wantValid)
+			}
+		})
+	}
+}
@@ -25,9 +25,11 @@ import (
 	pttcommon "github.com/ailabstw/go-pttai/common"
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/p2p/discover"
 	"github.com/ailabstw/go-pttai/pttdb"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
@@ -44,7 +46,7 @@ type Merkle struct {
 	dbMerkleToUpdatePrefixWithID []byte
 	dbMerkleUpdatingPrefixWithID []byte
 	PrefixID                     *types.PttID
-	db                           *pttdb.LDBBatch
+	db                           pttdb.IndexBatch
 	LastGenerateTS               types.Timestamp
 	BusyGenerateTS               types.Timestamp
 	LastSyncTS                   types.Timestamp
@@ -60,10 +62,15 @@ type Merkle struct {
 	lockToUpdateTS sync.Mutex
 	toUpdateTS     map[int64]bool
 
+	toGenerate chan struct{}
+
+	lockSyncStartTime sync.Mutex
+	syncStartTime     map[discover.NodeID]time.Time
+
 	Name string
 }
 
-func NewMerkle(dbOplogPrefix []byte, dbMerklePrefix []byte, prefixID *types.PttID, db *pttdb.LDBBatch, name string) (*Merkle, error) {
+func NewMerkle(dbOplogPrefix []byte, dbMerklePrefix []byte, prefixID *types.PttID, db pttdb.IndexBatch, name string) (*Merkle, error) {
 
 	prefixIDBytes := prefixID[:]
 
@@ -95,6 +102,10 @@ func NewMerkle(dbOplogPrefix []byte, dbMerklePrefix []byte, prefixID *types.PttI
 
 		forceSync: make(chan struct{}),
 
+		toGenerate: make(chan struct{}, 1),
+
+		syncStartTime: make(map[discover.NodeID]time.Time),
+
 		Name: name,
 	}
 
@@ -528,6 +539,27 @@ func (m *Merkle) GetMerkleTreeList(ts types.Timestamp, isNow bool) ([]*MerkleNod
 	return merkleTreeList, nowMerkleTreeList, nil
 }
 
+/*
+GetMerkleRoot digests the merkle-tree-list until ts (including the now-level).
+The merkles with the same oplogs have the same root.
+*/
+func (m *Merkle) GetMerkleRoot(ts types.Timestamp) ([]byte, error) {
+	merkleTreeList, nowMerkleTreeList, err := m.GetMerkleTreeList(ts, true)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([][]byte, 0, len(merkleTreeList)+len(nowMerkleTreeList))
+	for _, node := range merkleTreeList {
+		addrs = append(addrs, node.Addr)
+	}
+	for _, node := range nowMerkleTreeList {
+		addrs = append(addrs, node.Addr)
+	}
+
+	return crypto.Keccak256(addrs...), nil
+}
+
 func (m *Merkle) GetMerkleTreeListByLevel(level MerkleTreeLevel, ts types.Timestamp, nextTS types.Timestamp) ([]*MerkleNode, error) {
 	return m.GetMerkleTreeListCore(level, ts, nextTS)
 }
@@ -708,6 +740,8 @@ func (m *Merkle) SetUpdateTS(ts types.Timestamp) error {
 
 	m.toUpdateTS[hrTS.Ts] = true
 
+	m.notifyToGenerate()
+
 	return nil
 }
 
@@ -723,6 +757,8 @@ func (m *Merkle) SetUpdateTS2(ts types.Timestamp, ts2 types.Timestamp) error {
 
 	log.Debug("SetUpdateTS2: to key", "hrTS", hrTS, "hrTS2", hrTS2, "merkle", m.Name)
 
+	m.notifyToGenerate()
+
 	key := m.MarshalToUpdateTSKey(hrTS)
 
 	m.db.DB().Put(key, pttdb.ValueTrue)
@@ -865,6 +901,46 @@ func (m *Merkle) ForceSync() chan struct{} {
 	return m.forceSync
 }
 
+/*
+ToGenerate notifies that the merkle-tree is updated and is to be generated (live merkle-tree).
+*/
+func (m *Merkle) ToGenerate() chan struct{} {
+	return m.toGenerate
+}
+
+func (m *Merkle) notifyToGenerate() {
+	select {
+	case m.toGenerate <- struct{}{}:
+	default:
+	}
+}
+
+/*
+SetSyncStartTime records the time when I initiate sync-oplog with the peer (for the sync-latency).
+*/
+func (m *Merkle) SetSyncStartTime(peer *PttPeer) {
+	m.lockSyncStartTime.Lock()
+	defer m.lockSyncStartTime.Unlock()
+
+	m.syncStartTime[*peer.GetID()] = time.Now()
+}
+
+/*
+PopSyncStartTime gets and removes the time when I initiated sync-oplog with the peer.
+*/
+func (m *Merkle) PopSyncStartTime(peer *PttPeer) (time.Time, bool) {
+	m.lockSyncStartTime.Lock()
+	defer m.lockSyncStartTime.Unlock()
+
+	nodeID := *peer.GetID()
+	startTime, ok := m.syncStartTime[nodeID]
+	if ok {
+		delete(m.syncStartTime, nodeID)
+	}
+
+	return startTime, ok
+}
+
 func (m *Merkle) TryForceSync(pm ProtocolManager) error {
 	err := m.trySetBusyForceSync()
 	log.Debug("TryForceSync: after TrySetBusyForceSync", "e", err, "merkle", m.Name, "entity", pm.Entity().IDString())
@@ -0,0 +1,127 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"bytes"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+/*
+MerkleRange is the live digest of the now-level merkle-nodes (the oplogs) within the hour starting from StartTS.
+
+The digests are computed from the now-level nodes directly, not from the generated hr-level nodes,
+so that the peers are able to compare the recent hours without waiting for the merkle-tree generation.
+The initiator of sync-oplog sends the digests of the recent hours, and the identical hours are skipped in sync-oplog-ack.
+*/
+type MerkleRange struct {
+	StartTS   types.Timestamp `json:"S"`
+	Addr      []byte          `json:"A"`
+	NChildren uint32          `json:"N"`
+}
+
+func NewMerkleRange(startTS types.Timestamp, nodes []*MerkleNode) *MerkleRange {
+	addrs := make([][]byte, len(nodes))
+	for i, node := range nodes {
+		addrs[i] = node.Addr
+	}
+
+	return &MerkleRange{
+		StartTS:   startTS,
+		Addr:      crypto.Keccak256(addrs...),
+		NChildren: uint32(len(nodes)),
+	}
+}
+
+/*
+GetMerkleRangeList gets the digests of the non-empty hours from the hour of startTS to endTS.
+*/
+func (m *Merkle) GetMerkleRangeList(startTS types.Timestamp, endTS types.Timestamp) ([]*MerkleRange, error) {
+	hourTS, _ := startTS.ToHRTimestamp()
+
+	ranges := make([]*MerkleRange, 0)
+	var nextHourTS types.Timestamp
+	for ; hourTS.IsLess(endTS); hourTS = nextHourTS {
+		nextHourTS = hourTS.NextHourTS()
+		if endTS.IsLess(nextHourTS) {
+			nextHourTS = endTS
+		}
+
+		nodes, err := m.GetMerkleTreeListByLevel(MerkleTreeLevelNow, hourTS, nextHourTS)
+		if err != nil {
+			return nil, err
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+
+		ranges = append(ranges, NewMerkleRange(hourTS, nodes))
+	}
+
+	return ranges, nil
+}
+
+/*
+MatchMerkleRanges returns the start-ts of the hours with the identical digests in both my-ranges and their-ranges.
+*/
+func MatchMerkleRanges(myRanges []*MerkleRange, theirRanges []*MerkleRange) []types.Timestamp {
+	theirRangeMap := make(map[types.Timestamp]*MerkleRange)
+	for _, theirRange := range theirRanges {
+		theirRangeMap[theirRange.StartTS] = theirRange
+	}
+
+	matchedTSs := make([]types.Timestamp, 0, len(myRanges))
+	for _, myRange := range myRanges {
+		theirRange, ok := theirRangeMap[myRange.StartTS]
+		if !ok {
+			continue
+		}
+		if myRange.NChildren != theirRange.NChildren || !bytes.Equal(myRange.Addr, theirRange.Addr) {
+			continue
+		}
+		matchedTSs = append(matchedTSs, myRange.StartTS)
+	}
+
+	return matchedTSs
+}
+
+/*
+filterMerkleNodesBySkipHours removes the nodes within the skipped hours.
+*/
+func filterMerkleNodesBySkipHours(nodes []*MerkleNode, skipHourTSs []types.Timestamp) []*MerkleNode {
+	if len(skipHourTSs) == 0 {
+		return nodes
+	}
+
+	skipHours := make(map[types.Timestamp]bool)
+	for _, ts := range skipHourTSs {
+		skipHours[ts] = true
+	}
+
+	newNodes := make([]*MerkleNode, 0, len(nodes))
+	for _, node := range nodes {
+		hourTS, _ := node.UpdateTS.ToHRTimestamp()
+		if skipHours[hourTS] {
+			continue
+		}
+		newNodes = append(newNodes, node)
+	}
+
+	return newNodes
+}
@@ -0,0 +1,164 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+func TestMatchMerkleRanges(t *testing.T) {
+	// setup test
+	hr0 := types.Timestamp{Ts: 3600}
+	hr1 := types.Timestamp{Ts: 7200}
+	hr2 := types.Timestamp{Ts: 10800}
+
+	node0 := &MerkleNode{Addr: []byte{1}, UpdateTS: types.Timestamp{Ts: 3601}}
+	node1 := &MerkleNode{Addr: []byte{2}, UpdateTS: types.Timestamp{Ts: 7201}}
+	node2 := &MerkleNode{Addr: []byte{3}, UpdateTS: types.Timestamp{Ts: 7202}}
+	node3 := &MerkleNode{Addr: []byte{4}, UpdateTS: types.Timestamp{Ts: 10801}}
+
+	myRanges := []*MerkleRange{
+		NewMerkleRange(hr0, []*MerkleNode{node0}),
+		NewMerkleRange(hr1, []*MerkleNode{node1, node2}),
+		NewMerkleRange(hr2, []*MerkleNode{node3}),
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name        string
+		theirRanges []*MerkleRange
+		want        []types.Timestamp
+	}{
+		{
+			name:        "empty",
+			theirRanges: nil,
+			want:        []types.Timestamp{},
+		},
+		{
+			name:        "identical",
+			theirRanges: myRanges,
+			want:        []types.Timestamp{hr0, hr1, hr2},
+		},
+		{
+			name: "diverged hr1",
+			theirRanges: []*MerkleRange{
+				NewMerkleRange(hr0, []*MerkleNode{node0}),
+				NewMerkleRange(hr1, []*MerkleNode{node1}),
+				NewMerkleRange(hr2, []*MerkleNode{node3}),
+			},
+			want: []types.Timestamp{hr0, hr2},
+		},
+		{
+			name: "missing hr0",
+			theirRanges: []*MerkleRange{
+				NewMerkleRange(hr1, []*MerkleNode{node1, node2}),
+			},
+			want: []types.Timestamp{hr1},
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchMerkleRanges(myRanges, tt.theirRanges); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchMerkleRanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_filterMerkleNodesBySkipHours(t *testing.T) {
+	// setup test
+	node0 := &MerkleNode{Addr: []byte{1}, UpdateTS: types.Timestamp{Ts: 3601}}
+	node1 := &MerkleNode{Addr: []byte{2}, UpdateTS: types.Timestamp{Ts: 7201}}
+	node2 := &MerkleNode{Addr: []byte{3}, UpdateTS: types.Timestamp{Ts: 10801}}
+
+	nodes := []*MerkleNode{node0, node1, node2}
+
+	// prepare test-cases
+	tests := []struct {
+		name        string
+		skipHourTSs []types.Timestamp
+		want        []*MerkleNode
+	}{
+		{
+			name:        "no skip",
+			skipHourTSs: nil,
+			want:        nodes,
+		},
+		{
+			name:        "skip hr1",
+			skipHourTSs: []types.Timestamp{types.Timestamp{Ts: 7200}},
+			want:        []*MerkleNode{node0, node2},
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterMerkleNodesBySkipHours(nodes, tt.skipHourTSs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterMerkleNodesBySkipHours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_syncOplogRangesStartTS(t *testing.T) {
+	// setup test
+	now := types.Timestamp{Ts: 1000000, NanoTs: 5}
+	minStartTS := types.Timestamp{Ts: (1000000 - int64(MaxSyncOplogRanges)*types.HRSeconds) / types.HRSeconds * types.HRSeconds}
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		startTS types.Timestamp
+		want    types.Timestamp
+	}{
+		{
+			name:    "zero",
+			startTS: types.ZeroTimestamp,
+			want:    minStartTS,
+		},
+		{
+			name:    "too early",
+			startTS: types.Timestamp{Ts: minStartTS.Ts - 1},
+			want:    minStartTS,
+		},
+		{
+			name:    "min",
+			startTS: minStartTS,
+			want:    minStartTS,
+		},
+		{
+			name:    "recent",
+			startTS: types.Timestamp{Ts: 999000},
+			want:    types.Timestamp{Ts: 999000},
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := syncOplogRangesStartTS(tt.startTS, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("syncOplogRangesStartTS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/pttdb"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func TestMerkle_SaveMerkleTree(t *testing.T) {
@@ -166,6 +167,53 @@ func TestMerkle_GetMerkleTreeList(t *testing.T) {
 	// teardown test
 }
 
+func TestMerkle_GetMerkleRoot(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	tDefaultMerkle.ResetUpdateTS()
+	tDefaultOplog.Save(true, tDefaultMerkle)
+	tDefaultOplog2.Save(true, tDefaultMerkle)
+	tDefaultMerkle.SaveMerkleTree(types.Timestamp{Ts: 1234567890, NanoTs: 0})
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		m       *Merkle
+		ts      types.Timestamp
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "now",
+			m:    tDefaultMerkle,
+			ts:   types.Timestamp{Ts: 1234567892, NanoTs: 0},
+			want: crypto.Keccak256(tDefaultMerkleNode1Now.Addr, tDefaultMerkleNode2Now.Addr),
+		},
+		{
+			name: "day",
+			m:    tDefaultMerkle,
+			ts:   types.Timestamp{Ts: 1234571490, NanoTs: 0}, // +3600
+			want: crypto.Keccak256(tDefaultMerkleNodeDay.Addr),
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.GetMerkleRoot(tt.ts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Merkle.GetMerkleRoot() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merkle.GetMerkleRoot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMerkle_LoadUpdatingTSList(t *testing.T) {
 	setupTest(t)
 	defer teardownTest(t)
@@ -16,19 +16,27 @@
 
 package service
 
-import "github.com/ailabstw/go-pttai/p2p"
+import (
+	"sync/atomic"
+
+	"github.com/ailabstw/go-pttai/p2p"
+)
 
 type MeteredMsgReadWriter interface {
 	p2p.MsgReadWriter
 
 	Version() uint
 	Init(version uint) error
+
+	SetPeerType(peerType PeerType)
 }
 
 type BaseMeteredMsgReadWriter struct {
 	p2p.MsgReadWriter
 
 	version uint
+
+	peerType int32
 }
 
 func NewBaseMeteredMsgReadWriter(rw p2p.MsgReadWriter, version uint) (MeteredMsgReadWriter, error) {
@@ -47,15 +55,35 @@ func (rw *BaseMeteredMsgReadWriter) Init(version uint) error {
 	return nil
 }
 
+/*
+SetPeerType sets the peer-type of the peer as the label of the metered msgs.
+*/
+func (rw *BaseMeteredMsgReadWriter) SetPeerType(peerType PeerType) {
+	atomic.StoreInt32(&rw.peerType, int32(peerType))
+}
+
+func (rw *BaseMeteredMsgReadWriter) PeerType() PeerType {
+	return PeerType(atomic.LoadInt32(&rw.peerType))
+}
+
 func (rw *BaseMeteredMsgReadWriter) ReadMsg() (p2p.Msg, error) {
 	msg, err := rw.MsgReadWriter.ReadMsg()
 	if err != nil {
 		return msg, err
 	}
 
+	markMsg("in", msg.Code, msg.Size, rw.PeerType())
+
 	return msg, nil
 }
 
 func (rw *BaseMeteredMsgReadWriter) WriteMsg(msg p2p.Msg) error {
-	return rw.MsgReadWriter.WriteMsg(msg)
+	err := rw.MsgReadWriter.WriteMsg(msg)
+	if err != nil {
+		return err
+	}
+
+	markMsg("out", msg.Code, msg.Size, rw.PeerType())
+
+	return nil
 }
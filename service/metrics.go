@@ -0,0 +1,153 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	// latency from sending sync-oplog to receiving the first sync-oplog-ack.
+	syncOplogLatencyTimer = metrics.NewRegisteredTimer("service/sync/oplog/latency", nil)
+
+	// the recent hours skipped (identical digests) / sent (diverged) in sync-oplog-ack.
+	syncOplogSkipHourMeter = metrics.NewRegisteredMeter("service/sync/oplog/skiphour", nil)
+	syncOplogDiffHourMeter = metrics.NewRegisteredMeter("service/sync/oplog/diffhour", nil)
+)
+
+/*
+metricName appends the labels (key-value pairs) to the name of the metric as name{key="value",...}.
+
+The labels are exported as-is by the prometheus-endpoint.
+*/
+func metricName(name string, labels ...string) string {
+	if len(labels) < 2 {
+		return name
+	}
+
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", labels[i], strconv.Quote(labels[i+1])))
+	}
+
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+func codeTypeLabel(code CodeType) string {
+	if str := code.String(); str != "" {
+		return str
+	}
+	return strconv.FormatUint(uint64(code), 10)
+}
+
+func oplogLabel(oplog *BaseOplog) string {
+	return strings.TrimPrefix(string(oplog.dbPrefix), ".")
+}
+
+/*
+markMsg marks the packets / bytes of the msg by the direction (in / out), the code-type and the peer-type.
+*/
+func markMsg(direction string, code uint64, size uint32, peerType PeerType) {
+	if !metrics.Enabled {
+		return
+	}
+
+	codeLabel, peerLabel := codeTypeLabel(CodeType(code)), peerType.String()
+
+	metrics.GetOrRegisterCounter(metricName("service/msg/"+direction+"/packets", "code", codeLabel, "peer", peerLabel), nil).Inc(1)
+	metrics.GetOrRegisterCounter(metricName("service/msg/"+direction+"/bytes", "code", codeLabel, "peer", peerLabel), nil).Inc(int64(size))
+}
+
+// markOplogVerifyFail marks the oplogs unable to be verified.
+func markOplogVerifyFail(oplog *BaseOplog) {
+	if !metrics.Enabled {
+		return
+	}
+
+	metrics.GetOrRegisterCounter(metricName("service/oplog/verify/fail", "oplog", oplogLabel(oplog)), nil).Inc(1)
+}
+
+// markOplogIntegrateFail marks the oplogs unable to be integrated.
+func markOplogIntegrateFail(oplog *BaseOplog, isPending bool) {
+	if !metrics.Enabled {
+		return
+	}
+
+	metrics.GetOrRegisterCounter(metricName("service/oplog/integrate/fail", "oplog", oplogLabel(oplog), "pending", strconv.FormatBool(isPending)), nil).Inc(1)
+}
+
+// updatePendingOplogs updates the number of the pending oplogs of the entity.
+func updatePendingOplogs(entityID string, oplog *BaseOplog, nPending int) {
+	if !metrics.Enabled {
+		return
+	}
+
+	metrics.GetOrRegisterGauge(metricName("service/oplog/pending", "entity", entityID, "oplog", oplogLabel(oplog)), nil).Update(int64(nPending))
+}
+
+// updateSyncOplogDuration updates the duration from initiating sync-oplog to merging the merkle-nodes of the merkle.
+func updateSyncOplogDuration(merkle *Merkle, startTime time.Time) {
+	if !metrics.Enabled {
+		return
+	}
+
+	metrics.GetOrRegisterTimer(metricName("service/sync/oplog/duration", "merkle", merkle.Name), nil).UpdateSince(startTime)
+}
+
+// markSyncOplogFail marks the failed sync-oplog-ack of the merkle.
+func markSyncOplogFail(merkle *Merkle) {
+	if !metrics.Enabled {
+		return
+	}
+
+	metrics.GetOrRegisterCounter(metricName("service/sync/oplog/fail", "merkle", merkle.Name), nil).Inc(1)
+}
+
+func entityPeersMetricName(pm *BaseProtocolManager) string {
+	entity := pm.Entity()
+
+	serviceName := ""
+	if service := entity.Service(); service != nil {
+		serviceName = service.Name()
+	}
+
+	return metricName("service/entity/peers", "entity", entity.IDString(), "service", serviceName)
+}
+
+// registerEntityPeers registers the gauge of the number of the peers of the entity.
+func registerEntityPeers(pm *BaseProtocolManager) {
+	if !metrics.Enabled {
+		return
+	}
+
+	metrics.NewRegisteredFunctionalGauge(entityPeersMetricName(pm), nil, func() int64 {
+		return int64(pm.Peers().Len(false))
+	})
+}
+
+func unregisterEntityPeers(pm *BaseProtocolManager) {
+	if !metrics.Enabled {
+		return
+	}
+
+	metrics.DefaultRegistry.Unregister(entityPeersMetricName(pm))
+}
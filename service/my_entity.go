@@ -50,6 +50,9 @@ type MyEntity interface {
 	CreateJoinEntityOplog(entity Entity) error
 
 	GetValidateKey() *types.PttID
+
+	GetPrivacySetting() *PrivacySetting
+	GetBlockList() *BlockList
 }
 
 type PttMyEntity interface {
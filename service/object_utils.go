@@ -99,6 +99,14 @@ func AliveObjects(theObjs []Object) []Object {
 }
 
 func GetObjList(obj Object, startID *types.PttID, limit int, listOrder pttdb.ListOrder, isLocked bool) ([]Object, error) {
+	return GetObjListWithFilter(obj, startID, limit, listOrder, nil, isLocked)
+}
+
+/*
+GetObjListWithFilter gets the list of the objects passing the filter (all the objects if filter is nil).
+The limit is applied to the filtered objects.
+*/
+func GetObjListWithFilter(obj Object, startID *types.PttID, limit int, listOrder pttdb.ListOrder, filter func(obj Object) bool, isLocked bool) ([]Object, error) {
 
 	baseObj := obj.GetBaseObject()
 	iter, err := baseObj.GetObjIterWithObj(startID, listOrder, isLocked)
@@ -129,6 +137,10 @@ func GetObjList(obj Object, startID *types.PttID, limit int, listOrder pttdb.Lis
 			continue
 		}
 
+		if filter != nil && !filter(each) {
+			continue
+		}
+
 		objs = append(objs, each)
 
 		i++
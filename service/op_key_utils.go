@@ -21,7 +21,6 @@ import (
 
 	"github.com/ailabstw/go-pttai/common"
 	"github.com/ailabstw/go-pttai/common/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ailabstw/go-pttai/log"
 	"github.com/ailabstw/go-pttai/pttdb"
 )
@@ -56,17 +55,7 @@ func (k *KeyInfo) Init(
 
 	pm.SetOpKeyObjDB(k)
 
-	key, err := crypto.ToECDSA(k.KeyBytes)
-	if err != nil {
-		return err
-	}
-
-	pubKeyBytes := crypto.FromECDSAPub(&key.PublicKey)
-
-	k.Key = key
-	k.PubKeyBytes = pubKeyBytes
-
-	return nil
+	return k.loadKey()
 }
 
 func (k *KeyInfo) NewEmptyObj() Object {
@@ -115,8 +104,8 @@ func (k *KeyInfo) Save(isLocked bool) error {
 		defer k.Unlock()
 	}
 
-	if k.Key == nil && k.KeyBytes != nil {
-		k.Key, err = crypto.ToECDSA(k.KeyBytes)
+	if k.Key == nil && k.EdKey == nil && k.KeyBytes != nil {
+		err = k.loadKey()
 		if err != nil {
 			return err
 		}
@@ -170,12 +159,10 @@ func (k *KeyInfo) Unmarshal(data []byte) error {
 
 	// it's possible that k.KeyBytes is nil because of the init-key.
 	if k.KeyBytes != nil {
-		k.Key, err = crypto.ToECDSA(k.KeyBytes)
+		err = k.loadKey()
 		if err != nil {
 			return err
 		}
-
-		k.PubKeyBytes = crypto.FromECDSAPub(&k.Key.PublicKey)
 	}
 
 	return nil
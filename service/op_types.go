@@ -53,6 +53,8 @@ const (
 	CodeTypeOpCheckMember
 	CodeTypeOpCheckMemberAck
 
+	CodeTypeOpPublic
+
 	NCodeType
 )
 
@@ -63,14 +65,17 @@ var codeTypeStr = map[CodeType]string{
 	CodeTypeJoin:    "join",
 	CodeTypeJoinAck: "join-ack",
 
-	CodeTypeOp:     "op",
-	CodeTypeOpAck:  "op-ack",
-	CodeTypeOpFail: "op-fail",
+	CodeTypeOp:          "op",
+	CodeTypeOpAck:       "op-ack",
+	CodeTypeRequireHash: "require-hash",
+	CodeTypeOpFail:      "op-fail",
 
 	CodeTypeRequestOpKey:     "request-op-key",
 	CodeTypeRequestOpKeyAck:  "request-op-key-ack",
 	CodeTypeRequestOpKeyFail: "request-op-key-fail",
 
+	CodeTypeEntityDeleted: "entity-deleted",
+
 	CodeTypeIdentifyPeer:     "identify-peer",
 	CodeTypeIdentifyPeerAck:  "identify-peer-ack",
 	CodeTypeIdentifyPeerFail: "identify-peer-fail",
@@ -82,6 +87,8 @@ var codeTypeStr = map[CodeType]string{
 
 	CodeTypeOpCheckMember:    "op-check-member",
 	CodeTypeOpCheckMemberAck: "op-check-member-ack",
+
+	CodeTypeOpPublic: "op-public",
 }
 
 func (c CodeType) String() string {
@@ -24,6 +24,7 @@ import (
 
 	"github.com/ailabstw/go-pttai/common"
 	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/key"
 	"github.com/ailabstw/go-pttai/log"
 	"github.com/ailabstw/go-pttai/pttdb"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -53,7 +54,7 @@ type BaseOplog struct {
 
 	Data OpData `json:"D,omitempty"`
 
-	db               *pttdb.LDBBatch
+	db               pttdb.IndexBatch
 	dbPrefixID       *types.PttID
 	dbPrefix         []byte
 	dbIdxPrefix      []byte
@@ -66,6 +67,7 @@ type BaseOplog struct {
 	Sig         []byte        `json:"S,omitempty"`
 	Pubkey      []byte        `json:"K,omitempty"`
 	KeyExtra    *KeyExtraInfo `json:"k,omitempty"`
+	KeyType     key.Type      `json:"kT,omitempty"`
 
 	// to remove when doing sign
 	UpdateTS types.Timestamp `json:"UT"`
@@ -82,11 +84,11 @@ type BaseOplog struct {
 	Extra   interface{} `json:"e,omitempty"`
 }
 
-func NewOplogForLoadData(data interface{}, db *pttdb.LDBBatch) *BaseOplog {
+func NewOplogForLoadData(data interface{}, db pttdb.IndexBatch) *BaseOplog {
 	return &BaseOplog{Data: data, db: db}
 }
 
-func NewOplog(id *types.PttID, ts types.Timestamp, doerID *types.PttID, op OpType, data interface{}, db *pttdb.LDBBatch, dbPrefixID *types.PttID, dbPrefix []byte, dbIdxPrefix []byte, dbMerklePrefix []byte, dbLock *types.LockMap) (*BaseOplog, error) {
+func NewOplog(id *types.PttID, ts types.Timestamp, doerID *types.PttID, op OpType, data interface{}, db pttdb.IndexBatch, dbPrefixID *types.PttID, dbPrefix []byte, dbIdxPrefix []byte, dbMerklePrefix []byte, dbLock *types.LockMap) (*BaseOplog, error) {
 
 	opID, err := types.NewPttID()
 	if err != nil {
@@ -124,7 +126,7 @@ func NewOplog(id *types.PttID, ts types.Timestamp, doerID *types.PttID, op OpTyp
 	return oplog, nil
 }
 
-func (o *BaseOplog) SetDB(db *pttdb.LDBBatch, id *types.PttID, prefix []byte, idxPrefix []byte, merklePrefix []byte, dbLock *types.LockMap) {
+func (o *BaseOplog) SetDB(db pttdb.IndexBatch, id *types.PttID, prefix []byte, idxPrefix []byte, merklePrefix []byte, dbLock *types.LockMap) {
 	dbPrefixInternal := dbPrefixToDBPrefixInternal(prefix)
 	dbPrefixMaster := dbPrefixToDBPrefixMaster(prefix)
 
@@ -138,7 +140,7 @@ func (o *BaseOplog) SetDB(db *pttdb.LDBBatch, id *types.PttID, prefix []byte, id
 	o.dbLock = dbLock
 }
 
-func (o *BaseOplog) GetDB() *pttdb.LDBBatch {
+func (o *BaseOplog) GetDB() pttdb.IndexBatch {
 	return o.db
 }
 
@@ -533,6 +535,7 @@ func (o *BaseOplog) Sign(keyInfo *KeyInfo) error {
 	o.Sig = nil
 	o.Salt = types.Salt{}
 	o.Pubkey = nil
+	o.KeyType = key.TypeSecp256k1
 
 	o.MasterLogID = nil
 	o.MasterSigns = nil
@@ -557,6 +560,7 @@ func (o *BaseOplog) Sign(keyInfo *KeyInfo) error {
 	o.Sig = sig
 	o.Pubkey = pubBytes
 	o.KeyExtra = keyInfo.Extra
+	o.KeyType = keyInfo.KeyType
 
 	o.UpdateTS = o.CreateTS
 	o.Hash, _ = o.SignsHash()
@@ -658,10 +662,11 @@ func (o *BaseOplog) MasterSign(id *types.PttID, keyInfo *KeyInfo) error {
 		ID:       id,
 		CreateTS: ts,
 
-		Hash:   hash,
-		Sig:    sig,
-		Pubkey: pubBytes,
-		Extra:  keyInfo.Extra,
+		Hash:    hash,
+		Sig:     sig,
+		Pubkey:  pubBytes,
+		Extra:   keyInfo.Extra,
+		KeyType: keyInfo.KeyType,
 	}
 
 	copy(masterSign.Salt[:], bytesWithSalt[len(marshaled):])
@@ -735,10 +740,11 @@ func (o *BaseOplog) InternalSign(id *types.PttID, keyInfo *KeyInfo) error {
 		ID:       id,
 		CreateTS: ts,
 
-		Hash:   hash,
-		Sig:    sig,
-		Pubkey: pubBytes,
-		Extra:  keyInfo.Extra,
+		Hash:    hash,
+		Sig:     sig,
+		Pubkey:  pubBytes,
+		Extra:   keyInfo.Extra,
+		KeyType: keyInfo.KeyType,
 	}
 
 	copy(internalSign.Salt[:], bytesWithSalt[len(marshaled):])
@@ -787,7 +793,7 @@ func (o *BaseOplog) Verify() error {
 	}
 
 	origUpdateTS := o.UpdateTS
-	origHash, origCreatorHash, origSalt, origSig, origPubBytes, origKeyExtra := o.Hash, o.CreatorHash, o.Salt, o.Sig, o.Pubkey, o.KeyExtra
+	origHash, origCreatorHash, origSalt, origSig, origPubBytes, origKeyExtra, origKeyType := o.Hash, o.CreatorHash, o.Salt, o.Sig, o.Pubkey, o.KeyExtra, o.KeyType
 	origMasterLogID, origMasterSigns, origInternalSigns := o.MasterLogID, o.MasterSigns, o.InternalSigns
 	origIsSync := o.IsSync
 	origIsNewer := o.IsNewer
@@ -795,6 +801,7 @@ func (o *BaseOplog) Verify() error {
 	defer func(o *BaseOplog) {
 		o.UpdateTS = origUpdateTS
 		o.Hash, o.CreatorHash, o.Salt, o.Sig, o.Pubkey = origHash, origCreatorHash, origSalt, origSig, origPubBytes
+		o.KeyType = origKeyType
 		o.MasterLogID, o.MasterSigns, o.InternalSigns = origMasterLogID, origMasterSigns, origInternalSigns
 		o.IsSync = origIsSync
 		o.IsNewer = origIsNewer
@@ -809,6 +816,7 @@ func (o *BaseOplog) Verify() error {
 	o.Salt = types.Salt{}
 	o.Pubkey = nil
 	o.KeyExtra = nil
+	o.KeyType = key.TypeSecp256k1
 
 	o.MasterLogID = nil
 	o.MasterSigns = nil
@@ -823,7 +831,7 @@ func (o *BaseOplog) Verify() error {
 	}
 	bytesWithSalt := append(marshaled, origSalt[:]...)
 
-	err = VerifyData(bytesWithSalt, origCreatorHash, origSig, origPubBytes, o.CreatorID, origKeyExtra)
+	err = VerifyData(bytesWithSalt, origCreatorHash, origSig, origKeyType, origPubBytes, o.CreatorID, origKeyExtra)
 	if err != nil {
 		log.Error("Verify (sign)", "bytesWithSalt", bytesWithSalt, "origSig", origSig, "origPubBytes", origPubBytes, "id", o.ID, "objID", o.ObjID)
 		return err
@@ -835,6 +843,7 @@ func (o *BaseOplog) Verify() error {
 	o.Salt = origSalt
 	o.Pubkey = origPubBytes
 	o.KeyExtra = origKeyExtra
+	o.KeyType = origKeyType
 
 	// master signs
 	if origMasterSigns != nil {
@@ -845,7 +854,7 @@ func (o *BaseOplog) Verify() error {
 			}
 			bytesWithSalt = append(marshaled, masterSign.Salt[:]...)
 
-			err = VerifyData(bytesWithSalt, masterSign.Hash, masterSign.Sig, masterSign.Pubkey, masterSign.ID, masterSign.Extra)
+			err = VerifyData(bytesWithSalt, masterSign.Hash, masterSign.Sig, masterSign.KeyType, masterSign.Pubkey, masterSign.ID, masterSign.Extra)
 			if err != nil {
 				log.Error("Verify (master-sign)", "bytesWithSalt", bytesWithSalt, "sig", masterSign.Sig, "pubBytes", masterSign.Pubkey, "id", o.ID, "objID", o.ObjID)
 				return err
@@ -862,7 +871,7 @@ func (o *BaseOplog) Verify() error {
 			}
 			bytesWithSalt = append(marshaled, internalSign.Salt[:]...)
 
-			err = VerifyData(bytesWithSalt, internalSign.Hash, internalSign.Sig, internalSign.Pubkey, internalSign.ID, internalSign.Extra)
+			err = VerifyData(bytesWithSalt, internalSign.Hash, internalSign.Sig, internalSign.KeyType, internalSign.Pubkey, internalSign.ID, internalSign.Extra)
 			if err != nil {
 				log.Error("Verify (internal-sign)", "bytesWithSalt", bytesWithSalt, "sig", internalSign.Sig, "pubBytes", internalSign.Pubkey, "id", o.ID, "objID", o.ObjID)
 				return err
@@ -0,0 +1,170 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/rpc"
+	"github.com/ethereum/go-ethereum/event"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+type OplogEventType uint8
+
+const (
+	OplogEventTypeInvalid OplogEventType = iota
+
+	// log0: the entity-level oplogs (board-oplog / friend-oplog)
+	OplogEventTypeLog0
+
+	OplogEventTypeMember
+)
+
+/*
+OplogEvent is posted to the event-mux of the service-protocol-manager when an oplog becomes valid in one of the entities of the service.
+*/
+type OplogEvent struct {
+	EntityID *types.PttID   `json:"EID"`
+	T        OplogEventType `json:"T"`
+	Oplog    *BaseOplog     `json:"O"`
+}
+
+type postedOplogKey struct {
+	ID       types.PttID
+	UpdateTS types.Timestamp
+}
+
+/*
+PostOplogEvents posts the valid oplogs to the event-mux of the service-protocol-manager.
+
+An oplog reaches here both from the broadcasting of the locally-valid oplogs and from the integration of the received oplogs.
+The oplogs are posted only once for each (id, update-ts), and are shallow-copied because the extra of the oplog is reset during broadcasting.
+*/
+func (pm *BaseProtocolManager) PostOplogEvents(evType OplogEventType, oplogs []*BaseOplog) {
+	entity := pm.Entity()
+
+	postOplogEvents(entity.Service().SPM().EventMux(), pm.postedOplogs, entity.GetID(), evType, oplogs)
+}
+
+func postOplogEvents(mux *event.TypeMux, postedOplogs *lru.Cache, entityID *types.PttID, evType OplogEventType, oplogs []*BaseOplog) {
+	for _, oplog := range oplogs {
+		if oplog.MasterLogID == nil {
+			continue
+		}
+
+		key := postedOplogKey{ID: *oplog.ID, UpdateTS: oplog.UpdateTS}
+		if isPosted, _ := postedOplogs.ContainsOrAdd(key, struct{}{}); isPosted {
+			continue
+		}
+
+		theOplog := *oplog
+		theOplog.IsNewer = false
+		theOplog.Extra = nil
+
+		mux.Post(&OplogEvent{EntityID: entityID, T: evType, Oplog: &theOplog})
+	}
+}
+
+/*
+SubscribeOplogEvents creates an RPC subscription which receives the oplog-events from mux.
+Receiving the events of all the entities if entityID is nil.
+*/
+func SubscribeOplogEvents(ctx context.Context, mux *event.TypeMux, entityID *types.PttID) (*rpc.Subscription, error) {
+	return subscribeEvents(ctx, mux, &OplogEvent{}, func(data interface{}) bool {
+		if entityID == nil {
+			return true
+		}
+
+		ev, ok := data.(*OplogEvent)
+		if !ok {
+			return false
+		}
+
+		return reflect.DeepEqual(ev.EntityID, entityID)
+	})
+}
+
+/*
+SubscribePttOplogs creates an RPC subscription which receives the ptt-oplogs from mux.
+*/
+func SubscribePttOplogs(ctx context.Context, mux *event.TypeMux) (*rpc.Subscription, error) {
+	return subscribeEvents(ctx, mux, &PttOplog{}, nil)
+}
+
+func subscribeEvents(ctx context.Context, mux *event.TypeMux, ev interface{}, isValid func(data interface{}) bool) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	sub, dataCh := bufferEvents(mux, ev, isValid)
+
+	go func() {
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case data, ok := <-dataCh:
+				if !ok {
+					return
+				}
+				notifier.Notify(rpcSub.ID, data)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+/*
+bufferEvents subscribes ev from mux and relays the valid events to a buffered channel.
+
+mux.Post is synchronous, so the relay never waits for the receiver of the channel:
+the events are dropped when the buffer is full (the receiver is too slow).
+The channel is closed when the subscription is unsubscribed.
+*/
+func bufferEvents(mux *event.TypeMux, ev interface{}, isValid func(data interface{}) bool) (*event.TypeMuxSubscription, <-chan interface{}) {
+	sub := mux.Subscribe(ev)
+	dataCh := make(chan interface{}, SizeEventSubscriptionBuffer)
+
+	go func() {
+		defer close(dataCh)
+
+		for obj := range sub.Chan() {
+			if isValid != nil && !isValid(obj.Data) {
+				continue
+			}
+
+			select {
+			case dataCh <- obj.Data:
+			default:
+				log.Warn("bufferEvents: buffer is full, drop the event", "type", reflect.TypeOf(obj.Data))
+			}
+		}
+	}()
+
+	return sub, dataCh
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ethereum/go-ethereum/event"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+func Test_postOplogEvents(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	mux := new(event.TypeMux)
+	defer mux.Stop()
+
+	sub, dataCh := bufferEvents(mux, &OplogEvent{}, nil)
+	defer sub.Unsubscribe()
+
+	postedOplogs, _ := lru.New(SizePostedOplogs)
+
+	entityID := &types.PttID{1}
+	logID := &types.PttID{2}
+	ts0 := types.Timestamp{Ts: 10}
+	ts1 := types.Timestamp{Ts: 20}
+
+	// prepare test-cases
+	tests := []struct {
+		name   string
+		oplogs []*BaseOplog
+		want   int
+	}{
+		{
+			name:   "pending",
+			oplogs: []*BaseOplog{{ID: logID, UpdateTS: ts0}},
+			want:   0,
+		},
+		{
+			name:   "valid",
+			oplogs: []*BaseOplog{{ID: logID, UpdateTS: ts0, MasterLogID: logID}},
+			want:   1,
+		},
+		{
+			name:   "valid-again (broadcast after integrated)",
+			oplogs: []*BaseOplog{{ID: logID, UpdateTS: ts0, MasterLogID: logID}},
+			want:   0,
+		},
+		{
+			name: "updated",
+			oplogs: []*BaseOplog{
+				{ID: logID, UpdateTS: ts1, MasterLogID: logID},
+				{ID: logID, UpdateTS: ts1, MasterLogID: logID},
+			},
+			want: 1,
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postOplogEvents(mux, postedOplogs, entityID, OplogEventTypeLog0, tt.oplogs)
+
+			got := 0
+			for {
+				select {
+				case data := <-dataCh:
+					ev := data.(*OplogEvent)
+					if *ev.EntityID != *entityID || *ev.Oplog.ID != *logID {
+						t.Errorf("postOplogEvents() = %v, want entity: %v log: %v", ev, entityID, logID)
+					}
+					got++
+					continue
+				case <-time.After(50 * time.Millisecond):
+				}
+				break
+			}
+
+			if got != tt.want {
+				t.Errorf("postOplogEvents() posted %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// teardown test
+}
+
+func Test_bufferEvents(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	mux := new(event.TypeMux)
+	defer mux.Stop()
+
+	// nobody receives from the channel.
+	sub, dataCh := bufferEvents(mux, &Typing{}, func(data interface{}) bool {
+		return data.(*Typing).IsTyping
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < SizeEventSubscriptionBuffer*2; i++ {
+			mux.Post(&Typing{IsTyping: i%2 == 0})
+		}
+	}()
+
+	// run test
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("mux.Post() is blocked by the slow subscriber")
+	}
+
+	sub.Unsubscribe()
+
+	got := 0
+	for data := range dataCh {
+		if !data.(*Typing).IsTyping {
+			t.Errorf("bufferEvents() = %v, want only the valid events", data)
+		}
+		got++
+	}
+	if got != SizeEventSubscriptionBuffer {
+		t.Errorf("bufferEvents() buffered %v, want %v", got, SizeEventSubscriptionBuffer)
+	}
+
+	// teardown test
+}
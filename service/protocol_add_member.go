@@ -29,16 +29,22 @@ func (pm *BaseProtocolManager) AddMember(id *types.PttID, isForce bool) (*Member
 	origMember := NewEmptyMember()
 	pm.SetMemberObjDB(origMember)
 
-	// 1. validate
-	if !isForce && !pm.IsMaster(myID, false) {
-		return nil, nil, types.ErrInvalidID
+	// 1. validate (the moderators are able to add the members.)
+	if !isForce {
+		if pm.Entity().GetStatus() != types.StatusAlive {
+			return nil, nil, types.ErrInvalidStatus
+		}
+
+		if !pm.IsMemberManager(myID, id) {
+			return nil, nil, types.ErrInvalidID
+		}
 	}
 
 	data := &MemberOpAddMember{}
 	person, oplog, err := pm.AddPerson(
 		id,
 		MemberOpTypeAddMember,
-		isForce,
+		true,
 
 		origMember,
 		data,
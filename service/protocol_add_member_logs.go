@@ -56,6 +56,10 @@ func (pm *BaseProtocolManager) handleAddMemberLog(oplog *BaseOplog, info *Proces
 
 func (pm *BaseProtocolManager) handlePendingAddMemberLog(oplog *BaseOplog, info *ProcessPersonInfo) (types.Bool, []*BaseOplog, error) {
 
+	if !pm.isValidMemberLogCreator(oplog) {
+		return false, nil, types.ErrInvalidID
+	}
+
 	person := NewEmptyMember()
 	pm.SetMemberObjDB(person)
 
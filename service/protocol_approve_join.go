@@ -58,7 +58,12 @@ func (p *BasePtt) ApproveJoin(confirmKey []byte) error {
 
 	id := entity.GetID()
 	name := entity.Name()
-	opKeyBytes := opKeyInfo.KeyBytes
+
+	// no op-key for the followers.
+	var opKeyBytes []byte
+	if opKeyInfo != nil {
+		opKeyBytes = opKeyInfo.KeyBytes
+	}
 
 	approveJoin := &ApproveJoin{
 		ID:         id,
@@ -72,12 +77,12 @@ func (p *BasePtt) ApproveJoin(confirmKey []byte) error {
 		return err
 	}
 
-	encData, err := p.EncryptData(ApproveJoinMsg, data, keyInfo)
+	encData, err := p.EncryptDataWithVersion(peer.DataVersion(), CodeTypeJoinAck, ApproveJoinMsg, data, keyInfo)
 	if err != nil {
 		return err
 	}
 
-	pttData, err := p.MarshalData(CodeTypeJoinAck, keyInfo.Hash, encData)
+	pttData, err := p.MarshalDataWithVersion(peer.DataVersion(), CodeTypeJoinAck, keyInfo.Hash, encData)
 	if err != nil {
 		return err
 	}
@@ -36,6 +36,8 @@ type ApproveJoinEntity struct {
 
 /*
 Required variables in joinEntity: ID
+
+The joiner of the public entity is approved as a follower (not added as a member) if requesting follow or if I am not a master.
 */
 func (pm *BaseProtocolManager) ApproveJoin(
 	joinEntity *JoinEntity,
@@ -46,26 +48,64 @@ func (pm *BaseProtocolManager) ApproveJoin(
 
 	myID := pm.Ptt().GetMyEntity().GetID()
 
-	if !pm.IsMaster(myID, false) && peer.PeerType != PeerTypeMe {
+	isMaster := pm.IsMaster(myID, false)
+	if !pm.IsJoinApprover(myID) && peer.PeerType != PeerTypeMe {
 		return nil, nil, types.ErrInvalidID
 	}
 
+	isFollow := joinEntity.IsFollow || (!isMaster && peer.PeerType != PeerTypeMe)
+	if isFollow && !pm.IsPublic() {
+		return nil, nil, ErrInvalidEntity
+	}
+
 	if pm.myMemberLog == nil {
 		return nil, nil, types.ErrInvalidStatus
 	}
 
-	opKey, err := pm.GetNewestOpKey(false)
-	log.Debug("ApproveJoin: after GetNewestOpKey", "err", err, "entity", pm.Entity().IDString(), "peer", peer)
-	if err != nil {
-		return nil, nil, err
+	// invite (the use is reserved before adding the member, and rolled back if the join fails)
+	var err error
+	isUsedInvite := false
+	if keyInfo != nil {
+		isUsedInvite, err = pm.useInvite(keyInfo.Hash, joinEntity.ID)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
-	opKeyLog := &OpKeyOplog{BaseOplog: &BaseOplog{}}
-	pm.SetOpKeyDB(opKeyLog.BaseOplog)
-	err = opKeyLog.Get(opKey.LogID, false)
+
+	opKey, approveJoin, err := pm.approveJoin(joinEntity, isFollow, peer)
 	if err != nil {
+		if isUsedInvite {
+			errUnuse := pm.unuseInvite(keyInfo.Hash, joinEntity.ID)
+			if errUnuse != nil {
+				log.Warn("ApproveJoin: unable to unuse invite", "entity", pm.Entity().IDString(), "id", joinEntity.ID, "e", errUnuse)
+			}
+		}
 		return nil, nil, err
 	}
 
+	return opKey, approveJoin, nil
+}
+
+func (pm *BaseProtocolManager) approveJoin(
+	joinEntity *JoinEntity,
+	isFollow bool,
+	peer *PttPeer,
+) (*KeyInfo, interface{}, error) {
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+
+	// op-key (the followers read the public entity with the signed data, not with the op-key)
+	var opKey *KeyInfo
+	var opKeyLog *BaseOplog
+	var err error
+	if !isFollow {
+		opKey, opKeyLog, err = pm.getNewestOpKeyWithOplog()
+		log.Debug("ApproveJoin: after getNewestOpKeyWithOplog", "err", err, "entity", pm.Entity().IDString(), "peer", peer)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// entity
 	entity := pm.Entity()
 
@@ -87,7 +127,7 @@ func (pm *BaseProtocolManager) ApproveJoin(
 	// XXX (force adding member. We are single-master for now.)
 	var memberLog *MemberOplog
 	memberLogs := make([]*BaseOplog, 0, 2)
-	if !reflect.DeepEqual(myID, joinEntity.ID) {
+	if !isFollow && !reflect.DeepEqual(myID, joinEntity.ID) {
 		log.Debug("ApproveJoin: peer not me", "joinEntity", joinEntity.ID, "myID", entity.GetCreatorID(), "entity", pm.Entity().IDString(), "peer", peer)
 		_, memberLog, err = pm.AddMember(joinEntity.ID, true)
 		log.Debug("ApproveJoin: after AddMember", "e", err)
@@ -107,6 +147,14 @@ func (pm *BaseProtocolManager) ApproveJoin(
 	memberLogs = append(memberLogs, pm.myMemberLog.BaseOplog)
 	log.Debug("ApproveJoin: after get memberLogs", "memberLogs", memberLogs)
 
+	// follower
+	if isFollow {
+		err = pm.RegisterFollower(joinEntity.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// register-peer
 	if peer.UserID == nil {
 		peer.UserID = joinEntity.ID
@@ -133,8 +181,24 @@ func (pm *BaseProtocolManager) ApproveJoin(
 		MasterLogs: masterLogs,
 		MemberLogs: memberLogs,
 		OpKey:      opKey,
-		OpKeyLog:   opKeyLog.BaseOplog,
+		OpKeyLog:   opKeyLog,
 	}
 
 	return opKey, approveJoin, nil
 }
+
+func (pm *BaseProtocolManager) getNewestOpKeyWithOplog() (*KeyInfo, *BaseOplog, error) {
+	opKey, err := pm.GetNewestOpKey(false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opKeyLog := &OpKeyOplog{BaseOplog: &BaseOplog{}}
+	pm.SetOpKeyDB(opKeyLog.BaseOplog)
+	err = opKeyLog.Get(opKey.LogID, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return opKey, opKeyLog.BaseOplog, nil
+}
@@ -0,0 +1,263 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/p2p/discover"
+)
+
+/*
+BanMember bans (or unbans) the user and the known nodes of the user by the master.
+
+	1. validate (alive, is-master, and not banning the masters).
+	2. new-oplog with the node-ids as the op-data.
+	3. sign oplog.
+	4. update entity if the oplog is valid.
+	5. save oplog and broadcast.
+	6. postban (deleting the member).
+*/
+func (pm *BaseProtocolManager) BanMember(id *types.PttID, nodeIDs []*discover.NodeID, isBanned bool) error {
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	entity := pm.Entity()
+
+	// 1. validate
+	if entity.GetStatus() != types.StatusAlive {
+		return types.ErrInvalidStatus
+	}
+
+	if !pm.IsMaster(myID, false) {
+		return types.ErrInvalidID
+	}
+
+	if isBanned && (reflect.DeepEqual(myID, id) || pm.IsMaster(id, false)) {
+		return types.ErrInvalidID
+	}
+
+	opData := &MemberOpBanMember{IsBanned: isBanned}
+	if isBanned {
+		opData.NodeIDs = pm.userNodeIDs(id, nodeIDs)
+	}
+
+	// lock
+	err := entity.Lock()
+	if err != nil {
+		return err
+	}
+
+	// 2. oplog
+	theOplog, err := pm.NewMemberOplog(id, MemberOpTypeBanMember, opData)
+	if err != nil {
+		entity.Unlock()
+		return err
+	}
+	oplog := theOplog.GetBaseOplog()
+
+	// 3. sign
+	err = pm.SignOplog(oplog)
+	if err != nil {
+		entity.Unlock()
+		return err
+	}
+
+	// 4. update entity
+	isValid := oplog.MasterLogID != nil
+	if isValid {
+		setEntityBanWithOplog(entity, opData, oplog)
+
+		err = entity.Save(true)
+		if err != nil {
+			entity.Unlock()
+			return err
+		}
+
+		oplog.IsSync = true
+	}
+	entity.Unlock()
+
+	// 5. oplog
+	err = oplog.Save(false, pm.MemberMerkle())
+	if err != nil {
+		return err
+	}
+
+	pm.broadcastMemberOplogCore(oplog)
+
+	// 6. postban
+	if isValid && isBanned {
+		pm.postbanMember(id)
+	}
+
+	return nil
+}
+
+func (pm *BaseProtocolManager) GetBans() []*BanInfo {
+	bans := pm.Entity().GetBans()
+
+	banned := make([]*BanInfo, 0, len(bans))
+	for _, ban := range bans {
+		if !ban.IsBanned {
+			continue
+		}
+		banned = append(banned, ban)
+	}
+
+	return banned
+}
+
+/*
+IsBanned returns whether the id or the node-id is banned in the entity.
+*/
+func (pm *BaseProtocolManager) IsBanned(id *types.PttID, nodeID *discover.NodeID) bool {
+	return IsBannedInList(pm.Entity().GetBans(), id, nodeID)
+}
+
+/*
+userNodeIDs includes the node-ids of the peers currently connected as the user.
+*/
+func (pm *BaseProtocolManager) userNodeIDs(id *types.PttID, nodeIDs []*discover.NodeID) []*discover.NodeID {
+	theNodeIDs := make([]*discover.NodeID, 0, len(nodeIDs))
+	theNodeIDs = append(theNodeIDs, nodeIDs...)
+
+	peers, _ := pm.GetPeers()
+	for _, peer := range peers {
+		if !reflect.DeepEqual(peer.UserID, id) {
+			continue
+		}
+		nodeID := peer.GetID()
+		isExists := false
+		for _, eachNodeID := range theNodeIDs {
+			if reflect.DeepEqual(eachNodeID, nodeID) {
+				isExists = true
+				break
+			}
+		}
+		if !isExists {
+			theNodeIDs = append(theNodeIDs, nodeID)
+		}
+	}
+
+	return theNodeIDs
+}
+
+/*
+postbanMember deletes the banned user from the members (by the master) and from the followers.
+*/
+func (pm *BaseProtocolManager) postbanMember(id *types.PttID) {
+	myID := pm.Ptt().GetMyEntity().GetID()
+
+	if pm.IsMaster(myID, false) && pm.IsMember(id, false) {
+		_, err := pm.DeleteMember(id)
+		if err != nil {
+			log.Warn("postbanMember: unable to delete member", "entity", pm.Entity().IDString(), "id", id, "e", err)
+		}
+	}
+
+	err := pm.UnregisterFollower(id)
+	if err != nil {
+		log.Warn("postbanMember: unable to unregister follower", "entity", pm.Entity().IDString(), "id", id, "e", err)
+	}
+
+	pm.UnregisterPeerByOtherUserID(id, true, false)
+}
+
+func setEntityBanWithOplog(entity Entity, opData *MemberOpBanMember, oplog *BaseOplog) *BanInfo {
+	ban := &BanInfo{
+		ID:       oplog.ObjID,
+		NodeIDs:  opData.NodeIDs,
+		IsBanned: opData.IsBanned,
+
+		UpdateTS: oplog.UpdateTS,
+		LogID:    oplog.ID,
+	}
+
+	entity.SetBans(setBanInList(entity.GetBans(), ban))
+
+	return ban
+}
+
+/**********
+ * Handle BanMemberLog
+ **********/
+
+/*
+handleBanMemberLog handles the valid ban-member oplog.
+The ban is set only if the creator is the master and the oplog is newer than the existing ban.
+*/
+func (pm *BaseProtocolManager) handleBanMemberLog(oplog *BaseOplog, info *ProcessPersonInfo) ([]*BaseOplog, error) {
+
+	entity := pm.Entity()
+
+	opData := &MemberOpBanMember{}
+	err := oplog.GetData(opData)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pm.IsMaster(oplog.CreatorID, false) {
+		return nil, types.ErrInvalidID
+	}
+
+	// lock
+	err = entity.Lock()
+	if err != nil {
+		return nil, err
+	}
+
+	// newer
+	origBan := GetBanInfo(entity.GetBans(), oplog.ObjID)
+	if origBan != nil && !origBan.UpdateTS.IsLess(oplog.UpdateTS) {
+		entity.Unlock()
+		return nil, ErrNewerOplog
+	}
+
+	// save
+	setEntityBanWithOplog(entity, opData, oplog)
+	err = entity.Save(true)
+	entity.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	oplog.IsSync = true
+
+	log.Debug("handleBanMemberLog: done", "entity", entity.IDString(), "id", oplog.ObjID, "isBanned", opData.IsBanned)
+
+	// postban
+	if opData.IsBanned {
+		go pm.postbanMember(oplog.ObjID)
+	}
+
+	return nil, nil
+}
+
+/*
+handlePendingBanMemberLog handles the pending ban-member oplog.
+Only the oplogs from the masters are to be signed.
+*/
+func (pm *BaseProtocolManager) handlePendingBanMemberLog(oplog *BaseOplog, info *ProcessPersonInfo) (types.Bool, []*BaseOplog, error) {
+
+	if !pm.IsMaster(oplog.CreatorID, false) {
+		return false, nil, types.ErrInvalidID
+	}
+
+	return true, nil, nil
+}
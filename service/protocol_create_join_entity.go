@@ -152,21 +152,23 @@ func (spm *BaseServiceProtocolManager) CreateJoinEntity(
 		return nil, err
 	}
 
-	// 9. op-key
-	pm.SetOpKeyObjDB(opKey)
-	err = opKey.Save(false)
-	if err != nil {
-		return nil, err
-	}
-	pm.SetOpKeyDB(opKeyLog)
-	err = opKeyLog.Save(false, nil)
-	if err != nil {
-		return nil, err
-	}
-	err = pm.RegisterOpKey(opKey, false)
-	log.Debug("CreateJoinEntity: after register op key", "e", err, "entity", pm.Entity().GetID(), "opKey", opKey.Hash)
-	if err != nil {
-		return nil, err
+	// 9. op-key (no op-key for the followers of the public entity)
+	if opKey != nil {
+		pm.SetOpKeyObjDB(opKey)
+		err = opKey.Save(false)
+		if err != nil {
+			return nil, err
+		}
+		pm.SetOpKeyDB(opKeyLog)
+		err = opKeyLog.Save(false, nil)
+		if err != nil {
+			return nil, err
+		}
+		err = pm.RegisterOpKey(opKey, false)
+		log.Debug("CreateJoinEntity: after register op key", "e", err, "entity", pm.Entity().GetID(), "opKey", opKey.Hash)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// 10. reset owner-id
@@ -150,6 +150,11 @@ func (pm *BaseProtocolManager) createObjectCore(
 		return nil, types.ErrInvalidStatus
 	}
 
+	myID := pm.Ptt().GetMyEntity().GetID()
+	if pm.IsFollower(myID) {
+		return nil, types.ErrInvalidID
+	}
+
 	// 2. new-obj
 	obj, opData, err := newObj(data)
 	if err != nil {
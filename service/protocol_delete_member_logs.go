@@ -56,6 +56,10 @@ func (pm *BaseProtocolManager) handleDeleteMemberLog(oplog *BaseOplog, info *Pro
 
 func (pm *BaseProtocolManager) handlePendingDeleteMemberLog(oplog *BaseOplog, info *ProcessPersonInfo) (types.Bool, []*BaseOplog, error) {
 
+	if !pm.isValidMemberLogCreator(oplog) {
+		return false, nil, types.ErrInvalidID
+	}
+
 	obj := NewEmptyMember()
 	pm.SetMemberObjDB(obj)
 
@@ -17,8 +17,6 @@
 package service
 
 import (
-	"reflect"
-
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ailabstw/go-pttai/log"
 )
@@ -75,7 +73,7 @@ func (pm *BaseProtocolManager) DeleteObject(
 	}
 
 	creatorID := origObj.GetCreatorID()
-	if !reflect.DeepEqual(myID, creatorID) && !pm.IsMaster(myID, false) {
+	if !pm.IsObjDeleter(myID, creatorID) {
 		return types.ErrInvalidID
 	}
 
@@ -240,6 +240,11 @@ func (pm *BaseProtocolManager) HandlePendingDeleteObjectLog(
 		return false, nil, ErrNewerOplog
 	}
 
+	// 3.1. only the creator, the masters and the moderators are able to delete the obj.
+	if !pm.IsObjDeleter(oplog.CreatorID, origObj.GetCreatorID()) {
+		return false, nil, types.ErrInvalidID
+	}
+
 	// 4. core
 	err = pm.handlePendingDeleteObjectLogCore(
 		oplog,
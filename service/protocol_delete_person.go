@@ -53,7 +53,7 @@ func (pm *BaseProtocolManager) DeletePerson(
 		return types.ErrInvalidStatus
 	}
 
-	if !pm.IsMaster(myID, false) && !reflect.DeepEqual(myID, id) {
+	if !pm.IsMemberManager(myID, id) && !reflect.DeepEqual(myID, id) {
 		return types.ErrInvalidID
 	}
 
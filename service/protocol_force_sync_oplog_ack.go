@@ -51,6 +51,7 @@ func (pm *BaseProtocolManager) ForceSyncOplogAck(
 			offsetHourTS,
 			startHourTS,
 			toTS,
+			nil,
 		)
 		log.Debug("ForceSyncOplogAck: (in-for-loop) after syncOplogAckCore", "nodes", nodes, "currentHour", currentHourTS, "nextHour", nextHourTS, "e", err, "entity", pm.Entity().GetID())
 		if err != nil {
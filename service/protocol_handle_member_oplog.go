@@ -36,6 +36,10 @@ func (pm *BaseProtocolManager) processMemberLog(oplog *BaseOplog, processInfo Pr
 		origLogs, err = pm.handleDeleteMemberLog(oplog, info)
 	case MemberOpTypeMigrateMember:
 		origLogs, err = pm.handleMigrateMemberLog(oplog, info)
+	case MemberOpTypeSetMemberRole:
+		origLogs, err = pm.handleSetMemberRoleLog(oplog, info)
+	case MemberOpTypeBanMember:
+		origLogs, err = pm.handleBanMemberLog(oplog, info)
 	}
 	return
 }
@@ -61,6 +65,10 @@ func (pm *BaseProtocolManager) processPendingMemberLog(oplog *BaseOplog, process
 		isToSign, origLogs, err = pm.handlePendingDeleteMemberLog(oplog, info)
 	case MemberOpTypeMigrateMember:
 		isToSign, origLogs, err = pm.handlePendingMigrateMemberLog(oplog, info)
+	case MemberOpTypeSetMemberRole:
+		isToSign, origLogs, err = pm.handlePendingSetMemberRoleLog(oplog, info)
+	case MemberOpTypeBanMember:
+		isToSign, origLogs, err = pm.handlePendingBanMemberLog(oplog, info)
 	}
 	return isToSign, origLogs, err
 }
@@ -83,6 +91,8 @@ func (pm *BaseProtocolManager) postprocessMemberOplogs(processInfo ProcessInfo,
 		}
 	}
 
+	pm.PostOplogEvents(OplogEventTypeMember, toBroadcastLogs)
+
 	pm.broadcastMemberOplogsCore(toBroadcastLogs)
 
 	if !isPending {
@@ -109,6 +119,8 @@ func (pm *BaseProtocolManager) SetNewestMemberOplog(oplog *BaseOplog) error {
 		isNewer, err = pm.setNewestDeleteMemberLog(oplog)
 	case MemberOpTypeMigrateMember:
 		isNewer, err = pm.setNewestMigrateMemberLog(oplog)
+	case MemberOpTypeSetMemberRole:
+	case MemberOpTypeBanMember:
 	}
 
 	if err != nil {
@@ -134,6 +146,8 @@ func (pm *BaseProtocolManager) HandleFailedMemberOplog(oplog *BaseOplog) error {
 		err = pm.handleFailedDeleteMemberLog(oplog)
 	case MemberOpTypeMigrateMember:
 		err = pm.handleFailedMigrateMemberLog(oplog)
+	case MemberOpTypeSetMemberRole:
+	case MemberOpTypeBanMember:
 	}
 
 	return err
@@ -153,6 +167,8 @@ func (pm *BaseProtocolManager) HandleFailedValidMemberOplog(oplog *BaseOplog, pr
 		err = pm.handleFailedValidDeleteMemberLog(oplog)
 	case MemberOpTypeMigrateMember:
 		err = pm.handleFailedValidMigrateMemberLog(oplog)
+	case MemberOpTypeSetMemberRole:
+	case MemberOpTypeBanMember:
 	}
 
 	return err
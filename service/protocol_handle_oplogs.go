@@ -147,6 +147,7 @@ func handleOplogs(
 			continue
 		}
 		if err != nil {
+			markOplogIntegrateFail(oplog, false)
 			break
 		}
 
@@ -324,6 +325,7 @@ func handlePendingOplogs(
 			continue
 		}
 		if err != nil {
+			markOplogIntegrateFail(oplog, true)
 			break
 		}
 
@@ -401,6 +403,11 @@ func handlePendingOplog(
 		return true, origLogs, nil
 	}
 
+	err = validatePendingOplogCreator(pm, oplog)
+	if err != nil {
+		return false, nil, err
+	}
+
 	// process pending log
 	isToSign, origLogs, err := processPendingLog(oplog, info)
 	if err == ErrNewerOplog {
@@ -438,6 +445,24 @@ func handlePendingOplog(
  * Handle Failed Oplogs
  **********/
 
+/*
+validatePendingOplogCreator skips the pending oplogs created by the followers of the public entity
+(not able to create oplogs) and by the banned users (the oplogs are not signed).
+*/
+func validatePendingOplogCreator(pm ProtocolManager, oplog *BaseOplog) error {
+	if pm.IsFollower(oplog.CreatorID) {
+		log.Warn("handlePendingOplog: oplog from follower", "entity", pm.Entity().GetID(), "creator", oplog.CreatorID, "oplog", oplog.ID)
+		return ErrSkipOplog
+	}
+
+	if pm.IsBanned(oplog.CreatorID, nil) {
+		log.Warn("handlePendingOplog: oplog from banned user", "entity", pm.Entity().GetID(), "creator", oplog.CreatorID, "oplog", oplog.ID)
+		return ErrSkipOplog
+	}
+
+	return nil
+}
+
 func HandleFailedOplogs(
 	oplogs []*BaseOplog,
 
@@ -576,6 +601,7 @@ func preprocessOplogs(
 		err = oplog.Verify()
 		if err != nil {
 			log.Warn("preprocessOplogs: unable to verify oplog", "op", oplog.Op, "e", err)
+			markOplogVerifyFail(oplog)
 			return nil, err
 		}
 	}
@@ -41,6 +41,17 @@ func (pm *BaseProtocolManager) IdentifyPeer(peer *PttPeer) {
 	}
 
 	ptt := pm.Ptt()
+
+	// the followers do not have the op-key, identifying the peer with my-id.
+	myID := ptt.GetMyEntity().GetID()
+	if pm.IsFollower(myID) {
+		err := ptt.IdentifyPeerWithMyID(peer)
+		if err != nil {
+			log.Warn("IdentifyPeer: unable to IdentifyPeerWithMyID", "e", err, "p", peer)
+		}
+		return
+	}
+
 	data, err := ptt.IdentifyPeer(pm.Entity().GetID(), pm.QuitSync(), peer, false)
 	if err != nil {
 		log.Warn("IdentifyPeer: unable to ptt.IdentifyPeer", "e", err, "p", peer, "userID", peer.UserID)
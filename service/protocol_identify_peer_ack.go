@@ -21,6 +21,7 @@ import (
 	"reflect"
 
 	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/key"
 	"github.com/ailabstw/go-pttai/log"
 )
 
@@ -31,6 +32,7 @@ type IdentifyPeerAck struct {
 	PubBytes     []byte        `json:"P,omitempty"`
 	Extra        *KeyExtraInfo `json:"E,omitempty"`
 	MyID         *types.PttID  `json:"M,omitempty"`
+	KeyType      key.Type      `json:"T,omitempty"`
 }
 
 /*
@@ -111,6 +113,7 @@ func (p *BasePtt) IdentifyPeerAck(challenge *types.Salt, peer *PttPeer) (*Identi
 		PubBytes:     pubBytes,
 		MyID:         myID,
 		Extra:        signKey.Extra,
+		KeyType:      signKey.KeyType,
 	}
 
 	return ackData, nil
@@ -126,7 +129,7 @@ func (p *BasePtt) HandleIdentifyPeerAck(entityID *types.PttID, data *IdentifyPee
 		return ErrInvalidData
 	}
 
-	err := VerifyData(data.AckChallenge, data.Hash, data.Sig, data.PubBytes, data.MyID, data.Extra)
+	err := VerifyData(data.AckChallenge, data.Hash, data.Sig, data.KeyType, data.PubBytes, data.MyID, data.Extra)
 	if err != nil {
 		log.Warn("HandleIdentifyPeerAck: unable to verify data", "peer", peer)
 		return err
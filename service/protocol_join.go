@@ -123,12 +123,12 @@ func (p *BasePtt) join(challenge []byte, hash *common.Address, joinKey *ecdsa.Pr
 
 	keyInfo := joinKeyToKeyInfo(joinKey)
 
-	encData, err := p.EncryptData(JoinMsg, data, keyInfo)
+	encData, err := p.EncryptDataWithVersion(peer.DataVersion(), CodeTypeJoin, JoinMsg, data, keyInfo)
 	if err != nil {
 		return err
 	}
 
-	pttData, err := p.MarshalData(CodeTypeJoin, hash, encData)
+	pttData, err := p.MarshalDataWithVersion(peer.DataVersion(), CodeTypeJoin, hash, encData)
 	if err != nil {
 		return err
 	}
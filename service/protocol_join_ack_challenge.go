@@ -47,12 +47,12 @@ func (p *BasePtt) JoinAckChallenge(keyInfo *KeyInfo, join *Join, peer *PttPeer,
 		return err
 	}
 
-	encData, err := p.EncryptData(JoinAckChallengeMsg, data, keyInfo)
+	encData, err := p.EncryptDataWithVersion(peer.DataVersion(), CodeTypeJoinAck, JoinAckChallengeMsg, data, keyInfo)
 	if err != nil {
 		return err
 	}
 
-	pttData, err := p.MarshalData(CodeTypeJoinAck, keyInfo.Hash, encData)
+	pttData, err := p.MarshalDataWithVersion(peer.DataVersion(), CodeTypeJoinAck, keyInfo.Hash, encData)
 	if err != nil {
 		return err
 	}
@@ -42,6 +42,7 @@ func (p *BasePtt) JoinEntity(joinRequest *JoinRequest, joinAckChallenge *JoinAck
 		ID:          id,
 		Name:        []byte(name),
 		Master0Hash: joinRequest.Master0Hash,
+		IsFollow:    joinRequest.IsFollow,
 	}
 
 	data, err := json.Marshal(joinEntity)
@@ -51,12 +52,12 @@ func (p *BasePtt) JoinEntity(joinRequest *JoinRequest, joinAckChallenge *JoinAck
 
 	keyInfo := joinKeyToKeyInfo(joinRequest.Key)
 
-	encData, err := p.EncryptData(JoinEntityMsg, data, keyInfo)
+	encData, err := p.EncryptDataWithVersion(peer.DataVersion(), CodeTypeJoin, JoinEntityMsg, data, keyInfo)
 	if err != nil {
 		return err
 	}
 
-	pttData, err := p.MarshalData(CodeTypeJoin, joinRequest.Hash, encData)
+	pttData, err := p.MarshalDataWithVersion(peer.DataVersion(), CodeTypeJoin, joinRequest.Hash, encData)
 	if err != nil {
 		return err
 	}
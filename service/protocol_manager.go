@@ -27,6 +27,7 @@ import (
 	"github.com/ailabstw/go-pttai/p2p/discover"
 	"github.com/ailabstw/go-pttai/pttdb"
 	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru"
 )
 
 type ProtocolManager interface {
@@ -181,6 +182,18 @@ type ProtocolManager interface {
 
 	MyMemberLog() *MemberOplog
 
+	// follower
+	IsPublic() bool
+	IsFollower(id *types.PttID) bool
+	RegisterFollower(id *types.PttID) error
+	UnregisterFollower(id *types.PttID) error
+	IsFollowerPeer(peer *PttPeer) bool
+	IsPublicPeer(peer *PttPeer) bool
+	PublicHash() *common.Address
+
+	// ban
+	IsBanned(id *types.PttID, nodeID *discover.NodeID) bool
+
 	ForceSyncMemberMerkle() (bool, error)
 
 	// log0
@@ -197,6 +210,12 @@ type ProtocolManager interface {
 
 	JoinKeyList() []*KeyInfo
 
+	// invite
+	CreateInvite(maxUses int, expireSeconds int64) (*Invite, error)
+	RevokeInvite(inviteID *types.PttID) error
+	GetInvite(inviteID *types.PttID) (*Invite, error)
+	GetInviteList() ([]*Invite, error)
+
 	// op
 
 	GetOpKeyFromHash(hash *common.Address, isLocked bool) (*KeyInfo, error)
@@ -217,7 +236,7 @@ type ProtocolManager interface {
 	ToRenewOpKeyTS() (types.Timestamp, error)
 
 	DBOpKeyLock() *types.LockMap
-	DBOpKey() *pttdb.LDBBatch
+	DBOpKey() pttdb.IndexBatch
 	DBOpKeyPrefix() []byte
 	DBOpKeyIdxPrefix() []byte
 
@@ -309,7 +328,7 @@ type ProtocolManager interface {
 	Ptt() Ptt
 
 	// db
-	DB() *pttdb.LDBBatch
+	DB() pttdb.IndexBatch
 	DBObjLock() *types.LockMap
 }
 
@@ -318,6 +337,8 @@ type MyProtocolManager interface {
 
 	SetMeDB(log *BaseOplog)
 	SetMasterDB(log *BaseOplog)
+
+	JoinBoard(joinRequest *JoinRequest) error
 }
 
 type PttProtocolManager interface {
@@ -328,6 +349,9 @@ type BaseProtocolManager struct {
 	// eventMux
 	eventMux *event.TypeMux
 
+	// oplog-event
+	postedOplogs *lru.Cache
+
 	// master
 	newestMasterLogID *types.PttID
 
@@ -376,6 +400,11 @@ type BaseProtocolManager struct {
 
 	joinKeyInfos []*KeyInfo
 
+	// invite
+	lockInvite sync.RWMutex
+
+	invites map[common.Address]*Invite
+
 	// op
 	lockOpKeyInfo sync.RWMutex
 
@@ -413,6 +442,14 @@ type BaseProtocolManager struct {
 	sendDataToPeersSub        *event.TypeMuxSubscription
 	sendDataToPeerWithCodeSub *event.TypeMuxSubscription
 
+	// follower
+	lockFollower sync.RWMutex
+	followers    map[types.PttID]struct{}
+
+	// read-receipt
+	lockReadReceipt sync.RWMutex
+	readReceipts    *lru.Cache
+
 	// sync
 	maxSyncRandomSeconds int
 	minSyncRandomSeconds int
@@ -433,7 +470,7 @@ type BaseProtocolManager struct {
 	ptt Ptt
 
 	// db
-	db     *pttdb.LDBBatch
+	db     pttdb.IndexBatch
 	dbLock *types.LockMap
 
 	// block
@@ -486,7 +523,7 @@ func NewBaseProtocolManager(
 	svc Service,
 
 	// db
-	db *pttdb.LDBBatch,
+	db pttdb.IndexBatch,
 
 ) (*BaseProtocolManager, error) {
 
@@ -544,12 +581,30 @@ func NewBaseProtocolManager(
 	dbMediaPrefix := append(DBMediaPrefix, entityID[:]...)
 	dbMediaIdxPrefix := append(DBMediaIdxPrefix, entityID[:]...)
 
+	// oplog-event
+	postedOplogs, err := lru.New(SizePostedOplogs)
+	if err != nil {
+		return nil, err
+	}
+
+	// read-receipt
+	readReceipts, err := lru.New(SizeReadReceipts)
+	if err != nil {
+		return nil, err
+	}
+
 	pm := &BaseProtocolManager{
 		eventMux: new(event.TypeMux),
 
+		// oplog-event
+		postedOplogs: postedOplogs,
+
 		// join
 		joinKeyInfos: make([]*KeyInfo, 0),
 
+		// invite
+		invites: make(map[common.Address]*Invite),
+
 		// master
 		isMaster:          isMaster,
 		dbMasterPrefix:    dbMasterPrefix,
@@ -604,6 +659,12 @@ func NewBaseProtocolManager(
 		isMemberPeer:    isMemberPeer,
 		isPendingPeer:   isPendingPeer,
 
+		// follower
+		followers: make(map[types.PttID]struct{}),
+
+		// read-receipt
+		readReceipts: readReceipts,
+
 		// sync
 		maxSyncRandomSeconds: maxSyncRandomSeconds,
 		minSyncRandomSeconds: minSyncRandomSeconds,
@@ -792,6 +853,10 @@ func (pm *BaseProtocolManager) Start() error {
 
 	pm.isStart = true
 
+	registerEntityPeers(pm)
+
+	pm.registerPublicHash()
+
 	log.Info("Start: to master merkle-tree", "entity", pm.Entity().IDString())
 
 	syncWG := pm.SyncWG()
@@ -849,6 +914,10 @@ func (pm *BaseProtocolManager) Stop() error {
 func (pm *BaseProtocolManager) Poststop() error {
 	if pm.isStart {
 		pm.syncWG.Wait()
+
+		unregisterEntityPeers(pm)
+
+		pm.unregisterPublicHash()
 	}
 
 	if pm.isPrestart {
@@ -875,7 +944,7 @@ func (pm *BaseProtocolManager) Ptt() Ptt {
 	return pm.ptt
 }
 
-func (pm *BaseProtocolManager) DB() *pttdb.LDBBatch {
+func (pm *BaseProtocolManager) DB() pttdb.IndexBatch {
 	return pm.db
 }
 
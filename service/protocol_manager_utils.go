@@ -142,8 +142,13 @@ func (pm *BaseProtocolManager) sendDataToPeersLoop() {
 	}
 }
 
+type pttDataKey struct {
+	version  PttDataVersion
+	isPublic bool
+}
+
 /*
-Send Data to Peers using op-key
+Send Data to Peers using op-key (signed with the public-hash for the public peers)
 */
 func (pm *BaseProtocolManager) sendDataToPeers(op OpType, data interface{}, peerList []*PttPeer) error {
 
@@ -157,26 +162,29 @@ func (pm *BaseProtocolManager) sendDataToPeers(op OpType, data interface{}, peer
 		return err
 	}
 
-	opKeyInfo, err := pm.GetOldestOpKey(false)
-	log.Debug("sendDataToPeers: after get opKey", "opKey", opKeyInfo, "entity", pm.Entity().IDString(), "e", err)
+	// encrypt / marshal once per ptt-data-version
+	pttDataByKey := make(map[pttDataKey]*PttData)
 
-	if err != nil {
-		return err
-	}
+	okCount := 0
+	nSkipped := 0
+	for _, peer := range peerList {
+		key := pttDataKey{version: peer.DataVersion(), isPublic: pm.IsPublicPeer(peer)}
 
-	ptt := pm.Ptt()
-	encData, err := ptt.EncryptData(op, dataBytes, opKeyInfo)
-	if err != nil {
-		return err
-	}
+		// the op-keys are not sent to the followers.
+		if key.isPublic && isOpKeyOp(op) {
+			nSkipped++
+			continue
+		}
 
-	pttData, err := ptt.MarshalData(CodeTypeOp, opKeyInfo.Hash, encData)
-	if err != nil {
-		return err
-	}
+		pttData, ok := pttDataByKey[key]
+		if !ok {
+			pttData, err = pm.marshalDataWithCode(CodeTypeOp, op, dataBytes, key.version, key.isPublic)
+			if err != nil {
+				return err
+			}
+			pttDataByKey[key] = pttData
+		}
 
-	okCount := 0
-	for _, peer := range peerList {
 		pttData.Node = peer.GetID()[:]
 		err := peer.SendData(pttData)
 		if err == nil {
@@ -185,7 +193,7 @@ func (pm *BaseProtocolManager) sendDataToPeers(op OpType, data interface{}, peer
 			log.Warn("sendDataToPeers: unable to SendData", "peer", peer, "entity", pm.Entity().IDString(), "e", err)
 		}
 	}
-	if okCount == 0 {
+	if okCount == 0 && nSkipped < len(peerList) {
 		return ErrNotSent
 	}
 
@@ -234,30 +242,54 @@ func (pm *BaseProtocolManager) sendDataToPeerWithCode(code CodeType, op OpType,
 		return err
 	}
 
-	opKeyInfo, err := pm.GetOldestOpKey(false)
-	if err != nil {
-		return err
+	isPublic := code == CodeTypeOp && pm.IsPublicPeer(peer)
+	if isPublic && isOpKeyOp(op) {
+		return nil
 	}
 
-	ptt := pm.Ptt()
-	encData, err := ptt.EncryptData(op, dataBytes, opKeyInfo)
+	pttData, err := pm.marshalDataWithCode(code, op, dataBytes, peer.DataVersion(), isPublic)
 	if err != nil {
 		return err
 	}
 
-	log.Debug("sendDataToPeerWithCode: to MarshalData", "hash", opKeyInfo.Hash, "entity", pm.Entity().IDString())
+	pttData.Node = peer.GetID()[:]
 
-	pttData, err := ptt.MarshalData(code, opKeyInfo.Hash, encData)
+	err = peer.SendData(pttData)
 	if err != nil {
 		return err
 	}
 
-	pttData.Node = peer.GetID()[:]
+	return nil
+}
 
-	err = peer.SendData(pttData)
+/*
+marshalDataWithCode encrypts the data with the op-key,
+or signs the data with my sign-key as CodeTypeOpPublic with the public-hash if isPublic.
+*/
+func (pm *BaseProtocolManager) marshalDataWithCode(code CodeType, op OpType, dataBytes []byte, version PttDataVersion, isPublic bool) (*PttData, error) {
+	ptt := pm.Ptt()
+
+	if isPublic {
+		hash := pm.PublicHash()
+		signedData, err := ptt.SignDataPublic(CodeTypeOpPublic, hash, op, dataBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		return ptt.MarshalDataWithVersion(version, CodeTypeOpPublic, hash, signedData)
+	}
+
+	opKeyInfo, err := pm.GetOldestOpKey(false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	encData, err := ptt.EncryptDataWithVersion(version, code, op, dataBytes, opKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug("marshalDataWithCode: to MarshalData", "hash", opKeyInfo.Hash, "entity", pm.Entity().IDString())
+
+	return ptt.MarshalDataWithVersion(version, code, opKeyInfo.Hash, encData)
 }
@@ -17,7 +17,10 @@
 package service
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"io"
 
 	"github.com/ailabstw/go-pttai/common"
 	"github.com/ailabstw/go-pttai/common/types"
@@ -87,24 +90,37 @@ func (pm *BaseProtocolManager) SplitContentBlocks(prefix []byte, objID *types.Pt
 }
 
 func (pm *BaseProtocolManager) SplitMediaBlocks(objID *types.PttID, buf []byte) (*types.PttID, [][][]byte, error) {
+	blockInfoID, hashs, _, err := pm.SplitMediaBlocksFromReader(objID, bytes.NewReader(buf))
+	return blockInfoID, hashs, err
+}
+
+/*
+SplitMediaBlocksFromReader splits the media into blocks while reading from r.
+
+Only one block (NByteInBlock bytes) is held in memory at a time, so files larger than the memory can be split. Returns the block-info-id, the hashs of the blocks and the number of bytes read.
+*/
+func (pm *BaseProtocolManager) SplitMediaBlocksFromReader(objID *types.PttID, r io.Reader) (*types.PttID, [][][]byte, int64, error) {
 
 	myEntity := pm.Ptt().GetMyEntity()
 
 	blockInfoID, err := types.NewPttID()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
 	hashs := make([][][]byte, 0)
 
 	fullDBPrefix, err := pm.FullBlockDBPrefix(nil)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
+	reader := bufio.NewReaderSize(r, NByteInBlock+2)
+
+	var size int64
 	lenCurrentBuf := 0
 	halfLenCurrentBuf := 0
-	realLenCurrentBuf := 0
+	var currentBuf []byte
 	var eachBlock *Block
 	var bufs [][]byte
 	var eachHashs [][]byte
@@ -112,14 +128,23 @@ func (pm *BaseProtocolManager) SplitMediaBlocks(objID *types.PttID, buf []byte)
 	var secondHalfBuf []byte
 	var scrambledBufs [][]byte
 
-	for blockID, currentBuf := 0, buf[0:]; len(currentBuf) != 0; blockID, currentBuf = blockID+1, currentBuf[lenCurrentBuf:] {
+	for blockID := 0; ; blockID++ {
 		// 1. Unless there is only 1 char, we hope that both blocks contains at least 1 char. Squeezing the last-char to the block.
-		realLenCurrentBuf = len(currentBuf)
-		if realLenCurrentBuf == NByteInBlock+1 {
-			lenCurrentBuf = realLenCurrentBuf
+		currentBuf, err = reader.Peek(NByteInBlock + 2)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return nil, nil, 0, err
+		}
+		if len(currentBuf) == 0 {
+			break
+		}
+		if len(currentBuf) == NByteInBlock+1 {
+			lenCurrentBuf = len(currentBuf)
 		} else {
 			lenCurrentBuf = common.MinInt(NByteInBlock, len(currentBuf))
 		}
+		currentBuf = common.CloneBytes(currentBuf[:lenCurrentBuf])
+		reader.Discard(lenCurrentBuf)
+		size += int64(lenCurrentBuf)
 
 		// 2. construct the bufs
 		halfLenCurrentBuf = (lenCurrentBuf + 1) / 2
@@ -131,7 +156,7 @@ func (pm *BaseProtocolManager) SplitMediaBlocks(objID *types.PttID, buf []byte)
 		// 3. scramble the buf
 		scrambledBufs, err = ScrambleBuf(bufs)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, 0, err
 		}
 
 		// 4. construct the hash
@@ -139,17 +164,17 @@ func (pm *BaseProtocolManager) SplitMediaBlocks(objID *types.PttID, buf []byte)
 		for subBlockID, scrambledBuf := range scrambledBufs {
 			eachBlock, err = NewBlock(uint32(blockID), uint8(subBlockID), scrambledBuf)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, 0, err
 			}
 			eachBlock.SetDB(pm.DB(), fullDBPrefix, objID, blockInfoID)
 			err = myEntity.SignBlock(eachBlock)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, 0, err
 			}
 
 			err = eachBlock.Save()
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, 0, err
 			}
 			eachHashs[subBlockID] = eachBlock.Hash
 		}
@@ -158,7 +183,7 @@ func (pm *BaseProtocolManager) SplitMediaBlocks(objID *types.PttID, buf []byte)
 		hashs = append(hashs, eachHashs)
 	}
 
-	return blockInfoID, hashs, nil
+	return blockInfoID, hashs, size, nil
 }
 
 /*
@@ -0,0 +1,176 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+/*
+IsPublic returns whether the entity is open for the non-members to follow.
+*/
+func (pm *BaseProtocolManager) IsPublic() bool {
+	return pm.Entity().GetEntityType() == EntityTypePublic
+}
+
+/*
+IsFollower returns whether the id is reading the public entity without being a member.
+Followers are able to sync the entity, but not able to create objects.
+The banned users are not followers.
+*/
+func (pm *BaseProtocolManager) IsFollower(id *types.PttID) bool {
+	if id == nil || !pm.IsPublic() {
+		return false
+	}
+
+	return !pm.IsMember(id, false) && !pm.IsBanned(id, nil)
+}
+
+/*
+RegisterFollower registers the id as a follower of the public entity.
+The followers are saved in the db and loaded in Start.
+*/
+func (pm *BaseProtocolManager) RegisterFollower(id *types.PttID) error {
+	if !pm.IsFollower(id) {
+		return types.ErrInvalidID
+	}
+
+	follower, err := NewFollower(pm.Entity().GetID(), id)
+	if err != nil {
+		return err
+	}
+
+	pm.lockFollower.Lock()
+	defer pm.lockFollower.Unlock()
+
+	err = follower.Save(pm.DB().DB())
+	if err != nil {
+		return err
+	}
+
+	pm.followers[*id] = struct{}{}
+
+	return nil
+}
+
+/*
+UnregisterFollower removes the id from the followers.
+*/
+func (pm *BaseProtocolManager) UnregisterFollower(id *types.PttID) error {
+	pm.lockFollower.Lock()
+	defer pm.lockFollower.Unlock()
+
+	delete(pm.followers, *id)
+
+	follower := &Follower{ID: id, EntityID: pm.Entity().GetID()}
+	return follower.Delete(pm.DB().DB())
+}
+
+/*
+loadFollowers registers the followers saved in the db.
+*/
+func (pm *BaseProtocolManager) loadFollowers() error {
+	followers, err := GetFollowerList(pm.DB().DB(), pm.Entity().GetID())
+	if err != nil {
+		return err
+	}
+
+	pm.lockFollower.Lock()
+	defer pm.lockFollower.Unlock()
+
+	for _, follower := range followers {
+		pm.followers[*follower.ID] = struct{}{}
+	}
+
+	return nil
+}
+
+/*
+IsFollowerPeer returns whether the peer is the registered follower (not a member) of the public entity.
+*/
+func (pm *BaseProtocolManager) IsFollowerPeer(peer *PttPeer) bool {
+	if !pm.IsFollower(peer.UserID) {
+		return false
+	}
+
+	pm.lockFollower.RLock()
+	defer pm.lockFollower.RUnlock()
+
+	_, ok := pm.followers[*peer.UserID]
+	return ok
+}
+
+/*
+IsJoinApprover returns whether the id is able to approve the joins.
+The masters approve the joins. The members of the public entity approve the follows.
+*/
+func (pm *BaseProtocolManager) IsJoinApprover(id *types.PttID) bool {
+	if pm.IsMaster(id, false) {
+		return true
+	}
+
+	return pm.IsPublic() && pm.IsMember(id, false)
+}
+
+/*
+PublicHash returns the hash of the public entity, used in place of the op-key hash
+for the signed (not encrypted) data to / from the followers.
+*/
+func (pm *BaseProtocolManager) PublicHash() *common.Address {
+	hash := common.BytesToAddress(crypto.Keccak256(pm.Entity().GetID()[:]))
+	return &hash
+}
+
+/*
+IsPublicPeer returns whether the data with the peer is signed with the public-hash instead of encrypted with the op-key.
+That is, either I or the peer is a follower of the public entity.
+*/
+func (pm *BaseProtocolManager) IsPublicPeer(peer *PttPeer) bool {
+	if !pm.IsPublic() {
+		return false
+	}
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	return pm.IsFollower(myID) || pm.IsFollowerPeer(peer)
+}
+
+/*
+registerPublicHash registers the public-hash and loads the followers of the public entity.
+*/
+func (pm *BaseProtocolManager) registerPublicHash() {
+	if !pm.IsPublic() {
+		return
+	}
+
+	err := pm.loadFollowers()
+	if err != nil {
+		log.Warn("registerPublicHash: unable to loadFollowers", "entity", pm.Entity().IDString(), "e", err)
+	}
+
+	pm.Ptt().AddPublicHash(pm.PublicHash(), pm.Entity().GetID(), false)
+}
+
+func (pm *BaseProtocolManager) unregisterPublicHash() {
+	if !pm.IsPublic() {
+		return
+	}
+
+	pm.Ptt().RemovePublicHash(pm.PublicHash(), pm.Entity().GetID(), false)
+}
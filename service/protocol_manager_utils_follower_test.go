@@ -0,0 +1,296 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+/*
+tPublicEntity is the entity with the entity-type and the bans.
+*/
+type tPublicEntity struct {
+	Entity
+	id         *types.PttID
+	entityType EntityType
+	bans       []*BanInfo
+}
+
+func (e *tPublicEntity) GetID() *types.PttID {
+	return e.id
+}
+
+func (e *tPublicEntity) IDString() string {
+	return e.id.String()
+}
+
+func (e *tPublicEntity) GetEntityType() EntityType {
+	return e.entityType
+}
+
+func (e *tPublicEntity) GetBans() []*BanInfo {
+	return e.bans
+}
+
+/*
+tFollowerMyEntity is my-entity with my-id and my sign-key.
+*/
+type tFollowerMyEntity struct {
+	PttMyEntity
+	id      *types.PttID
+	signKey *KeyInfo
+}
+
+func (e *tFollowerMyEntity) GetID() *types.PttID {
+	return e.id
+}
+
+func (e *tFollowerMyEntity) SignKey() *KeyInfo {
+	return e.signKey
+}
+
+type tFollowerPtt struct {
+	Ptt
+	myEntity *tFollowerMyEntity
+}
+
+func (p *tFollowerPtt) GetMyEntity() MyEntity {
+	return p.myEntity
+}
+
+var (
+	tFollowerMasterID = &types.PttID{1}
+	tFollowerMemberID = &types.PttID{2}
+	tFollowerID       = &types.PttID{3}
+	tFollowerBannedID = &types.PttID{4}
+)
+
+func setupFollowerTest(entityID *types.PttID, entityType EntityType, myID *types.PttID) *BaseProtocolManager {
+	pm := &BaseProtocolManager{
+		db:     tDBOplog,
+		dbLock: tDBLock,
+		entity: &tPublicEntity{
+			id:         entityID,
+			entityType: entityType,
+			bans:       []*BanInfo{&BanInfo{ID: tFollowerBannedID, IsBanned: true}},
+		},
+		ptt:       &tFollowerPtt{myEntity: &tFollowerMyEntity{id: myID}},
+		followers: make(map[types.PttID]struct{}),
+	}
+	pm.isMaster = func(id *types.PttID, isLocked bool) bool {
+		return id != nil && *id == *tFollowerMasterID
+	}
+	pm.isMember = func(id *types.PttID, isLocked bool) bool {
+		return id != nil && (*id == *tFollowerMasterID || *id == *tFollowerMemberID)
+	}
+
+	return pm
+}
+
+func TestBaseProtocolManager_IsJoinApprover(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	entityID, _ := types.NewPttID()
+	privatePM := setupFollowerTest(entityID, EntityTypePrivate, tFollowerMasterID)
+	publicPM := setupFollowerTest(entityID, EntityTypePublic, tFollowerMasterID)
+
+	// prepare test-cases
+	tests := []struct {
+		name string
+		pm   *BaseProtocolManager
+		id   *types.PttID
+		want bool
+	}{
+		{name: "private master", pm: privatePM, id: tFollowerMasterID, want: true},
+		{name: "private member", pm: privatePM, id: tFollowerMemberID, want: false},
+		{name: "private non-member", pm: privatePM, id: tFollowerID, want: false},
+		{name: "public master", pm: publicPM, id: tFollowerMasterID, want: true},
+		{name: "public member", pm: publicPM, id: tFollowerMemberID, want: true},
+		{name: "public follower", pm: publicPM, id: tFollowerID, want: false},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pm.IsJoinApprover(tt.id); got != tt.want {
+				t.Errorf("BaseProtocolManager.IsJoinApprover() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseProtocolManager_IsFollower(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	entityID, _ := types.NewPttID()
+	privatePM := setupFollowerTest(entityID, EntityTypePrivate, tFollowerMasterID)
+	publicPM := setupFollowerTest(entityID, EntityTypePublic, tFollowerMasterID)
+
+	// prepare test-cases
+	tests := []struct {
+		name string
+		pm   *BaseProtocolManager
+		id   *types.PttID
+		want bool
+	}{
+		{name: "public non-member", pm: publicPM, id: tFollowerID, want: true},
+		{name: "public member", pm: publicPM, id: tFollowerMemberID, want: false},
+		{name: "public banned", pm: publicPM, id: tFollowerBannedID, want: false},
+		{name: "public nil", pm: publicPM, id: nil, want: false},
+		{name: "private non-member", pm: privatePM, id: tFollowerID, want: false},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pm.IsFollower(tt.id); got != tt.want {
+				t.Errorf("BaseProtocolManager.IsFollower() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseProtocolManager_RegisterFollower(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	entityID, _ := types.NewPttID()
+	pm := setupFollowerTest(entityID, EntityTypePublic, tFollowerMasterID)
+	followerPeer := &PttPeer{UserID: tFollowerID}
+
+	// run test
+	err := pm.RegisterFollower(tFollowerMemberID)
+	if err != types.ErrInvalidID {
+		t.Errorf("BaseProtocolManager.RegisterFollower() member: error = %v, want %v", err, types.ErrInvalidID)
+	}
+
+	err = pm.RegisterFollower(tFollowerID)
+	if err != nil {
+		t.Errorf("BaseProtocolManager.RegisterFollower() error = %v", err)
+	}
+	if !pm.IsFollowerPeer(followerPeer) {
+		t.Errorf("BaseProtocolManager.IsFollowerPeer() = false, want true")
+	}
+
+	// followers are loaded from the db after restarting.
+	pm2 := setupFollowerTest(entityID, EntityTypePublic, tFollowerMasterID)
+	if pm2.IsFollowerPeer(followerPeer) {
+		t.Errorf("BaseProtocolManager.IsFollowerPeer() before loadFollowers = true, want false")
+	}
+	err = pm2.loadFollowers()
+	if err != nil {
+		t.Errorf("BaseProtocolManager.loadFollowers() error = %v", err)
+	}
+	if !pm2.IsFollowerPeer(followerPeer) {
+		t.Errorf("BaseProtocolManager.IsFollowerPeer() after loadFollowers = false, want true")
+	}
+
+	// the followers of the other entities are not loaded.
+	otherEntityID, _ := types.NewPttID()
+	otherPM := setupFollowerTest(otherEntityID, EntityTypePublic, tFollowerMasterID)
+	otherPM.loadFollowers()
+	if otherPM.IsFollowerPeer(followerPeer) {
+		t.Errorf("BaseProtocolManager.IsFollowerPeer() other entity = true, want false")
+	}
+
+	// unregister
+	err = pm2.UnregisterFollower(tFollowerID)
+	if err != nil {
+		t.Errorf("BaseProtocolManager.UnregisterFollower() error = %v", err)
+	}
+	pm3 := setupFollowerTest(entityID, EntityTypePublic, tFollowerMasterID)
+	pm3.loadFollowers()
+	if pm3.IsFollowerPeer(followerPeer) {
+		t.Errorf("BaseProtocolManager.IsFollowerPeer() after UnregisterFollower = true, want false")
+	}
+}
+
+func TestBaseProtocolManager_IsPublicPeer(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	entityID, _ := types.NewPttID()
+	privatePM := setupFollowerTest(entityID, EntityTypePrivate, tFollowerMasterID)
+	publicPM := setupFollowerTest(entityID, EntityTypePublic, tFollowerMasterID)
+	publicPM.followers[*tFollowerID] = struct{}{}
+	followerPM := setupFollowerTest(entityID, EntityTypePublic, tFollowerID)
+
+	// prepare test-cases
+	tests := []struct {
+		name string
+		pm   *BaseProtocolManager
+		peer *PttPeer
+		want bool
+	}{
+		{name: "follower peer", pm: publicPM, peer: &PttPeer{UserID: tFollowerID}, want: true},
+		{name: "member peer", pm: publicPM, peer: &PttPeer{UserID: tFollowerMemberID}, want: false},
+		{name: "unidentified peer", pm: publicPM, peer: &PttPeer{}, want: false},
+		{name: "I am follower", pm: followerPM, peer: &PttPeer{UserID: tFollowerMasterID}, want: true},
+		{name: "private", pm: privatePM, peer: &PttPeer{UserID: tFollowerID}, want: false},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pm.IsPublicPeer(tt.peer); got != tt.want {
+				t.Errorf("BaseProtocolManager.IsPublicPeer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validatePendingOplogCreator(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	entityID, _ := types.NewPttID()
+	privatePM := setupFollowerTest(entityID, EntityTypePrivate, tFollowerMasterID)
+	publicPM := setupFollowerTest(entityID, EntityTypePublic, tFollowerMasterID)
+
+	// prepare test-cases
+	tests := []struct {
+		name      string
+		pm        *BaseProtocolManager
+		creatorID *types.PttID
+		want      error
+	}{
+		{name: "public member", pm: publicPM, creatorID: tFollowerMemberID, want: nil},
+		{name: "public non-member", pm: publicPM, creatorID: tFollowerID, want: ErrSkipOplog},
+		{name: "public banned", pm: publicPM, creatorID: tFollowerBannedID, want: ErrSkipOplog},
+		{name: "private member", pm: privatePM, creatorID: tFollowerMemberID, want: nil},
+		{name: "private banned", pm: privatePM, creatorID: tFollowerBannedID, want: ErrSkipOplog},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oplog := &BaseOplog{CreatorID: tt.creatorID}
+			if got := validatePendingOplogCreator(tt.pm, oplog); got != tt.want {
+				t.Errorf("validatePendingOplogCreator() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
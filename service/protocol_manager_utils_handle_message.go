@@ -21,16 +21,9 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-func PMHandleMessageWrapper(pm ProtocolManager, hash *common.Address, encData []byte, peer *PttPeer) error {
-	opKeyInfo, err := pm.GetOpKeyFromHash(hash, false)
+func PMHandleMessageWrapper(pm ProtocolManager, code CodeType, hash *common.Address, encData []byte, peer *PttPeer) error {
+	op, dataBytes, err := pmOpenData(pm, code, hash, encData, peer)
 	if err != nil {
-		log.Error("PMHandleMessageWrapper: unable to GetOpKeyFromHash", "e", err, "entity", pm.Entity().IDString(), "peer", peer)
-		return err
-	}
-
-	op, dataBytes, err := pm.Ptt().DecryptData(encData, opKeyInfo)
-	if err != nil {
-		log.Error("PMHandleMessageWrapper: unable to DecryptData", "e", err, "entity", pm.Entity().IDString(), "peer", peer)
 		return err
 	}
 
@@ -274,3 +267,43 @@ func PMHandleMessageWrapper(pm ProtocolManager, hash *common.Address, encData []
 
 	return err
 }
+
+/*
+pmOpenData decrypts the data with the op-key, or verifies the signed data of the public entity.
+The op-key oplogs are not accepted from the public data.
+*/
+func pmOpenData(pm ProtocolManager, code CodeType, hash *common.Address, encData []byte, peer *PttPeer) (OpType, []byte, error) {
+	if code == CodeTypeOpPublic {
+		op, dataBytes, err := pm.Ptt().VerifyDataPublic(code, hash, encData, peer)
+		if err != nil {
+			log.Error("PMHandleMessageWrapper: unable to VerifyDataPublic", "e", err, "entity", pm.Entity().IDString(), "peer", peer)
+			return 0, nil, err
+		}
+		if isOpKeyOp(op) {
+			log.Error("PMHandleMessageWrapper: op-key op in public data", "op", op, "entity", pm.Entity().IDString(), "peer", peer)
+			return 0, nil, ErrInvalidOp
+		}
+		return op, dataBytes, nil
+	}
+
+	opKeyInfo, err := pm.GetOpKeyFromHash(hash, false)
+	if err != nil {
+		log.Error("PMHandleMessageWrapper: unable to GetOpKeyFromHash", "e", err, "entity", pm.Entity().IDString(), "peer", peer)
+		return 0, nil, err
+	}
+
+	op, dataBytes, err := pm.Ptt().DecryptDataWithVersion(peer.DataVersion(), code, encData, opKeyInfo)
+	if err != nil {
+		log.Error("PMHandleMessageWrapper: unable to DecryptData", "e", err, "entity", pm.Entity().IDString(), "peer", peer)
+		return 0, nil, err
+	}
+
+	return op, dataBytes, nil
+}
+
+/*
+isOpKeyOp returns whether the op is carrying the op-keys or the op-key oplogs.
+*/
+func isOpKeyOp(op OpType) bool {
+	return op >= AddOpKeyOplogMsg && op <= SyncCreateOpKeyAckMsg
+}
@@ -0,0 +1,293 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+/*
+CreateInvite creates the invite able to be used by maxUses users (0 as unlimited) within expireSeconds.
+*/
+func (pm *BaseProtocolManager) CreateInvite(maxUses int, expireSeconds int64) (*Invite, error) {
+	entity := pm.Entity()
+	if entity.GetStatus() != types.StatusAlive {
+		return nil, ErrInvalidStatus
+	}
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	if !pm.IsJoinApprover(myID) {
+		return nil, types.ErrInvalidID
+	}
+
+	entityID := entity.GetID()
+	invite, err := NewInvite(entityID, myID, maxUses, expireSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	pm.lockInvite.Lock()
+	defer pm.lockInvite.Unlock()
+
+	err = invite.Save(pm.DB().DB())
+	if err != nil {
+		return nil, err
+	}
+
+	pm.invites[*invite.KeyInfo.Hash] = invite
+	pm.ptt.AddJoinKey(invite.KeyInfo.Hash, entityID, false)
+
+	return invite, nil
+}
+
+/*
+RevokeInvite revokes the invite. The invite is kept in the invite-list with IsRevoked.
+*/
+func (pm *BaseProtocolManager) RevokeInvite(inviteID *types.PttID) error {
+	pm.lockInvite.Lock()
+	defer pm.lockInvite.Unlock()
+
+	invite, err := pm.getInvite(inviteID)
+	if err != nil {
+		return err
+	}
+
+	if invite.IsRevoked {
+		return nil
+	}
+
+	invite.IsRevoked = true
+	err = invite.Save(pm.DB().DB())
+	if err != nil {
+		return err
+	}
+
+	pm.removeInvite(invite)
+
+	return nil
+}
+
+func (pm *BaseProtocolManager) GetInvite(inviteID *types.PttID) (*Invite, error) {
+	pm.lockInvite.RLock()
+	defer pm.lockInvite.RUnlock()
+
+	return pm.getInvite(inviteID)
+}
+
+func (pm *BaseProtocolManager) getInvite(inviteID *types.PttID) (*Invite, error) {
+	invite := &Invite{ID: inviteID, EntityID: pm.Entity().GetID()}
+	key, err := invite.MarshalKey()
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := pm.DB().DB().Get(key)
+	if err == leveldb.ErrNotFound {
+		return nil, ErrInvalidInvite
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = invite.Unmarshal(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return invite, nil
+}
+
+/*
+GetInviteList returns all the invites of the entity, including the revoked / expired / used-up invites.
+*/
+func (pm *BaseProtocolManager) GetInviteList() ([]*Invite, error) {
+	pm.lockInvite.RLock()
+	defer pm.lockInvite.RUnlock()
+
+	return GetInviteList(pm.DB().DB(), pm.Entity().GetID())
+}
+
+/*
+loadInvites registers the join-keys of the valid invites.
+*/
+func (pm *BaseProtocolManager) loadInvites() error {
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return err
+	}
+
+	pm.lockInvite.Lock()
+	defer pm.lockInvite.Unlock()
+
+	invites, err := GetInviteList(pm.DB().DB(), pm.Entity().GetID())
+	if err != nil {
+		return err
+	}
+
+	entityID := pm.Entity().GetID()
+	for _, invite := range invites {
+		if !invite.IsValid(ts) {
+			continue
+		}
+
+		pm.invites[*invite.KeyInfo.Hash] = invite
+		pm.ptt.AddJoinKey(invite.KeyInfo.Hash, entityID, false)
+	}
+
+	return nil
+}
+
+/*
+cleanInvites removes the join-keys of the expired invites.
+*/
+func (pm *BaseProtocolManager) cleanInvites() error {
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return err
+	}
+
+	pm.lockInvite.Lock()
+	defer pm.lockInvite.Unlock()
+
+	for _, invite := range pm.invites {
+		if invite.IsValid(ts) {
+			continue
+		}
+		pm.removeInvite(invite)
+	}
+
+	return nil
+}
+
+func (pm *BaseProtocolManager) removeInvite(invite *Invite) {
+	delete(pm.invites, *invite.KeyInfo.Hash)
+	pm.ptt.RemoveJoinKey(invite.KeyInfo.Hash, pm.Entity().GetID(), false)
+}
+
+func (pm *BaseProtocolManager) getInviteKeyFromHash(hash *common.Address) (*KeyInfo, error) {
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return nil, err
+	}
+
+	pm.lockInvite.RLock()
+	defer pm.lockInvite.RUnlock()
+
+	invite, ok := pm.invites[*hash]
+	if !ok || !invite.IsValid(ts) {
+		return nil, ErrInvalidKeyInfo
+	}
+
+	return invite.KeyInfo, nil
+}
+
+/*
+useInvite checks whether the id is able to join with the join-key hash,
+and records the use of the invite in the same lock, so that the concurrent joins
+are not able to consume more than MaxUses.
+The join-key is removed if the invite is used up.
+
+The join-keys not from the invites (ex: the renewed join-keys) are always valid.
+The invites already removed from the joins (revoked / expired / used-up) are loaded from the db.
+
+Returns true if the use is newly recorded (to be rolled back with unuseInvite if the join fails).
+*/
+func (pm *BaseProtocolManager) useInvite(hash *common.Address, id *types.PttID) (bool, error) {
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return false, err
+	}
+
+	pm.lockInvite.Lock()
+	defer pm.lockInvite.Unlock()
+
+	invite, ok := pm.invites[*hash]
+	if !ok {
+		invite, err = pm.getInvite(keyInfoHashToID(hash))
+		if err == ErrInvalidInvite {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if !invite.IsValidFor(id, ts) {
+		return false, ErrInvalidInvite
+	}
+
+	if !invite.Use(id, ts) {
+		return false, nil
+	}
+
+	err = invite.Save(pm.DB().DB())
+	if err != nil {
+		invite.Unuse(id)
+		return false, err
+	}
+
+	log.Debug("useInvite: done", "entity", pm.Entity().IDString(), "invite", invite.ID, "id", id, "nUses", invite.NUses())
+
+	if !invite.IsValid(ts) {
+		pm.removeInvite(invite)
+	}
+
+	return true, nil
+}
+
+/*
+unuseInvite rolls back the use of the invite by the id (the join failed after useInvite),
+and adds back the join-key if the invite is valid again.
+*/
+func (pm *BaseProtocolManager) unuseInvite(hash *common.Address, id *types.PttID) error {
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return err
+	}
+
+	pm.lockInvite.Lock()
+	defer pm.lockInvite.Unlock()
+
+	invite, ok := pm.invites[*hash]
+	if !ok {
+		invite, err = pm.getInvite(keyInfoHashToID(hash))
+		if err != nil {
+			return err
+		}
+	}
+
+	if !invite.Unuse(id) {
+		return nil
+	}
+
+	err = invite.Save(pm.DB().DB())
+	if err != nil {
+		return err
+	}
+
+	log.Debug("unuseInvite: done", "entity", pm.Entity().IDString(), "invite", invite.ID, "id", id, "nUses", invite.NUses())
+
+	if !ok && invite.IsValid(ts) {
+		pm.invites[*invite.KeyInfo.Hash] = invite
+		pm.ptt.AddJoinKey(invite.KeyInfo.Hash, pm.Entity().GetID(), false)
+	}
+
+	return nil
+}
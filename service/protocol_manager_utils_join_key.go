@@ -41,7 +41,7 @@ func (pm *BaseProtocolManager) GetJoinKeyFromHash(hash *common.Address) (*KeyInf
 	}
 
 	if keyInfo == nil {
-		return nil, ErrInvalidKeyInfo
+		return pm.getInviteKeyFromHash(hash)
 	}
 
 	return keyInfo, nil
@@ -65,12 +65,14 @@ func (pm *BaseProtocolManager) CreateJoinKeyLoop() error {
 	defer ticker.Stop()
 
 	pm.createJoinKey()
+	pm.loadInvites()
 
 loop:
 	for {
 		select {
 		case <-ticker.C:
 			pm.createJoinKey()
+			pm.cleanInvites()
 		case <-pm.QuitSync():
 			log.Debug("CreateJoinKeyLoop: QuitSync", "entity", pm.Entity().IDString())
 			break loop
@@ -94,7 +96,7 @@ func (pm *BaseProtocolManager) createJoinKey() error {
 		return nil
 	}
 
-	if !pm.IsMaster(myEntity.GetID(), false) {
+	if !pm.IsJoinApprover(myEntity.GetID()) {
 		return nil
 	}
 
@@ -150,4 +152,11 @@ func (pm *BaseProtocolManager) CleanJoinKey() {
 	for _, keyInfo := range pm.joinKeyInfos {
 		pm.ptt.RemoveJoinKey(keyInfo.Hash, entityID, false)
 	}
+
+	pm.lockInvite.Lock()
+	defer pm.lockInvite.Unlock()
+
+	for _, invite := range pm.invites {
+		pm.removeInvite(invite)
+	}
 }
@@ -279,6 +279,11 @@ func (pm *BaseProtocolManager) ConnectMaster() error {
 		return nil
 	}
 
+	// the followers dial with the public-hash.
+	if pm.IsFollower(myID) {
+		return pm.Ptt().AddDial(nodeID, pm.PublicHash(), PeerTypeImportant, true)
+	}
+
 	opKey, err := pm.GetOldestOpKey(false)
 	if err != nil {
 		return err
@@ -110,6 +110,9 @@ func (pm *BaseProtocolManager) loadMyMemberLog() error {
 	if !pm.Entity().IsOwner(myID) {
 		return nil
 	}
+	if pm.IsFollower(myID) {
+		return nil
+	}
 	memberLog, err := pm.GetMemberLogByMemberID(myID, false)
 	if err != nil {
 		return err
@@ -44,9 +44,19 @@ func PMOplogMerkleTreeLoop(pm ProtocolManager, merkle *Merkle) error {
 
 	pmGenerateOplogMerkleTree(pm, merkle)
 
+	var delayGenerate <-chan time.Time
+
 loop:
 	for {
 		select {
+		case <-merkle.ToGenerate():
+			if delayGenerate == nil {
+				delayGenerate = time.After(GenerateOplogMerkleTreeDelay)
+			}
+		case <-delayGenerate:
+			delayGenerate = nil
+			log.Debug("PMOplogMerkleTreeLoop (toGenerate): to pmGenerateOplogMerkleTree", "merkle", merkleName)
+			pmGenerateOplogMerkleTree(pm, merkle)
 		case <-ticker.C:
 			log.Debug("PMOplogMerkleTreeLoop (ticker): to pmGenerateOplogMerkleTree", "merkle", merkleName)
 			pmGenerateOplogMerkleTree(pm, merkle)
@@ -359,6 +359,8 @@ func (pm *BaseProtocolManager) GetPendingOplogs(setDB func(oplog *BaseOplog), pe
 		return nil, nil, err
 	}
 
+	updatePendingOplogs(pm.Entity().IDString(), oplog, len(pendingLogs)+len(internalPendingLogs))
+
 	isMyPeer := false
 	isMasterPeer := false
 	if peer != nil {
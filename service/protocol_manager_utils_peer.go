@@ -197,7 +197,11 @@ func (pm *BaseProtocolManager) defaultIsMemberPeer(peer *PttPeer) bool {
 		return false
 	}
 
-	return pm.IsMember(peer.UserID, false)
+	if pm.IsMember(peer.UserID, false) {
+		return true
+	}
+
+	return pm.IsFollowerPeer(peer)
 }
 
 func (pm *BaseProtocolManager) IsPendingPeer(peer *PttPeer) bool {
@@ -212,8 +216,11 @@ func (pm *BaseProtocolManager) defaultIsPendingPeer(peer *PttPeer) bool {
 	return pm.Peers().IsPendingPeer(peer, false)
 }
 
+/*
+IsSuspiciousID returns whether the join from the id and the node-id is auto-rejected (banned).
+*/
 func (pm *BaseProtocolManager) IsSuspiciousID(id *types.PttID, nodeID *discover.NodeID) bool {
-	return false
+	return pm.IsBanned(id, nodeID)
 }
 
 func (pm *BaseProtocolManager) IsGoodID(id *types.PttID, nodeID *discover.NodeID) bool {
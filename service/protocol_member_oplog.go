@@ -25,6 +25,8 @@ func (pm *BaseProtocolManager) BroadcastMemberOplog(oplog *MemberOplog) error {
 }
 
 func (pm *BaseProtocolManager) broadcastMemberOplogCore(oplog *BaseOplog) error {
+	pm.PostOplogEvents(OplogEventTypeMember, []*BaseOplog{oplog})
+
 	return pm.BroadcastOplog(oplog, AddMemberOplogMsg, AddPendingMemberOplogMsg)
 }
 
@@ -60,7 +62,7 @@ func (pm *BaseProtocolManager) CleanMemberOplog(isRetainLog bool) {
 	pm.CleanOplog(oplog, pm.MemberMerkle())
 
 	// retain my-log
-	if isRetainLog {
+	if isRetainLog && pm.myMemberLog != nil {
 		myLog := pm.myMemberLog
 		myLog.Save(false, nil)
 	}
@@ -0,0 +1,172 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+/*
+SendReadReceipt sends my read-position to the peers of the entity if I share the read-receipts.
+*/
+func (pm *BaseProtocolManager) SendReadReceipt(op OpType, objID *types.PttID, lastSeen types.Timestamp) error {
+	myEntity := pm.Ptt().GetMyEntity()
+	setting := myEntity.GetPrivacySetting()
+	if setting == nil || !setting.IsShareReadReceipt {
+		return nil
+	}
+
+	data := &ReadReceipt{
+		EntityID: pm.Entity().GetID(),
+		ObjID:    objID,
+		UserID:   myEntity.GetID(),
+		LastSeen: lastSeen,
+	}
+
+	return pm.SendDataToPeers(op, data, pm.Peers().PeerList(false))
+}
+
+func (pm *BaseProtocolManager) HandleReadReceipt(dataBytes []byte, peer *PttPeer) error {
+	data := &ReadReceipt{}
+	err := json.Unmarshal(dataBytes, data)
+	if err != nil {
+		return err
+	}
+
+	err = pm.validateEphemeralUser(data.UserID, peer)
+	if err != nil {
+		return err
+	}
+	data.EntityID = pm.Entity().GetID()
+
+	if !pm.setReadReceipt(data) {
+		return nil
+	}
+
+	pm.Entity().Service().SPM().EventMux().Post(data)
+
+	return nil
+}
+
+/*
+setReadReceipt sets the read-receipt if it is newer than the existing one.
+
+At most SizeReadReceipts read-receipts are kept in each entity, the least recently updated ones are evicted.
+*/
+func (pm *BaseProtocolManager) setReadReceipt(receipt *ReadReceipt) bool {
+	pm.lockReadReceipt.Lock()
+	defer pm.lockReadReceipt.Unlock()
+
+	key := newReadReceiptKey(receipt.UserID, receipt.ObjID)
+	origReceipt, ok := pm.readReceipts.Peek(key)
+	if ok && !origReceipt.(*ReadReceipt).LastSeen.IsLess(receipt.LastSeen) {
+		return false
+	}
+
+	pm.readReceipts.Add(key, receipt)
+
+	return true
+}
+
+/*
+GetReadReceipts gets the read-receipts of the entity (objID is nil) or of the object in the entity.
+*/
+func (pm *BaseProtocolManager) GetReadReceipts(objID *types.PttID) []*ReadReceipt {
+	pm.lockReadReceipt.RLock()
+	defer pm.lockReadReceipt.RUnlock()
+
+	receipts := make([]*ReadReceipt, 0)
+	for _, key := range pm.readReceipts.Keys() {
+		val, ok := pm.readReceipts.Peek(key)
+		if !ok {
+			continue
+		}
+		receipt := val.(*ReadReceipt)
+		if !reflect.DeepEqual(receipt.ObjID, objID) {
+			continue
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts
+}
+
+/**********
+ * Typing
+ **********/
+
+/*
+SendTyping sends my typing-state to the peers of the entity if I share the typing-states.
+*/
+func (pm *BaseProtocolManager) SendTyping(op OpType, objID *types.PttID, isTyping bool) error {
+	myEntity := pm.Ptt().GetMyEntity()
+	setting := myEntity.GetPrivacySetting()
+	if setting == nil || !setting.IsShareTyping {
+		return nil
+	}
+
+	ts, err := types.GetTimestamp()
+	if err != nil {
+		return err
+	}
+
+	data := &Typing{
+		EntityID: pm.Entity().GetID(),
+		ObjID:    objID,
+		UserID:   myEntity.GetID(),
+		IsTyping: isTyping,
+		UpdateTS: ts,
+	}
+
+	return pm.SendDataToPeers(op, data, pm.Peers().PeerList(false))
+}
+
+func (pm *BaseProtocolManager) HandleTyping(dataBytes []byte, peer *PttPeer) error {
+	data := &Typing{}
+	err := json.Unmarshal(dataBytes, data)
+	if err != nil {
+		return err
+	}
+
+	err = pm.validateEphemeralUser(data.UserID, peer)
+	if err != nil {
+		return err
+	}
+	data.EntityID = pm.Entity().GetID()
+
+	pm.Entity().Service().SPM().EventMux().Post(data)
+
+	return nil
+}
+
+/*
+validateEphemeralUser validates that the ephemeral data is from the member who owns the peer.
+*/
+func (pm *BaseProtocolManager) validateEphemeralUser(userID *types.PttID, peer *PttPeer) error {
+	if userID == nil || peer.UserID == nil || !reflect.DeepEqual(userID, peer.UserID) {
+		return ErrInvalidData
+	}
+
+	if !pm.IsMember(userID, false) {
+		return types.ErrInvalidID
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+func TestBaseProtocolManager_setReadReceipt(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	readReceipts, _ := lru.New(SizeReadReceipts)
+	pm := &BaseProtocolManager{readReceipts: readReceipts}
+
+	objID := &types.PttID{1}
+	ts0 := types.Timestamp{Ts: 10}
+	ts1 := types.Timestamp{Ts: 20}
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		receipt *ReadReceipt
+		want    bool
+	}{
+		{
+			name:    "entity",
+			receipt: &ReadReceipt{UserID: tMyID, LastSeen: ts1},
+			want:    true,
+		},
+		{
+			name:    "entity-older",
+			receipt: &ReadReceipt{UserID: tMyID, LastSeen: ts0},
+			want:    false,
+		},
+		{
+			name:    "obj",
+			receipt: &ReadReceipt{UserID: tMyID, ObjID: objID, LastSeen: ts0},
+			want:    true,
+		},
+		{
+			name:    "obj-newer",
+			receipt: &ReadReceipt{UserID: tMyID, ObjID: objID, LastSeen: ts1},
+			want:    true,
+		},
+		{
+			name:    "other-user",
+			receipt: &ReadReceipt{UserID: tDefaultID, LastSeen: ts0},
+			want:    true,
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pm.setReadReceipt(tt.receipt); got != tt.want {
+				t.Errorf("BaseProtocolManager.setReadReceipt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	receipts := pm.GetReadReceipts(nil)
+	if len(receipts) != 2 {
+		t.Errorf("BaseProtocolManager.GetReadReceipts(nil) = %v, want 2 receipts", len(receipts))
+	}
+
+	receipts = pm.GetReadReceipts(objID)
+	if len(receipts) != 1 || receipts[0].LastSeen != ts1 {
+		t.Errorf("BaseProtocolManager.GetReadReceipts(objID) = %v, want [%v]", receipts, ts1)
+	}
+
+	// teardown test
+}
+
+func TestBaseProtocolManager_setReadReceiptEvict(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	readReceipts, _ := lru.New(3)
+	pm := &BaseProtocolManager{readReceipts: readReceipts}
+
+	ts := types.Timestamp{Ts: 10}
+	for i := 0; i < 5; i++ {
+		pm.setReadReceipt(&ReadReceipt{UserID: tMyID, ObjID: &types.PttID{byte(i + 1)}, LastSeen: ts})
+	}
+
+	// run test
+	if pm.readReceipts.Len() != 3 {
+		t.Errorf("BaseProtocolManager.setReadReceipt() kept %v, want 3", pm.readReceipts.Len())
+	}
+
+	if receipts := pm.GetReadReceipts(&types.PttID{1}); len(receipts) != 0 {
+		t.Errorf("BaseProtocolManager.GetReadReceipts(evicted) = %v, want none", receipts)
+	}
+
+	if receipts := pm.GetReadReceipts(&types.PttID{5}); len(receipts) != 1 {
+		t.Errorf("BaseProtocolManager.GetReadReceipts(newest) = %v, want 1 receipt", receipts)
+	}
+
+	// teardown test
+}
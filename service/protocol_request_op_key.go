@@ -47,6 +47,11 @@ func (p *BasePtt) RequestOpKey(hash *common.Address, peer *PttPeer) error {
 }
 
 func (p *BasePtt) RequestOpKeyByEntity(entity Entity, peer *PttPeer) error {
+	// the followers of the public entity do not get the op-keys.
+	if peer.UserID == nil || entity.PM().IsPublicPeer(peer) {
+		return types.ErrInvalidID
+	}
+
 	opKeys := entity.PM().OpKeyList()
 
 	opKeyOplogs, err := entity.PM().GetOpKeyOplogList(nil, 0, pttdb.ListOrderNext, types.StatusAlive)
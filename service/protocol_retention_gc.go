@@ -0,0 +1,140 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"time"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/pttdb"
+)
+
+/*
+PMRetentionLoop periodically enforces the retention policy of the entity.
+*/
+func PMRetentionLoop(pm ProtocolManager, gcRetention func() error) error {
+	ticker := time.NewTicker(RetentionGCSeconds)
+	defer ticker.Stop()
+
+loop:
+	for {
+		select {
+		case <-ticker.C:
+			if pm.Entity().GetStatus() != types.StatusAlive {
+				continue
+			}
+			err := gcRetention()
+			if err != nil {
+				log.Warn("PMRetentionLoop: unable to gcRetention", "e", err, "entity", pm.Entity().IDString())
+			}
+		case <-pm.QuitSync():
+			break loop
+		}
+	}
+
+	return nil
+}
+
+/*
+GCRetentionOplogs removes the valid oplogs expired with the retention,
+including the merkle-leaves, and marks the merkle-tree to be regenerated.
+*/
+func (pm *BaseProtocolManager) GCRetentionOplogs(
+	merkle *Merkle,
+
+	setLogDB func(oplog *BaseOplog),
+	isExpiredLog func(oplog *BaseOplog) bool,
+) (int, error) {
+
+	oplog := &BaseOplog{}
+	setLogDB(oplog)
+
+	iter, err := GetOplogIterWithOplog(oplog, nil, pttdb.ListOrderNext, types.StatusAlive, false)
+	if err != nil {
+		return 0, err
+	}
+
+	toDeleteLogs := make([]*BaseOplog, 0)
+	for iter.Next() {
+		eachLog := &BaseOplog{}
+		err = eachLog.Unmarshal(iter.Value())
+		if err != nil {
+			continue
+		}
+
+		if !isExpiredLog(eachLog) {
+			continue
+		}
+
+		toDeleteLogs = append(toDeleteLogs, eachLog)
+	}
+	iter.Release()
+
+	nDeleted := 0
+	for _, eachLog := range toDeleteLogs {
+		setLogDB(eachLog)
+		err = eachLog.Delete(false)
+		if err != nil {
+			continue
+		}
+
+		if merkle != nil {
+			merkle.SetUpdateTS(eachLog.UpdateTS)
+		}
+		nDeleted++
+	}
+
+	return nDeleted, nil
+}
+
+/*
+ValidateRetentionCreateLog skips the create-log of the object expired with the retention-policy,
+so the objects removed in gc are not created again when synced from the peers not gc-ed yet.
+
+The expiry is derived from the policy and the create-ts of the object
+(with the local objects from getObjs if MaxCount is set),
+so nothing is required to be kept after gc.
+*/
+func (pm *BaseProtocolManager) ValidateRetentionCreateLog(oplog *BaseOplog, getObjs func() ([]*RetentionObject, error)) error {
+	policy := pm.Entity().GetRetention()
+	if policy.IsEmpty() || oplog.ObjID == nil {
+		return nil
+	}
+
+	now, err := types.GetTimestamp()
+	if err != nil {
+		return err
+	}
+
+	var objs []*RetentionObject
+	if policy.MaxCount > 0 {
+		objs, err = getObjs()
+		if err != nil {
+			return err
+		}
+	}
+
+	obj := &RetentionObject{ID: oplog.ObjID, CreateTS: oplog.CreateTS}
+	if !policy.IsExpired(obj, objs, now) {
+		return nil
+	}
+
+	log.Debug("ValidateRetentionCreateLog: expired", "oplog", oplog.ID, "obj", oplog.ObjID, "createTS", oplog.CreateTS, "entity", pm.Entity().IDString())
+
+	return ErrSkipOplog
+}
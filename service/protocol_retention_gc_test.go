@@ -0,0 +1,110 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+/*
+tRetentionEntity is the entity with the retention-policy.
+*/
+type tRetentionEntity struct {
+	Entity
+	id        *types.PttID
+	retention *RetentionPolicy
+}
+
+func (e *tRetentionEntity) GetID() *types.PttID {
+	return e.id
+}
+
+func (e *tRetentionEntity) IDString() string {
+	return e.id.String()
+}
+
+func (e *tRetentionEntity) GetRetention() *RetentionPolicy {
+	return e.retention
+}
+
+func setupRetentionTest(policy *RetentionPolicy) *BaseProtocolManager {
+	return &BaseProtocolManager{
+		db:     tDBOplog,
+		dbLock: tDBLock,
+		entity: &tRetentionEntity{
+			id:        tDefaultID,
+			retention: policy,
+		},
+	}
+}
+
+func TestBaseProtocolManager_ValidateRetentionCreateLog(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	// now is 123456789, with the 2 objects kept after gc.
+	policy := &RetentionPolicy{MaxAgeSeconds: 100, MaxCount: 2}
+	pm := setupRetentionTest(policy)
+	emptyPM := setupRetentionTest(nil)
+
+	id0 := &types.PttID{1}
+	id1 := &types.PttID{2}
+	id2 := &types.PttID{3}
+
+	objs := []*RetentionObject{
+		&RetentionObject{ID: id1, CreateTS: types.Timestamp{Ts: 123456720}},
+		&RetentionObject{ID: id2, CreateTS: types.Timestamp{Ts: 123456740}},
+	}
+	getObjs := func() ([]*RetentionObject, error) {
+		return objs, nil
+	}
+
+	newLog := func(objID *types.PttID, ts int64) *BaseOplog {
+		oplog, _ := NewOplog(objID, types.Timestamp{Ts: ts}, tMyID, tDefaultOpType, nil, tDBOplog, tDefaultID, tDBOplogPrefix, tDBOplogIdxPrefix, tDBOplogMerklePrefix, tDBLock)
+		return oplog
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		pm      *BaseProtocolManager
+		oplog   *BaseOplog
+		wantErr error
+	}{
+		{name: "max-age", pm: pm, oplog: newLog(id0, 123456600), wantErr: ErrSkipOplog},
+		{name: "max-count", pm: pm, oplog: newLog(id0, 123456700), wantErr: ErrSkipOplog},
+		{name: "newer", pm: pm, oplog: newLog(id0, 123456750), wantErr: nil},
+		{name: "kept", pm: pm, oplog: newLog(id1, 123456720), wantErr: nil},
+		{name: "empty policy", pm: emptyPM, oplog: newLog(id0, 123456600), wantErr: nil},
+		// nothing is kept in memory, the same as restarted.
+		{name: "restarted", pm: setupRetentionTest(policy), oplog: newLog(id0, 123456700), wantErr: ErrSkipOplog},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.pm.ValidateRetentionCreateLog(tt.oplog, getObjs); err != tt.wantErr {
+				t.Errorf("BaseProtocolManager.ValidateRetentionCreateLog() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// teardown test
+}
@@ -109,6 +109,7 @@ func (pm *BaseProtocolManager) postdeleteOpKey(
 func (k *KeyInfo) RemoveMeta() {
 	k.Hash = nil
 	k.Key = nil
+	k.EdKey = nil
 	k.KeyBytes = nil
 	k.PubKeyBytes = nil
 	k.Extra = nil
@@ -0,0 +1,254 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ailabstw/go-pttai/pttdb"
+)
+
+/*
+SetMemberRole sets the role of the member by the master.
+
+	1. validate (alive, is-master, and the member is alive and not a master).
+	2. new-oplog with the role as the op-data.
+	3. sign oplog.
+	4. update member if the oplog is valid.
+	5. save oplog and broadcast.
+*/
+func (pm *BaseProtocolManager) SetMemberRole(id *types.PttID, role MemberRole) error {
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	entity := pm.Entity()
+
+	// 1. validate
+	if entity.GetStatus() != types.StatusAlive {
+		return types.ErrInvalidStatus
+	}
+
+	if !pm.IsMaster(myID, false) {
+		return types.ErrInvalidID
+	}
+
+	if !role.IsValid() {
+		return ErrInvalidMemberRole
+	}
+
+	if pm.IsMaster(id, false) {
+		return types.ErrInvalidID
+	}
+
+	// lock
+	member := NewEmptyMember()
+	pm.SetMemberObjDB(member)
+	member.SetID(id)
+
+	err := member.Lock()
+	if err != nil {
+		return err
+	}
+	defer member.Unlock()
+
+	err = member.GetByID(true)
+	if err != nil {
+		return err
+	}
+
+	if member.Status != types.StatusAlive {
+		return types.ErrInvalidStatus
+	}
+
+	// 2. oplog
+	opData := &MemberOpSetMemberRole{Role: role}
+	theOplog, err := pm.NewMemberOplog(id, MemberOpTypeSetMemberRole, opData)
+	if err != nil {
+		return err
+	}
+	oplog := theOplog.GetBaseOplog()
+
+	// 3. sign
+	err = pm.SignOplog(oplog)
+	if err != nil {
+		return err
+	}
+
+	// 4. update member
+	if oplog.MasterLogID != nil {
+		setMemberRoleWithOplog(member, opData, oplog)
+
+		err = member.Save(true)
+		if err != nil {
+			return err
+		}
+
+		oplog.IsSync = true
+	}
+
+	// 5. oplog
+	err = oplog.Save(false, pm.MemberMerkle())
+	if err != nil {
+		return err
+	}
+
+	pm.broadcastMemberOplogCore(oplog)
+
+	return nil
+}
+
+func setMemberRoleWithOplog(member *Member, opData *MemberOpSetMemberRole, oplog *BaseOplog) {
+	member.RoleInfo = &MemberRoleInfo{
+		Role:     opData.Role,
+		UpdateTS: oplog.UpdateTS,
+		LogID:    oplog.ID,
+	}
+}
+
+/*
+GetRoleMemberList gets the alive members with the roles other than the plain member.
+*/
+func (pm *BaseProtocolManager) GetRoleMemberList() ([]*Member, error) {
+	members, err := pm.GetMemberList(nil, 0, pttdb.ListOrderNext, false)
+	if err != nil {
+		return nil, err
+	}
+
+	roleMembers := make([]*Member, 0, len(members))
+	for _, member := range members {
+		if member.Status != types.StatusAlive || member.GetRole() == MemberRoleMember {
+			continue
+		}
+		roleMembers = append(roleMembers, member)
+	}
+
+	return roleMembers, nil
+}
+
+func (pm *BaseProtocolManager) IsModerator(id *types.PttID, isLocked bool) bool {
+	member, err := pm.GetMember(id, isLocked)
+	if err != nil {
+		return false
+	}
+
+	return member.Status == types.StatusAlive && member.GetRole() == MemberRoleModerator
+}
+
+/*
+IsMemberManager returns whether the id is able to add / delete the member.
+The masters are able to manage all the members,
+and the moderators are able to manage the members other than the masters.
+*/
+func (pm *BaseProtocolManager) IsMemberManager(id *types.PttID, memberID *types.PttID) bool {
+	if pm.IsMaster(id, false) {
+		return true
+	}
+
+	return pm.IsModerator(id, false) && !pm.IsMaster(memberID, false)
+}
+
+/*
+IsObjDeleter returns whether the id is able to delete the object created by creatorID.
+*/
+func (pm *BaseProtocolManager) IsObjDeleter(id *types.PttID, creatorID *types.PttID) bool {
+	if reflect.DeepEqual(id, creatorID) {
+		return true
+	}
+
+	return pm.IsMaster(id, false) || pm.IsModerator(id, false)
+}
+
+/*
+isValidMemberLogCreator returns whether the creator of the add / delete member oplog is able to manage the member.
+The members are able to add / delete themselves (migrate / leave).
+*/
+func (pm *BaseProtocolManager) isValidMemberLogCreator(oplog *BaseOplog) bool {
+	if reflect.DeepEqual(oplog.CreatorID, oplog.ObjID) {
+		return true
+	}
+
+	return pm.IsMemberManager(oplog.CreatorID, oplog.ObjID)
+}
+
+/**********
+ * Handle SetMemberRoleLog
+ **********/
+
+/*
+handleSetMemberRoleLog handles the valid set-member-role oplog.
+The role is set only if the creator is the master and the oplog is newer than the existing role.
+*/
+func (pm *BaseProtocolManager) handleSetMemberRoleLog(oplog *BaseOplog, info *ProcessPersonInfo) ([]*BaseOplog, error) {
+
+	opData := &MemberOpSetMemberRole{}
+	err := oplog.GetData(opData)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pm.IsMaster(oplog.CreatorID, false) {
+		return nil, types.ErrInvalidID
+	}
+
+	// lock
+	member := NewEmptyMember()
+	pm.SetMemberObjDB(member)
+	member.SetID(oplog.ObjID)
+
+	err = member.Lock()
+	if err != nil {
+		return nil, err
+	}
+	defer member.Unlock()
+
+	err = member.GetByID(true)
+	if err != nil {
+		return nil, err
+	}
+
+	// newer
+	if member.RoleInfo != nil && !member.RoleInfo.UpdateTS.IsLess(oplog.UpdateTS) {
+		return nil, ErrNewerOplog
+	}
+
+	// save
+	setMemberRoleWithOplog(member, opData, oplog)
+	err = member.Save(true)
+	if err != nil {
+		return nil, err
+	}
+
+	oplog.IsSync = true
+
+	log.Debug("handleSetMemberRoleLog: done", "entity", pm.Entity().IDString(), "member", member.ID, "role", opData.Role)
+
+	return nil, nil
+}
+
+/*
+handlePendingSetMemberRoleLog handles the pending set-member-role oplog.
+Only the oplogs from the masters are to be signed.
+*/
+func (pm *BaseProtocolManager) handlePendingSetMemberRoleLog(oplog *BaseOplog, info *ProcessPersonInfo) (types.Bool, []*BaseOplog, error) {
+
+	if !pm.IsMaster(oplog.CreatorID, false) {
+		return false, nil, types.ErrInvalidID
+	}
+
+	return true, nil, nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+var (
+	tRoleMasterID      = &types.PttID{1}
+	tRoleModeratorID   = &types.PttID{2}
+	tRoleMemberID      = &types.PttID{3}
+	tRoleMemberID2     = &types.PttID{4}
+	tRoleExModeratorID = &types.PttID{5}
+)
+
+/*
+tRoleEntity is the entity with only the id required to set the member db.
+*/
+type tRoleEntity struct {
+	Entity
+	id *types.PttID
+}
+
+func (e *tRoleEntity) GetID() *types.PttID {
+	return e.id
+}
+
+/*
+setupRoleTest sets up the pm with the master, the moderator, the members and the deleted moderator.
+*/
+func setupRoleTest(t *testing.T) *BaseProtocolManager {
+	entityID := &types.PttID{9}
+
+	pm := &BaseProtocolManager{
+		db:                tDBOplog,
+		dbLock:            tDBLock,
+		entity:            &tRoleEntity{id: entityID},
+		dbMemberPrefix:    append(DBMemberPrefix, entityID[:]...),
+		dbMemberIdxPrefix: append(DBMemberIdxPrefix, entityID[:]...),
+	}
+	pm.isMaster = func(id *types.PttID, isLocked bool) bool {
+		return reflect.DeepEqual(id, tRoleMasterID)
+	}
+
+	members := []struct {
+		id     *types.PttID
+		role   MemberRole
+		status types.Status
+	}{
+		{tRoleMasterID, MemberRoleMember, types.StatusAlive},
+		{tRoleModeratorID, MemberRoleModerator, types.StatusAlive},
+		{tRoleMemberID, MemberRoleMember, types.StatusAlive},
+		{tRoleMemberID2, MemberRoleMember, types.StatusAlive},
+		{tRoleExModeratorID, MemberRoleModerator, types.StatusDeleted},
+	}
+
+	for _, m := range members {
+		member := NewMember(m.id, tDefaultTimestamp, tRoleMasterID, entityID, nil, m.status)
+		member.RoleInfo = &MemberRoleInfo{Role: m.role, UpdateTS: tDefaultTimestamp}
+		pm.SetMemberObjDB(member)
+
+		err := member.Save(false)
+		if err != nil {
+			t.Fatalf("unable to save member: id: %v e: %v", m.id, err)
+		}
+	}
+
+	return pm
+}
+
+func TestBaseProtocolManager_IsMemberManager(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	pm := setupRoleTest(t)
+
+	// prepare test-cases
+	tests := []struct {
+		name     string
+		id       *types.PttID
+		memberID *types.PttID
+		want     bool
+	}{
+		{"master-member", tRoleMasterID, tRoleMemberID, true},
+		{"master-moderator", tRoleMasterID, tRoleModeratorID, true},
+		{"moderator-member", tRoleModeratorID, tRoleMemberID, true},
+		{"moderator-master", tRoleModeratorID, tRoleMasterID, false},
+		{"member-member", tRoleMemberID, tRoleMemberID2, false},
+		{"member-master", tRoleMemberID, tRoleMasterID, false},
+		{"deleted-moderator-member", tRoleExModeratorID, tRoleMemberID, false},
+		{"non-member", &types.PttID{6}, tRoleMemberID, false},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pm.IsMemberManager(tt.id, tt.memberID); got != tt.want {
+				t.Errorf("BaseProtocolManager.IsMemberManager() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// teardown test
+}
+
+func TestBaseProtocolManager_IsObjDeleter(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	pm := setupRoleTest(t)
+
+	// prepare test-cases
+	tests := []struct {
+		name      string
+		id        *types.PttID
+		creatorID *types.PttID
+		want      bool
+	}{
+		{"creator", tRoleMemberID, tRoleMemberID, true},
+		{"master-member", tRoleMasterID, tRoleMemberID, true},
+		{"moderator-member", tRoleModeratorID, tRoleMemberID, true},
+		{"moderator-master", tRoleModeratorID, tRoleMasterID, true},
+		{"member-member", tRoleMemberID2, tRoleMemberID, false},
+		{"member-moderator", tRoleMemberID, tRoleModeratorID, false},
+		{"deleted-moderator-member", tRoleExModeratorID, tRoleMemberID, false},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pm.IsObjDeleter(tt.id, tt.creatorID); got != tt.want {
+				t.Errorf("BaseProtocolManager.IsObjDeleter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// teardown test
+}
+
+func TestBaseProtocolManager_isValidMemberLogCreator(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	pm := setupRoleTest(t)
+
+	// prepare test-cases
+	tests := []struct {
+		name      string
+		creatorID *types.PttID
+		objID     *types.PttID
+		want      bool
+	}{
+		{"self", tRoleMemberID, tRoleMemberID, true},
+		{"self-master", tRoleMasterID, tRoleMasterID, true},
+		{"master-add-member", tRoleMasterID, tRoleMemberID, true},
+		{"moderator-add-member", tRoleModeratorID, tRoleMemberID, true},
+		{"moderator-remove-master", tRoleModeratorID, tRoleMasterID, false},
+		{"member-add-member", tRoleMemberID, tRoleMemberID2, false},
+		{"non-member-add-member", &types.PttID{6}, &types.PttID{7}, false},
+		{"deleted-moderator-add-member", tRoleExModeratorID, tRoleMemberID, false},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oplog := &BaseOplog{CreatorID: tt.creatorID, ObjID: tt.objID}
+			if got := pm.isValidMemberLogCreator(oplog); got != tt.want {
+				t.Errorf("BaseProtocolManager.isValidMemberLogCreator() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// teardown test
+}
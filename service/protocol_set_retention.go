@@ -0,0 +1,222 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+)
+
+/*
+SetRetention sets the retention policy of the entity by the master.
+
+	1. validate (alive and is-master).
+	2. new-oplog with the policy as the op-data.
+	3. sign oplog.
+	4. update entity if the oplog is valid.
+	5. save oplog and broadcast.
+	6. postset (enforcing the retention).
+*/
+func (pm *BaseProtocolManager) SetRetention(
+	op OpType,
+	maxAgeSeconds int64,
+	maxCount int,
+
+	merkle *Merkle,
+
+	newOplog func(objID *types.PttID, op OpType, opData OpData) (Oplog, error),
+	broadcastLog func(oplog *BaseOplog) error,
+	postsetRetention func(policy *RetentionPolicy) error,
+) error {
+
+	myID := pm.Ptt().GetMyEntity().GetID()
+	entity := pm.Entity()
+
+	// 1. validate
+	if entity.GetStatus() != types.StatusAlive {
+		return types.ErrInvalidStatus
+	}
+
+	if !pm.IsMaster(myID, false) {
+		return types.ErrInvalidID
+	}
+
+	_, err := NewRetentionPolicy(maxAgeSeconds, maxCount)
+	if err != nil {
+		return err
+	}
+
+	opData := &OpSetRetention{
+		MaxAgeSeconds: maxAgeSeconds,
+		MaxCount:      maxCount,
+	}
+
+	// lock
+	err = entity.Lock()
+	if err != nil {
+		return err
+	}
+
+	// 2. oplog
+	entityID := entity.GetID()
+	theOplog, err := newOplog(entityID, op, opData)
+	if err != nil {
+		entity.Unlock()
+		return err
+	}
+	oplog := theOplog.GetBaseOplog()
+
+	// 3. sign
+	err = pm.SignOplog(oplog)
+	if err != nil {
+		entity.Unlock()
+		return err
+	}
+
+	// 4. update entity
+	var policy *RetentionPolicy
+	if oplog.MasterLogID != nil {
+		policy = setEntityRetentionWithOplog(entity, opData, oplog)
+
+		err = entity.Save(true)
+		if err != nil {
+			entity.Unlock()
+			return err
+		}
+
+		oplog.IsSync = true
+	}
+	entity.Unlock()
+
+	// 5. oplog
+	err = oplog.Save(false, merkle)
+	if err != nil {
+		return err
+	}
+
+	broadcastLog(oplog)
+
+	// 6. postset
+	if policy != nil && postsetRetention != nil {
+		postsetRetention(policy)
+	}
+
+	return nil
+}
+
+func (pm *BaseProtocolManager) GetRetention() (*RetentionPolicy, error) {
+	entity := pm.Entity()
+
+	err := entity.RLock()
+	if err != nil {
+		return nil, err
+	}
+	defer entity.RUnlock()
+
+	policy := entity.GetRetention()
+	if policy == nil {
+		return &RetentionPolicy{}, nil
+	}
+
+	return policy, nil
+}
+
+/**********
+ * Handle SetRetentionLog
+ **********/
+
+/*
+HandleSetRetentionLog handles the valid set-retention oplog.
+The policy is set only if the creator is the master and the oplog is newer than the existing policy.
+*/
+func (pm *BaseProtocolManager) HandleSetRetentionLog(
+	oplog *BaseOplog,
+
+	postsetRetention func(policy *RetentionPolicy) error,
+) ([]*BaseOplog, error) {
+
+	entity := pm.Entity()
+
+	opData := &OpSetRetention{}
+	err := oplog.GetData(opData)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pm.IsMaster(oplog.CreatorID, false) {
+		return nil, types.ErrInvalidID
+	}
+
+	// lock
+	err = entity.Lock()
+	if err != nil {
+		return nil, err
+	}
+
+	// newer
+	origPolicy := entity.GetRetention()
+	if origPolicy != nil && !origPolicy.UpdateTS.IsLess(oplog.UpdateTS) {
+		entity.Unlock()
+		return nil, ErrNewerOplog
+	}
+
+	// save
+	policy := setEntityRetentionWithOplog(entity, opData, oplog)
+	err = entity.Save(true)
+	entity.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	oplog.IsSync = true
+
+	log.Debug("HandleSetRetentionLog: done", "entity", entity.IDString(), "maxAge", policy.MaxAgeSeconds, "maxCount", policy.MaxCount)
+
+	// postset
+	if postsetRetention != nil {
+		go postsetRetention(policy)
+	}
+
+	return nil, nil
+}
+
+/*
+HandlePendingSetRetentionLog handles the pending set-retention oplog.
+Only the oplogs from the masters are to be signed.
+*/
+func (pm *BaseProtocolManager) HandlePendingSetRetentionLog(oplog *BaseOplog) (types.Bool, []*BaseOplog, error) {
+
+	if !pm.IsMaster(oplog.CreatorID, false) {
+		return false, nil, types.ErrInvalidID
+	}
+
+	return true, nil, nil
+}
+
+func setEntityRetentionWithOplog(entity Entity, opData *OpSetRetention, oplog *BaseOplog) *RetentionPolicy {
+	policy := &RetentionPolicy{
+		MaxAgeSeconds: opData.MaxAgeSeconds,
+		MaxCount:      opData.MaxCount,
+
+		UpdateTS: oplog.UpdateTS,
+		LogID:    oplog.ID,
+	}
+
+	entity.SetRetention(policy)
+
+	return policy
+}
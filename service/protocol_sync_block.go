@@ -78,10 +78,9 @@ func (pm *BaseProtocolManager) HandleSyncBlock(
 		return nil
 	}
 
-	blocks := make([]*Block, 0, lenObjs*NSubBlock)
+	blocks := make([]*Block, 0, MaxSyncBlock)
 
 	var blockInfo *BlockInfo
-	var newBlocks []*Block
 	var syncInfo SyncInfo
 	for _, syncBlockID := range data.IDs {
 		newObj, err := obj.GetNewObjByID(syncBlockID.ObjID, false)
@@ -108,15 +107,54 @@ func (pm *BaseProtocolManager) HandleSyncBlock(
 		}
 		pm.SetBlockInfoDB(blockInfo, syncBlockID.ObjID)
 
-		newBlocks, err = GetBlockList(blockInfo, 0, false)
+		blocks, err = pm.syncBlockAckWithBlockInfo(syncAckMsg, blockInfo, blocks, peer)
 		if err != nil {
+			return err
+		}
+	}
+
+	return pm.SyncBlockAck(syncAckMsg, blocks, peer)
+}
+
+/*
+syncBlockAckWithBlockInfo iterates through the blocks of the block-info,
+and sends the blocks to the peer once there are MaxSyncBlock blocks.
+
+The large media is sent incrementally without loading all the blocks in memory.
+Returns the blocks not sent yet.
+*/
+func (pm *BaseProtocolManager) syncBlockAckWithBlockInfo(syncAckMsg OpType, blockInfo *BlockInfo, blocks []*Block, peer *PttPeer) ([]*Block, error) {
+	if !blockInfo.GetIsAllGood() {
+		return blocks, nil
+	}
+
+	iter, err := blockInfo.GetBlockIterWithBlockInfo(false)
+	if err != nil {
+		return blocks, nil
+	}
+	defer iter.Release()
+
+	var each *Block
+	for iter.Next() {
+		each = NewEmptyBlock()
+		err = each.Unmarshal(iter.Value())
+		if err != nil {
+			continue
+		}
+
+		blocks = append(blocks, each)
+		if len(blocks) < MaxSyncBlock {
 			continue
 		}
 
-		blocks = append(blocks, newBlocks...)
+		err = pm.SyncBlockAck(syncAckMsg, blocks, peer)
+		if err != nil {
+			return nil, err
+		}
+		blocks = make([]*Block, 0, MaxSyncBlock)
 	}
 
-	return pm.SyncBlockAck(syncAckMsg, blocks, peer)
+	return blocks, nil
 }
 
 func blocksToBlocksByIDsByObjs(blocks []*Block) map[types.PttID]map[types.PttID][]*Block {
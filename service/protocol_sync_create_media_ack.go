@@ -76,6 +76,7 @@ func (pm *BaseProtocolManager) updateSyncCreateMedia(theToObj Object, theFromObj
 	toObj.BlockInfo = fromObj.BlockInfo
 	toObj.MediaType = fromObj.MediaType
 	toObj.MediaData = fromObj.MediaData
+	toObj.Thumbnails = fromObj.Thumbnails
 
 	return nil
 }
@@ -18,8 +18,6 @@ package service
 
 import (
 	"encoding/json"
-
-	"github.com/ethereum/go-ethereum/crypto"
 )
 
 type SyncCreateOpKeyAck struct {
@@ -92,19 +90,18 @@ func (pm *BaseProtocolManager) updateCreateOpKey(theToObj Object, theFromObj Obj
 		return ErrInvalidObject
 	}
 
-	key, err := crypto.ToECDSA(fromObj.KeyBytes)
-	if err != nil {
-		return err
-	}
-
 	//toObj.BaseObject = fromObj.BaseObject
 	//pm.SetOpKeyObjDB(toObj)
 
 	toObj.Hash = fromObj.Hash
-	toObj.Key = key
+	toObj.KeyType = fromObj.KeyType
 	toObj.KeyBytes = fromObj.KeyBytes
-	toObj.PubKeyBytes = crypto.FromECDSAPub(&key.PublicKey)
 	toObj.Extra = fromObj.Extra
 
+	err := toObj.loadKey()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
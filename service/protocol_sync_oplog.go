@@ -27,6 +27,8 @@ import (
 type SyncOplog struct {
 	ToSyncTime  types.Timestamp `json:"LT"`
 	ToSyncNodes []*MerkleNode   `json:"LN"`
+
+	Ranges []*MerkleRange `json:"R,omitempty"`
 }
 
 /*
@@ -34,6 +36,9 @@ SyncOplog: I initiate sync-oplog.
 
 Expected merkle-tree-list length: 24 (hour) + 31 (day) + 12 (month) + n (year)
 (should be within the packet-limit)
+
+The live digests of the recent hours (after toSyncTime) are included,
+so that the peer sends only the oplogs of the diverged hours in sync-oplog-ack.
 */
 func (pm *BaseProtocolManager) SyncOplog(peer *PttPeer, merkle *Merkle, op OpType) error {
 
@@ -56,9 +61,12 @@ func (pm *BaseProtocolManager) SyncOplog(peer *PttPeer, merkle *Merkle, op OpTyp
 		return nil
 	}
 
-	_, err := pm.GetOldestOpKey(false)
-	if err != nil {
-		return pm.Ptt().RequestOpKeyByEntity(entity, peer)
+	// the data with the public peers is signed, not requiring the op-key.
+	if !pm.IsPublicPeer(peer) {
+		_, err := pm.GetOldestOpKey(false)
+		if err != nil {
+			return pm.Ptt().RequestOpKeyByEntity(entity, peer)
+		}
 	}
 
 	toSyncTime, err := merkle.ToSyncTime()
@@ -71,11 +79,19 @@ func (pm *BaseProtocolManager) SyncOplog(peer *PttPeer, merkle *Merkle, op OpTyp
 		return err
 	}
 
+	ranges, err := pm.syncOplogRanges(toSyncTime, merkle)
+	if err != nil {
+		return err
+	}
+
 	syncOplog := &SyncOplog{
 		ToSyncTime:  toSyncTime,
 		ToSyncNodes: toSyncNodes,
+		Ranges:      ranges,
 	}
 
+	merkle.SetSyncStartTime(peer)
+
 	err = pm.SendDataToPeer(op, syncOplog, peer)
 	if err != nil {
 		return err
@@ -84,6 +100,47 @@ func (pm *BaseProtocolManager) SyncOplog(peer *PttPeer, merkle *Merkle, op OpTyp
 	return nil
 }
 
+/*
+syncOplogRanges gets the live digests of the hours from toSyncTime to now (at most MaxSyncOplogRanges recent hours).
+*/
+func (pm *BaseProtocolManager) syncOplogRanges(toSyncTime types.Timestamp, merkle *Merkle) ([]*MerkleRange, error) {
+	if toSyncTime.IsEqual(types.ZeroTimestamp) {
+		toSyncTime = pm.Entity().GetCreateTS()
+	}
+
+	now, err := types.GetTimestamp()
+	if err != nil {
+		return nil, err
+	}
+
+	ranges, err := merkle.GetMerkleRangeList(syncOplogRangesStartTS(toSyncTime, now), now)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ranges) > MaxSyncOplogRanges {
+		ranges = ranges[len(ranges)-MaxSyncOplogRanges:]
+	}
+
+	return ranges, nil
+}
+
+/*
+syncOplogRangesStartTS clamps startTS to the recent MaxSyncOplogRanges hours,
+so that we do not scan the hours of the whole history of the entity.
+*/
+func syncOplogRangesStartTS(startTS types.Timestamp, now types.Timestamp) types.Timestamp {
+	minStartTS := now
+	minStartTS.Ts -= int64(MaxSyncOplogRanges) * types.HRSeconds
+	minStartTS, _ = minStartTS.ToHRTimestamp()
+
+	if startTS.IsLess(minStartTS) {
+		return minStartTS
+	}
+
+	return startTS
+}
+
 /*
 HandleSyncOplog: I received sync-oplog. (MerkleTreeList should be within the packet-limit.)
 
@@ -164,5 +221,5 @@ func (pm *BaseProtocolManager) HandleSyncOplog(
 	}
 
 	// 3. SyncOplogAck
-	return pm.SyncOplogAck(toSyncTime, myToSyncTime, merkle, forceSyncOplogAckMsg, syncOplogAckMsg, peer)
+	return pm.SyncOplogAck(toSyncTime, myToSyncTime, data.Ranges, merkle, forceSyncOplogAckMsg, syncOplogAckMsg, peer)
 }
@@ -31,16 +31,20 @@ type SyncOplogAck struct {
 	EndHourTS   types.Timestamp `json:"ETS"`
 	StartTS     types.Timestamp `json:"sTS"`
 	EndTS       types.Timestamp `json:"eTS"`
+
+	SkipHourTSs []types.Timestamp `json:"SH,omitempty"`
 }
 
 /*
 SyncOplogAck: sending SyncOplogAck. Passed validating the oplogs until toSyncTime.
+	=> skip the hours with the same digests as their-ranges.
 	=> get the merkleNodes with level as MerkleTreeLevelNow from offsetHoursTS to now (blocked).
-	=> Send the merkleNodes to the peer.
+	=> Send the merkleNodes (and the skipped hours) to the peer.
 */
 func (pm *BaseProtocolManager) SyncOplogAck(
 	toSyncTime types.Timestamp,
 	myToSyncTime types.Timestamp,
+	theirRanges []*MerkleRange,
 	merkle *Merkle,
 
 	forceSyncOplogAckMsg OpType,
@@ -64,12 +68,35 @@ func (pm *BaseProtocolManager) SyncOplogAck(
 		return err
 	}
 
+	// skip-hours
+	var skipHourTSs []types.Timestamp
+	if len(theirRanges) > 0 {
+		myRanges, err := merkle.GetMerkleRangeList(syncOplogRangesStartTS(offsetHourTS, now), now)
+		if err != nil {
+			return err
+		}
+
+		skipHourTSs = MatchMerkleRanges(myRanges, theirRanges)
+
+		syncOplogSkipHourMeter.Mark(int64(len(skipHourTSs)))
+		syncOplogDiffHourMeter.Mark(int64(len(myRanges) - len(skipHourTSs)))
+	}
+	skipHours := make(map[types.Timestamp]bool)
+	for _, ts := range skipHourTSs {
+		skipHours[ts] = true
+	}
+
 	nodes := make([]*MerkleNode, 0, MaxSyncOplogAck)
 
 	startHourTS := offsetHourTS
 	nextHourTS := offsetHourTS
 
 	for currentHourTS := offsetHourTS; currentHourTS.IsLess(now); currentHourTS = nextHourTS {
+		if skipHours[currentHourTS] {
+			nextHourTS = currentHourTS.NextHourTS()
+			continue
+		}
+
 		nodes, startHourTS, nextHourTS, err = pm.syncOplogAckCore(
 			merkle,
 			peer,
@@ -79,6 +106,7 @@ func (pm *BaseProtocolManager) SyncOplogAck(
 			offsetHourTS,
 			startHourTS,
 			now,
+			skipHourTSs,
 		)
 		log.Debug("SyncOplogAck: (in-for-loop) after syncOplogAckCore", "nodes", nodes, "currentHour", currentHourTS, "nextHour", nextHourTS, "e", err, "entity", pm.Entity().GetID())
 		if err != nil {
@@ -87,8 +115,8 @@ func (pm *BaseProtocolManager) SyncOplogAck(
 	}
 
 	// deal with the last part of the nodes.
-	log.Debug("SyncOplogAck: after syncOplogAckCore", "nodes", nodes, "entity", pm.Entity().GetID())
-	if len(nodes) == 0 {
+	log.Debug("SyncOplogAck: after syncOplogAckCore", "nodes", nodes, "skipHours", len(skipHourTSs), "entity", pm.Entity().GetID())
+	if len(nodes) == 0 && len(skipHourTSs) == 0 {
 		return nil
 	}
 
@@ -99,6 +127,7 @@ func (pm *BaseProtocolManager) SyncOplogAck(
 		EndHourTS:   now,
 		StartTS:     startHourTS,
 		EndTS:       now,
+		SkipHourTSs: skipHourTSs,
 	}
 
 	err = pm.SendDataToPeer(syncOplogAckMsg, syncOplogAck, peer)
@@ -131,6 +160,8 @@ func (pm *BaseProtocolManager) syncOplogAckCore(
 	startHourTS types.Timestamp,
 	now types.Timestamp,
 
+	skipHourTSs []types.Timestamp,
+
 ) ([]*MerkleNode, types.Timestamp, types.Timestamp, error) {
 
 	nextHourTS := currentHourTS.NextHourTS()
@@ -160,6 +191,7 @@ func (pm *BaseProtocolManager) syncOplogAckCore(
 			EndHourTS:   currentHourTS,
 			StartTS:     startHourTS,
 			EndTS:       currentHourTS,
+			SkipHourTSs: skipHourTSs,
 		}
 
 		err = pm.SendDataToPeer(syncOplogAckMsg, syncOplogAck, peer)
@@ -203,6 +235,7 @@ func (pm *BaseProtocolManager) syncOplogAckCore(
 			EndHourTS:   nextHourTS,
 			StartTS:     startTS,
 			EndTS:       endTS,
+			SkipHourTSs: skipHourTSs,
 		}
 
 		err = pm.SendDataToPeer(syncOplogAckMsg, syncOplogAck, peer)
@@ -249,23 +282,33 @@ func (pm *BaseProtocolManager) HandleSyncOplogAck(
 
 	myNodes, err := merkle.GetMerkleTreeListByLevel(MerkleTreeLevelNow, data.StartHourTS, data.EndHourTS)
 	if err != nil {
+		markSyncOplogFail(merkle)
 		return err
 	}
 
+	startTime, isSyncStart := merkle.PopSyncStartTime(peer)
+	if isSyncStart {
+		syncOplogLatencyTimer.UpdateSince(startTime)
+	}
+
 	myNodes = pm.handleSyncOplogAckFilterTS(myNodes, data.StartTS, data.EndTS)
 
+	var myLastNode *MerkleNode
+	if len(myNodes) > 0 {
+		myLastNode = myNodes[len(myNodes)-1]
+	}
+
+	// the skipped hours are identical in both sides.
+	myNodes = filterMerkleNodesBySkipHours(myNodes, data.SkipHourTSs)
+
 	myNewKeys, theirNewKeys, err := MergeKeysInMerkleNodes(myNodes, data.Nodes)
 	log.Debug("HandleSyncOplogAck: after MergeMerkleNodeKeys", "myNewKeys", myNewKeys, "theirNewKeys", theirNewKeys, "myNodes", myNodes, "startTS", data.StartTS, "endTS", data.EndTS, "e", err, "entity", pm.Entity().GetID())
 	if err != nil {
+		markSyncOplogFail(merkle)
 		return err
 	}
 
-	var myLastNode *MerkleNode
-	if len(myNodes) > 0 {
-		myLastNode = myNodes[len(myNodes)-1]
-	}
-
-	return pm.SyncOplogNewOplogs(
+	err = pm.SyncOplogNewOplogs(
 		data,
 		myNewKeys,
 		theirNewKeys,
@@ -279,6 +322,16 @@ func (pm *BaseProtocolManager) HandleSyncOplogAck(
 		postsync,
 		newLogsMsg,
 	)
+	if err != nil {
+		markSyncOplogFail(merkle)
+		return err
+	}
+
+	if isSyncStart {
+		updateSyncOplogDuration(merkle, startTime)
+	}
+
+	return nil
 }
 
 func (pm *BaseProtocolManager) handleSyncOplogAckFilterTS(nodes []*MerkleNode, startTS types.Timestamp, endTS types.Timestamp) []*MerkleNode {
@@ -36,6 +36,7 @@ type Ptt interface {
 	// event-mux
 
 	ErrChan() *types.Chan
+	EventMux() *event.TypeMux
 
 	// peers
 	IdentifyPeer(entityID *types.PttID, quitSync chan struct{}, peer *PttPeer, isForce bool) (*IdentifyPeer, error)
@@ -43,6 +44,7 @@ type Ptt interface {
 	HandleIdentifyPeerAck(entityID *types.PttID, data *IdentifyPeerAck, peer *PttPeer) error
 
 	FinishIdentifyPeer(peer *PttPeer, isLocked bool, isResetPeerType bool) error
+	IdentifyPeerWithMyID(peer *PttPeer) error
 
 	ResetPeerType(peer *PttPeer, isLocked bool, isResetPeerType bool) error
 
@@ -70,6 +72,11 @@ type Ptt interface {
 	RemoveOpKey(hash *common.Address, entityID *types.PttID, isLocked bool) error
 	RequestOpKeyByEntity(entity Entity, peer *PttPeer) error
 
+	// public
+
+	AddPublicHash(hash *common.Address, entityID *types.PttID, isLocked bool) error
+	RemovePublicHash(hash *common.Address, entityID *types.PttID, isLocked bool) error
+
 	// sync
 
 	SyncWG() *sync.WaitGroup
@@ -86,8 +93,17 @@ type Ptt interface {
 	EncryptData(op OpType, data []byte, keyInfo *KeyInfo) ([]byte, error)
 	DecryptData(ciphertext []byte, keyInfo *KeyInfo) (OpType, []byte, error)
 
+	EncryptDataWithVersion(version PttDataVersion, code CodeType, op OpType, data []byte, keyInfo *KeyInfo) ([]byte, error)
+	DecryptDataWithVersion(version PttDataVersion, code CodeType, ciphertext []byte, keyInfo *KeyInfo) (OpType, []byte, error)
+
 	MarshalData(code CodeType, hash *common.Address, encData []byte) (*PttData, error)
 	UnmarshalData(pttData *PttData) (CodeType, *common.Address, []byte, error)
+
+	MarshalDataWithVersion(version PttDataVersion, code CodeType, hash *common.Address, encData []byte) (*PttData, error)
+	UnmarshalDataWithVersion(pttData *PttData) (PttDataVersion, CodeType, *common.Address, []byte, error)
+
+	SignDataPublic(code CodeType, hash *common.Address, op OpType, data []byte) ([]byte, error)
+	VerifyDataPublic(code CodeType, hash *common.Address, signedData []byte, peer *PttPeer) (OpType, []byte, error)
 }
 
 type MyPtt interface {
@@ -157,6 +173,10 @@ type BasePtt struct {
 	lockOps sync.RWMutex
 	ops     map[common.Address]*types.PttID
 
+	// publics
+	lockPublics sync.RWMutex
+	publics     map[common.Address]*types.PttID
+
 	// sync
 	quitSync chan struct{}
 	syncWG   sync.WaitGroup
@@ -232,6 +252,9 @@ func NewPtt(ctx *ServiceContext, cfg *Config, myNodeID *discover.NodeID, myNodeK
 		// ops
 		ops: make(map[common.Address]*types.PttID),
 
+		// publics
+		publics: make(map[common.Address]*types.PttID),
+
 		// sync
 		quitSync: make(chan struct{}),
 
@@ -409,6 +432,10 @@ func (p *BasePtt) ErrChan() *types.Chan {
 	return p.errChan
 }
 
+func (p *BasePtt) EventMux() *event.TypeMux {
+	return p.eventMux
+}
+
 /**********
  * Server
  **********/
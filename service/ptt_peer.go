@@ -158,6 +158,17 @@ func (p *PttPeer) RW() p2p.MsgReadWriter {
 	return p.rw
 }
 
+/*
+DataVersion returns the ptt-data-version based on the protocol-version negotiated with the peer.
+*/
+func (p *PttPeer) DataVersion() PttDataVersion {
+	if p.version < Ptt5 {
+		return PttDataVersionCFB
+	}
+
+	return PttDataVersionAEAD
+}
+
 func (p *PttPeer) SendData(data *PttData) error {
 	//log.Debug("SendData", "p", p, "data", data)
 	return p2p.Send(p.rw, uint64(data.Code), data)
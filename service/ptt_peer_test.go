@@ -0,0 +1,54 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/ailabstw/go-pttai/p2p"
+	"github.com/ailabstw/go-pttai/p2p/discover"
+)
+
+func TestPttPeer_DataVersion(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		version uint
+		want    PttDataVersion
+	}{
+		{name: "ptt4", version: Ptt4, want: PttDataVersionCFB},
+		{name: "ptt5", version: Ptt5, want: PttDataVersionAEAD},
+		{name: "before ptt4", version: Ptt4 - 1, want: PttDataVersionCFB},
+		{name: "after ptt5", version: Ptt5 + 1, want: PttDataVersionAEAD},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peer, _ := NewPttPeer(tt.version, p2p.NewPeer(discover.NodeID{1}, "peer", nil), nil, tDefaultPtt)
+			if got := peer.DataVersion(); got != tt.want {
+				t.Errorf("PttPeer.DataVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// teardown test
+}
@@ -21,11 +21,22 @@ import (
 	"github.com/ailabstw/go-pttai/p2p/discover"
 )
 
+// PttDataVersion
+type PttDataVersion uint8
+
+const (
+	// PttDataVersionCFB: AES-CFB without integrity-tag (ptt4)
+	PttDataVersionCFB PttDataVersion = iota
+	// PttDataVersionAEAD: AES-GCM with code-type / op-type as the associated data (ptt5)
+	PttDataVersionAEAD
+)
+
 // PttEventData
 type PttEventData struct {
-	Code    CodeType `json:"C"`
-	Hash    []byte   `json:"H,omitempty"`
-	EncData []byte   `json:"D,omitempty"`
+	V       PttDataVersion `json:"V,omitempty"`
+	Code    CodeType       `json:"C"`
+	Hash    []byte         `json:"H,omitempty"`
+	EncData []byte         `json:"D,omitempty"`
 }
 
 // PttData
@@ -108,6 +108,112 @@ func (p *BasePtt) DecryptData(ciphertext []byte, keyInfo *KeyInfo) (OpType, []by
 	return op, data, nil
 }
 
+/*
+EncryptDataAEAD encrypts data in ptt-layer with AES-GCM.
+
+The op is in plain-text (4 bytes) in front of the nonce, and is bound with the code as the associated data,
+so the tampered ciphertext / op / code is rejected in DecryptDataAEAD.
+
+    format: op (4 bytes) | nonce (12 bytes) | sealed-data
+*/
+func (p *BasePtt) EncryptDataAEAD(code CodeType, op OpType, data []byte, keyInfo *KeyInfo) ([]byte, error) {
+	aead, err := newAEAD(keyInfo.KeyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := aead.NonceSize()
+	ciphertext := make([]byte, SizeOpType+nonceSize, SizeOpType+nonceSize+len(data)+aead.Overhead())
+
+	opBytes := ciphertext[:SizeOpType]
+	binary.BigEndian.PutUint32(opBytes, uint32(op))
+
+	nonce := ciphertext[SizeOpType:]
+	err = genIV(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	ad := aeadAssociatedData(code, opBytes)
+
+	return aead.Seal(ciphertext, nonce, data, ad), nil
+}
+
+/*
+DecryptDataAEAD decrypts data in ptt-layer with AES-GCM.
+*/
+func (p *BasePtt) DecryptDataAEAD(code CodeType, ciphertext []byte, keyInfo *KeyInfo) (OpType, []byte, error) {
+	aead, err := newAEAD(keyInfo.KeyBytes)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	nonceSize := aead.NonceSize()
+	if len(ciphertext) < SizeOpType+nonceSize+aead.Overhead() {
+		return 0, nil, ErrInvalidData
+	}
+
+	opBytes := ciphertext[:SizeOpType]
+	nonce := ciphertext[SizeOpType : SizeOpType+nonceSize]
+	sealed := ciphertext[SizeOpType+nonceSize:]
+
+	ad := aeadAssociatedData(code, opBytes)
+
+	data, err := aead.Open(nil, nonce, sealed, ad)
+	if err != nil {
+		return 0, nil, ErrInvalidData
+	}
+
+	op := OpType(binary.BigEndian.Uint32(opBytes))
+
+	return op, data, nil
+}
+
+/*
+EncryptDataWithVersion encrypts data based on the ptt-data-version negotiated with the peer.
+*/
+func (p *BasePtt) EncryptDataWithVersion(version PttDataVersion, code CodeType, op OpType, data []byte, keyInfo *KeyInfo) ([]byte, error) {
+	switch version {
+	case PttDataVersionCFB:
+		return p.EncryptData(op, data, keyInfo)
+	case PttDataVersionAEAD:
+		return p.EncryptDataAEAD(code, op, data, keyInfo)
+	}
+
+	return nil, ErrInvalidPttDataVersion
+}
+
+/*
+DecryptDataWithVersion decrypts data based on the ptt-data-version negotiated with the peer.
+*/
+func (p *BasePtt) DecryptDataWithVersion(version PttDataVersion, code CodeType, ciphertext []byte, keyInfo *KeyInfo) (OpType, []byte, error) {
+	switch version {
+	case PttDataVersionCFB:
+		return p.DecryptData(ciphertext, keyInfo)
+	case PttDataVersionAEAD:
+		return p.DecryptDataAEAD(code, ciphertext, keyInfo)
+	}
+
+	return 0, nil, ErrInvalidPttDataVersion
+}
+
+func newAEAD(keyBytes []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func aeadAssociatedData(code CodeType, opBytes []byte) []byte {
+	ad := make([]byte, SizeCodeType+SizeOpType)
+	binary.BigEndian.PutUint64(ad[:SizeCodeType], uint64(code))
+	copy(ad[SizeCodeType:], opBytes)
+
+	return ad
+}
+
 func addBase64Padding(value string) string {
 	m := len(value) % 4
 	if m != 0 {
@@ -149,8 +255,17 @@ MarshalData marshals the encrypted data based on ptt-protocol.
 The purpose is to have checksum to ensure that the data is not randomly-modified (preventing machine-error)
 */
 func (p *BasePtt) MarshalData(code CodeType, hash *common.Address, encData []byte) (*PttData, error) {
+	return p.MarshalDataWithVersion(PttDataVersionCFB, code, hash, encData)
+}
+
+/*
+MarshalDataWithVersion marshals the encrypted data based on ptt-protocol,
+with the ptt-data-version (the encryption scheme of encData) in the checksummed ptt-event.
+*/
+func (p *BasePtt) MarshalDataWithVersion(version PttDataVersion, code CodeType, hash *common.Address, encData []byte) (*PttData, error) {
 	// 2. forms pttEvent
 	ev := &PttEventData{
+		V:       version,
 		Code:    code,
 		Hash:    hash[:],
 		EncData: encData,
@@ -177,9 +292,10 @@ MarshalData marshals the encrypted data based on ptt-protocol.
 	enc: encrypted-data
 The purpose is to have checksum to ensure that the data is not randomly-modified (preventing machine-error)
 */
-func (p *BasePtt) MarshalDataWithoutHash(code CodeType, encData []byte) (*PttData, error) {
+func (p *BasePtt) MarshalDataWithoutHash(version PttDataVersion, code CodeType, encData []byte) (*PttData, error) {
 	// 2. forms pttEvent
 	ev := &PttEventData{
+		V:       version,
 		Code:    code,
 		EncData: encData,
 	}
@@ -237,15 +353,23 @@ func (p *BasePtt) checksumData(bytes []byte) ([]byte, []byte, error) {
 UnmarshalData unmarshal the pttData to the original data
 */
 func (p *BasePtt) UnmarshalData(pttData *PttData) (CodeType, *common.Address, []byte, error) {
+	_, code, hash, encData, err := p.UnmarshalDataWithVersion(pttData)
+	return code, hash, encData, err
+}
+
+/*
+UnmarshalDataWithVersion unmarshal the pttData to the original data, including the ptt-data-version.
+*/
+func (p *BasePtt) UnmarshalDataWithVersion(pttData *PttData) (PttDataVersion, CodeType, *common.Address, []byte, error) {
 	ev, err := p.verifyChecksumEventData(pttData)
 	if err != nil {
-		return CodeTypeInvalid, nil, nil, err
+		return PttDataVersionCFB, CodeTypeInvalid, nil, nil, err
 	}
 
 	hashAddr := &common.Address{}
 	copy(hashAddr[:], ev.Hash[:])
 
-	return ev.Code, hashAddr, ev.EncData, nil
+	return ev.V, ev.Code, hashAddr, ev.EncData, nil
 }
 
 func (p *BasePtt) verifyChecksumEventData(pttData *PttData) (*PttEventData, error) {
@@ -283,7 +407,7 @@ func (p *BasePtt) SendDataToPeer(code CodeType, data interface{}, peer *PttPeer)
 		return err
 	}
 
-	pttData, err := p.MarshalDataWithoutHash(code, marshaledData)
+	pttData, err := p.MarshalDataWithoutHash(peer.DataVersion(), code, marshaledData)
 	if err != nil {
 		return err
 	}
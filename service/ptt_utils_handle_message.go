@@ -71,12 +71,17 @@ func (p *BasePtt) HandleMessage(code CodeType, data *PttData, peer *PttPeer) err
 		return ErrInvalidData
 	}
 
-	evCode, evHash, encData, err := p.UnmarshalData(data)
+	evVersion, evCode, evHash, encData, err := p.UnmarshalDataWithVersion(data)
 	if err != nil {
 		log.Error("HandleMessage: unable to unmarshal", "data", data, "e", err)
 		return err
 	}
 
+	if evVersion != peer.DataVersion() {
+		log.Error("HandleMessage: data-version not match", "evVersion", evVersion, "peerVersion", peer.DataVersion(), "peer", peer)
+		return ErrInvalidData
+	}
+
 	if evCode != code || (code < CodeTypeRequireHash && !reflect.DeepEqual(evHash[:], data.Hash[:])) {
 		log.Error("HandleMessage: hash not match", "evHash", evHash, "dataHash", data.Hash)
 		return ErrInvalidData
@@ -92,6 +97,8 @@ func (p *BasePtt) HandleMessage(code CodeType, data *PttData, peer *PttPeer) err
 		err = p.HandleCodeOp(evHash, encData, peer)
 	case CodeTypeOpFail:
 		err = p.HandleCodeOpFail(evHash, encData, peer)
+	case CodeTypeOpPublic:
+		err = p.HandleCodeOpPublic(evHash, encData, peer)
 
 	case CodeTypeRequestOpKey:
 		err = p.HandleCodeRequestOpKey(evHash, encData, peer)
@@ -146,7 +153,7 @@ func (p *BasePtt) HandleCodeJoin(hash *common.Address, encData []byte, peer *Ptt
 		return err
 	}
 
-	op, dataBytes, err := p.DecryptData(encData, keyInfo)
+	op, dataBytes, err := p.DecryptDataWithVersion(peer.DataVersion(), CodeTypeJoin, encData, keyInfo)
 	if err != nil {
 		log.Error("HandleCodeJoin: unable to DecryptData", "e", err)
 		return err
@@ -176,7 +183,7 @@ func (p *BasePtt) HandleCodeJoinAck(hash *common.Address, encData []byte, peer *
 
 	keyInfo := joinKeyToKeyInfo(joinRequest.Key)
 
-	op, dataBytes, err := p.DecryptData(encData, keyInfo)
+	op, dataBytes, err := p.DecryptDataWithVersion(peer.DataVersion(), CodeTypeJoinAck, encData, keyInfo)
 	if err != nil {
 		return err
 	}
@@ -206,7 +213,7 @@ func (p *BasePtt) HandleCodeOp(hash *common.Address, encData []byte, peer *PttPe
 
 	pm := entity.PM()
 
-	err = PMHandleMessageWrapper(pm, hash, encData, peer)
+	err = PMHandleMessageWrapper(pm, CodeTypeOp, hash, encData, peer)
 
 	return err
 }
@@ -252,7 +259,7 @@ func (p *BasePtt) HandleCodeIdentifyPeer(hash *common.Address, encData []byte, p
 
 	pm := entity.PM()
 
-	err = PMHandleMessageWrapper(pm, hash, encData, peer)
+	err = PMHandleMessageWrapper(pm, CodeTypeIdentifyPeer, hash, encData, peer)
 	if err != nil {
 		p.IdentifyPeerFail(hash, peer)
 	}
@@ -0,0 +1,118 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/p2p"
+	"github.com/ailabstw/go-pttai/p2p/discover"
+)
+
+/*
+TestPtt_HandleMessageWithVersion handles the op from the ptt4 / ptt5 peers for the unknown entity.
+The ptt-data with the version not matching the peer is rejected,
+and the op-fail is replied with the data-version of the peer.
+*/
+func TestPtt_HandleMessageWithVersion(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	p := tDefaultPtt
+
+	// prepare test-cases
+	tests := []struct {
+		name        string
+		peerVersion uint
+		dataVersion PttDataVersion
+		wantErr     error
+	}{
+		{name: "ptt4 peer cfb data", peerVersion: Ptt4, dataVersion: PttDataVersionCFB},
+		{name: "ptt5 peer aead data", peerVersion: Ptt5, dataVersion: PttDataVersionAEAD},
+		{name: "ptt4 peer aead data", peerVersion: Ptt4, dataVersion: PttDataVersionAEAD, wantErr: ErrInvalidData},
+		{name: "ptt5 peer cfb data", peerVersion: Ptt5, dataVersion: PttDataVersionCFB, wantErr: ErrInvalidData},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw, peerRW := p2p.MsgPipe()
+			defer rw.Close()
+
+			peer, _ := NewPttPeer(tt.peerVersion, p2p.NewPeer(discover.NodeID{1}, "peer", nil), rw, p)
+
+			encData, err := p.EncryptDataWithVersion(tt.dataVersion, CodeTypeOp, tDefaultOp, tDefaultDataBytes, tDefaultKeyInfo)
+			if err != nil {
+				t.Errorf("Ptt.EncryptDataWithVersion() error = %v", err)
+				return
+			}
+			pttData, err := p.MarshalDataWithVersion(tt.dataVersion, CodeTypeOp, &tDefaultHash, encData)
+			if err != nil {
+				t.Errorf("Ptt.MarshalDataWithVersion() error = %v", err)
+				return
+			}
+			pttData.Node = tDefaultNodeID[:]
+
+			errc := make(chan error, 1)
+			go func() {
+				errc <- p.HandleMessage(CodeTypeOp, pttData, peer)
+			}()
+
+			if tt.wantErr != nil {
+				if err := <-errc; err != tt.wantErr {
+					t.Errorf("Ptt.HandleMessage() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			msg, err := peerRW.ReadMsg()
+			if err != nil {
+				t.Errorf("ReadMsg() error = %v", err)
+				return
+			}
+			replyData := &PttData{}
+			err = msg.Decode(replyData)
+			if err != nil {
+				t.Errorf("Decode() error = %v", err)
+				return
+			}
+
+			if err := <-errc; err != nil {
+				t.Errorf("Ptt.HandleMessage() error = %v", err)
+			}
+
+			version, code, _, _, err := p.UnmarshalDataWithVersion(replyData)
+			if err != nil {
+				t.Errorf("Ptt.UnmarshalDataWithVersion() error = %v", err)
+				return
+			}
+			if code != CodeTypeOpFail || version != tt.dataVersion {
+				t.Errorf("Ptt.HandleMessage() reply = (%v, %v), want (%v, %v)", code, version, CodeTypeOpFail, tt.dataVersion)
+			}
+
+			// the ptt4 nodes do not know the version-field.
+			isWithVersion := bytes.Contains(replyData.EvWithSalt, []byte(`"V":`))
+			if isWithVersion != (tt.dataVersion != PttDataVersionCFB) {
+				t.Errorf("Ptt.HandleMessage() reply with version-field = %v, data-version %v", isWithVersion, tt.dataVersion)
+			}
+		})
+	}
+
+	// teardown test
+}
@@ -416,6 +416,9 @@ func (p *BasePtt) SetPeerType(peer *PttPeer, peerType PeerType, isForce bool, is
 	}
 
 	peer.PeerType = peerType
+	if rw, ok := peer.RW().(MeteredMsgReadWriter); ok {
+		rw.SetPeerType(peerType)
+	}
 
 	log.Debug("SetPeerType", "peer", peer, "origPeerType", origPeerType, "peerType", peerType)
 
@@ -781,6 +784,9 @@ func (p *BasePtt) AddDial(nodeID *discover.NodeID, opKey *common.Address, peerTy
 
 		// just do the specific entity
 		entity, err := p.getEntityFromHash(opKey, &p.lockOps, p.ops)
+		if err != nil {
+			entity, err = p.getEntityFromHash(opKey, &p.lockPublics, p.publics)
+		}
 		if err != nil {
 			return err
 		}
@@ -836,6 +842,9 @@ func (p *BasePtt) checkDialEntity(peer *PttPeer) (Entity, error) {
 	defer p.lockOps.RUnlock()
 
 	entityID := p.ops[*dialInfo.OpKey]
+	if entityID == nil {
+		entityID = p.getPublicEntityID(dialInfo.OpKey)
+	}
 	if entityID == nil {
 		return nil, nil
 	}
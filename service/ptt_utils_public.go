@@ -0,0 +1,189 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"reflect"
+
+	pttcommon "github.com/ailabstw/go-pttai/common"
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/log"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+/*
+PublicData is the data of the public entity sent to / from the followers.
+
+The data is signed by the user sending the data instead of encrypted with the op-key,
+so the followers are able to read the entity without holding the op-key.
+The signed bytes are code | hash | op | data, binding the data to the code and to the entity.
+*/
+type PublicData struct {
+	Op   OpType    `json:"O"`
+	Data []byte    `json:"D"`
+	Sign *SignInfo `json:"S"`
+}
+
+func (p *BasePtt) AddPublicHash(hash *common.Address, entityID *types.PttID, isLocked bool) error {
+	if !isLocked {
+		p.LockPublics()
+		defer p.UnlockPublics()
+	}
+
+	log.Debug("AddPublicHash: to add hash", "hash", hash, "entityID", entityID)
+
+	p.publics[*hash] = entityID
+
+	return nil
+}
+
+func (p *BasePtt) RemovePublicHash(hash *common.Address, entityID *types.PttID, isLocked bool) error {
+	if !isLocked {
+		p.LockPublics()
+		defer p.UnlockPublics()
+	}
+
+	log.Debug("RemovePublicHash: to remove hash", "hash", hash, "entityID", entityID)
+
+	delete(p.publics, *hash)
+
+	return nil
+}
+
+func (p *BasePtt) getPublicEntityID(hash *common.Address) *types.PttID {
+	p.lockPublics.RLock()
+	defer p.lockPublics.RUnlock()
+
+	return p.publics[*hash]
+}
+
+func (p *BasePtt) LockPublics() {
+	p.lockPublics.Lock()
+}
+
+func (p *BasePtt) UnlockPublics() {
+	p.lockPublics.Unlock()
+}
+
+/*
+SignDataPublic signs the data with my sign-key.
+*/
+func (p *BasePtt) SignDataPublic(code CodeType, hash *common.Address, op OpType, data []byte) ([]byte, error) {
+	if p.myEntity == nil {
+		return nil, ErrInvalidEntity
+	}
+
+	marshaled, err := marshalPublicData(code, hash, op, data)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := p.myEntity.SignKey()
+	bytesWithSalt, signHash, sig, pubBytes, err := SignData(marshaled, keyInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	sign := &SignInfo{
+		ID:      p.myEntity.GetID(),
+		Hash:    signHash,
+		Sig:     sig,
+		Pubkey:  pubBytes,
+		Extra:   keyInfo.Extra,
+		KeyType: keyInfo.KeyType,
+	}
+	copy(sign.Salt[:], bytesWithSalt[len(marshaled):])
+
+	publicData := &PublicData{
+		Op:   op,
+		Data: data,
+		Sign: sign,
+	}
+
+	return json.Marshal(publicData)
+}
+
+/*
+VerifyDataPublic verifies that the data is signed by the user of the peer.
+*/
+func (p *BasePtt) VerifyDataPublic(code CodeType, hash *common.Address, signedData []byte, peer *PttPeer) (OpType, []byte, error) {
+	if peer.UserID == nil {
+		return 0, nil, types.ErrInvalidID
+	}
+
+	publicData := &PublicData{}
+	err := json.Unmarshal(signedData, publicData)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	sign := publicData.Sign
+	if sign == nil || !reflect.DeepEqual(sign.ID, peer.UserID) {
+		return 0, nil, types.ErrInvalidID
+	}
+
+	marshaled, err := marshalPublicData(code, hash, publicData.Op, publicData.Data)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	bytesWithSalt, err := pttcommon.Concat([][]byte{marshaled, sign.Salt[:]})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = VerifyData(bytesWithSalt, sign.Hash, sign.Sig, sign.KeyType, sign.Pubkey, peer.UserID, sign.Extra)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return publicData.Op, publicData.Data, nil
+}
+
+func marshalPublicData(code CodeType, hash *common.Address, op OpType, data []byte) ([]byte, error) {
+	codeBytes, err := MarshalCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	opBytes := make([]byte, SizeOpType)
+	binary.BigEndian.PutUint32(opBytes, uint32(op))
+
+	return pttcommon.Concat([][]byte{codeBytes, hash[:], opBytes, data})
+}
+
+/*
+HandleCodeOpPublic handles the signed data of the public entity.
+The peer is required to be identified to verify the signature.
+*/
+func (p *BasePtt) HandleCodeOpPublic(hash *common.Address, encData []byte, peer *PttPeer) error {
+	entity, err := p.getEntityFromHash(hash, &p.lockPublics, p.publics)
+	if err != nil {
+		log.Error("HandleCodeOpPublic: invalid entity", "hash", hash, "e", err)
+		return err
+	}
+
+	if peer.UserID == nil {
+		return p.IdentifyPeerFail(hash, peer)
+	}
+
+	pm := entity.PM()
+
+	return PMHandleMessageWrapper(pm, CodeTypeOpPublic, hash, encData, peer)
+}
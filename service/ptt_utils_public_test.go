@@ -0,0 +1,92 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPtt_VerifyDataPublic(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	p := &BasePtt{
+		myEntity: &tFollowerMyEntity{id: tUserIDMe, signKey: tKeyInfoMe},
+	}
+
+	hash := &common.Address{1}
+	otherHash := &common.Address{2}
+	data := []byte("public-data")
+	op := AddMasterOplogMsg
+
+	signed, err := p.SignDataPublic(CodeTypeOpPublic, hash, op, data)
+	if err != nil {
+		t.Errorf("Ptt.SignDataPublic() error = %v", err)
+		return
+	}
+
+	publicData := &PublicData{}
+	json.Unmarshal(signed, publicData)
+	publicData.Data = []byte("tampered-data")
+	tampered, _ := json.Marshal(publicData)
+
+	otherID := &types.PttID{1}
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		code    CodeType
+		hash    *common.Address
+		signed  []byte
+		peer    *PttPeer
+		wantOp  OpType
+		want    []byte
+		wantErr bool
+	}{
+		{name: "signed", code: CodeTypeOpPublic, hash: hash, signed: signed, peer: &PttPeer{UserID: tUserIDMe}, wantOp: op, want: data},
+		{name: "tampered data", code: CodeTypeOpPublic, hash: hash, signed: tampered, peer: &PttPeer{UserID: tUserIDMe}, wantErr: true},
+		{name: "other entity", code: CodeTypeOpPublic, hash: otherHash, signed: signed, peer: &PttPeer{UserID: tUserIDMe}, wantErr: true},
+		{name: "other code", code: CodeTypeOp, hash: hash, signed: signed, peer: &PttPeer{UserID: tUserIDMe}, wantErr: true},
+		{name: "other user", code: CodeTypeOpPublic, hash: hash, signed: signed, peer: &PttPeer{UserID: otherID}, wantErr: true},
+		{name: "unidentified peer", code: CodeTypeOpPublic, hash: hash, signed: signed, peer: &PttPeer{}, wantErr: true},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOp, got, err := p.VerifyDataPublic(tt.code, tt.hash, tt.signed, tt.peer)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ptt.VerifyDataPublic() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if gotOp != tt.wantOp {
+				t.Errorf("Ptt.VerifyDataPublic() op = %v, want %v", gotOp, tt.wantOp)
+			}
+			if string(got) != string(tt.want) {
+				t.Errorf("Ptt.VerifyDataPublic() data = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
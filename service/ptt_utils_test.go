@@ -349,6 +349,153 @@ func TestPtt_UnmarshalData(t *testing.T) {
 	// teardown test
 }
 
+func TestPtt_EncryptDecryptDataAEAD(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	p := tDefaultPtt
+
+	encData, err := p.EncryptDataAEAD(CodeTypeOp, tDefaultOp, tDefaultDataBytes, tDefaultKeyInfo)
+	if err != nil {
+		t.Errorf("Ptt.EncryptDataAEAD() error = %v", err)
+		return
+	}
+
+	tamperedData := make([]byte, len(encData))
+	copy(tamperedData, encData)
+	tamperedData[len(tamperedData)-1] ^= 0x01
+
+	tamperedOp := make([]byte, len(encData))
+	copy(tamperedOp, encData)
+	tamperedOp[0] ^= 0x01
+
+	// prepare test-cases
+	type args struct {
+		code   CodeType
+		encMsg []byte
+		key    *KeyInfo
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    OpType
+		want1   []byte
+		wantErr bool
+	}{
+		{
+			name:  "ok",
+			args:  args{code: CodeTypeOp, encMsg: encData, key: tDefaultKeyInfo},
+			want:  tDefaultOp,
+			want1: tDefaultDataBytes,
+		},
+		{
+			name:    "tampered data",
+			args:    args{code: CodeTypeOp, encMsg: tamperedData, key: tDefaultKeyInfo},
+			wantErr: true,
+		},
+		{
+			name:    "tampered op",
+			args:    args{code: CodeTypeOp, encMsg: tamperedOp, key: tDefaultKeyInfo},
+			wantErr: true,
+		},
+		{
+			name:    "wrong code",
+			args:    args{code: CodeTypeJoin, encMsg: encData, key: tDefaultKeyInfo},
+			wantErr: true,
+		},
+		{
+			name:    "too short",
+			args:    args{code: CodeTypeOp, encMsg: encData[:SizeOpType], key: tDefaultKeyInfo},
+			wantErr: true,
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := p.DecryptDataAEAD(tt.args.code, tt.args.encMsg, tt.args.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ptt.DecryptDataAEAD() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Ptt.DecryptDataAEAD() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("Ptt.DecryptDataAEAD() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+
+	// teardown test
+}
+
+func TestPtt_EncryptDecryptDataWithVersion(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	p := tDefaultPtt
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		version PttDataVersion
+		wantErr bool
+	}{
+		{name: "cfb", version: PttDataVersionCFB},
+		{name: "aead", version: PttDataVersionAEAD},
+		{name: "invalid", version: PttDataVersionAEAD + 1, wantErr: true},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encData, err := p.EncryptDataWithVersion(tt.version, CodeTypeOp, tDefaultOp, tDefaultDataBytes, tDefaultKeyInfo)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ptt.EncryptDataWithVersion() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			pttData, err := p.MarshalDataWithVersion(tt.version, CodeTypeOp, &tDefaultHash, encData)
+			if err != nil {
+				t.Errorf("Ptt.MarshalDataWithVersion() error = %v", err)
+				return
+			}
+
+			version, code, hash, gotEncData, err := p.UnmarshalDataWithVersion(pttData)
+			if err != nil {
+				t.Errorf("Ptt.UnmarshalDataWithVersion() error = %v", err)
+				return
+			}
+			if version != tt.version || code != CodeTypeOp || !reflect.DeepEqual(hash, &tDefaultHash) {
+				t.Errorf("Ptt.UnmarshalDataWithVersion() got = (%v, %v, %v), want (%v, %v, %v)", version, code, hash, tt.version, CodeTypeOp, &tDefaultHash)
+			}
+
+			op, dataBytes, err := p.DecryptDataWithVersion(version, code, gotEncData, tDefaultKeyInfo)
+			if err != nil {
+				t.Errorf("Ptt.DecryptDataWithVersion() error = %v", err)
+				return
+			}
+			if op != tDefaultOp {
+				t.Errorf("Ptt.DecryptDataWithVersion() got = %v, want %v", op, tDefaultOp)
+			}
+			if !reflect.DeepEqual(dataBytes, tDefaultDataBytes) {
+				t.Errorf("Ptt.DecryptDataWithVersion() got1 = %v, want %v", dataBytes, tDefaultDataBytes)
+			}
+		})
+	}
+
+	// teardown test
+}
+
 func Test_addAndRemoveBase64Padding(t *testing.T) {
 	type args struct {
 		value string
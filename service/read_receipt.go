@@ -0,0 +1,109 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/rpc"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+/*
+ReadReceipt is the read-position of a user in an entity, or in an object of the entity if ObjID is not nil (ex: the article in the board).
+
+Read-receipts are ephemeral. They are sent directly to the peers of the entity without the oplogs, and are kept in memory only.
+*/
+type ReadReceipt struct {
+	EntityID *types.PttID    `json:"EID"`
+	ObjID    *types.PttID    `json:"OID,omitempty"`
+	UserID   *types.PttID    `json:"UID"`
+	LastSeen types.Timestamp `json:"LT"`
+}
+
+/*
+Typing is the ephemeral typing-state of a user in an entity (or in an object of the entity).
+*/
+type Typing struct {
+	EntityID *types.PttID    `json:"EID"`
+	ObjID    *types.PttID    `json:"OID,omitempty"`
+	UserID   *types.PttID    `json:"UID"`
+	IsTyping bool            `json:"T"`
+	UpdateTS types.Timestamp `json:"UT"`
+}
+
+/*
+PrivacySetting is the setting of what I share with the others.
+Both read-receipts and typing-states are opt-in.
+*/
+type PrivacySetting struct {
+	IsShareReadReceipt bool `json:"r"`
+	IsShareTyping      bool `json:"t"`
+}
+
+type readReceiptKey struct {
+	UserID types.PttID
+	ObjID  types.PttID
+}
+
+func newReadReceiptKey(userID *types.PttID, objID *types.PttID) readReceiptKey {
+	key := readReceiptKey{UserID: *userID}
+	if objID != nil {
+		key.ObjID = *objID
+	}
+	return key
+}
+
+/*
+SubscribeReadReceipts creates an RPC subscription which receives the read-receipts from mux.
+Receiving the read-receipts of all the entities if entityID is nil.
+*/
+func SubscribeReadReceipts(ctx context.Context, mux *event.TypeMux, entityID *types.PttID) (*rpc.Subscription, error) {
+	return subscribeEvents(ctx, mux, &ReadReceipt{}, func(data interface{}) bool {
+		if entityID == nil {
+			return true
+		}
+
+		receipt, ok := data.(*ReadReceipt)
+		if !ok {
+			return false
+		}
+
+		return reflect.DeepEqual(receipt.EntityID, entityID)
+	})
+}
+
+/*
+SubscribeTypings creates an RPC subscription which receives the typing-states from mux.
+Receiving the typing-states of all the entities if entityID is nil.
+*/
+func SubscribeTypings(ctx context.Context, mux *event.TypeMux, entityID *types.PttID) (*rpc.Subscription, error) {
+	return subscribeEvents(ctx, mux, &Typing{}, func(data interface{}) bool {
+		if entityID == nil {
+			return true
+		}
+
+		typing, ok := data.(*Typing)
+		if !ok {
+			return false
+		}
+
+		return reflect.DeepEqual(typing.EntityID, entityID)
+	})
+}
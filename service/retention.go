@@ -0,0 +1,149 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+/*
+RetentionPolicy is the per-entity retention policy set by the masters.
+
+The policy is replicated via a master-signed entity-oplog (with OpSetRetention as the op-data),
+and every member enforces the policy locally.
+*/
+type RetentionPolicy struct {
+	MaxAgeSeconds int64 `json:"A,omitempty"`
+	MaxCount      int   `json:"C,omitempty"`
+
+	UpdateTS types.Timestamp `json:"UT"`
+	LogID    *types.PttID    `json:"l,omitempty"`
+}
+
+func NewRetentionPolicy(maxAgeSeconds int64, maxCount int) (*RetentionPolicy, error) {
+	if maxAgeSeconds < 0 || maxCount < 0 {
+		return nil, ErrInvalidRetention
+	}
+
+	return &RetentionPolicy{
+		MaxAgeSeconds: maxAgeSeconds,
+		MaxCount:      maxCount,
+	}, nil
+}
+
+/*
+OpSetRetention is the op-data of the set-retention oplog.
+
+The op-data is without nested structs (ex: the update-ts),
+so that the relayed oplog is marshaled the same as signed.
+*/
+type OpSetRetention struct {
+	MaxAgeSeconds int64 `json:"A,omitempty"`
+	MaxCount      int   `json:"C,omitempty"`
+}
+
+/*
+IsEmpty returns true if nothing is to be expired with the policy.
+*/
+func (p *RetentionPolicy) IsEmpty() bool {
+	return p == nil || (p.MaxAgeSeconds <= 0 && p.MaxCount <= 0)
+}
+
+/*
+RetentionObject is the minimal info of the object to determine the retention.
+*/
+type RetentionObject struct {
+	ID       *types.PttID
+	CreateTS types.Timestamp
+}
+
+/*
+ExpiredIDs returns the ids of the objects older than MaxAgeSeconds,
+or not within the newest MaxCount objects.
+
+The objects are ordered by create-ts (then by id) so that every member gets the same result with the same objects.
+*/
+func (p *RetentionPolicy) ExpiredIDs(objs []*RetentionObject, now types.Timestamp) map[types.PttID]bool {
+	expiredIDs := make(map[types.PttID]bool)
+	if p.IsEmpty() {
+		return expiredIDs
+	}
+
+	sorted := make([]*RetentionObject, len(objs))
+	copy(sorted, objs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].CreateTS != sorted[j].CreateTS {
+			return sorted[j].CreateTS.IsLess(sorted[i].CreateTS)
+		}
+		return bytes.Compare(sorted[i].ID[:], sorted[j].ID[:]) > 0
+	})
+
+	expireTS := p.AgeExpireTS(now)
+
+	for i, obj := range sorted {
+		if p.MaxCount > 0 && i >= p.MaxCount {
+			expiredIDs[*obj.ID] = true
+			continue
+		}
+
+		if obj.CreateTS.IsLess(expireTS) {
+			expiredIDs[*obj.ID] = true
+		}
+	}
+
+	return expiredIDs
+}
+
+/*
+AgeExpireTS returns the create-ts before which the objects are older than MaxAgeSeconds.
+*/
+func (p *RetentionPolicy) AgeExpireTS(now types.Timestamp) types.Timestamp {
+	if p.IsEmpty() || p.MaxAgeSeconds <= 0 || now.Ts <= p.MaxAgeSeconds {
+		return types.ZeroTimestamp
+	}
+
+	expireTS := now
+	expireTS.Ts -= p.MaxAgeSeconds
+
+	return expireTS
+}
+
+/*
+IsExpired checks whether the object is expired with the policy among the objs, the same as ExpiredIDs.
+The object is not required to be in the objs (ex: the object synced from the peers).
+
+The objs are required only with MaxCount.
+*/
+func (p *RetentionPolicy) IsExpired(obj *RetentionObject, objs []*RetentionObject, now types.Timestamp) bool {
+	if p.IsEmpty() {
+		return false
+	}
+
+	allObjs := make([]*RetentionObject, 0, len(objs)+1)
+	allObjs = append(allObjs, obj)
+	for _, eachObj := range objs {
+		if *eachObj.ID == *obj.ID {
+			continue
+		}
+		allObjs = append(allObjs, eachObj)
+	}
+
+	return p.ExpiredIDs(allObjs, now)[*obj.ID]
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+)
+
+func TestRetentionPolicy_ExpiredIDs(t *testing.T) {
+	// setup test
+	id0 := &types.PttID{1}
+	id1 := &types.PttID{2}
+	id2 := &types.PttID{3}
+
+	objs := []*RetentionObject{
+		&RetentionObject{ID: id1, CreateTS: types.Timestamp{Ts: 200}},
+		&RetentionObject{ID: id0, CreateTS: types.Timestamp{Ts: 100}},
+		&RetentionObject{ID: id2, CreateTS: types.Timestamp{Ts: 300}},
+	}
+
+	now := types.Timestamp{Ts: 350}
+
+	// prepare test-cases
+	tests := []struct {
+		name   string
+		policy *RetentionPolicy
+		want   map[types.PttID]bool
+	}{
+		{
+			name:   "nil",
+			policy: nil,
+			want:   map[types.PttID]bool{},
+		},
+		{
+			name:   "empty",
+			policy: &RetentionPolicy{},
+			want:   map[types.PttID]bool{},
+		},
+		{
+			name:   "max-age",
+			policy: &RetentionPolicy{MaxAgeSeconds: 200},
+			want:   map[types.PttID]bool{*id0: true},
+		},
+		{
+			name:   "max-count",
+			policy: &RetentionPolicy{MaxCount: 1},
+			want:   map[types.PttID]bool{*id0: true, *id1: true},
+		},
+		{
+			name:   "max-age-and-count",
+			policy: &RetentionPolicy{MaxAgeSeconds: 100, MaxCount: 2},
+			want:   map[types.PttID]bool{*id0: true, *id1: true},
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.ExpiredIDs(objs, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RetentionPolicy.ExpiredIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// teardown test
+}
+
+func TestRetentionPolicy_IsExpired(t *testing.T) {
+	// setup test
+	id0 := &types.PttID{1}
+	id1 := &types.PttID{2}
+	id2 := &types.PttID{3}
+
+	objs := []*RetentionObject{
+		&RetentionObject{ID: id1, CreateTS: types.Timestamp{Ts: 200}},
+		&RetentionObject{ID: id2, CreateTS: types.Timestamp{Ts: 300}},
+	}
+
+	now := types.Timestamp{Ts: 350}
+
+	// prepare test-cases
+	tests := []struct {
+		name   string
+		policy *RetentionPolicy
+		obj    *RetentionObject
+		want   bool
+	}{
+		{
+			name:   "nil",
+			policy: nil,
+			obj:    &RetentionObject{ID: id0, CreateTS: types.Timestamp{Ts: 100}},
+			want:   false,
+		},
+		{
+			name:   "max-age",
+			policy: &RetentionPolicy{MaxAgeSeconds: 200},
+			obj:    &RetentionObject{ID: id0, CreateTS: types.Timestamp{Ts: 100}},
+			want:   true,
+		},
+		{
+			name:   "max-count",
+			policy: &RetentionPolicy{MaxCount: 2},
+			obj:    &RetentionObject{ID: id0, CreateTS: types.Timestamp{Ts: 100}},
+			want:   true,
+		},
+		{
+			name:   "max-count newer",
+			policy: &RetentionPolicy{MaxCount: 2},
+			obj:    &RetentionObject{ID: id0, CreateTS: types.Timestamp{Ts: 250}},
+			want:   false,
+		},
+		{
+			name:   "max-count in objs",
+			policy: &RetentionPolicy{MaxCount: 2},
+			obj:    &RetentionObject{ID: id1, CreateTS: types.Timestamp{Ts: 200}},
+			want:   false,
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.IsExpired(tt.obj, objs, now); got != tt.want {
+				t.Errorf("RetentionPolicy.IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// teardown test
+}
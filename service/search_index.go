@@ -0,0 +1,416 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ailabstw/go-pttai/common"
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+/*
+SearchDoc is the record of an indexed object.
+
+The token-keys are in the form of: prefix | token | 0 | createTS | id
+so that the docs with the same token are iterated in the order of createTS.
+*/
+type SearchDoc struct {
+	V         types.Version
+	ID        *types.PttID    `json:"ID"`
+	EntityID  *types.PttID    `json:"EID"`
+	RefID     *types.PttID    `json:"RID,omitempty"` // the parent of the object (ex: the article of the comment)
+	Type      uint8           `json:"t"`             // defined by each service
+	CreatorID *types.PttID    `json:"CID"`
+	CreateTS  types.Timestamp `json:"CT"`
+	UpdateTS  types.Timestamp `json:"UT"`
+
+	Tokens []string `json:"T"`
+}
+
+/*
+SearchIndex is the local inverted-index of the objects.
+*/
+type SearchIndex struct {
+	lock sync.Mutex
+
+	db          pttdb.DB
+	dbPrefix    []byte
+	dbDocPrefix []byte
+}
+
+func NewSearchIndex(db pttdb.DB, dbPrefix []byte, dbDocPrefix []byte) *SearchIndex {
+	return &SearchIndex{
+		db:          db,
+		dbPrefix:    dbPrefix,
+		dbDocPrefix: dbDocPrefix,
+	}
+}
+
+/*
+Index (re-)indexes the doc with the texts. The original tokens of the doc are removed.
+*/
+func (s *SearchIndex) Index(doc *SearchDoc, texts [][]byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	batch := s.db.NewBatch()
+
+	err := s.removeCore(doc.ID, batch)
+	if err != nil {
+		return err
+	}
+
+	doc.V = types.CurrentVersion
+	doc.Tokens = SearchIndexTokens(texts)
+	if len(doc.Tokens) == 0 {
+		return batch.Write()
+	}
+
+	var key []byte
+	for _, token := range doc.Tokens {
+		key, err = s.marshalTokenKey(token, doc.CreateTS, doc.ID)
+		if err != nil {
+			return err
+		}
+		err = batch.Put(key, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	docKey, err := s.marshalDocKey(doc.ID)
+	if err != nil {
+		return err
+	}
+	marshaled, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	err = batch.Put(docKey, marshaled)
+	if err != nil {
+		return err
+	}
+
+	return batch.Write()
+}
+
+/*
+Remove removes the doc and its tokens from the index.
+*/
+func (s *SearchIndex) Remove(id *types.PttID) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	batch := s.db.NewBatch()
+
+	err := s.removeCore(id, batch)
+	if err != nil {
+		return err
+	}
+
+	return batch.Write()
+}
+
+func (s *SearchIndex) removeCore(id *types.PttID, batch pttdb.Batch) error {
+	doc, err := s.GetDoc(id)
+	if err == leveldb.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var key []byte
+	for _, token := range doc.Tokens {
+		key, err = s.marshalTokenKey(token, doc.CreateTS, doc.ID)
+		if err != nil {
+			return err
+		}
+		batch.Delete(key)
+	}
+
+	docKey, err := s.marshalDocKey(id)
+	if err != nil {
+		return err
+	}
+	batch.Delete(docKey)
+
+	return nil
+}
+
+func (s *SearchIndex) GetDoc(id *types.PttID) (*SearchDoc, error) {
+	docKey, err := s.marshalDocKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	marshaled, err := s.db.Get(docKey)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &SearchDoc{}
+	err = json.Unmarshal(marshaled, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+/*
+Search searches the docs containing all the tokens of the query.
+
+	entityID: only the docs in the entity if not nil.
+	startID: the doc to start with (inclusive). The order is based on the createTS of the docs.
+	isValid: additional filter of the docs.
+*/
+func (s *SearchIndex) Search(query []byte, entityID *types.PttID, startID *types.PttID, limit int, listOrder pttdb.ListOrder, isValid func(doc *SearchDoc) bool) ([]*SearchDoc, error) {
+	tokens := SearchQueryTokens(query)
+	if len(tokens) == 0 {
+		return nil, ErrInvalidData
+	}
+
+	// iterate with the longest token, which is likely to be the most selective one.
+	mainToken := tokens[0]
+	for _, token := range tokens[1:] {
+		if len(token) > len(mainToken) {
+			mainToken = token
+		}
+	}
+
+	prefix, err := s.marshalTokenPrefix(mainToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var start []byte
+	if startID != nil {
+		startDoc, err := s.GetDoc(startID)
+		if err == leveldb.ErrNotFound {
+			return nil, types.ErrInvalidID
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, err = s.marshalTokenKey(mainToken, startDoc.CreateTS, startID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	iter, err := s.db.NewIteratorWithPrefix(start, prefix, listOrder)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+
+	iterFunc := pttdb.GetFuncIter(iter, listOrder)
+
+	docs := make([]*SearchDoc, 0)
+	var key []byte
+	var doc *SearchDoc
+	for iterFunc() {
+		if limit > 0 && len(docs) >= limit {
+			break
+		}
+
+		key = iter.Key()
+		if len(key) < types.SizePttID {
+			continue
+		}
+
+		id := &types.PttID{}
+		copy(id[:], key[len(key)-types.SizePttID:])
+
+		doc, err = s.GetDoc(id)
+		if err != nil {
+			continue
+		}
+
+		if entityID != nil && !reflect.DeepEqual(doc.EntityID, entityID) {
+			continue
+		}
+
+		if !s.isDocWithTokens(doc, tokens) {
+			continue
+		}
+
+		if isValid != nil && !isValid(doc) {
+			continue
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+func (s *SearchIndex) isDocWithTokens(doc *SearchDoc, tokens []string) bool {
+	for _, token := range tokens {
+		key, err := s.marshalTokenKey(token, doc.CreateTS, doc.ID)
+		if err != nil {
+			return false
+		}
+
+		isExists, err := s.db.Has(key)
+		if err != nil || !isExists {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *SearchIndex) marshalTokenPrefix(token string) ([]byte, error) {
+	return common.Concat([][]byte{s.dbPrefix, []byte(token), []byte{0}})
+}
+
+func (s *SearchIndex) marshalTokenKey(token string, ts types.Timestamp, id *types.PttID) ([]byte, error) {
+	tsBytes, err := ts.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return common.Concat([][]byte{s.dbPrefix, []byte(token), []byte{0}, tsBytes, id[:]})
+}
+
+func (s *SearchIndex) marshalDocKey(id *types.PttID) ([]byte, error) {
+	return common.Concat([][]byte{s.dbDocPrefix, id[:]})
+}
+
+/**********
+ * Tokenize
+ **********/
+
+/*
+SearchIndexTokens tokenizes the texts for indexing.
+
+ 1. Latin-like words are split by non-letter / non-digit characters and lower-cased.
+ 2. CJK characters are indexed as both unigrams and bigrams,
+    so that both single-character queries and the phrases are searchable.
+*/
+func SearchIndexTokens(texts [][]byte) []string {
+	tokens := make([]string, 0)
+	for _, text := range texts {
+		tokens = appendSearchTokens(tokens, text, false)
+	}
+
+	return uniqueSearchTokens(tokens)
+}
+
+/*
+SearchQueryTokens tokenizes the query.
+
+CJK characters are queried as bigrams if possible (unigram only for single CJK character).
+*/
+func SearchQueryTokens(query []byte) []string {
+	tokens := appendSearchTokens(nil, query, true)
+
+	return uniqueSearchTokens(tokens)
+}
+
+func appendSearchTokens(tokens []string, text []byte, isQuery bool) []string {
+	text = []byte(strings.ToLower(string(text)))
+
+	var word []rune
+	var cjk []rune
+
+	flushWord := func() {
+		if len(word) == 0 {
+			return
+		}
+		tokens = append(tokens, truncateSearchToken(string(word)))
+		word = word[:0]
+	}
+
+	flushCJK := func() {
+		if len(cjk) == 0 {
+			return
+		}
+		if len(cjk) == 1 || !isQuery {
+			for _, r := range cjk {
+				tokens = append(tokens, string(r))
+			}
+		}
+		for i := 0; i < len(cjk)-1; i++ {
+			tokens = append(tokens, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for len(text) > 0 {
+		r, size := utf8.DecodeRune(text)
+		text = text[size:]
+
+		switch {
+		case isSearchCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+
+	return tokens
+}
+
+func isSearchCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func truncateSearchToken(token string) string {
+	if len(token) <= MaxSearchTokenLength {
+		return token
+	}
+
+	theBytes := []byte(token)[:MaxSearchTokenLength]
+	for len(theBytes) > 0 && !utf8.Valid(theBytes) {
+		theBytes = theBytes[:len(theBytes)-1]
+	}
+
+	return string(theBytes)
+}
+
+func uniqueSearchTokens(tokens []string) []string {
+	tokenMap := make(map[string]bool)
+	theTokens := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if tokenMap[token] {
+			continue
+		}
+		tokenMap[token] = true
+		theTokens = append(theTokens, token)
+	}
+
+	return theTokens
+}
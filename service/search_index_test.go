@@ -0,0 +1,204 @@
+// Copyright 2019 The go-pttai Authors
+// This file is part of the go-pttai library.
+//
+// The go-pttai library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-pttai library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-pttai library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/pttdb"
+)
+
+func TestSearchIndexTokens(t *testing.T) {
+	// prepare test-cases
+	tests := []struct {
+		name  string
+		texts [][]byte
+		want  []string
+	}{
+		{
+			name:  "latin",
+			texts: [][]byte{[]byte("Hello, World! hello go-pttai 2019")},
+			want:  []string{"hello", "world", "go", "pttai", "2019"},
+		},
+		{
+			name:  "cjk",
+			texts: [][]byte{[]byte("台灣大學")},
+			want:  []string{"台", "灣", "大", "學", "台灣", "灣大", "大學"},
+		},
+		{
+			name:  "mixed",
+			texts: [][]byte{[]byte("PTT是台灣"), []byte("ok")},
+			want:  []string{"ptt", "是", "台", "灣", "是台", "台灣", "ok"},
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SearchIndexTokens(tt.texts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchIndexTokens() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryTokens(t *testing.T) {
+	// prepare test-cases
+	tests := []struct {
+		name  string
+		query []byte
+		want  []string
+	}{
+		{
+			name:  "cjk-phrase",
+			query: []byte("台灣大學"),
+			want:  []string{"台灣", "灣大", "大學"},
+		},
+		{
+			name:  "cjk-char",
+			query: []byte("台 PTT"),
+			want:  []string{"台", "ptt"},
+		},
+		{
+			name:  "empty",
+			query: []byte(" ,. "),
+			want:  []string{},
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SearchQueryTokens(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchQueryTokens() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchIndex_Search(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	s := NewSearchIndex(tDBOplogCore, []byte(".tsrd"), []byte(".tsrc"))
+
+	entityID1, _ := types.NewPttID()
+	entityID2, _ := types.NewPttID()
+
+	newDoc := func(entityID *types.PttID, ts int64) *SearchDoc {
+		id, _ := types.NewPttID()
+		return &SearchDoc{ID: id, EntityID: entityID, CreateTS: types.Timestamp{Ts: ts}}
+	}
+
+	doc1 := newDoc(entityID1, 1)
+	doc2 := newDoc(entityID1, 2)
+	doc3 := newDoc(entityID2, 3)
+
+	s.Index(doc1, [][]byte{[]byte("台灣大學 hello")})
+	s.Index(doc2, [][]byte{[]byte("大學 world")})
+	s.Index(doc3, [][]byte{[]byte("台灣大學 world")})
+
+	docIDs := func(docs []*SearchDoc) []*types.PttID {
+		ids := make([]*types.PttID, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.ID
+		}
+		return ids
+	}
+
+	// prepare test-cases
+	type args struct {
+		query     string
+		entityID  *types.PttID
+		startID   *types.PttID
+		limit     int
+		listOrder pttdb.ListOrder
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []*types.PttID
+		wantErr bool
+	}{
+		{
+			name: "phrase",
+			args: args{query: "台灣大學", listOrder: pttdb.ListOrderNext},
+			want: []*types.PttID{doc1.ID, doc3.ID},
+		},
+		{
+			name: "and",
+			args: args{query: "大學 WORLD", listOrder: pttdb.ListOrderPrev},
+			want: []*types.PttID{doc3.ID, doc2.ID},
+		},
+		{
+			name: "entity",
+			args: args{query: "大學", entityID: entityID1, listOrder: pttdb.ListOrderNext},
+			want: []*types.PttID{doc1.ID, doc2.ID},
+		},
+		{
+			name: "paginate",
+			args: args{query: "大學", startID: doc2.ID, limit: 1, listOrder: pttdb.ListOrderNext},
+			want: []*types.PttID{doc2.ID},
+		},
+		{
+			name: "not-found",
+			args: args{query: "hello world", listOrder: pttdb.ListOrderNext},
+			want: []*types.PttID{},
+		},
+		{
+			name:    "empty",
+			args:    args{query: " ", listOrder: pttdb.ListOrderNext},
+			wantErr: true,
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Search([]byte(tt.args.query), tt.args.entityID, tt.args.startID, tt.args.limit, tt.args.listOrder, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SearchIndex.Search() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotIDs := docIDs(got); !reflect.DeepEqual(gotIDs, tt.want) {
+				t.Errorf("SearchIndex.Search() = %v, want %v", gotIDs, tt.want)
+			}
+		})
+	}
+
+	// re-index and remove
+	s.Index(doc1, [][]byte{[]byte("world")})
+	s.Remove(doc3.ID)
+
+	got, _ := s.Search([]byte("world"), nil, nil, 0, pttdb.ListOrderNext, nil)
+	if gotIDs := docIDs(got); !reflect.DeepEqual(gotIDs, []*types.PttID{doc1.ID, doc2.ID}) {
+		t.Errorf("SearchIndex.Search() after re-index = %v, want %v", gotIDs, []*types.PttID{doc1.ID, doc2.ID})
+	}
+
+	got, _ = s.Search([]byte("台灣"), nil, nil, 0, pttdb.ListOrderNext, nil)
+	if len(got) != 0 {
+		t.Errorf("SearchIndex.Search() after remove = %v, want empty", docIDs(got))
+	}
+
+	// teardown test
+}
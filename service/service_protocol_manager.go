@@ -21,6 +21,7 @@ import (
 
 	"github.com/ailabstw/go-pttai/common/types"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
 )
 
 /*
@@ -59,9 +60,15 @@ type ServiceProtocolManager interface {
 	RUnlock(id *types.PttID) error
 
 	NewEmptyEntity() Entity
+
+	// event-mux
+	EventMux() *event.TypeMux
 }
 
 type BaseServiceProtocolManager struct {
+	// eventMux
+	eventMux *event.TypeMux
+
 	lock     sync.RWMutex
 	entities map[types.PttID]Entity
 
@@ -98,6 +105,8 @@ func NewBaseServiceProtocolManager(ptt Ptt, service Service) (*BaseServiceProtoc
 	}
 
 	spm := &BaseServiceProtocolManager{
+		eventMux: new(event.TypeMux),
+
 		entities: make(map[types.PttID]Entity),
 
 		noMorePeers: ptt.NoMorePeers(),
@@ -134,7 +143,11 @@ func (spm *BaseServiceProtocolManager) Start() error {
 }
 
 func (spm *BaseServiceProtocolManager) Stop() error {
-	return spm.StopEntities()
+	err := spm.StopEntities()
+
+	spm.eventMux.Stop()
+
+	return err
 }
 
 func (spm *BaseServiceProtocolManager) Ptt() Ptt {
@@ -149,6 +162,10 @@ func (spm *BaseServiceProtocolManager) NewEmptyEntity() Entity {
 	return nil
 }
 
+func (spm *BaseServiceProtocolManager) EventMux() *event.TypeMux {
+	return spm.eventMux
+}
+
 /*
 Lock locks the entity-level lock.
 */
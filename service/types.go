@@ -16,7 +16,10 @@
 
 package service
 
-import "github.com/ailabstw/go-pttai/common/types"
+import (
+	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/key"
+)
 
 // ServiceConstructor is the function signature of the constructors needed to be
 // registered for service instantiation.
@@ -107,11 +110,12 @@ type SignInfo struct {
 	ID       *types.PttID    `json:"ID"`
 	CreateTS types.Timestamp `json:"CT"`
 
-	Hash   []byte        `json:"H"`
-	Salt   types.Salt    `json:"s"`
-	Sig    []byte        `json:"S"`
-	Pubkey []byte        `json:"K"`
-	Extra  *KeyExtraInfo `json:"e,omitempty"`
+	Hash    []byte        `json:"H"`
+	Salt    types.Salt    `json:"s"`
+	Sig     []byte        `json:"S"`
+	Pubkey  []byte        `json:"K"`
+	Extra   *KeyExtraInfo `json:"e,omitempty"`
+	KeyType key.Type      `json:"T,omitempty"`
 }
 
 type SyncID struct {
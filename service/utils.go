@@ -26,9 +26,11 @@ import (
 
 	pttcommon "github.com/ailabstw/go-pttai/common"
 	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/key"
 	"github.com/ailabstw/go-pttai/log"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ed25519"
 )
 
 func SignData(bytes []byte, keyInfo *KeyInfo) ([]byte, []byte, []byte, []byte, error) {
@@ -42,9 +44,19 @@ func SignData(bytes []byte, keyInfo *KeyInfo) ([]byte, []byte, []byte, []byte, e
 		return nil, nil, nil, nil, err
 	}
 	hash := crypto.Keccak256(bytesWithSalt)
-	sig, err := crypto.Sign(hash, keyInfo.Key)
-	if err != nil {
-		return nil, nil, nil, nil, err
+
+	var sig []byte
+	switch keyInfo.KeyType {
+	case key.TypeEd25519:
+		if len(keyInfo.EdKey) != ed25519.PrivateKeySize {
+			return nil, nil, nil, nil, ErrInvalidKey
+		}
+		sig = ed25519.Sign(keyInfo.EdKey, hash)
+	default:
+		sig, err = crypto.Sign(hash, keyInfo.Key)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
 	}
 
 	keyInfo.Count++
@@ -52,9 +64,9 @@ func SignData(bytes []byte, keyInfo *KeyInfo) ([]byte, []byte, []byte, []byte, e
 	return bytesWithSalt, hash, sig, keyInfo.PubKeyBytes, nil
 }
 
-func VerifyData(bytesWithSalt []byte, expectedHash []byte, sig []byte, pubKeyBytes []byte, doerID *types.PttID, extra *KeyExtraInfo) error {
+func VerifyData(bytesWithSalt []byte, expectedHash []byte, sig []byte, keyType key.Type, pubKeyBytes []byte, doerID *types.PttID, extra *KeyExtraInfo) error {
 
-	isValidKey := verifyDataCheckKey(pubKeyBytes, doerID, extra)
+	isValidKey := verifyDataCheckKey(keyType, pubKeyBytes, doerID, extra)
 	if !isValidKey {
 		log.Error("VerifyData: not valid key", "pubKeyBytes", pubKeyBytes, "doerID", doerID)
 		return ErrInvalidKey
@@ -66,7 +78,7 @@ func VerifyData(bytesWithSalt []byte, expectedHash []byte, sig []byte, pubKeyByt
 		return ErrInvalidData
 	}
 
-	isGood := crypto.VerifySignature(pubKeyBytes, hash, sig[:64])
+	isGood := key.VerifySignature(keyType, pubKeyBytes, hash, sig)
 	if !isGood {
 		log.Error("VerifyData: unable to VerifySignature", "pubKeyBytes", pubKeyBytes, "hash", hash, "sig", sig)
 		return ErrInvalidData
@@ -74,16 +86,27 @@ func VerifyData(bytesWithSalt []byte, expectedHash []byte, sig []byte, pubKeyByt
 	return nil
 }
 
-func verifyDataCheckKey(pubKeyBytes []byte, doerID *types.PttID, extra *KeyExtraInfo) bool {
-	if extra == nil {
+func verifyDataCheckKey(keyType key.Type, pubKeyBytes []byte, doerID *types.PttID, extra *KeyExtraInfo) bool {
+	if extra != nil {
+		return extra.IsValid(keyType, pubKeyBytes, doerID)
+	}
+
+	switch keyType {
+	case key.TypeSecp256k1:
 		pubKey, err := crypto.UnmarshalPubkey(pubKeyBytes)
 		if err != nil {
 			return false
 		}
 		return doerID.IsSamePubKey(pubKey)
+	case key.TypeEd25519:
+		addr, err := key.PubkeyBytesToAddressWithType(keyType, pubKeyBytes)
+		if err != nil {
+			return false
+		}
+		return reflect.DeepEqual(doerID[:common.AddressLength], addr[:])
 	}
 
-	return extra.IsValid(pubKeyBytes, doerID)
+	return false
 }
 
 func DBPrefix(dbPrefix []byte, id *types.PttID) ([]byte, error) {
@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/ailabstw/go-pttai/common/types"
+	"github.com/ailabstw/go-pttai/key"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func TestSignData(t *testing.T) {
@@ -116,7 +118,7 @@ func TestVerifyData(t *testing.T) {
 	// run test
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := VerifyData(tt.args.bytesWithSalt, tt.args.hash, tt.args.sig, tt.args.keyBytes, tt.args.doerID, tt.args.extra); (err != nil) != tt.wantErr {
+			if err := VerifyData(tt.args.bytesWithSalt, tt.args.hash, tt.args.sig, key.TypeSecp256k1, tt.args.keyBytes, tt.args.doerID, tt.args.extra); (err != nil) != tt.wantErr {
 				t.Errorf("VerifyData() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -124,3 +126,85 @@ func TestVerifyData(t *testing.T) {
 
 	// teardown test
 }
+
+func TestSignVerifyDataEd25519(t *testing.T) {
+	// setup test
+	setupTest(t)
+	defer teardownTest(t)
+
+	masterKey, _ := crypto.GenerateKey()
+	doerID, _ := types.NewPttIDFromKey(masterKey)
+	otherKey, _ := crypto.GenerateKey()
+	otherID, _ := types.NewPttIDFromKey(otherKey)
+
+	keyInfo, err := newEd25519KeyInfo(masterKey, doerID, doerID)
+	if err != nil {
+		t.Errorf("newEd25519KeyInfo() error = %v", err)
+		return
+	}
+
+	bytesWithSalt, hash, sig, pubBytes, err := SignData([]byte("test"), keyInfo)
+	if err != nil {
+		t.Errorf("SignData() error = %v", err)
+		return
+	}
+
+	tamperedSig := append([]byte{}, sig...)
+	tamperedSig[0] ^= 0xff
+
+	// define test-structure
+	type args struct {
+		sig     []byte
+		keyType key.Type
+		doerID  *types.PttID
+	}
+
+	// prepare test-cases
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			args:    args{sig: sig, keyType: key.TypeEd25519, doerID: doerID},
+			wantErr: false,
+		},
+		{
+			args:    args{sig: tamperedSig, keyType: key.TypeEd25519, doerID: doerID},
+			wantErr: true,
+		},
+		{
+			args:    args{sig: sig, keyType: key.TypeSecp256k1, doerID: doerID},
+			wantErr: true,
+		},
+		{
+			args:    args{sig: sig, keyType: key.TypeEd25519, doerID: otherID},
+			wantErr: true,
+		},
+	}
+
+	// run test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyData(bytesWithSalt, hash, tt.args.sig, tt.args.keyType, pubBytes, tt.args.doerID, keyInfo.Extra); (err != nil) != tt.wantErr {
+				t.Errorf("VerifyData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// the cert signed without the domain is not valid.
+	noDomainSig, _ := crypto.Sign(crypto.Keccak256(pubBytes), masterKey)
+	noDomainExtra := &KeyExtraInfo{
+		KeyType: KeyTypeEd25519Cert,
+		Data: &KeyEd25519Cert{
+			Parent:     crypto.FromECDSAPub(&masterKey.PublicKey),
+			ParentType: key.TypeSecp256k1,
+			Sig:        noDomainSig,
+		},
+	}
+	if err := VerifyData(bytesWithSalt, hash, sig, key.TypeEd25519, pubBytes, doerID, noDomainExtra); err == nil {
+		t.Errorf("VerifyData() cert without domain: no error")
+	}
+
+	// teardown test
+}